@@ -29,6 +29,18 @@ type AutoPlayAlgorithm interface {
 	SelectReturnTributeCard(hand []*sdk.Card, receivedCard *sdk.Card) *sdk.Card
 }
 
+// InferenceAwareAlgorithm 可以利用对手手牌推断的算法
+// 输入提供者在发现算法实现该接口时，会为其所在座位维护一个 HandInference
+type InferenceAwareAlgorithm interface {
+	SetHandInference(inference *HandInference)
+}
+
+// ObservationAwareAlgorithm 需要公开历史信息（出牌记录、级别等）的算法
+// 输入提供者在发现算法实现该接口时，会告知其所在座位并提供公开信息跟踪器
+type ObservationAwareAlgorithm interface {
+	SetObservationSource(seat int, tracker *ObservationTracker)
+}
+
 // SimpleAutoPlayAlgorithm 简单的自动出牌算法实现
 type SimpleAutoPlayAlgorithm struct {
 	level int // 当前级别
@@ -0,0 +1,331 @@
+package ai
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"guandan-world/sdk"
+)
+
+// Difficulty 机器人难度
+type Difficulty string
+
+const (
+	DifficultyBeginner     Difficulty = "beginner"     // 入门：较多随机出牌，几乎不记牌
+	DifficultyIntermediate Difficulty = "intermediate" // 中级：少量随机出牌，会遗忘部分已出的牌
+	DifficultyExpert       Difficulty = "expert"       // 专家：不加噪声，完整记牌
+)
+
+// ParseDifficulty 解析难度字符串（不区分大小写），空字符串视为中级
+func ParseDifficulty(s string) (Difficulty, error) {
+	switch Difficulty(strings.ToLower(strings.TrimSpace(s))) {
+	case DifficultyBeginner:
+		return DifficultyBeginner, nil
+	case DifficultyIntermediate, "":
+		return DifficultyIntermediate, nil
+	case DifficultyExpert:
+		return DifficultyExpert, nil
+	default:
+		return "", fmt.Errorf("unknown difficulty: %s", s)
+	}
+}
+
+// Personality 机器人性格参数，取值范围均为0-1
+type Personality struct {
+	BombAggression float64 `json:"bomb_aggression"` // 无法用普通牌跟牌时主动用炸弹的倾向
+	LeadBig        float64 `json:"lead_big"`        // 首出时优先打大牌抢控制权的倾向
+	RiskAppetite   float64 `json:"risk_appetite"`   // 愿意拆散炸弹/钢板等牌组去跟牌的倾向
+}
+
+// BotConfig 机器人配置
+type BotConfig struct {
+	Difficulty  Difficulty  `json:"difficulty"`
+	Personality Personality `json:"personality"`
+	Epsilon     float64     `json:"epsilon"`     // 随机选择合法出牌的概率
+	ForgetRate  float64     `json:"forget_rate"` // 记牌时遗忘一张已出牌的概率（1表示不记牌）
+	Seed        int64       `json:"seed"`        // 随机种子，0表示使用当前时间
+}
+
+// DefaultBotConfig 返回指定难度的默认配置
+func DefaultBotConfig(difficulty Difficulty) BotConfig {
+	switch difficulty {
+	case DifficultyBeginner:
+		return BotConfig{
+			Difficulty:  DifficultyBeginner,
+			Personality: Personality{BombAggression: 0.2, LeadBig: 0.3, RiskAppetite: 0.7},
+			Epsilon:     0.3,
+			ForgetRate:  1.0,
+		}
+	case DifficultyExpert:
+		return BotConfig{
+			Difficulty:  DifficultyExpert,
+			Personality: Personality{BombAggression: 0.5, LeadBig: 0.1, RiskAppetite: 0.3},
+			Epsilon:     0,
+			ForgetRate:  0,
+		}
+	default:
+		return BotConfig{
+			Difficulty:  DifficultyIntermediate,
+			Personality: Personality{BombAggression: 0.3, LeadBig: 0.2, RiskAppetite: 0.5},
+			Epsilon:     0.1,
+			ForgetRate:  0.4,
+		}
+	}
+}
+
+// BotAutoPlayAlgorithm 可调强度与性格的机器人算法
+// 在 SmartAutoPlayAlgorithm 的基础上加入可控的噪声和性格偏好
+type BotAutoPlayAlgorithm struct {
+	smart  *SmartAutoPlayAlgorithm
+	config BotConfig
+	rng    *rand.Rand
+}
+
+// NewBotAutoPlayAlgorithm 创建机器人算法实例
+func NewBotAutoPlayAlgorithm(level int, config BotConfig) AutoPlayAlgorithm {
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &BotAutoPlayAlgorithm{
+		smart:  &SmartAutoPlayAlgorithm{level: level},
+		config: config,
+		rng:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// NewBotAutoPlayAlgorithmWithDifficulty 使用难度的默认配置创建机器人算法
+func NewBotAutoPlayAlgorithmWithDifficulty(level int, difficulty Difficulty) AutoPlayAlgorithm {
+	return NewBotAutoPlayAlgorithm(level, DefaultBotConfig(difficulty))
+}
+
+// Config 返回机器人配置
+func (algo *BotAutoPlayAlgorithm) Config() BotConfig {
+	return algo.config
+}
+
+// SetHandInference 实现 InferenceAwareAlgorithm
+// 完全不记牌的机器人不使用推断，其余按遗忘率降低记牌精度
+func (algo *BotAutoPlayAlgorithm) SetHandInference(inference *HandInference) {
+	if inference == nil || algo.config.ForgetRate >= 1 {
+		algo.smart.SetHandInference(nil)
+		return
+	}
+	inference.SetForgetting(algo.config.ForgetRate, rand.New(rand.NewSource(algo.rng.Int63())))
+	algo.smart.SetHandInference(inference)
+}
+
+// SelectCardsToPlay 实现自动出牌逻辑
+func (algo *BotAutoPlayAlgorithm) SelectCardsToPlay(hand []*sdk.Card, trickInfo *sdk.TrickInfo) []*sdk.Card {
+	if len(hand) == 0 {
+		return nil
+	}
+
+	// 噪声：以 epsilon 概率随机选择一个合法出牌
+	if algo.config.Epsilon > 0 && algo.rng.Float64() < algo.config.Epsilon {
+		return algo.randomLegalPlay(hand, trickInfo)
+	}
+
+	if trickInfo.IsLeader {
+		return algo.selectLead(hand)
+	}
+	return algo.selectFollow(hand, trickInfo)
+}
+
+// selectLead 首出：按 LeadBig 倾向决定是否先打大牌
+func (algo *BotAutoPlayAlgorithm) selectLead(hand []*sdk.Card) []*sdk.Card {
+	if algo.rng.Float64() < algo.config.Personality.LeadBig {
+		groups := algo.smart.identifyAllPossibleGroups(hand)
+		var best *CardGroup
+		for _, group := range groups {
+			if group.Comp == nil || group.Comp.IsBomb() {
+				continue
+			}
+			if best == nil || group.Strength > best.Strength {
+				best = group
+			}
+		}
+		if best != nil {
+			return best.Cards
+		}
+	}
+	return algo.smart.selectBestFirstPlay(hand)
+}
+
+// selectFollow 跟牌：按风险偏好和炸弹倾向调整智能算法的选择
+func (algo *BotAutoPlayAlgorithm) selectFollow(hand []*sdk.Card, trickInfo *sdk.TrickInfo) []*sdk.Card {
+	cards := algo.smart.tryToFollowSmart(hand, trickInfo)
+
+	if cards != nil {
+		comp := sdk.FromCardList(cards, nil)
+		if comp != nil && !comp.IsBomb() && !trickInfo.LeadComp.IsBomb() {
+			// 保守的机器人不愿意为了跟牌拆散高价值牌组
+			if algo.breaksValuableGroup(hand, cards) && algo.rng.Float64() >= algo.config.Personality.RiskAppetite {
+				return nil
+			}
+		}
+		return cards
+	}
+
+	// 智能算法选择过牌时，按炸弹倾向决定是否用最小的炸弹压
+	if trickInfo.LeadComp == nil || algo.rng.Float64() >= algo.config.Personality.BombAggression {
+		return nil
+	}
+	groups := algo.smart.identifyAllPossibleGroups(hand)
+	var smallest *CardGroup
+	for _, group := range groups {
+		if group.Comp == nil || !group.Comp.IsBomb() || !group.Comp.GreaterThan(trickInfo.LeadComp) {
+			continue
+		}
+		if smallest == nil || group.Strength < smallest.Strength {
+			smallest = group
+		}
+	}
+	if smallest == nil {
+		return nil
+	}
+	return smallest.Cards
+}
+
+// breaksValuableGroup 判断打出 cards 是否会拆散手中的炸弹、钢板、钢管或同花顺
+func (algo *BotAutoPlayAlgorithm) breaksValuableGroup(hand []*sdk.Card, cards []*sdk.Card) bool {
+	used := make(map[*sdk.Card]bool, len(cards))
+	for _, card := range cards {
+		used[card] = true
+	}
+	for _, group := range algo.smart.identifyAllPossibleGroups(hand) {
+		switch group.CompType {
+		case sdk.TypeNaiveBomb, sdk.TypeJokerBomb, sdk.TypePlate, sdk.TypeTube, sdk.TypeStraightFlush:
+		default:
+			continue
+		}
+		overlap := 0
+		for _, card := range group.Cards {
+			if used[card] {
+				overlap++
+			}
+		}
+		if overlap > 0 && overlap < len(group.Cards) {
+			return true
+		}
+	}
+	return false
+}
+
+// randomLegalPlay 随机选择一个合法出牌（跟牌时过牌也是一个选项）
+func (algo *BotAutoPlayAlgorithm) randomLegalPlay(hand []*sdk.Card, trickInfo *sdk.TrickInfo) []*sdk.Card {
+	legal := EnumerateLegalPlays(hand, trickInfo)
+	if !trickInfo.IsLeader {
+		// nil 表示过牌
+		legal = append(legal, nil)
+	}
+	if len(legal) == 0 {
+		return algo.smart.SelectCardsToPlay(hand, trickInfo)
+	}
+	return legal[algo.rng.Intn(len(legal))]
+}
+
+// SelectTributeCard 选择贡牌
+func (algo *BotAutoPlayAlgorithm) SelectTributeCard(hand []*sdk.Card, excludeHeartTrump bool) *sdk.Card {
+	return algo.smart.SelectTributeCard(hand, excludeHeartTrump)
+}
+
+// SelectReturnTributeCard 选择还贡牌
+func (algo *BotAutoPlayAlgorithm) SelectReturnTributeCard(hand []*sdk.Card, receivedCard *sdk.Card) *sdk.Card {
+	return algo.smart.SelectReturnTributeCard(hand, receivedCard)
+}
+
+// NewAlgorithmByName 根据名称创建算法，供命令行和服务端按名称选择机器人
+// 支持 "simple"、"smart"、难度名称 "beginner"、"intermediate"、"expert"，
+// "smart:<权重文件路径>" 使用调优后的智能算法权重（见 SmartWeights），
+// "neural:<权重文件路径>" 加载神经网络机器人，
+// 以及通过 RegisterAlgorithm 注册的 "<前缀>:<参数>"（如 ai/remote 的 "process:<命令>"）
+func NewAlgorithmByName(name string, level int) (AutoPlayAlgorithm, error) {
+	factory, err := NewAlgorithmFactory(name)
+	if err != nil {
+		return nil, err
+	}
+	return factory(level, 0), nil
+}
+
+// AlgorithmFactory 按级别和随机种子创建算法实例
+// 种子为0时带随机性的算法使用当前时间作为种子
+type AlgorithmFactory func(level int, seed int64) AutoPlayAlgorithm
+
+// NewAlgorithmFactory 根据名称创建算法工厂，名称格式同 NewAlgorithmByName
+// 批量模拟时每场比赛需要新的算法实例，工厂只解析一次名称、只加载一次模型
+func NewAlgorithmFactory(name string) (AlgorithmFactory, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "simple":
+		return func(level int, seed int64) AutoPlayAlgorithm {
+			return NewSimpleAutoPlayAlgorithm(level)
+		}, nil
+	case "smart", "":
+		return func(level int, seed int64) AutoPlayAlgorithm {
+			return NewSmartAutoPlayAlgorithm(level)
+		}, nil
+	}
+	if path, ok := strings.CutPrefix(strings.TrimSpace(name), "smart:"); ok {
+		weights, err := LoadSmartWeights(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load smart weights: %w", err)
+		}
+		return func(level int, seed int64) AutoPlayAlgorithm {
+			return NewSmartAutoPlayAlgorithmWithWeights(level, weights)
+		}, nil
+	}
+	if path, ok := strings.CutPrefix(strings.TrimSpace(name), "neural:"); ok {
+		model, err := LoadNeuralModel(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load neural model: %w", err)
+		}
+		return func(level int, seed int64) AutoPlayAlgorithm {
+			return NewNeuralAutoPlayAlgorithm(model, level, NeuralConfig{Seed: seed})
+		}, nil
+	}
+	if prefix, arg, ok := strings.Cut(strings.TrimSpace(name), ":"); ok {
+		if parser, exists := registeredAlgorithm(prefix); exists {
+			return parser(arg)
+		}
+	}
+	difficulty, err := ParseDifficulty(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown algorithm: %s", name)
+	}
+	return func(level int, seed int64) AutoPlayAlgorithm {
+		config := DefaultBotConfig(difficulty)
+		config.Seed = seed
+		return NewBotAutoPlayAlgorithm(level, config)
+	}, nil
+}
+
+// AlgorithmParser 解析 "<前缀>:<参数>" 中的参数并创建算法工厂
+type AlgorithmParser func(arg string) (AlgorithmFactory, error)
+
+var (
+	algorithmParsersMutex sync.RWMutex
+	algorithmParsers      = make(map[string]AlgorithmParser)
+)
+
+// RegisterAlgorithm 注册带前缀的算法名称，使 NewAlgorithmFactory 能创建 ai 包无法直接引用的算法
+// （如依赖 ai 包的外部进程机器人），通常在实现包的 init 中调用
+// 参数:
+//
+//	prefix: 名称前缀，不含冒号
+//	parser: 解析前缀之后的参数
+func RegisterAlgorithm(prefix string, parser AlgorithmParser) {
+	algorithmParsersMutex.Lock()
+	defer algorithmParsersMutex.Unlock()
+	algorithmParsers[prefix] = parser
+}
+
+// registeredAlgorithm 返回前缀对应的解析函数
+func registeredAlgorithm(prefix string) (AlgorithmParser, bool) {
+	algorithmParsersMutex.RLock()
+	defer algorithmParsersMutex.RUnlock()
+	parser, exists := algorithmParsers[prefix]
+	return parser, exists
+}
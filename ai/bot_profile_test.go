@@ -0,0 +1,160 @@
+package ai
+
+import (
+	"fmt"
+	"testing"
+
+	"guandan-world/sdk"
+)
+
+func TestParseDifficulty(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Difficulty
+		wantErr  bool
+	}{
+		{"beginner", DifficultyBeginner, false},
+		{"Expert", DifficultyExpert, false},
+		{" intermediate ", DifficultyIntermediate, false},
+		{"", DifficultyIntermediate, false},
+		{"grandmaster", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDifficulty(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDifficulty(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseDifficulty(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestDefaultBotConfig_NoiseDecreasesWithDifficulty(t *testing.T) {
+	beginner := DefaultBotConfig(DifficultyBeginner)
+	intermediate := DefaultBotConfig(DifficultyIntermediate)
+	expert := DefaultBotConfig(DifficultyExpert)
+
+	if !(beginner.Epsilon > intermediate.Epsilon && intermediate.Epsilon > expert.Epsilon) {
+		t.Errorf("epsilon should decrease with difficulty: %v %v %v", beginner.Epsilon, intermediate.Epsilon, expert.Epsilon)
+	}
+	if !(beginner.ForgetRate > intermediate.ForgetRate && intermediate.ForgetRate > expert.ForgetRate) {
+		t.Errorf("forget rate should decrease with difficulty: %v %v %v", beginner.ForgetRate, intermediate.ForgetRate, expert.ForgetRate)
+	}
+}
+
+func TestBotAutoPlayAlgorithm_RandomPlaysAreLegal(t *testing.T) {
+	config := DefaultBotConfig(DifficultyBeginner)
+	config.Epsilon = 1
+	config.Seed = 7
+	algo := NewBotAutoPlayAlgorithm(2, config)
+
+	hand := []*sdk.Card{
+		createCard(3, "Spade"),
+		createCard(5, "Heart"),
+		createCard(5, "Club"),
+		createCard(9, "Diamond"),
+		createCard(12, "Spade"),
+		createCard(13, "Heart"),
+	}
+	lead := sdk.FromCardList([]*sdk.Card{createCard(8, "Spade")}, nil)
+
+	for i := 0; i < 50; i++ {
+		cards := algo.SelectCardsToPlay(hand, &sdk.TrickInfo{IsLeader: false, LeadComp: lead})
+		if cards == nil {
+			continue
+		}
+		comp := sdk.FromCardList(cards, nil)
+		if comp == nil || !comp.IsValid() || !comp.GreaterThan(lead) {
+			t.Fatalf("random play %v does not beat the lead", cards)
+		}
+	}
+
+	for i := 0; i < 50; i++ {
+		if cards := algo.SelectCardsToPlay(hand, &sdk.TrickInfo{IsLeader: true}); len(cards) == 0 {
+			t.Fatal("leader must always play cards")
+		}
+	}
+}
+
+func TestBotAutoPlayAlgorithm_DeterministicPerSeed(t *testing.T) {
+	config := DefaultBotConfig(DifficultyBeginner)
+	config.Seed = 42
+	hand := []*sdk.Card{
+		createCard(3, "Spade"),
+		createCard(4, "Heart"),
+		createCard(4, "Club"),
+		createCard(7, "Diamond"),
+		createCard(7, "Spade"),
+		createCard(7, "Heart"),
+		createCard(11, "Club"),
+	}
+
+	a := NewBotAutoPlayAlgorithm(2, config)
+	b := NewBotAutoPlayAlgorithm(2, config)
+	for i := 0; i < 30; i++ {
+		trickInfo := &sdk.TrickInfo{IsLeader: true}
+		ca := a.SelectCardsToPlay(hand, trickInfo)
+		cb := b.SelectCardsToPlay(hand, trickInfo)
+		if len(ca) != len(cb) {
+			t.Fatalf("same seed should produce same plays: %v vs %v", ca, cb)
+		}
+		for j := range ca {
+			if ca[j] != cb[j] {
+				t.Fatalf("same seed should produce same plays: %v vs %v", ca, cb)
+			}
+		}
+	}
+}
+
+func TestBotAutoPlayAlgorithm_BombAggression(t *testing.T) {
+	hand := []*sdk.Card{
+		createCard(3, "Spade"),
+		createCard(9, "Spade"),
+		createCard(9, "Heart"),
+		createCard(9, "Club"),
+		createCard(9, "Diamond"),
+	}
+	trickInfo := &sdk.TrickInfo{
+		IsLeader: false,
+		LeadComp: sdk.FromCardList([]*sdk.Card{createCard(14, "Spade")}, nil),
+	}
+
+	config := BotConfig{Personality: Personality{BombAggression: 1}, Seed: 1}
+	if cards := NewBotAutoPlayAlgorithm(2, config).SelectCardsToPlay(hand, trickInfo); len(cards) != 4 {
+		t.Errorf("aggressive bot should bomb, got %v", cards)
+	}
+
+	config.Personality.BombAggression = 0
+	if cards := NewBotAutoPlayAlgorithm(2, config).SelectCardsToPlay(hand, trickInfo); cards != nil {
+		t.Errorf("passive bot should pass, got %v", cards)
+	}
+}
+
+func TestNewAlgorithmByName(t *testing.T) {
+	for _, name := range []string{"simple", "smart", "beginner", "intermediate", "expert"} {
+		if _, err := NewAlgorithmByName(name, 2); err != nil {
+			t.Errorf("NewAlgorithmByName(%q) failed: %v", name, err)
+		}
+	}
+	if _, err := NewAlgorithmByName("unknown", 2); err == nil {
+		t.Error("expected error for unknown algorithm")
+	}
+	if _, err := NewAlgorithmByName("custom:arg", 2); err == nil {
+		t.Error("expected error for unregistered prefix")
+	}
+
+	RegisterAlgorithm("custom", func(arg string) (AlgorithmFactory, error) {
+		if arg != "arg" {
+			return nil, fmt.Errorf("unexpected arg %q", arg)
+		}
+		return func(level int, seed int64) AutoPlayAlgorithm {
+			return NewSimpleAutoPlayAlgorithm(level)
+		}, nil
+	})
+	if _, err := NewAlgorithmByName("custom:arg", 2); err != nil {
+		t.Errorf("registered algorithm failed: %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package ai
+
+import (
+	"strconv"
+	"strings"
+
+	"guandan-world/sdk"
+)
+
+// 特征编码
+//
+// 所有编码都是定长的 float64 向量，布局由 FeatureVersion 标识。布局一旦发布就不再修改，
+// 需要调整时递增版本号，离线数据和模型权重通过版本号判断是否兼容。
+//
+// 牌面计数向量（54维）：下标为 (点数-2)*4+花色序号（花色顺序同 sdk.Colors），52、53 为小王、大王，
+// 值为该牌面的张数除以2（两副牌）。
+//
+// 座位统一使用相对座位：0 为自己，1 为下家，2 为对家，3 为上家。
+//
+// 观察向量布局（ObservationFeatureSize 维）：
+//
+//	[0, 54)      自己的手牌计数
+//	[54, 67)     当前级别 one-hot（2-14）
+//	[67, 80)     己方级别 one-hot
+//	[80, 93)     对方级别 one-hot
+//	[93]         是否首出
+//	[94, 148)    领先牌计数
+//	[148, 160)   领先牌型 one-hot（sdk.CompType）
+//	[160, 164)   领先座位 one-hot（相对座位，没有领先牌时全0）
+//	[164, 168)   各相对座位剩余手牌数 / 27
+//	[168, 384)   各相对座位本局已出的牌计数（4×54）
+//	[384, 438)   未见牌计数（除自己手牌和已出牌外的剩余牌）
+//	[438, 910)   最近 HistoryWindow 条公开动作，从新到旧，每条59维：
+//	             相对座位 one-hot(4) + 过牌标记(1) + 出牌计数(54)，不足时补0
+//
+// 出牌向量布局（MoveFeatureSize 维）：
+//
+//	[0, 54)      出牌计数
+//	[54, 66)     牌型 one-hot（sdk.CompType）
+//	[66]         过牌标记
+//	[67]         张数 / 27
+
+// FeatureVersion 特征编码布局版本
+const FeatureVersion = 1
+
+const (
+	levelSlots       = 13 // 级别 2-14
+	compTypeSlots    = 12 // sdk.CompType 的取值个数
+	historyEntrySize = 4 + 1 + cardKinds
+
+	// HandFeatureSize 牌面计数向量维度
+	HandFeatureSize = cardKinds
+
+	// ObservationFeatureSize 观察向量维度
+	ObservationFeatureSize = cardKinds + 3*levelSlots + 1 + cardKinds + compTypeSlots + 4 + 4 +
+		4*cardKinds + cardKinds + HistoryWindow*historyEntrySize
+
+	// MoveFeatureSize 出牌向量维度
+	MoveFeatureSize = cardKinds + compTypeSlots + 2
+)
+
+// EncodeHandFeatures 把一组牌编码为54维牌面计数向量
+func EncodeHandFeatures(cards []*sdk.Card) []float64 {
+	features := make([]float64, HandFeatureSize)
+	for _, card := range cards {
+		if k := cardKindIndex(card); k >= 0 {
+			features[k] += 0.5
+		}
+	}
+	return features
+}
+
+// EncodeObservation 把观察编码为定长向量
+func EncodeObservation(obs *Observation) []float64 {
+	features := make([]float64, ObservationFeatureSize)
+	if obs == nil {
+		return features
+	}
+	offset := 0
+
+	// 自己的手牌
+	var seen [cardKinds]int
+	for _, id := range obs.Hand {
+		if k := kindIndexFromID(id); k >= 0 {
+			features[offset+k] += 0.5
+			seen[k]++
+		}
+	}
+	offset += cardKinds
+
+	// 级别
+	myTeam := obs.Seat % 2
+	setLevel(features[offset:], obs.Level)
+	offset += levelSlots
+	setLevel(features[offset:], obs.TeamLevels[myTeam])
+	offset += levelSlots
+	setLevel(features[offset:], obs.TeamLevels[1-myTeam])
+	offset += levelSlots
+
+	// 领先牌
+	if obs.IsLeader {
+		features[offset] = 1
+	}
+	offset++
+	encodeIDs(features[offset:offset+cardKinds], obs.Lead)
+	offset += cardKinds
+	if len(obs.Lead) > 0 && int(obs.LeadType) < compTypeSlots {
+		features[offset+int(obs.LeadType)] = 1
+	}
+	offset += compTypeSlots
+	if len(obs.Lead) > 0 && obs.LeadSeat >= 0 {
+		features[offset+relativeSeat(obs.Seat, obs.LeadSeat)] = 1
+	}
+	offset += 4
+
+	// 剩余手牌数
+	for seat := 0; seat < 4; seat++ {
+		features[offset+relativeSeat(obs.Seat, seat)] = float64(obs.HandSizes[seat]) / initialHandLen
+	}
+	offset += 4
+
+	// 已出的牌
+	for seat := 0; seat < 4; seat++ {
+		base := offset + relativeSeat(obs.Seat, seat)*cardKinds
+		for _, id := range obs.Played[seat] {
+			if k := kindIndexFromID(id); k >= 0 {
+				features[base+k] += 0.5
+				seen[k]++
+			}
+		}
+	}
+	offset += 4 * cardKinds
+
+	// 未见的牌
+	for k := 0; k < cardKinds; k++ {
+		if unseen := deckCopies - seen[k]; unseen > 0 {
+			features[offset+k] = float64(unseen) / deckCopies
+		}
+	}
+	offset += cardKinds
+
+	// 最近的公开动作，从新到旧
+	for i := 0; i < HistoryWindow && i < len(obs.History); i++ {
+		entry := obs.History[len(obs.History)-1-i]
+		base := offset + i*historyEntrySize
+		if entry.Seat >= 0 && entry.Seat < 4 {
+			features[base+relativeSeat(obs.Seat, entry.Seat)] = 1
+		}
+		if len(entry.Cards) == 0 {
+			features[base+4] = 1
+		}
+		encodeIDs(features[base+5:base+historyEntrySize], entry.Cards)
+	}
+
+	return features
+}
+
+// EncodeMove 把一次出牌编码为定长向量，cards 为空表示过牌
+func EncodeMove(cards []*sdk.Card) []float64 {
+	features := make([]float64, MoveFeatureSize)
+	if len(cards) == 0 {
+		features[cardKinds+compTypeSlots] = 1
+		return features
+	}
+	for _, card := range cards {
+		if k := cardKindIndex(card); k >= 0 {
+			features[k] += 0.5
+		}
+	}
+	if comp := sdk.FromCardList(cards, nil); comp != nil && int(comp.GetType()) < compTypeSlots {
+		features[cardKinds+int(comp.GetType())] = 1
+	}
+	features[cardKinds+compTypeSlots+1] = float64(len(cards)) / initialHandLen
+	return features
+}
+
+// relativeSeat 返回 seat 相对于 mySeat 的座位（0自己，1下家，2对家，3上家）
+func relativeSeat(mySeat, seat int) int {
+	return ((seat-mySeat)%4 + 4) % 4
+}
+
+func setLevel(features []float64, level int) {
+	if level >= 2 && level <= 14 {
+		features[level-2] = 1
+	}
+}
+
+func encodeIDs(features []float64, ids []string) {
+	for _, id := range ids {
+		if k := kindIndexFromID(id); k >= 0 {
+			features[k] += 0.5
+		}
+	}
+}
+
+// kindIndexFromID 把 "Color_Number" 格式的牌ID映射到牌面索引，无效ID返回-1
+func kindIndexFromID(id string) int {
+	sep := strings.LastIndexByte(id, '_')
+	if sep < 0 {
+		return -1
+	}
+	number, err := strconv.Atoi(id[sep+1:])
+	if err != nil {
+		return -1
+	}
+	return kindIndex(number, id[:sep])
+}
@@ -0,0 +1,140 @@
+package ai
+
+import (
+	"testing"
+
+	"guandan-world/sdk"
+)
+
+func TestFeatureSizes(t *testing.T) {
+	// 布局发布后不能改变，修改时必须递增 FeatureVersion
+	if ObservationFeatureSize != 910 || MoveFeatureSize != 68 || HandFeatureSize != 54 {
+		t.Fatalf("feature layout changed: obs=%d move=%d hand=%d",
+			ObservationFeatureSize, MoveFeatureSize, HandFeatureSize)
+	}
+}
+
+func TestEncodeHandFeatures(t *testing.T) {
+	hand := []*sdk.Card{
+		createCard(5, "Heart"),
+		createCard(5, "Heart"),
+		createCard(16, "Joker"),
+	}
+	features := EncodeHandFeatures(hand)
+	if features[kindIndex(5, "Heart")] != 1 || features[53] != 0.5 {
+		t.Errorf("unexpected hand encoding")
+	}
+	if sum(features) != 1.5 {
+		t.Errorf("expected total 1.5, got %v", sum(features))
+	}
+}
+
+func TestEncodeObservation(t *testing.T) {
+	obs := &Observation{
+		Seat:       1,
+		Level:      5,
+		TeamLevels: [2]int{3, 5},
+		Hand:       []string{"Spade_3", "Heart_14"},
+		Lead:       []string{"Club_9"},
+		LeadType:   sdk.TypeSingle,
+		LeadSeat:   0,
+		HandSizes:  [4]int{10, 2, 27, 27},
+		Played:     [4][]string{{"Club_9", "Diamond_4"}, nil, nil, nil},
+		History:    []HistoryEntry{{Seat: 0, Cards: []string{"Club_9"}}},
+	}
+	features := EncodeObservation(obs)
+	if len(features) != ObservationFeatureSize {
+		t.Fatalf("unexpected size %d", len(features))
+	}
+
+	if features[kindIndex(3, "Spade")] != 0.5 {
+		t.Error("own hand not encoded")
+	}
+	if features[54+5-2] != 1 || features[67+5-2] != 1 || features[80+3-2] != 1 {
+		t.Error("levels should be encoded relative to own team")
+	}
+	if features[148+int(sdk.TypeSingle)] != 1 {
+		t.Error("lead type not encoded")
+	}
+	if features[160+3] != 1 {
+		t.Error("lead seat 0 is the previous player of seat 1")
+	}
+	if features[164] != 2.0/27 || features[167] != 10.0/27 {
+		t.Error("hand sizes should be ordered by relative seat")
+	}
+	if features[168+3*54+kindIndex(9, "Club")] != 0.5 {
+		t.Error("played cards should be stored under the relative seat")
+	}
+	if features[384+kindIndex(9, "Club")] != 0.5 || features[384+kindIndex(3, "Spade")] != 0.5 || features[384+kindIndex(7, "Heart")] != 1 {
+		t.Error("unseen counts should exclude own and played cards")
+	}
+	if features[438+3] != 1 || features[438+4] != 0 || features[438+5+kindIndex(9, "Club")] != 0.5 {
+		t.Error("most recent history entry not encoded")
+	}
+}
+
+func TestEncodeMove(t *testing.T) {
+	pass := EncodeMove(nil)
+	if pass[66] != 1 || sum(pass) != 1 {
+		t.Error("pass should only set the pass flag")
+	}
+
+	pair := EncodeMove([]*sdk.Card{createCard(8, "Heart"), createCard(8, "Spade")})
+	if pair[54+int(sdk.TypePair)] != 1 || pair[67] != 2.0/27 || pair[66] != 0 {
+		t.Error("unexpected pair encoding")
+	}
+}
+
+func TestObservationTracker(t *testing.T) {
+	tracker := NewObservationTracker()
+	tracker.OnGameEvent(&sdk.GameEvent{Type: sdk.EventDealStarted, Data: map[string]interface{}{
+		"deal_level": 4, "team0_level": 4, "team1_level": 2,
+	}})
+	played := []*sdk.Card{levelCard(9, "Club", 4), levelCard(9, "Heart", 4)}
+	tracker.OnGameEvent(&sdk.GameEvent{Type: sdk.EventPlayerPlayed, Data: map[string]interface{}{
+		"player_seat": 2, "cards": played,
+	}})
+	tracker.OnGameEvent(&sdk.GameEvent{Type: sdk.EventPlayerPassed, Data: map[string]interface{}{
+		"player_seat": 3,
+	}})
+
+	hand := []*sdk.Card{levelCard(10, "Spade", 4), levelCard(10, "Club", 4)}
+	lead := sdk.FromCardList(played, nil)
+	obs := tracker.Observe(0, hand, &sdk.TrickInfo{LeadComp: lead})
+
+	if obs.Level != 4 || obs.TeamLevels != [2]int{4, 2} {
+		t.Errorf("unexpected levels: %d %v", obs.Level, obs.TeamLevels)
+	}
+	if obs.HandSizes != [4]int{2, 27, 25, 27} {
+		t.Errorf("unexpected hand sizes: %v", obs.HandSizes)
+	}
+	if obs.LeadSeat != 2 || obs.LeadType != sdk.TypePair || len(obs.Lead) != 2 {
+		t.Errorf("unexpected lead: seat %d type %v", obs.LeadSeat, obs.LeadType)
+	}
+	if len(obs.History) != 2 || obs.History[1].Seat != 3 || len(obs.History[1].Cards) != 0 {
+		t.Errorf("unexpected history: %+v", obs.History)
+	}
+	if len(obs.Played[2]) != 2 {
+		t.Errorf("unexpected played cards: %v", obs.Played)
+	}
+
+	for i := 0; i < HistoryWindow+3; i++ {
+		tracker.OnGameEvent(&sdk.GameEvent{Type: sdk.EventPlayerPassed, Data: map[string]interface{}{"player_seat": 1}})
+	}
+	if obs := tracker.Observe(0, hand, nil); len(obs.History) != HistoryWindow {
+		t.Errorf("history should be capped at %d, got %d", HistoryWindow, len(obs.History))
+	}
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func levelCard(number int, color string, level int) *sdk.Card {
+	card, _ := sdk.NewCard(number, color, level)
+	return card
+}
@@ -2,6 +2,9 @@ module guandan-world/ai
 
 go 1.22.1
 
-require guandan-world/sdk v0.0.0
+require (
+	github.com/gorilla/websocket v1.5.3
+	guandan-world/sdk v0.0.0
+)
 
 replace guandan-world/sdk => ../sdk
@@ -0,0 +1,669 @@
+package ai
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"guandan-world/sdk"
+)
+
+// 对手手牌推断
+//
+// HandInference 基于公开信息（自己的手牌、已出的牌、过牌、进贡/还贡）维护其余三个座位
+// 未知手牌的概率分布。模型假设每一张未见的牌独立地分配给某个座位，分配概率正比于
+// 该座位剩余的未知手牌数量乘以该座位对该牌的权重：
+//   - 过牌是软证据：对手在能压过时也可能选择不压，因此只按比例降低权重
+//   - 进贡是硬证据：进贡规则自动选出除红桃主牌外最大的牌，所以进贡者不可能持有更大的牌
+//
+// HandInference 同时实现 sdk.EventObserver，可以直接挂到 GameDriver 上通过事件自动更新。
+
+const (
+	deckCopies     = 2  // 掼蛋使用两副牌
+	cardKinds      = 54 // 每副牌的牌面种类数（52张普通牌 + 2张王）
+	initialHandLen = 27 // 每人初始手牌数
+)
+
+// 过牌证据对高于领出牌的牌面的权重折减系数（越小表示证据越强）
+var passEvidenceFactors = map[sdk.CompType]float64{
+	sdk.TypeSingle:    0.35,
+	sdk.TypePair:      0.6,
+	sdk.TypeTriple:    0.8,
+	sdk.TypeFullHouse: 0.8,
+}
+
+// HandInference 对手手牌推断器
+type HandInference struct {
+	mutex sync.RWMutex
+
+	mySeat int
+	level  int
+
+	myHand    [cardKinds]int    // 自己手中每种牌面的张数
+	played    [cardKinds]int    // 已经打出的每种牌面的张数
+	known     [4][cardKinds]int // 已知由某座位持有的牌（如收到的贡牌）
+	handSizes [4]int            // 每个座位当前的手牌数
+	weights   [4][cardKinds]float64
+
+	// 当前轮次的领先牌及其出牌者，用于解释事件流中的过牌
+	leadComp sdk.CardComp
+	leadSeat int
+
+	// 模拟记牌能力有限：以 forgetRate 概率忘记他人打出的牌
+	forgetRate float64
+	forgetRng  *rand.Rand
+}
+
+// NewHandInference 创建手牌推断器
+// 参数:
+//
+//	mySeat: 自己的座位号(0-3)
+//	level: 当前牌局级别
+//	myHand: 自己的手牌
+//
+// 返回值:
+//
+//	*HandInference: 推断器实例，所有未见的牌在其余座位之间均匀分布
+func NewHandInference(mySeat, level int, myHand []*sdk.Card) *HandInference {
+	hi := &HandInference{}
+	hi.Reset(mySeat, level, myHand)
+	return hi
+}
+
+// Reset 在新牌局开始时重置推断状态
+func (hi *HandInference) Reset(mySeat, level int, myHand []*sdk.Card) {
+	hi.mutex.Lock()
+	defer hi.mutex.Unlock()
+
+	hi.mySeat = mySeat
+	hi.level = level
+	hi.myHand = [cardKinds]int{}
+	hi.played = [cardKinds]int{}
+	hi.known = [4][cardKinds]int{}
+	hi.leadComp = nil
+	hi.leadSeat = -1
+	for seat := 0; seat < 4; seat++ {
+		hi.handSizes[seat] = initialHandLen
+		for k := 0; k < cardKinds; k++ {
+			hi.weights[seat][k] = 1.0
+		}
+	}
+	for _, card := range myHand {
+		if k := cardKindIndex(card); k >= 0 {
+			hi.myHand[k]++
+		}
+	}
+	// myHand 为 nil 表示手牌尚未知晓，稍后通过 SetHand 同步
+	if myHand != nil {
+		hi.handSizes[mySeat] = len(myHand)
+	}
+}
+
+// SetHand 同步自己的手牌（例如进贡/还贡之后）
+func (hi *HandInference) SetHand(myHand []*sdk.Card) {
+	hi.mutex.Lock()
+	defer hi.mutex.Unlock()
+
+	hi.myHand = [cardKinds]int{}
+	for _, card := range myHand {
+		if k := cardKindIndex(card); k >= 0 {
+			hi.myHand[k]++
+		}
+	}
+	hi.handSizes[hi.mySeat] = len(myHand)
+}
+
+// SetForgetting 设置遗忘率，用于降低弱机器人的记牌精度
+// 被遗忘的牌仍被视为未见的牌，rate 为0时关闭遗忘
+func (hi *HandInference) SetForgetting(rate float64, rng *rand.Rand) {
+	hi.mutex.Lock()
+	defer hi.mutex.Unlock()
+	hi.forgetRate = rate
+	hi.forgetRng = rng
+}
+
+// ObservePlay 记录某座位打出的牌
+func (hi *HandInference) ObservePlay(seat int, cards []*sdk.Card) {
+	hi.mutex.Lock()
+	defer hi.mutex.Unlock()
+	hi.observePlayLocked(seat, cards)
+}
+
+func (hi *HandInference) observePlayLocked(seat int, cards []*sdk.Card) {
+	if seat < 0 || seat > 3 {
+		return
+	}
+	for _, card := range cards {
+		k := cardKindIndex(card)
+		if k < 0 {
+			continue
+		}
+		if seat != hi.mySeat && hi.forgetRate > 0 && hi.forgetRng != nil && hi.forgetRng.Float64() < hi.forgetRate {
+			continue
+		}
+		hi.played[k]++
+		switch {
+		case seat == hi.mySeat:
+			if hi.myHand[k] > 0 {
+				hi.myHand[k]--
+			}
+		case hi.known[seat][k] > 0:
+			hi.known[seat][k]--
+		}
+	}
+	hi.handSizes[seat] -= len(cards)
+	if hi.handSizes[seat] < 0 {
+		hi.handSizes[seat] = 0
+	}
+}
+
+// ObservePass 记录某座位在 leadSeat 领先 lead 牌组时选择了过牌
+// 对队友领先时的过牌不作为证据（通常是让牌而非管不上）
+func (hi *HandInference) ObservePass(seat int, lead sdk.CardComp, leadSeat int) {
+	hi.mutex.Lock()
+	defer hi.mutex.Unlock()
+	hi.observePassLocked(seat, lead, leadSeat)
+}
+
+func (hi *HandInference) observePassLocked(seat int, lead sdk.CardComp, leadSeat int) {
+	if seat < 0 || seat > 3 || seat == hi.mySeat || lead == nil {
+		return
+	}
+	if leadSeat >= 0 && leadSeat%2 == seat%2 {
+		return
+	}
+	factor, ok := passEvidenceFactors[lead.GetType()]
+	if !ok {
+		return
+	}
+	leadCards := lead.GetCards()
+	if len(leadCards) == 0 {
+		return
+	}
+	// 葫芦以三张部分的点数比较，其余牌型所有牌点数相同（变化牌除外）
+	threshold := representativeCard(leadCards)
+	if threshold == nil {
+		return
+	}
+	for k := 0; k < cardKinds; k++ {
+		card := cardFromKindIndex(k, hi.level)
+		if card.GreaterThan(threshold) {
+			hi.weights[seat][k] *= factor
+		}
+	}
+}
+
+// ObserveTribute 记录进贡：giver 将 card 进贡给 receiver
+// 进贡牌是 giver 除红桃主牌外最大的牌，因此 giver 不持有比它更大的其他牌
+func (hi *HandInference) ObserveTribute(giver, receiver int, card *sdk.Card) {
+	hi.mutex.Lock()
+	defer hi.mutex.Unlock()
+
+	k := cardKindIndex(card)
+	if k < 0 {
+		return
+	}
+	if giver >= 0 && giver <= 3 && giver != hi.mySeat {
+		for other := 0; other < cardKinds; other++ {
+			candidate := cardFromKindIndex(other, hi.level)
+			if candidate.IsWildcard() {
+				continue
+			}
+			if candidate.GreaterThan(card) {
+				hi.weights[giver][other] = 0
+			}
+		}
+	}
+	hi.transferLocked(giver, receiver, k)
+}
+
+// ObserveReturnTribute 记录还贡：giver 将 card 还给 receiver
+func (hi *HandInference) ObserveReturnTribute(giver, receiver int, card *sdk.Card) {
+	hi.mutex.Lock()
+	defer hi.mutex.Unlock()
+
+	if k := cardKindIndex(card); k >= 0 {
+		hi.transferLocked(giver, receiver, k)
+	}
+}
+
+// transferLocked 处理一张公开的牌在两个座位之间的转移
+func (hi *HandInference) transferLocked(giver, receiver, k int) {
+	if giver >= 0 && giver <= 3 {
+		if giver != hi.mySeat && hi.known[giver][k] > 0 {
+			hi.known[giver][k]--
+		}
+		hi.handSizes[giver]--
+	}
+	if receiver >= 0 && receiver <= 3 {
+		// 自己的手牌通过 SetHand 同步，这里只记录他人持有的已知牌
+		if receiver != hi.mySeat {
+			hi.known[receiver][k]++
+		}
+		hi.handSizes[receiver]++
+	}
+}
+
+// Seat 返回推断器所属的座位号
+func (hi *HandInference) Seat() int {
+	hi.mutex.RLock()
+	defer hi.mutex.RUnlock()
+	return hi.mySeat
+}
+
+// HandSize 返回某座位当前的手牌数
+func (hi *HandInference) HandSize(seat int) int {
+	hi.mutex.RLock()
+	defer hi.mutex.RUnlock()
+	if seat < 0 || seat > 3 {
+		return 0
+	}
+	return hi.handSizes[seat]
+}
+
+// LeadSeat 返回当前轮次领先牌的出牌座位（通过事件观察得到，未知时为-1）
+func (hi *HandInference) LeadSeat() int {
+	hi.mutex.RLock()
+	defer hi.mutex.RUnlock()
+	return hi.leadSeat
+}
+
+// CardProbability 返回 seat 至少持有一张与 card 相同牌面（点数+花色）的概率
+func (hi *HandInference) CardProbability(seat int, card *sdk.Card) float64 {
+	hi.mutex.RLock()
+	defer hi.mutex.RUnlock()
+
+	k := cardKindIndex(card)
+	if k < 0 || seat < 0 || seat > 3 {
+		return 0
+	}
+	if seat == hi.mySeat {
+		if hi.myHand[k] > 0 {
+			return 1
+		}
+		return 0
+	}
+	if hi.known[seat][k] > 0 {
+		return 1
+	}
+	p := hi.assignProbabilityLocked(seat, k)
+	return 1 - math.Pow(1-p, float64(hi.unseenLocked(k)))
+}
+
+// ExpectedRankCount 返回 seat 持有点数为 number 的牌的期望张数
+func (hi *HandInference) ExpectedRankCount(seat, number int) float64 {
+	hi.mutex.RLock()
+	defer hi.mutex.RUnlock()
+
+	if seat < 0 || seat > 3 {
+		return 0
+	}
+	total := 0.0
+	for _, k := range kindsOfNumber(number) {
+		if seat == hi.mySeat {
+			total += float64(hi.myHand[k])
+			continue
+		}
+		total += float64(hi.known[seat][k])
+		total += float64(hi.unseenLocked(k)) * hi.assignProbabilityLocked(seat, k)
+	}
+	return total
+}
+
+// BombProbability 返回 seat 持有不小于 minNumber 的炸弹的概率
+// 参数:
+//
+//	seat: 座位号(0-3)
+//	minNumber: 炸弹的最小点数（按当前级别的大小顺序比较，级别牌大于A）
+//
+// 返回值:
+//
+//	float64: 概率值(0-1)
+//
+// 功能说明:
+//   - 统计四张及以上的同点数炸弹（红桃主牌可以作为变化牌补足张数）和四王炸
+//   - 不同点数之间按独立近似合并，同花顺不计入
+func (hi *HandInference) BombProbability(seat, minNumber int) float64 {
+	hi.mutex.RLock()
+	defer hi.mutex.RUnlock()
+
+	if seat < 0 || seat > 3 {
+		return 0
+	}
+
+	wildcardKind := -1
+	if hi.level >= 2 && hi.level <= 14 {
+		wildcardKind = kindIndex(hi.level, "Heart")
+	}
+	wildDist := hi.countDistributionLocked(seat, []int{wildcardKind})
+
+	noBomb := 1.0
+	for number := 2; number <= 14; number++ {
+		if rankOrder(number, hi.level) < rankOrder(minNumber, hi.level) {
+			continue
+		}
+		kinds := kindsOfNumber(number)
+		if number == hi.level {
+			// 红桃主牌本身就是这个点数，不能重复计算
+			noBomb *= 1 - tailProbability(hi.countDistributionLocked(seat, kinds), 4)
+			continue
+		}
+		rankDist := hi.countDistributionLocked(seat, kinds)
+		p := 0.0
+		for w, pw := range wildDist {
+			need := 4 - w
+			p += pw * tailProbability(rankDist, need)
+		}
+		noBomb *= 1 - p
+	}
+
+	jokerDist := hi.countDistributionLocked(seat, kindsOfNumber(15))
+	bigJokerDist := hi.countDistributionLocked(seat, kindsOfNumber(16))
+	jokerBomb := tailProbability(jokerDist, 2) * tailProbability(bigJokerDist, 2)
+
+	return 1 - noBomb*(1-jokerBomb)
+}
+
+// SampleHands 按当前分布采样一组与已知信息一致的完整手牌
+// 返回值:
+//
+//	[4][]*sdk.Card: 每个座位的手牌，自己的座位为自己的实际手牌
+//
+// 功能说明:
+//   - 每个座位采样得到的张数等于其当前手牌数
+//   - 可用于基于确定化（determinization）的搜索算法
+func (hi *HandInference) SampleHands(rng *rand.Rand) [4][]*sdk.Card {
+	hi.mutex.RLock()
+	defer hi.mutex.RUnlock()
+
+	var hands [4][]*sdk.Card
+	var slots [4]int
+	for seat := 0; seat < 4; seat++ {
+		if seat == hi.mySeat {
+			for k := 0; k < cardKinds; k++ {
+				for i := 0; i < hi.myHand[k]; i++ {
+					hands[seat] = append(hands[seat], cardFromKindIndex(k, hi.level))
+				}
+			}
+			continue
+		}
+		knownCount := 0
+		for k := 0; k < cardKinds; k++ {
+			for i := 0; i < hi.known[seat][k]; i++ {
+				hands[seat] = append(hands[seat], cardFromKindIndex(k, hi.level))
+			}
+			knownCount += hi.known[seat][k]
+		}
+		slots[seat] = hi.handSizes[seat] - knownCount
+		if slots[seat] < 0 {
+			slots[seat] = 0
+		}
+	}
+
+	pool := make([]int, 0, deckCopies*cardKinds)
+	for k := 0; k < cardKinds; k++ {
+		for i := 0; i < hi.unseenLocked(k); i++ {
+			pool = append(pool, k)
+		}
+	}
+	rng.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+
+	// 约束越强（可能持有的座位越少）的牌越先分配，避免后期只能违反约束
+	var eligible [cardKinds]int
+	for k := 0; k < cardKinds; k++ {
+		for seat := 0; seat < 4; seat++ {
+			if seat != hi.mySeat && slots[seat] > 0 && hi.weights[seat][k] > 0 {
+				eligible[k]++
+			}
+		}
+	}
+	sort.SliceStable(pool, func(i, j int) bool { return eligible[pool[i]] < eligible[pool[j]] })
+
+	for _, k := range pool {
+		var scores [4]float64
+		total := 0.0
+		for seat := 0; seat < 4; seat++ {
+			if seat == hi.mySeat || slots[seat] == 0 {
+				continue
+			}
+			scores[seat] = hi.weights[seat][k] * float64(slots[seat])
+			total += scores[seat]
+		}
+		if total == 0 {
+			// 约束冲突时退化为只按剩余张数分配，保证张数正确
+			for seat := 0; seat < 4; seat++ {
+				if seat != hi.mySeat {
+					scores[seat] = float64(slots[seat])
+					total += scores[seat]
+				}
+			}
+			if total == 0 {
+				break
+			}
+		}
+		r := rng.Float64() * total
+		chosen := -1
+		for seat := 0; seat < 4; seat++ {
+			if scores[seat] == 0 {
+				continue
+			}
+			chosen = seat
+			if r < scores[seat] {
+				break
+			}
+			r -= scores[seat]
+		}
+		hands[chosen] = append(hands[chosen], cardFromKindIndex(k, hi.level))
+		slots[chosen]--
+	}
+
+	return hands
+}
+
+// OnGameEvent 实现 sdk.EventObserver，根据引擎事件自动更新推断
+func (hi *HandInference) OnGameEvent(event *sdk.GameEvent) {
+	data, ok := event.Data.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	switch event.Type {
+	case sdk.EventTrickStarted:
+		hi.mutex.Lock()
+		hi.leadComp = nil
+		hi.leadSeat = -1
+		hi.mutex.Unlock()
+
+	case sdk.EventPlayerPlayed:
+		seat, _ := data["player_seat"].(int)
+		cards, _ := data["cards"].([]*sdk.Card)
+		hi.mutex.Lock()
+		hi.observePlayLocked(seat, cards)
+		hi.leadSeat = seat
+		hi.leadComp = nil
+		if deal, ok := data["deal_state"].(*sdk.Deal); ok && deal != nil && deal.CurrentTrick != nil {
+			hi.leadComp = deal.CurrentTrick.LeadComp
+		}
+		if hi.leadComp == nil {
+			hi.leadComp = sdk.FromCardList(cards, nil)
+		}
+		hi.mutex.Unlock()
+
+	case sdk.EventPlayerPassed:
+		seat, _ := data["player_seat"].(int)
+		hi.mutex.Lock()
+		hi.observePassLocked(seat, hi.leadComp, hi.leadSeat)
+		hi.mutex.Unlock()
+
+	case sdk.EventTributeGiven:
+		giver, _ := data["giver"].(int)
+		receiver, _ := data["receiver"].(int)
+		if card, ok := data["card"].(*sdk.Card); ok && card != nil {
+			hi.ObserveTribute(giver, receiver, card)
+		}
+
+	case sdk.EventReturnTribute:
+		giver, _ := data["player"].(int)
+		receiver, _ := data["target_player"].(int)
+		if card, ok := data["return_card"].(*sdk.Card); ok && card != nil {
+			hi.ObserveReturnTribute(giver, receiver, card)
+		}
+	}
+}
+
+// unseenLocked 返回某牌面尚未出现在自己手中、出牌记录或已知持有记录中的张数
+func (hi *HandInference) unseenLocked(k int) int {
+	n := deckCopies - hi.myHand[k] - hi.played[k]
+	for seat := 0; seat < 4; seat++ {
+		if seat != hi.mySeat {
+			n -= hi.known[seat][k]
+		}
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// assignProbabilityLocked 返回一张未见的 k 牌属于 seat 的概率
+func (hi *HandInference) assignProbabilityLocked(seat, k int) float64 {
+	var scores [4]float64
+	total := 0.0
+	for s := 0; s < 4; s++ {
+		if s == hi.mySeat {
+			continue
+		}
+		slots := hi.handSizes[s]
+		for kk := 0; kk < cardKinds; kk++ {
+			slots -= hi.known[s][kk]
+		}
+		if slots <= 0 {
+			continue
+		}
+		scores[s] = hi.weights[s][k] * float64(slots)
+		total += scores[s]
+	}
+	if total == 0 {
+		return 0
+	}
+	return scores[seat] / total
+}
+
+// countDistributionLocked 返回 seat 持有给定牌面集合的张数分布
+func (hi *HandInference) countDistributionLocked(seat int, kinds []int) []float64 {
+	dist := []float64{1}
+	base := 0
+	for _, k := range kinds {
+		if k < 0 {
+			continue
+		}
+		if seat == hi.mySeat {
+			base += hi.myHand[k]
+			continue
+		}
+		base += hi.known[seat][k]
+		p := hi.assignProbabilityLocked(seat, k)
+		for i := 0; i < hi.unseenLocked(k); i++ {
+			next := make([]float64, len(dist)+1)
+			for c, pc := range dist {
+				next[c] += pc * (1 - p)
+				next[c+1] += pc * p
+			}
+			dist = next
+		}
+	}
+	if base == 0 {
+		return dist
+	}
+	shifted := make([]float64, len(dist)+base)
+	copy(shifted[base:], dist)
+	return shifted
+}
+
+// tailProbability 返回分布中张数不小于 need 的概率
+func tailProbability(dist []float64, need int) float64 {
+	if need <= 0 {
+		return 1
+	}
+	p := 0.0
+	for c := need; c < len(dist); c++ {
+		p += dist[c]
+	}
+	return p
+}
+
+// representativeCard 返回用于比较大小的代表牌（忽略变化牌，葫芦取三张部分）
+func representativeCard(cards []*sdk.Card) *sdk.Card {
+	counts := make(map[int]int)
+	var best *sdk.Card
+	for _, card := range cards {
+		if card.IsWildcard() {
+			continue
+		}
+		counts[card.Number]++
+		if best == nil || counts[card.Number] > counts[best.Number] {
+			best = card
+		}
+	}
+	if best == nil && len(cards) > 0 {
+		best = cards[0]
+	}
+	return best
+}
+
+// rankOrder 返回考虑级别后的点数大小顺序（级别牌介于A与王之间）
+func rankOrder(number, level int) float64 {
+	if number == level && number <= 14 {
+		return 14.5
+	}
+	return float64(number)
+}
+
+// cardKindIndex 将牌映射到0-53的牌面索引，无效牌返回-1
+func cardKindIndex(card *sdk.Card) int {
+	if card == nil {
+		return -1
+	}
+	return kindIndex(card.Number, card.Color)
+}
+
+func kindIndex(number int, color string) int {
+	if number == 15 || number == 16 {
+		return 52 + number - 15
+	}
+	if number < 2 || number > 14 {
+		return -1
+	}
+	for i, c := range sdk.Colors {
+		if c == color {
+			return (number-2)*4 + i
+		}
+	}
+	return -1
+}
+
+// cardFromKindIndex 根据牌面索引创建牌
+func cardFromKindIndex(k, level int) *sdk.Card {
+	var card *sdk.Card
+	if k >= 52 {
+		card, _ = sdk.NewCard(15+k-52, "Joker", level)
+	} else {
+		card, _ = sdk.NewCard(k/4+2, sdk.Colors[k%4], level)
+	}
+	return card
+}
+
+// kindsOfNumber 返回某点数对应的所有牌面索引
+func kindsOfNumber(number int) []int {
+	if number == 15 || number == 16 {
+		return []int{52 + number - 15}
+	}
+	kinds := make([]int, 0, 4)
+	for i := range sdk.Colors {
+		if k := kindIndex(number, sdk.Colors[i]); k >= 0 {
+			kinds = append(kinds, k)
+		}
+	}
+	return kinds
+}
@@ -0,0 +1,208 @@
+package ai
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"guandan-world/sdk"
+)
+
+// 生成一手27张的测试手牌：2-10 每个点数各一张黑桃、梅花、方块
+func inferenceTestHand() []*sdk.Card {
+	hand := make([]*sdk.Card, 0, 27)
+	for number := 2; number <= 10; number++ {
+		for _, color := range []string{"Spade", "Club", "Diamond"} {
+			hand = append(hand, createCard(number, color))
+		}
+	}
+	return hand
+}
+
+func TestHandInference_UniformPrior(t *testing.T) {
+	hi := NewHandInference(0, 2, inferenceTestHand())
+
+	// 其余三个座位的先验应该对称
+	bigJoker := createCard(16, "Joker")
+	p1 := hi.CardProbability(1, bigJoker)
+	for seat := 2; seat <= 3; seat++ {
+		if p := hi.CardProbability(seat, bigJoker); math.Abs(p-p1) > 1e-9 {
+			t.Errorf("seat %d probability %v differs from seat 1 %v", seat, p, p1)
+		}
+	}
+
+	// 期望张数之和等于未见张数
+	total := 0.0
+	for seat := 1; seat <= 3; seat++ {
+		total += hi.ExpectedRankCount(seat, 14)
+	}
+	if math.Abs(total-8) > 1e-9 {
+		t.Errorf("expected 8 unseen aces across opponents, got %v", total)
+	}
+	total = 0.0
+	for seat := 1; seat <= 3; seat++ {
+		total += hi.ExpectedRankCount(seat, 5)
+	}
+	if math.Abs(total-5) > 1e-9 {
+		t.Errorf("expected 5 unseen fives across opponents, got %v", total)
+	}
+
+	if got := hi.ExpectedRankCount(0, 5); got != 3 {
+		t.Errorf("own seat should report exact count 3, got %v", got)
+	}
+}
+
+func TestHandInference_PassLowersHigherCards(t *testing.T) {
+	hi := NewHandInference(0, 2, inferenceTestHand())
+	before := hi.ExpectedRankCount(1, 14)
+
+	lead := sdk.FromCardList([]*sdk.Card{createCard(13, "Heart")}, nil)
+	hi.ObservePass(1, lead, 0)
+
+	after := hi.ExpectedRankCount(1, 14)
+	if after >= before {
+		t.Errorf("pass over K should lower expected aces for seat 1: before %v after %v", before, after)
+	}
+	if hi.ExpectedRankCount(3, 14) <= before {
+		t.Error("probability mass should move to the other seats")
+	}
+
+	// 对队友领先时的过牌不作为证据
+	partner := NewHandInference(0, 2, inferenceTestHand())
+	partner.ObservePass(3, lead, 1)
+	if got := partner.ExpectedRankCount(3, 14); math.Abs(got-before) > 1e-9 {
+		t.Errorf("pass on partner lead should not change inference, got %v want %v", got, before)
+	}
+}
+
+func TestHandInference_TributeRevealsHighestCard(t *testing.T) {
+	hi := NewHandInference(0, 2, inferenceTestHand())
+
+	// 座位1向座位2进贡一张K，说明座位1没有A、级别牌（非红桃）和王
+	hi.ObserveTribute(1, 2, createCard(13, "Spade"))
+
+	if p := hi.CardProbability(1, createCard(16, "Joker")); p != 0 {
+		t.Errorf("giver cannot hold a big joker, got %v", p)
+	}
+	if p := hi.CardProbability(1, createCard(14, "Club")); p != 0 {
+		t.Errorf("giver cannot hold an ace, got %v", p)
+	}
+	if p := hi.CardProbability(2, createCard(13, "Spade")); p != 1 {
+		t.Errorf("receiver must hold the tribute card, got %v", p)
+	}
+	if p := hi.BombProbability(1, 14); p != 0 {
+		t.Errorf("giver cannot hold a bomb of aces or jokers, got %v", p)
+	}
+	if hi.HandSize(1) != 26 || hi.HandSize(2) != 28 {
+		t.Errorf("unexpected hand sizes after tribute: %d %d", hi.HandSize(1), hi.HandSize(2))
+	}
+
+	hi.ObserveReturnTribute(2, 1, createCard(3, "Heart"))
+	if hi.HandSize(1) != 27 || hi.HandSize(2) != 27 {
+		t.Errorf("unexpected hand sizes after return tribute: %d %d", hi.HandSize(1), hi.HandSize(2))
+	}
+	if p := hi.CardProbability(1, createCard(3, "Heart")); p != 1 {
+		t.Errorf("return tribute card should be known, got %v", p)
+	}
+}
+
+func TestHandInference_BombProbability(t *testing.T) {
+	hi := NewHandInference(0, 2, inferenceTestHand())
+
+	p := hi.BombProbability(1, 11)
+	if p <= 0 || p >= 1 {
+		t.Errorf("bomb probability should be strictly between 0 and 1, got %v", p)
+	}
+	if hi.BombProbability(1, 14) > p {
+		t.Error("a higher minimum should not increase bomb probability")
+	}
+
+	// 出现过的牌不能再组成炸弹
+	for _, color := range []string{"Spade", "Heart", "Club", "Diamond"} {
+		hi.ObservePlay(2, []*sdk.Card{createCard(14, color)})
+	}
+	if hi.ExpectedRankCount(1, 14) > 4 {
+		t.Error("expected aces should drop after plays")
+	}
+}
+
+func TestHandInference_SampleHands(t *testing.T) {
+	hand := inferenceTestHand()
+	hi := NewHandInference(0, 2, hand)
+	hi.ObserveTribute(1, 2, createCard(13, "Spade"))
+	hi.ObservePlay(3, []*sdk.Card{createCard(11, "Heart")})
+
+	rng := rand.New(rand.NewSource(42))
+	for i := 0; i < 20; i++ {
+		hands := hi.SampleHands(rng)
+		if len(hands[0]) != len(hand) {
+			t.Fatalf("own hand should be preserved, got %d cards", len(hands[0]))
+		}
+		for seat := 1; seat <= 3; seat++ {
+			if len(hands[seat]) != hi.HandSize(seat) {
+				t.Fatalf("seat %d sampled %d cards, want %d", seat, len(hands[seat]), hi.HandSize(seat))
+			}
+		}
+		for _, card := range hands[1] {
+			if !card.IsWildcard() && card.GreaterThan(createCard(13, "Spade")) {
+				t.Fatalf("tribute giver sampled card %s above its tribute", card)
+			}
+		}
+	}
+}
+
+func TestHandInference_OnGameEvent(t *testing.T) {
+	hi := NewHandInference(0, 2, inferenceTestHand())
+	before := hi.ExpectedRankCount(2, 14)
+
+	lead := []*sdk.Card{createCard(13, "Heart")}
+	hi.OnGameEvent(&sdk.GameEvent{Type: sdk.EventTrickStarted, Data: map[string]interface{}{}})
+	hi.OnGameEvent(&sdk.GameEvent{
+		Type: sdk.EventPlayerPlayed,
+		Data: map[string]interface{}{"player_seat": 1, "cards": lead},
+	})
+	hi.OnGameEvent(&sdk.GameEvent{
+		Type: sdk.EventPlayerPassed,
+		Data: map[string]interface{}{"player_seat": 2},
+	})
+
+	if hi.LeadSeat() != 1 {
+		t.Errorf("lead seat should be 1, got %d", hi.LeadSeat())
+	}
+	if hi.HandSize(1) != 26 {
+		t.Errorf("seat 1 should have 26 cards, got %d", hi.HandSize(1))
+	}
+	if hi.ExpectedRankCount(2, 14) >= before {
+		t.Error("pass event should lower seat 2 expected aces")
+	}
+}
+
+func TestSmartAutoPlayAlgorithm_InterceptWithBomb(t *testing.T) {
+	hand := []*sdk.Card{
+		createCard(3, "Spade"),
+		createCard(9, "Spade"),
+		createCard(9, "Heart"),
+		createCard(9, "Club"),
+		createCard(9, "Diamond"),
+	}
+	lead := []*sdk.Card{createCard(14, "Spade")}
+	trickInfo := &sdk.TrickInfo{IsLeader: false, LeadComp: sdk.FromCardList(lead, nil)}
+
+	algo := NewSmartAutoPlayAlgorithm(2)
+	if cards := algo.SelectCardsToPlay(hand, trickInfo); cards != nil {
+		t.Fatalf("without inference the algorithm should pass, got %v", cards)
+	}
+
+	hi := NewHandInference(0, 2, hand)
+	algo.(InferenceAwareAlgorithm).SetHandInference(hi)
+	hi.ObservePlay(1, make([]*sdk.Card, 22))
+	hi.OnGameEvent(&sdk.GameEvent{
+		Type: sdk.EventPlayerPlayed,
+		Data: map[string]interface{}{"player_seat": 1, "cards": lead},
+	})
+
+	cards := algo.SelectCardsToPlay(hand, trickInfo)
+	if len(cards) != 4 {
+		t.Fatalf("expected a bomb to intercept an opponent close to going out, got %v", cards)
+	}
+}
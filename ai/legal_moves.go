@@ -0,0 +1,78 @@
+package ai
+
+import (
+	"sort"
+
+	"guandan-world/sdk"
+)
+
+// EnumerateLegalPlays 列出手牌中当前可以打出的牌组
+// 参数:
+//
+//	hand: 手牌
+//	trickInfo: 当前轮次信息，首出时任何有效牌组都合法，跟牌时必须能压过领先牌组
+//
+// 返回值:
+//
+//	[][]*sdk.Card: 合法的出牌列表（不包含过牌），按张数从少到多排列
+//
+// 功能说明:
+//   - 候选牌组来自单张和智能算法识别的牌组，并不保证穷举所有组合
+//   - 返回的每个牌组都经过 sdk.HandMask 校验，结果与 sdk.FromCardList 一致
+func EnumerateLegalPlays(hand []*sdk.Card, trickInfo *sdk.TrickInfo) [][]*sdk.Card {
+	if len(hand) == 0 {
+		return nil
+	}
+
+	level := hand[0].Level
+	finder := &SmartAutoPlayAlgorithm{level: level}
+
+	candidates := make([][]*sdk.Card, 0, len(hand))
+	for _, card := range hand {
+		candidates = append(candidates, []*sdk.Card{card})
+	}
+	for _, group := range finder.identifyAllPossibleGroups(hand) {
+		candidates = append(candidates, group.Cards)
+	}
+
+	var lead sdk.CardComp
+	if trickInfo != nil && !trickInfo.IsLeader {
+		lead = trickInfo.LeadComp
+	}
+	leadMask := sdk.MaskCompOf(lead)
+
+	seen := make(map[string]bool)
+	legal := make([][]*sdk.Card, 0, len(candidates))
+	for _, cards := range candidates {
+		comp := sdk.NewHandMask(cards).Classify(sdk.TypeFold)
+		if !comp.IsValid() {
+			continue
+		}
+		if lead != nil && !comp.GreaterThan(leadMask) {
+			continue
+		}
+		key := playKey(cards)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		legal = append(legal, cards)
+	}
+
+	sort.SliceStable(legal, func(i, j int) bool { return len(legal[i]) < len(legal[j]) })
+	return legal
+}
+
+// playKey 生成与牌的顺序无关的牌组标识，用于去重
+func playKey(cards []*sdk.Card) string {
+	ids := make([]string, len(cards))
+	for i, card := range cards {
+		ids[i] = card.GetID()
+	}
+	sort.Strings(ids)
+	key := ""
+	for _, id := range ids {
+		key += id + ","
+	}
+	return key
+}
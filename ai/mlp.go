@@ -0,0 +1,259 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+)
+
+// NeuralModelFormat 权重文件格式标识
+const NeuralModelFormat = "guandan-mlp/1"
+
+// 激活函数
+const (
+	ActivationLinear = "linear"
+	ActivationReLU   = "relu"
+	ActivationTanh   = "tanh"
+)
+
+// MLPLayer 全连接层，Weights 按行存储（Out 行 × In 列）
+type MLPLayer struct {
+	In         int       `json:"in"`
+	Out        int       `json:"out"`
+	Activation string    `json:"activation"`
+	Weights    []float64 `json:"weights"`
+	Bias       []float64 `json:"bias"`
+}
+
+// MLP 多层感知机，纯Go实现的CPU推理
+type MLP struct {
+	Layers []*MLPLayer `json:"layers"`
+}
+
+// NeuralModel 权重文件的内容
+// 策略网络输入为观察向量与出牌向量的拼接，输出该出牌的得分；
+// 价值网络（可选）输入为观察向量，输出当前局面对己方的评估
+type NeuralModel struct {
+	Format         string `json:"format"`
+	FeatureVersion int    `json:"feature_version"`
+	Policy         *MLP   `json:"policy"`
+	Value          *MLP   `json:"value,omitempty"`
+}
+
+// PolicyInputSize 策略网络的输入维度
+const PolicyInputSize = ObservationFeatureSize + MoveFeatureSize
+
+// NewMLP 创建随机初始化的MLP（He初始化），隐藏层使用ReLU，输出层为线性
+// 参数:
+//
+//	sizes: 各层维度，包括输入和输出，例如 [978, 128, 1]
+//	rng: 随机数生成器
+func NewMLP(sizes []int, rng *rand.Rand) *MLP {
+	mlp := &MLP{}
+	for i := 0; i+1 < len(sizes); i++ {
+		layer := &MLPLayer{
+			In:         sizes[i],
+			Out:        sizes[i+1],
+			Activation: ActivationReLU,
+			Weights:    make([]float64, sizes[i]*sizes[i+1]),
+			Bias:       make([]float64, sizes[i+1]),
+		}
+		if i+2 == len(sizes) {
+			layer.Activation = ActivationLinear
+		}
+		scale := math.Sqrt(2.0 / float64(sizes[i]))
+		for j := range layer.Weights {
+			layer.Weights[j] = rng.NormFloat64() * scale
+		}
+		mlp.Layers = append(mlp.Layers, layer)
+	}
+	return mlp
+}
+
+// InputSize 返回输入维度
+func (m *MLP) InputSize() int {
+	if len(m.Layers) == 0 {
+		return 0
+	}
+	return m.Layers[0].In
+}
+
+// OutputSize 返回输出维度
+func (m *MLP) OutputSize() int {
+	if len(m.Layers) == 0 {
+		return 0
+	}
+	return m.Layers[len(m.Layers)-1].Out
+}
+
+// Validate 检查各层维度是否一致
+func (m *MLP) Validate() error {
+	if len(m.Layers) == 0 {
+		return errors.New("mlp has no layers")
+	}
+	for i, layer := range m.Layers {
+		if layer.In <= 0 || layer.Out <= 0 {
+			return fmt.Errorf("layer %d has invalid size %dx%d", i, layer.Out, layer.In)
+		}
+		if len(layer.Weights) != layer.In*layer.Out || len(layer.Bias) != layer.Out {
+			return fmt.Errorf("layer %d: expected %d weights and %d biases, got %d and %d",
+				i, layer.In*layer.Out, layer.Out, len(layer.Weights), len(layer.Bias))
+		}
+		switch layer.Activation {
+		case ActivationLinear, ActivationReLU, ActivationTanh, "":
+		default:
+			return fmt.Errorf("layer %d: unknown activation %q", i, layer.Activation)
+		}
+		if i > 0 && m.Layers[i-1].Out != layer.In {
+			return fmt.Errorf("layer %d input %d does not match previous output %d", i, layer.In, m.Layers[i-1].Out)
+		}
+	}
+	return nil
+}
+
+// Forward 前向计算
+func (m *MLP) Forward(input []float64) []float64 {
+	x := input
+	for _, layer := range m.Layers {
+		x = layer.forward(x)
+	}
+	return x
+}
+
+// forward 计算一层的输出
+func (l *MLPLayer) forward(input []float64) []float64 {
+	out := make([]float64, l.Out)
+	for o := 0; o < l.Out; o++ {
+		row := l.Weights[o*l.In : (o+1)*l.In]
+		sum := l.Bias[o]
+		for i, v := range input {
+			if v != 0 {
+				sum += row[i] * v
+			}
+		}
+		out[o] = sum
+	}
+	l.activate(out)
+	return out
+}
+
+// partial 计算第一层对输入中 [from, from+len(input)) 区间的线性贡献（不含偏置和激活），
+// 用于把与出牌无关的观察部分只计算一次
+func (l *MLPLayer) partial(input []float64, from int) []float64 {
+	out := make([]float64, l.Out)
+	for o := 0; o < l.Out; o++ {
+		row := l.Weights[o*l.In+from : o*l.In+from+len(input)]
+		sum := 0.0
+		for i, v := range input {
+			if v != 0 {
+				sum += row[i] * v
+			}
+		}
+		out[o] = sum
+	}
+	return out
+}
+
+func (l *MLPLayer) activate(values []float64) {
+	switch l.Activation {
+	case ActivationReLU:
+		for i, v := range values {
+			if v < 0 {
+				values[i] = 0
+			}
+		}
+	case ActivationTanh:
+		for i, v := range values {
+			values[i] = math.Tanh(v)
+		}
+	}
+}
+
+// NewRandomNeuralModel 创建随机初始化的模型，用作训练起点或测试
+// 参数:
+//
+//	hidden: 策略网络和价值网络的隐藏层维度
+//	seed: 随机种子
+func NewRandomNeuralModel(hidden []int, seed int64) *NeuralModel {
+	rng := rand.New(rand.NewSource(seed))
+	policySizes := append(append([]int{PolicyInputSize}, hidden...), 1)
+	valueSizes := append(append([]int{ObservationFeatureSize}, hidden...), 1)
+	return &NeuralModel{
+		Format:         NeuralModelFormat,
+		FeatureVersion: FeatureVersion,
+		Policy:         NewMLP(policySizes, rng),
+		Value:          NewMLP(valueSizes, rng),
+	}
+}
+
+// Validate 检查模型与当前特征编码是否兼容
+func (m *NeuralModel) Validate() error {
+	if m.Format != NeuralModelFormat {
+		return fmt.Errorf("unsupported model format %q", m.Format)
+	}
+	if m.FeatureVersion != FeatureVersion {
+		return fmt.Errorf("model uses feature version %d, expected %d", m.FeatureVersion, FeatureVersion)
+	}
+	if m.Policy == nil {
+		return errors.New("model has no policy network")
+	}
+	if err := m.Policy.Validate(); err != nil {
+		return fmt.Errorf("policy: %w", err)
+	}
+	if m.Policy.InputSize() != PolicyInputSize || m.Policy.OutputSize() != 1 {
+		return fmt.Errorf("policy network must map %d inputs to 1 output", PolicyInputSize)
+	}
+	if m.Value != nil {
+		if err := m.Value.Validate(); err != nil {
+			return fmt.Errorf("value: %w", err)
+		}
+		if m.Value.InputSize() != ObservationFeatureSize || m.Value.OutputSize() != 1 {
+			return fmt.Errorf("value network must map %d inputs to 1 output", ObservationFeatureSize)
+		}
+	}
+	return nil
+}
+
+// ReadNeuralModel 从 JSON 读取并校验模型
+func ReadNeuralModel(r io.Reader) (*NeuralModel, error) {
+	var model NeuralModel
+	if err := json.NewDecoder(r).Decode(&model); err != nil {
+		return nil, fmt.Errorf("failed to decode model: %w", err)
+	}
+	if err := model.Validate(); err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+// LoadNeuralModel 从权重文件加载模型
+func LoadNeuralModel(path string) (*NeuralModel, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadNeuralModel(file)
+}
+
+// Write 把模型以 JSON 写出
+func (m *NeuralModel) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(m)
+}
+
+// Save 把模型保存到权重文件
+func (m *NeuralModel) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := m.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,145 @@
+package ai
+
+import (
+	"math"
+	"math/rand"
+
+	"guandan-world/sdk"
+)
+
+// NeuralConfig 神经网络机器人配置
+type NeuralConfig struct {
+	Temperature float64 // 采样温度，0 表示总是选择得分最高的出牌
+	Seed        int64   // 随机种子，相同的种子和输入产生相同的选择
+}
+
+// NeuralAutoPlayAlgorithm 使用策略网络对合法出牌打分的自动出牌算法
+// 纯Go实现，不依赖cgo或GPU。贡牌和还贡仍使用规则算法
+type NeuralAutoPlayAlgorithm struct {
+	model       *NeuralModel
+	temperature float64
+	rng         *rand.Rand
+	fallback    *SmartAutoPlayAlgorithm
+
+	seat    int
+	tracker *ObservationTracker
+}
+
+// NewNeuralAutoPlayAlgorithm 创建神经网络机器人
+// 参数:
+//
+//	model: 已校验的模型（见 LoadNeuralModel）
+//	level: 当前级别
+//	config: 采样配置
+func NewNeuralAutoPlayAlgorithm(model *NeuralModel, level int, config NeuralConfig) *NeuralAutoPlayAlgorithm {
+	return &NeuralAutoPlayAlgorithm{
+		model:       model,
+		temperature: config.Temperature,
+		rng:         rand.New(rand.NewSource(config.Seed)),
+		fallback:    &SmartAutoPlayAlgorithm{level: level},
+	}
+}
+
+// SetObservationSource 实现 ObservationAwareAlgorithm
+func (algo *NeuralAutoPlayAlgorithm) SetObservationSource(seat int, tracker *ObservationTracker) {
+	algo.seat = seat
+	algo.tracker = tracker
+}
+
+// SelectCardsToPlay 实现 AutoPlayAlgorithm
+func (algo *NeuralAutoPlayAlgorithm) SelectCardsToPlay(hand []*sdk.Card, trickInfo *sdk.TrickInfo) []*sdk.Card {
+	if len(hand) == 0 {
+		return nil
+	}
+	if trickInfo == nil {
+		trickInfo = &sdk.TrickInfo{IsLeader: true}
+	}
+
+	moves := EnumerateLegalPlays(hand, trickInfo)
+	if !trickInfo.IsLeader {
+		moves = append(moves, nil)
+	}
+	if len(moves) == 0 {
+		return algo.fallback.SelectCardsToPlay(hand, trickInfo)
+	}
+
+	scores := algo.ScoreMoves(algo.observe(hand, trickInfo), moves)
+	return moves[algo.choose(scores)]
+}
+
+// SelectTributeCard 实现 AutoPlayAlgorithm
+func (algo *NeuralAutoPlayAlgorithm) SelectTributeCard(hand []*sdk.Card, excludeHeartTrump bool) *sdk.Card {
+	return algo.fallback.SelectTributeCard(hand, excludeHeartTrump)
+}
+
+// SelectReturnTributeCard 实现 AutoPlayAlgorithm
+func (algo *NeuralAutoPlayAlgorithm) SelectReturnTributeCard(hand []*sdk.Card, receivedCard *sdk.Card) *sdk.Card {
+	return algo.fallback.SelectReturnTributeCard(hand, receivedCard)
+}
+
+// ScoreMoves 用策略网络为每个候选出牌打分，nil 表示过牌
+// 第一层中观察部分的贡献对所有候选出牌相同，只计算一次
+func (algo *NeuralAutoPlayAlgorithm) ScoreMoves(obs *Observation, moves [][]*sdk.Card) []float64 {
+	policy := algo.model.Policy
+	first := policy.Layers[0]
+	shared := first.partial(EncodeObservation(obs), 0)
+
+	scores := make([]float64, len(moves))
+	for i, move := range moves {
+		hidden := first.partial(EncodeMove(move), ObservationFeatureSize)
+		for j := range hidden {
+			hidden[j] += shared[j] + first.Bias[j]
+		}
+		first.activate(hidden)
+		for _, layer := range policy.Layers[1:] {
+			hidden = layer.forward(hidden)
+		}
+		scores[i] = hidden[0]
+	}
+	return scores
+}
+
+// Evaluate 用价值网络评估局面，模型没有价值网络时返回 false
+func (algo *NeuralAutoPlayAlgorithm) Evaluate(obs *Observation) (float64, bool) {
+	if algo.model.Value == nil {
+		return 0, false
+	}
+	return algo.model.Value.Forward(EncodeObservation(obs))[0], true
+}
+
+// observe 构造当前座位的观察，没有公开信息跟踪器时只包含手牌和领先牌
+func (algo *NeuralAutoPlayAlgorithm) observe(hand []*sdk.Card, trickInfo *sdk.TrickInfo) *Observation {
+	tracker := algo.tracker
+	if tracker == nil {
+		tracker = NewObservationTracker()
+	}
+	return tracker.Observe(algo.seat, hand, trickInfo)
+}
+
+// choose 按温度从得分中选择下标：温度为0时取最大值，否则按 softmax(score/T) 采样
+func (algo *NeuralAutoPlayAlgorithm) choose(scores []float64) int {
+	best := 0
+	for i, score := range scores {
+		if score > scores[best] {
+			best = i
+		}
+	}
+	if algo.temperature <= 0 {
+		return best
+	}
+
+	weights := make([]float64, len(scores))
+	total := 0.0
+	for i, score := range scores {
+		weights[i] = math.Exp((score - scores[best]) / algo.temperature)
+		total += weights[i]
+	}
+	r := algo.rng.Float64() * total
+	for i, w := range weights {
+		r -= w
+		if r < 0 {
+			return i
+		}
+	}
+	return best
+}
@@ -0,0 +1,134 @@
+package ai
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"testing"
+
+	"guandan-world/sdk"
+)
+
+func neuralTestHand() []*sdk.Card {
+	return []*sdk.Card{
+		createCard(3, "Spade"), createCard(3, "Heart"),
+		createCard(6, "Club"), createCard(7, "Diamond"),
+		createCard(8, "Heart"), createCard(9, "Spade"), createCard(10, "Club"),
+		createCard(13, "Heart"), createCard(13, "Spade"), createCard(13, "Club"),
+		createCard(15, "Joker"),
+	}
+}
+
+func TestNeuralModel_RoundTrip(t *testing.T) {
+	model := NewRandomNeuralModel([]int{8}, 1)
+	var buf bytes.Buffer
+	if err := model.Write(&buf); err != nil {
+		t.Fatalf("failed to write model: %v", err)
+	}
+	loaded, err := ReadNeuralModel(&buf)
+	if err != nil {
+		t.Fatalf("failed to read model: %v", err)
+	}
+
+	obs := NewObservationTracker().Observe(0, neuralTestHand(), &sdk.TrickInfo{IsLeader: true})
+	a, _ := NewNeuralAutoPlayAlgorithm(model, 2, NeuralConfig{}).Evaluate(obs)
+	b, _ := NewNeuralAutoPlayAlgorithm(loaded, 2, NeuralConfig{}).Evaluate(obs)
+	if a != b {
+		t.Errorf("loaded model should evaluate identically: %v vs %v", a, b)
+	}
+}
+
+func TestNeuralModel_Validate(t *testing.T) {
+	model := NewRandomNeuralModel([]int{8}, 1)
+	model.FeatureVersion = FeatureVersion + 1
+	if err := model.Validate(); err == nil {
+		t.Error("model with another feature version should be rejected")
+	}
+
+	model = NewRandomNeuralModel([]int{8}, 1)
+	model.Policy.Layers[1].Weights = model.Policy.Layers[1].Weights[1:]
+	if err := model.Validate(); err == nil {
+		t.Error("model with truncated weights should be rejected")
+	}
+
+	model = NewRandomNeuralModel([]int{8}, 1)
+	model.Policy = NewMLP([]int{ObservationFeatureSize, 8, 1}, rand.New(rand.NewSource(1)))
+	if err := model.Validate(); err == nil {
+		t.Error("policy network without move features should be rejected")
+	}
+}
+
+func TestNeuralAutoPlayAlgorithm_ScoreMovesMatchesForward(t *testing.T) {
+	model := NewRandomNeuralModel([]int{12, 6}, 3)
+	algo := NewNeuralAutoPlayAlgorithm(model, 2, NeuralConfig{})
+
+	hand := neuralTestHand()
+	obs := NewObservationTracker().Observe(0, hand, &sdk.TrickInfo{IsLeader: true})
+	moves := append(EnumerateLegalPlays(hand, &sdk.TrickInfo{IsLeader: true}), nil)
+	scores := algo.ScoreMoves(obs, moves)
+
+	obsFeatures := EncodeObservation(obs)
+	for i, move := range moves {
+		input := append(append([]float64{}, obsFeatures...), EncodeMove(move)...)
+		expected := model.Policy.Forward(input)[0]
+		if math.Abs(scores[i]-expected) > 1e-9 {
+			t.Fatalf("move %d: split scoring %v differs from full forward %v", i, scores[i], expected)
+		}
+	}
+}
+
+func TestNeuralAutoPlayAlgorithm_Deterministic(t *testing.T) {
+	model := NewRandomNeuralModel([]int{16}, 5)
+	hand := neuralTestHand()
+	trick := &sdk.TrickInfo{IsLeader: true}
+
+	run := func() []string {
+		algo := NewNeuralAutoPlayAlgorithm(model, 2, NeuralConfig{Temperature: 2, Seed: 42})
+		var keys []string
+		for i := 0; i < 20; i++ {
+			keys = append(keys, playKey(algo.SelectCardsToPlay(hand, trick)))
+		}
+		return keys
+	}
+
+	first, second := run(), run()
+	distinct := make(map[string]bool)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("selection %d differs with the same seed: %s vs %s", i, first[i], second[i])
+		}
+		distinct[first[i]] = true
+	}
+	if len(distinct) < 2 {
+		t.Error("high temperature sampling should produce different plays")
+	}
+}
+
+func TestNeuralAutoPlayAlgorithm_LegalFollow(t *testing.T) {
+	model := NewRandomNeuralModel([]int{16}, 9)
+	algo := NewNeuralAutoPlayAlgorithm(model, 2, NeuralConfig{Temperature: 1, Seed: 3})
+	lead := sdk.FromCardList([]*sdk.Card{createCard(12, "Diamond"), createCard(12, "Club")}, nil)
+	trick := &sdk.TrickInfo{IsLeader: false, LeadComp: lead}
+
+	for i := 0; i < 50; i++ {
+		cards := algo.SelectCardsToPlay(neuralTestHand(), trick)
+		if cards == nil {
+			continue
+		}
+		comp := sdk.FromCardList(cards, nil)
+		if comp == nil || !comp.IsValid() || !comp.GreaterThan(lead) {
+			t.Fatalf("neural bot chose an illegal follow: %s", playKey(cards))
+		}
+	}
+}
+
+func BenchmarkNeuralAutoPlayAlgorithm(b *testing.B) {
+	model := NewRandomNeuralModel([]int{128, 64}, 1)
+	algo := NewNeuralAutoPlayAlgorithm(model, 2, NeuralConfig{})
+	hand := neuralTestHand()
+	trick := &sdk.TrickInfo{IsLeader: true}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		algo.SelectCardsToPlay(hand, trick)
+	}
+}
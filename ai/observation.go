@@ -0,0 +1,176 @@
+package ai
+
+import (
+	"sync"
+
+	"guandan-world/sdk"
+)
+
+// HistoryWindow 观察中保留的最近公开动作数量
+const HistoryWindow = 8
+
+// HistoryEntry 一次公开动作（出牌或过牌）
+type HistoryEntry struct {
+	Seat  int      `json:"seat"`
+	Cards []string `json:"cards,omitempty"` // 为空表示过牌
+}
+
+// Observation 某个座位在决策时的脱敏观察：只包含自己的手牌和公开信息
+// 牌使用 sdk.Card.GetID() 的格式（"Color_Number"），便于序列化和离线处理
+type Observation struct {
+	Seat       int            `json:"seat"`
+	Level      int            `json:"level"`       // 当前牌局的级别
+	TeamLevels [2]int         `json:"team_levels"` // 两队当前级别
+	Hand       []string       `json:"hand"`        // 自己的手牌
+	IsLeader   bool           `json:"is_leader"`   // 是否为首出
+	Lead       []string       `json:"lead,omitempty"`
+	LeadType   sdk.CompType   `json:"lead_type"`
+	LeadSeat   int            `json:"lead_seat"`  // 领先牌的出牌座位（没有时为-1）
+	HandSizes  [4]int         `json:"hand_sizes"` // 各座位剩余手牌数
+	Played     [4][]string    `json:"played"`     // 本局各座位已打出的牌
+	History    []HistoryEntry `json:"history"`    // 本局最近的公开动作（最多 HistoryWindow 条，从旧到新）
+}
+
+// ObservationTracker 通过游戏事件跟踪一局中的公开信息，用于构造各座位的 Observation
+// 同一个跟踪器可以为所有座位构造观察
+type ObservationTracker struct {
+	mutex      sync.RWMutex
+	level      int
+	teamLevels [2]int
+	handSizes  [4]int
+	played     [4][]string
+	history    []HistoryEntry
+	leadSeat   int
+}
+
+// NewObservationTracker 创建公开信息跟踪器
+func NewObservationTracker() *ObservationTracker {
+	t := &ObservationTracker{}
+	t.reset(2, [2]int{2, 2})
+	return t
+}
+
+func (t *ObservationTracker) reset(level int, teamLevels [2]int) {
+	t.level = level
+	t.teamLevels = teamLevels
+	for i := 0; i < 4; i++ {
+		t.handSizes[i] = initialHandLen
+		t.played[i] = nil
+	}
+	t.history = nil
+	t.leadSeat = -1
+}
+
+// OnGameEvent 实现 sdk.EventObserver
+func (t *ObservationTracker) OnGameEvent(event *sdk.GameEvent) {
+	data, ok := event.Data.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	switch event.Type {
+	case sdk.EventDealStarted:
+		level, _ := data["deal_level"].(int)
+		team0, _ := data["team0_level"].(int)
+		team1, _ := data["team1_level"].(int)
+		t.reset(level, [2]int{team0, team1})
+	case sdk.EventTrickStarted:
+		t.leadSeat = -1
+	case sdk.EventPlayerPlayed:
+		seat, ok := data["player_seat"].(int)
+		cards, _ := data["cards"].([]*sdk.Card)
+		if !ok || seat < 0 || seat >= 4 {
+			return
+		}
+		ids := cardIDs(cards)
+		t.handSizes[seat] -= len(cards)
+		t.played[seat] = append(t.played[seat], ids...)
+		t.leadSeat = seat
+		t.appendHistory(HistoryEntry{Seat: seat, Cards: ids})
+	case sdk.EventPlayerPassed:
+		if seat, ok := data["player_seat"].(int); ok && seat >= 0 && seat < 4 {
+			t.appendHistory(HistoryEntry{Seat: seat})
+		}
+	case sdk.EventTributeGiven:
+		giver, _ := data["giver"].(int)
+		receiver, _ := data["receiver"].(int)
+		t.transfer(giver, receiver)
+	case sdk.EventReturnTribute:
+		giver, _ := data["player"].(int)
+		receiver, _ := data["target_player"].(int)
+		t.transfer(giver, receiver)
+	}
+}
+
+func (t *ObservationTracker) appendHistory(entry HistoryEntry) {
+	t.history = append(t.history, entry)
+	if len(t.history) > HistoryWindow {
+		t.history = t.history[len(t.history)-HistoryWindow:]
+	}
+}
+
+func (t *ObservationTracker) transfer(giver, receiver int) {
+	if giver >= 0 && giver < 4 && receiver >= 0 && receiver < 4 && giver != receiver {
+		t.handSizes[giver]--
+		t.handSizes[receiver]++
+	}
+}
+
+// Observe 构造指定座位的观察
+// 参数:
+//
+//	seat: 决策座位
+//	hand: 该座位当前手牌
+//	trickInfo: 当前轮次信息（贡牌、还贡决策时为nil）
+//
+// 返回值:
+//
+//	*Observation: 观察快照，不与跟踪器共享内存
+func (t *ObservationTracker) Observe(seat int, hand []*sdk.Card, trickInfo *sdk.TrickInfo) *Observation {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	obs := &Observation{
+		Seat:       seat,
+		Level:      t.level,
+		TeamLevels: t.teamLevels,
+		Hand:       cardIDs(hand),
+		LeadSeat:   -1,
+		HandSizes:  t.handSizes,
+		History:    make([]HistoryEntry, len(t.history)),
+	}
+	if len(hand) > 0 {
+		obs.Level = hand[0].Level
+	}
+	if seat >= 0 && seat < 4 {
+		obs.HandSizes[seat] = len(hand)
+	}
+	for i := 0; i < 4; i++ {
+		obs.Played[i] = append([]string{}, t.played[i]...)
+	}
+	copy(obs.History, t.history)
+
+	if trickInfo != nil {
+		obs.IsLeader = trickInfo.IsLeader
+		if !trickInfo.IsLeader && trickInfo.LeadComp != nil {
+			obs.Lead = cardIDs(trickInfo.LeadComp.GetCards())
+			obs.LeadType = trickInfo.LeadComp.GetType()
+			obs.LeadSeat = t.leadSeat
+		}
+	}
+	return obs
+}
+
+// cardIDs 把牌转换为ID列表
+func cardIDs(cards []*sdk.Card) []string {
+	ids := make([]string, 0, len(cards))
+	for _, card := range cards {
+		if card != nil {
+			ids = append(ids, card.GetID())
+		}
+	}
+	return ids
+}
@@ -0,0 +1,99 @@
+package remote
+
+import (
+	"sync"
+	"time"
+)
+
+// breakerState 熔断器状态
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // 正常放行
+	breakerOpen                         // 熔断中，直接拒绝
+	breakerHalfOpen                     // 冷却结束，放行一次试探请求
+)
+
+// CircuitBreaker 连续失败达到阈值后熔断一段时间，避免每个回合都等待一个已经不可用的机器人服务
+type CircuitBreaker struct {
+	mutex     sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	openCount int
+}
+
+// NewCircuitBreaker 创建熔断器
+// 参数:
+//
+//	threshold: 连续失败多少次后熔断
+//	cooldown: 熔断持续时间，结束后放行一次试探请求
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow 判断当前是否允许发出请求
+func (b *CircuitBreaker) Allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		// 试探请求尚未返回结果
+		return false
+	default:
+		return true
+	}
+}
+
+// Success 记录一次成功，熔断器恢复正常
+func (b *CircuitBreaker) Success() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.state = breakerClosed
+	b.failures = 0
+}
+
+// Failure 记录一次失败，达到阈值或试探失败时熔断
+func (b *CircuitBreaker) Failure() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+		b.openCount++
+	}
+}
+
+// IsOpen 返回熔断器是否处于熔断或试探状态
+func (b *CircuitBreaker) IsOpen() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.state != breakerClosed
+}
+
+// OpenCount 返回熔断次数
+func (b *CircuitBreaker) OpenCount() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.openCount
+}
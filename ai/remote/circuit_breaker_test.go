@@ -0,0 +1,47 @@
+package remote
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	breaker := NewCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	breaker.Failure()
+	if !breaker.Allow() || breaker.IsOpen() {
+		t.Fatal("breaker should stay closed below the threshold")
+	}
+	breaker.Success()
+	breaker.Failure()
+	if breaker.IsOpen() {
+		t.Fatal("success should reset the failure count")
+	}
+
+	breaker.Failure()
+	if !breaker.IsOpen() || breaker.Allow() {
+		t.Fatal("breaker should open at the threshold")
+	}
+
+	now = now.Add(time.Minute)
+	if !breaker.Allow() {
+		t.Fatal("breaker should allow a probe after the cooldown")
+	}
+	if breaker.Allow() {
+		t.Fatal("only one probe should be allowed while half-open")
+	}
+
+	breaker.Failure()
+	if breaker.Allow() || breaker.OpenCount() != 2 {
+		t.Fatalf("failed probe should reopen the breaker, opens=%d", breaker.OpenCount())
+	}
+
+	now = now.Add(time.Minute)
+	breaker.Allow()
+	breaker.Success()
+	if breaker.IsOpen() || !breaker.Allow() {
+		t.Fatal("successful probe should close the breaker")
+	}
+}
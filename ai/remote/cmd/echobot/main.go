@@ -0,0 +1,30 @@
+// echobot 是外部机器人协议的参考实现：总是打出第一个合法出牌
+//
+// 用法:
+//
+//	echobot [-name echo] [-delay 0s] [-crash-after 0] [-garbage] [-stall]
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"guandan-world/ai/remote"
+)
+
+func main() {
+	options := remote.EchoBotOptions{}
+	flag.StringVar(&options.Name, "name", "echo", "name reported in the handshake")
+	flag.DurationVar(&options.Delay, "delay", 0, "delay before each decision")
+	flag.IntVar(&options.CrashAfter, "crash-after", 0, "exit after this many decisions (0 = never)")
+	flag.BoolVar(&options.Garbage, "garbage", false, "reply with cards that are not in hand")
+	flag.BoolVar(&options.Stall, "stall", false, "stop reading input after the handshake")
+	flag.Parse()
+
+	// 标准输出只用于协议消息，日志写到标准错误
+	log.SetOutput(os.Stderr)
+	if err := remote.RunEchoBot(os.Stdin, os.Stdout, options); err != nil {
+		log.Fatalf("echobot: %v", err)
+	}
+}
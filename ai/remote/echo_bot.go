@@ -0,0 +1,135 @@
+package remote
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// EchoBotOptions 参考机器人的行为选项，主要用于测试超时和崩溃处理
+type EchoBotOptions struct {
+	Name       string        // 握手时报告的名称
+	Delay      time.Duration // 每次决策前的延迟
+	CrashAfter int           // 回复这么多次决策后退出（0表示不退出）
+	Garbage    bool          // 回复不存在的牌，用于测试非法回复的处理
+	Stall      bool          // 握手后不再读取输入，用于测试写入超时的处理
+}
+
+// RunEchoBot 运行参考机器人：总是选择第一个合法出牌（跟牌时没有可出的牌则过牌），
+// 贡牌和还贡选择第一张可选的牌
+// 参数:
+//
+//	in: 读取服务端消息
+//	out: 写出回复
+//	options: 行为选项
+//
+// 返回值:
+//
+//	error: 读写失败时返回错误，收到 quit、输入结束或达到 CrashAfter 时返回nil
+func RunEchoBot(in io.Reader, out io.Writer, options EchoBotOptions) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	encoder := json.NewEncoder(out)
+	decisions := 0
+
+	for scanner.Scan() {
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			continue
+		}
+
+		if req.Type == MsgQuit {
+			return nil
+		}
+		reply := EchoReply(&req, options)
+		if reply == nil {
+			// new_deal 和 event 消息只用于同步状态，不需要回复
+			continue
+		}
+
+		if req.Type != MsgHello {
+			if options.CrashAfter > 0 && decisions >= options.CrashAfter {
+				return nil
+			}
+			decisions++
+			if options.Delay > 0 {
+				time.Sleep(options.Delay)
+			}
+		}
+		if err := encoder.Encode(reply); err != nil {
+			return err
+		}
+		if options.Stall {
+			// 卡死但不退出，服务端的写入会在管道写满后阻塞
+			for {
+				time.Sleep(time.Hour)
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// EchoReply 参考机器人对单条消息的回复，不需要回复的消息返回nil
+func EchoReply(req *Request, options EchoBotOptions) *Reply {
+	switch req.Type {
+	case MsgHello:
+		name := options.Name
+		if name == "" {
+			name = "echo"
+		}
+		return &Reply{Type: MsgReady, Name: name}
+	case MsgPlay:
+		reply := &Reply{Type: MsgDecision, ID: req.ID, Action: "pass"}
+		if req.View != nil && len(req.View.LegalPlays) > 0 {
+			reply.Action = "play"
+			reply.Cards = req.View.LegalPlays[0]
+		}
+		if options.Garbage {
+			reply.Action = "play"
+			reply.Cards = []string{"Spade_99"}
+		}
+		return reply
+	case MsgTributeSelect, MsgReturnTribute:
+		reply := &Reply{Type: MsgCard, ID: req.ID}
+		if req.View != nil && len(req.View.Hand) > 0 {
+			reply.Card = req.View.Hand[0]
+		}
+		if options.Garbage {
+			reply.Card = "Spade_99"
+		}
+		return reply
+	}
+	return nil
+}
+
+// NewEchoBotHandler 返回以 HTTP 方式提供参考机器人的处理器
+// 每个 POST 请求体是一条 Request，响应体是对应的 Reply
+func NewEchoBotHandler(options EchoBotOptions) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if options.Delay > 0 {
+			select {
+			case <-time.After(options.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		reply := EchoReply(&req, options)
+		if reply == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(reply)
+	})
+}
@@ -0,0 +1,76 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DeadlineHeader 请求头中携带的决策截止时间（RFC3339Nano），机器人服务可据此安排搜索时间
+const DeadlineHeader = "X-Guandan-Deadline"
+
+// HTTPTransport 通过 HTTP POST 调用机器人服务：请求体是一条 Request，响应体是对应的 Reply
+// HTTP 是无状态的，公开事件不会被推送，玩家视角中已包含做决策所需的公开信息
+type HTTPTransport struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPTransport 创建 HTTP 传输层，client 为 nil 时使用 http.DefaultClient
+func NewHTTPTransport(url string, client *http.Client) *HTTPTransport {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPTransport{url: url, client: client}
+}
+
+// RoundTrip 实现 Transport
+func (t *HTTPTransport) RoundTrip(ctx context.Context, req *Request) (*Reply, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if deadline, ok := ctx.Deadline(); ok {
+		httpReq.Header.Set(DeadlineHeader, deadline.UTC().Format(time.RFC3339Nano))
+	}
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return nil, &RetryableError{Err: fmt.Errorf("bot service request failed: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		io.Copy(io.Discard, resp.Body)
+		return nil, &RetryableError{Err: fmt.Errorf("bot service returned %s", resp.Status)}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bot service returned %s", resp.Status)
+	}
+
+	var reply Reply
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return nil, fmt.Errorf("failed to decode bot reply: %w", err)
+	}
+	return &reply, nil
+}
+
+// Notify 实现 Transport，HTTP 传输不推送事件
+func (t *HTTPTransport) Notify(req *Request) error {
+	return nil
+}
+
+// Close 实现 Transport
+func (t *HTTPTransport) Close() error {
+	return nil
+}
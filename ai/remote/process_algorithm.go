@@ -0,0 +1,155 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"guandan-world/ai"
+	"guandan-world/sdk"
+)
+
+// 注册 "process:<命令>" 算法名称，引用本包后服务端和模拟器即可按名称使用外部机器人
+func init() {
+	ai.RegisterAlgorithm("process", NewProcessAlgorithmFactory)
+}
+
+// NewProcessAlgorithmFactory 解析 "process:<命令> [参数...]" 中的命令行并创建算法工厂
+// 每个算法实例在第一次使用时启动自己的机器人进程，比赛结束后结束进程
+func NewProcessAlgorithmFactory(commandLine string) (ai.AlgorithmFactory, error) {
+	fields := strings.Fields(commandLine)
+	if len(fields) == 0 {
+		return nil, errors.New("bot command is required")
+	}
+	if _, err := exec.LookPath(fields[0]); err != nil {
+		return nil, fmt.Errorf("bot command not found: %w", err)
+	}
+	return func(level int, seed int64) ai.AutoPlayAlgorithm {
+		return NewProcessAlgorithm(DefaultProcessConfig(fields[0], fields[1:]...), level)
+	}, nil
+}
+
+// ProcessAlgorithm 把外部进程机器人包装为 ai.AutoPlayAlgorithm，
+// 用于只接受算法的场合（服务端机器人座位、模拟器 -seats）
+// 它同时实现 sdk.EventObserver，输入提供者会把游戏事件转发给它
+type ProcessAlgorithm struct {
+	config   *ProcessConfig
+	fallback ai.AutoPlayAlgorithm
+
+	mutex    sync.Mutex
+	seat     int
+	provider *ProcessInputProvider
+	failed   bool // 进程启动失败，之后一直使用兜底算法
+}
+
+// NewProcessAlgorithm 创建外部进程机器人算法，进程在第一次使用时启动
+// 参数:
+//
+//	config: 进程配置，未设置兜底算法时使用智能算法
+//	level: 兜底算法的级别
+func NewProcessAlgorithm(config *ProcessConfig, level int) *ProcessAlgorithm {
+	if config.Fallback == nil {
+		config.Fallback = ai.NewSmartAutoPlayAlgorithm(level)
+	}
+	return &ProcessAlgorithm{config: config, fallback: config.Fallback}
+}
+
+// SetObservationSource 实现 ai.ObservationAwareAlgorithm，只用于得知所在座位
+func (a *ProcessAlgorithm) SetObservationSource(seat int, tracker *ai.ObservationTracker) {
+	a.mutex.Lock()
+	a.seat = seat
+	a.mutex.Unlock()
+}
+
+// OnGameEvent 实现 sdk.EventObserver，把公开事件转发给机器人，比赛结束时结束进程
+func (a *ProcessAlgorithm) OnGameEvent(event *sdk.GameEvent) {
+	provider, _ := a.start()
+	if provider == nil {
+		return
+	}
+	provider.OnGameEvent(event)
+	if event.Type == sdk.EventMatchEnded {
+		a.Close()
+	}
+}
+
+// SelectCardsToPlay 实现 ai.AutoPlayAlgorithm
+func (a *ProcessAlgorithm) SelectCardsToPlay(hand []*sdk.Card, trickInfo *sdk.TrickInfo) []*sdk.Card {
+	provider, seat := a.start()
+	if provider == nil {
+		return a.fallback.SelectCardsToPlay(hand, trickInfo)
+	}
+	decision, err := provider.RequestPlayDecision(context.Background(), seat, hand, trickInfo)
+	if err != nil || decision.Action != sdk.ActionPlay {
+		return nil
+	}
+	return decision.Cards
+}
+
+// SelectTributeCard 实现 ai.AutoPlayAlgorithm，从候选牌中选择
+func (a *ProcessAlgorithm) SelectTributeCard(hand []*sdk.Card, excludeHeartTrump bool) *sdk.Card {
+	provider, seat := a.start()
+	if provider == nil {
+		return a.fallback.SelectTributeCard(hand, excludeHeartTrump)
+	}
+	card, err := provider.RequestTributeSelection(context.Background(), seat, hand)
+	if err != nil {
+		return nil
+	}
+	return card
+}
+
+// SelectReturnTributeCard 实现 ai.AutoPlayAlgorithm
+func (a *ProcessAlgorithm) SelectReturnTributeCard(hand []*sdk.Card, receivedCard *sdk.Card) *sdk.Card {
+	provider, seat := a.start()
+	if provider == nil {
+		return a.fallback.SelectReturnTributeCard(hand, receivedCard)
+	}
+	card, err := provider.RequestReturnTribute(context.Background(), seat, hand)
+	if err != nil {
+		return nil
+	}
+	return card
+}
+
+// Stats 返回当前机器人进程的运行统计，进程未启动时为零值
+func (a *ProcessAlgorithm) Stats() ProviderStats {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.provider == nil {
+		return ProviderStats{}
+	}
+	return a.provider.Stats()
+}
+
+// Close 结束机器人进程，之后再使用时会启动新进程
+func (a *ProcessAlgorithm) Close() error {
+	a.mutex.Lock()
+	provider := a.provider
+	a.provider = nil
+	a.mutex.Unlock()
+
+	if provider == nil {
+		return nil
+	}
+	return provider.Close()
+}
+
+// start 返回机器人进程和所在座位，必要时启动进程；启动失败时返回nil
+func (a *ProcessAlgorithm) start() (*ProcessInputProvider, int) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.provider == nil && !a.failed {
+		provider, err := NewProcessInputProvider(a.config)
+		if err != nil {
+			a.failed = true
+			return nil, a.seat
+		}
+		a.provider = provider
+	}
+	return a.provider, a.seat
+}
@@ -0,0 +1,69 @@
+package remote
+
+import (
+	"os"
+	"testing"
+
+	"guandan-world/ai"
+	"guandan-world/sdk"
+)
+
+func TestProcessAlgorithm_ByName(t *testing.T) {
+	// 子进程继承环境变量，作为参考机器人运行
+	t.Setenv("GUANDAN_ECHO_BOT", "1")
+
+	algorithm, err := ai.NewAlgorithmByName("process:"+os.Args[0], 2)
+	if err != nil {
+		t.Fatalf("NewAlgorithmByName failed: %v", err)
+	}
+	bot, ok := algorithm.(*ProcessAlgorithm)
+	if !ok {
+		t.Fatalf("expected a process algorithm, got %T", algorithm)
+	}
+	defer bot.Close()
+	bot.SetObservationSource(1, nil)
+
+	hand := testHand()
+	cards := bot.SelectCardsToPlay(hand, &sdk.TrickInfo{IsLeader: true})
+	if len(cards) == 0 || cards[0] != hand[0] {
+		t.Fatalf("echo bot should lead with the first legal play, got %v", cards)
+	}
+	if card := bot.SelectReturnTributeCard(hand, nil); card != hand[0] {
+		t.Errorf("expected first card as return tribute, got %v", card)
+	}
+	if stats := bot.Stats(); stats.Decisions != 2 {
+		t.Errorf("expected two decisions from the bot, got %+v", stats)
+	}
+
+	// 比赛结束后进程退出，再次使用时重新启动
+	bot.OnGameEvent(&sdk.GameEvent{Type: sdk.EventMatchEnded})
+	if stats := bot.Stats(); stats.Decisions != 0 {
+		t.Errorf("the bot process should be closed after the match, got %+v", stats)
+	}
+	if cards := bot.SelectCardsToPlay(hand, &sdk.TrickInfo{IsLeader: true}); len(cards) == 0 {
+		t.Error("a new bot process should be started for the next match")
+	}
+}
+
+func TestProcessAlgorithm_InvalidCommand(t *testing.T) {
+	if _, err := ai.NewAlgorithmFactory("process:"); err == nil {
+		t.Error("expected error for a missing command")
+	}
+	if _, err := ai.NewAlgorithmFactory("process:/nonexistent/bot"); err == nil {
+		t.Error("expected error for a command that does not exist")
+	}
+}
+
+func TestProcessAlgorithm_StartFailureFallsBack(t *testing.T) {
+	// 进程无法完成握手时使用兜底算法
+	bot := NewProcessAlgorithm(DefaultProcessConfig("/nonexistent/bot"), 2)
+	defer bot.Close()
+
+	hand := testHand()
+	if cards := bot.SelectCardsToPlay(hand, &sdk.TrickInfo{IsLeader: true}); len(cards) == 0 {
+		t.Error("the fallback algorithm should lead")
+	}
+	if card := bot.SelectReturnTributeCard(hand, nil); card == nil {
+		t.Error("the fallback algorithm should return a tribute")
+	}
+}
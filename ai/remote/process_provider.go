@@ -0,0 +1,413 @@
+package remote
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"guandan-world/ai"
+	"guandan-world/sdk"
+)
+
+// ErrBotUnavailable 机器人进程已退出或协议出错，已切换到兜底算法
+var ErrBotUnavailable = errors.New("bot process unavailable")
+
+// ProcessConfig 外部进程机器人配置
+type ProcessConfig struct {
+	Command         string               // 可执行文件路径
+	Args            []string             // 命令行参数
+	Dir             string               // 工作目录
+	Env             []string             // 额外的环境变量（追加到当前进程环境）
+	StartupTimeout  time.Duration        // 握手超时
+	DecisionTimeout time.Duration        // 单次决策的时间预算上限（ctx 的截止时间更早时以 ctx 为准）
+	Stderr          io.Writer            // 机器人标准错误输出的去向，nil 表示丢弃
+	Fallback        ai.AutoPlayAlgorithm // 超时、崩溃或非法回复时使用的兜底算法
+}
+
+// DefaultProcessConfig 返回默认配置
+func DefaultProcessConfig(command string, args ...string) *ProcessConfig {
+	return &ProcessConfig{
+		Command:         command,
+		Args:            args,
+		StartupTimeout:  5 * time.Second,
+		DecisionTimeout: 10 * time.Second,
+	}
+}
+
+// ProviderStats 外部机器人运行统计
+type ProviderStats struct {
+	Decisions      int `json:"decisions"`       // 机器人成功给出的决策数
+	Timeouts       int `json:"timeouts"`        // 超时次数
+	InvalidReplies int `json:"invalid_replies"` // 非法回复次数
+	Fallbacks      int `json:"fallbacks"`       // 使用兜底算法的次数
+	Crashes        int `json:"crashes"`         // 进程崩溃次数
+	Retries        int `json:"retries"`         // 远程调用的重试次数
+	ShortCircuits  int `json:"short_circuits"`  // 熔断期间直接使用兜底算法的次数
+	CircuitOpens   int `json:"circuit_opens"`   // 熔断次数
+}
+
+// ProcessInputProvider 通过外部进程做决策的 PlayerInputProvider
+// 同一个进程可以为多个座位服务，请求中带有座位号
+type ProcessInputProvider struct {
+	config *ProcessConfig
+	name   string
+
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	replies chan *Reply
+	exited  chan struct{}
+	exitErr error
+
+	requestMutex sync.Mutex // 同一时间只有一个未完成的请求
+	writeMutex   sync.Mutex
+	nextID       uint64
+
+	statsMutex sync.Mutex
+	stats      ProviderStats
+	dead       bool
+
+	tracker *ViewTracker
+}
+
+// NewProcessInputProvider 启动外部机器人进程并完成握手
+// 参数:
+//
+//	config: 进程配置
+//
+// 返回值:
+//
+//	*ProcessInputProvider: 已就绪的输入提供者
+//	error: 进程启动失败或握手超时
+func NewProcessInputProvider(config *ProcessConfig) (*ProcessInputProvider, error) {
+	if config == nil || config.Command == "" {
+		return nil, errors.New("bot command is required")
+	}
+	if config.StartupTimeout <= 0 {
+		config.StartupTimeout = 5 * time.Second
+	}
+	if config.DecisionTimeout <= 0 {
+		config.DecisionTimeout = 10 * time.Second
+	}
+	if config.Fallback == nil {
+		config.Fallback = ai.NewSmartAutoPlayAlgorithm(2)
+	}
+
+	cmd := exec.Command(config.Command, config.Args...)
+	cmd.Dir = config.Dir
+	if len(config.Env) > 0 {
+		cmd.Env = append(cmd.Environ(), config.Env...)
+	}
+	cmd.Stderr = config.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bot stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bot stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start bot process: %w", err)
+	}
+
+	p := &ProcessInputProvider{
+		config:  config,
+		cmd:     cmd,
+		stdin:   stdin,
+		replies: make(chan *Reply, 16),
+		exited:  make(chan struct{}),
+		tracker: NewViewTracker(),
+	}
+	go p.readLoop(stdout)
+
+	if err := p.handshake(); err != nil {
+		p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// handshake 发送 hello 并等待 ready
+func (p *ProcessInputProvider) handshake() error {
+	hello := &Request{Type: MsgHello, Protocol: ProtocolVersion}
+	if err := p.send(context.Background(), hello, p.config.StartupTimeout); err != nil {
+		return fmt.Errorf("failed to send hello: %w", err)
+	}
+
+	timer := time.NewTimer(p.config.StartupTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case reply := <-p.replies:
+			if reply.Type == MsgReady {
+				p.name = reply.Name
+				return nil
+			}
+		case <-p.exited:
+			return fmt.Errorf("bot exited during handshake: %v", p.exitErr)
+		case <-timer.C:
+			return errors.New("bot handshake timed out")
+		}
+	}
+}
+
+// readLoop 逐行读取机器人输出，进程退出后关闭 exited
+func (p *ProcessInputProvider) readLoop(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var reply Reply
+		if err := json.Unmarshal(line, &reply); err != nil {
+			p.recordInvalid()
+			continue
+		}
+		select {
+		case p.replies <- &reply:
+		default:
+			// 没有等待中的请求却不断输出，丢弃多余的回复
+			p.recordInvalid()
+		}
+	}
+	p.exitErr = p.cmd.Wait()
+	if p.exitErr == nil {
+		p.exitErr = scanner.Err()
+	}
+	close(p.exited)
+}
+
+// Name 返回机器人在握手时报告的名称
+func (p *ProcessInputProvider) Name() string {
+	return p.name
+}
+
+// Stats 返回运行统计
+func (p *ProcessInputProvider) Stats() ProviderStats {
+	p.statsMutex.Lock()
+	defer p.statsMutex.Unlock()
+	return p.stats
+}
+
+// Alive 返回机器人进程是否仍可用
+func (p *ProcessInputProvider) Alive() bool {
+	p.statsMutex.Lock()
+	defer p.statsMutex.Unlock()
+	return !p.dead
+}
+
+// Close 通知机器人退出并回收进程
+func (p *ProcessInputProvider) Close() error {
+	if p.Alive() {
+		_ = p.send(context.Background(), &Request{Type: MsgQuit}, time.Second)
+	}
+	_ = p.stdin.Close()
+
+	select {
+	case <-p.exited:
+	case <-time.After(time.Second):
+		_ = p.cmd.Process.Kill()
+		<-p.exited
+	}
+	p.markDead(false)
+	return nil
+}
+
+// OnGameEvent 实现 sdk.EventObserver，把公开事件转发给机器人
+func (p *ProcessInputProvider) OnGameEvent(event *sdk.GameEvent) {
+	p.tracker.OnGameEvent(event)
+	if !p.Alive() {
+		return
+	}
+	if req := publicEventRequest(event); req != nil {
+		if err := p.send(context.Background(), req, p.config.DecisionTimeout); err != nil {
+			p.markDead(true)
+		}
+	}
+}
+
+// RequestPlayDecision 实现PlayerInputProvider接口
+func (p *ProcessInputProvider) RequestPlayDecision(ctx context.Context, playerSeat int, hand []*sdk.Card, trickInfo *sdk.TrickInfo) (*sdk.PlayDecision, error) {
+	view := BuildPlayerView(playerSeat, hand, trickInfo, p.tracker)
+	reply, err := p.request(ctx, &Request{Type: MsgPlay, View: view}, MsgDecision)
+	if err == nil {
+		decision, verr := ValidatePlayReply(reply, hand, trickInfo)
+		if verr == nil {
+			p.recordDecision()
+			return decision, nil
+		}
+		p.recordInvalid()
+	}
+	p.recordFallback()
+	return fallbackPlay(p.config.Fallback, hand, trickInfo), nil
+}
+
+// RequestTributeSelection 实现PlayerInputProvider接口
+func (p *ProcessInputProvider) RequestTributeSelection(ctx context.Context, playerSeat int, options []*sdk.Card) (*sdk.Card, error) {
+	view := BuildPlayerView(playerSeat, options, nil, p.tracker)
+	view.LegalPlays = nil
+	reply, err := p.request(ctx, &Request{Type: MsgTributeSelect, View: view}, MsgCard)
+	if err == nil {
+		card, verr := ValidateCardReply(reply, options)
+		if verr == nil {
+			p.recordDecision()
+			return card, nil
+		}
+		p.recordInvalid()
+	}
+	p.recordFallback()
+	return fallbackSelect(p.config.Fallback, options), nil
+}
+
+// RequestReturnTribute 实现PlayerInputProvider接口
+func (p *ProcessInputProvider) RequestReturnTribute(ctx context.Context, playerSeat int, hand []*sdk.Card) (*sdk.Card, error) {
+	view := BuildPlayerView(playerSeat, hand, nil, p.tracker)
+	view.LegalPlays = nil
+	reply, err := p.request(ctx, &Request{Type: MsgReturnTribute, View: view}, MsgCard)
+	if err == nil {
+		card, verr := ValidateCardReply(reply, hand)
+		if verr == nil {
+			p.recordDecision()
+			return card, nil
+		}
+		p.recordInvalid()
+	}
+	p.recordFallback()
+	return fallbackReturn(p.config.Fallback, hand), nil
+}
+
+// request 发送请求并等待对应 id 的回复
+func (p *ProcessInputProvider) request(ctx context.Context, req *Request, replyType string) (*Reply, error) {
+	if !p.Alive() {
+		return nil, ErrBotUnavailable
+	}
+
+	p.requestMutex.Lock()
+	defer p.requestMutex.Unlock()
+
+	budget := p.config.DecisionTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < budget {
+			budget = remaining
+		}
+	}
+	if budget <= 0 {
+		p.recordTimeout()
+		return nil, context.DeadlineExceeded
+	}
+
+	p.nextID++
+	req.ID = p.nextID
+	req.TimeMS = budget.Milliseconds()
+	// 写入也计入时间预算：不读取标准输入的机器人不能拖住对局
+	deadline := time.Now().Add(budget)
+	if err := p.send(ctx, req, budget); err != nil {
+		if ctx.Err() != nil {
+			p.recordTimeout()
+			return nil, ctx.Err()
+		}
+		p.markDead(true)
+		return nil, ErrBotUnavailable
+	}
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	for {
+		select {
+		case reply := <-p.replies:
+			if reply.ID != req.ID {
+				// 之前超时请求的迟到回复
+				continue
+			}
+			if reply.Type != replyType {
+				return nil, fmt.Errorf("unexpected reply type %q", reply.Type)
+			}
+			return reply, nil
+		case <-p.exited:
+			p.markDead(true)
+			return nil, ErrBotUnavailable
+		case <-ctx.Done():
+			p.recordTimeout()
+			return nil, ctx.Err()
+		case <-timer.C:
+			p.recordTimeout()
+			return nil, context.DeadlineExceeded
+		}
+	}
+}
+
+// send 写入一行JSON，写入超过 timeout 时判定机器人已卡死，ctx 结束时返回 ctx.Err()
+func (p *ProcessInputProvider) send(ctx context.Context, req *Request, timeout time.Duration) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	done := make(chan error, 1)
+	go func() {
+		p.writeMutex.Lock()
+		defer p.writeMutex.Unlock()
+		_, err := p.stdin.Write(data)
+		done <- err
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		// 调用方放弃（如对局被停止）不代表机器人卡死，写入协程完成后自行退出
+		return ctx.Err()
+	case <-timer.C:
+	}
+
+	// 机器人不再读取标准输入，管道已满：结束进程以释放阻塞的写入协程
+	p.recordTimeout()
+	p.markDead(true)
+	_ = p.cmd.Process.Kill()
+	return ErrBotUnavailable
+}
+
+func (p *ProcessInputProvider) markDead(crashed bool) {
+	p.statsMutex.Lock()
+	defer p.statsMutex.Unlock()
+	if !p.dead && crashed {
+		p.stats.Crashes++
+	}
+	p.dead = true
+}
+
+func (p *ProcessInputProvider) recordDecision() {
+	p.statsMutex.Lock()
+	p.stats.Decisions++
+	p.statsMutex.Unlock()
+}
+
+func (p *ProcessInputProvider) recordTimeout() {
+	p.statsMutex.Lock()
+	p.stats.Timeouts++
+	p.statsMutex.Unlock()
+}
+
+func (p *ProcessInputProvider) recordInvalid() {
+	p.statsMutex.Lock()
+	p.stats.InvalidReplies++
+	p.statsMutex.Unlock()
+}
+
+func (p *ProcessInputProvider) recordFallback() {
+	p.statsMutex.Lock()
+	p.stats.Fallbacks++
+	p.statsMutex.Unlock()
+}
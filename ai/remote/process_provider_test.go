@@ -0,0 +1,256 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"guandan-world/sdk"
+)
+
+// 测试进程复用当前测试二进制：设置 GUANDAN_ECHO_BOT=1 时作为参考机器人运行
+func TestMain(m *testing.M) {
+	if os.Getenv("GUANDAN_ECHO_BOT") == "1" {
+		options := EchoBotOptions{
+			Garbage: os.Getenv("GUANDAN_ECHO_BOT_GARBAGE") == "1",
+			Stall:   os.Getenv("GUANDAN_ECHO_BOT_STALL") == "1",
+		}
+		if delay, err := time.ParseDuration(os.Getenv("GUANDAN_ECHO_BOT_DELAY")); err == nil {
+			options.Delay = delay
+		}
+		if n, err := strconv.Atoi(os.Getenv("GUANDAN_ECHO_BOT_CRASH_AFTER")); err == nil {
+			options.CrashAfter = n
+		}
+		if err := RunEchoBot(os.Stdin, os.Stdout, options); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func startEchoBot(t *testing.T, decisionTimeout time.Duration, env ...string) *ProcessInputProvider {
+	t.Helper()
+	config := DefaultProcessConfig(os.Args[0])
+	config.Env = append([]string{"GUANDAN_ECHO_BOT=1"}, env...)
+	config.DecisionTimeout = decisionTimeout
+	provider, err := NewProcessInputProvider(config)
+	if err != nil {
+		t.Fatalf("failed to start echo bot: %v", err)
+	}
+	t.Cleanup(func() { provider.Close() })
+	return provider
+}
+
+func testCard(number int, color string) *sdk.Card {
+	card, _ := sdk.NewCard(number, color, 2)
+	return card
+}
+
+func testHand() []*sdk.Card {
+	return []*sdk.Card{
+		testCard(3, "Spade"),
+		testCard(5, "Heart"),
+		testCard(5, "Club"),
+		testCard(9, "Diamond"),
+		testCard(13, "Heart"),
+	}
+}
+
+func TestProcessInputProvider_Decisions(t *testing.T) {
+	provider := startEchoBot(t, 5*time.Second)
+	if provider.Name() != "echo" {
+		t.Errorf("expected bot name echo, got %q", provider.Name())
+	}
+
+	hand := testHand()
+	decision, err := provider.RequestPlayDecision(context.Background(), 0, hand, &sdk.TrickInfo{IsLeader: true})
+	if err != nil {
+		t.Fatalf("RequestPlayDecision failed: %v", err)
+	}
+	if decision.Action != sdk.ActionPlay || len(decision.Cards) == 0 {
+		t.Fatalf("leader should play cards, got %+v", decision)
+	}
+	if decision.Cards[0] != hand[0] {
+		t.Errorf("decision should reference the original hand cards")
+	}
+
+	lead := sdk.FromCardList([]*sdk.Card{testCard(14, "Spade")}, nil)
+	decision, err = provider.RequestPlayDecision(context.Background(), 1, hand, &sdk.TrickInfo{IsLeader: false, LeadComp: lead})
+	if err != nil {
+		t.Fatalf("RequestPlayDecision failed: %v", err)
+	}
+	if decision.Action != sdk.ActionPass {
+		t.Errorf("echo bot should pass when nothing beats the lead, got %+v", decision)
+	}
+
+	card, err := provider.RequestReturnTribute(context.Background(), 2, hand)
+	if err != nil || card != hand[0] {
+		t.Errorf("expected first card as return tribute, got %v (%v)", card, err)
+	}
+
+	if stats := provider.Stats(); stats.Decisions != 3 || stats.Fallbacks != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestProcessInputProvider_TimeoutFallsBack(t *testing.T) {
+	provider := startEchoBot(t, 50*time.Millisecond, "GUANDAN_ECHO_BOT_DELAY=300ms")
+
+	hand := testHand()
+	decision, err := provider.RequestPlayDecision(context.Background(), 0, hand, &sdk.TrickInfo{IsLeader: true})
+	if err != nil {
+		t.Fatalf("RequestPlayDecision should fall back instead of failing: %v", err)
+	}
+	if decision.Action != sdk.ActionPlay {
+		t.Errorf("fallback leader decision should play, got %+v", decision)
+	}
+
+	stats := provider.Stats()
+	if stats.Timeouts != 1 || stats.Fallbacks != 1 {
+		t.Errorf("expected one timeout and one fallback, got %+v", stats)
+	}
+	if !provider.Alive() {
+		t.Error("a slow bot should stay alive")
+	}
+}
+
+func TestProcessInputProvider_ContextDeadline(t *testing.T) {
+	provider := startEchoBot(t, 5*time.Second, "GUANDAN_ECHO_BOT_DELAY=300ms")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := provider.RequestPlayDecision(ctx, 0, testHand(), &sdk.TrickInfo{IsLeader: true}); err != nil {
+		t.Fatalf("RequestPlayDecision failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("ctx deadline should bound the wait, took %v", elapsed)
+	}
+	if provider.Stats().Timeouts != 1 {
+		t.Errorf("expected a timeout, got %+v", provider.Stats())
+	}
+}
+
+func TestProcessInputProvider_CancelledContextKeepsBot(t *testing.T) {
+	provider := startEchoBot(t, 5*time.Second)
+
+	// 调用方放弃请求时机器人仍然可用，不计为崩溃
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 20; i++ {
+		if _, err := provider.RequestPlayDecision(ctx, 0, testHand(), &sdk.TrickInfo{IsLeader: true}); err != nil {
+			t.Fatalf("RequestPlayDecision should fall back instead of failing: %v", err)
+		}
+	}
+	if !provider.Alive() {
+		t.Fatal("a cancelled request should not kill the bot")
+	}
+	if stats := provider.Stats(); stats.Crashes != 0 || stats.Fallbacks != 20 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	if _, err := provider.RequestPlayDecision(context.Background(), 0, testHand(), &sdk.TrickInfo{IsLeader: true}); err != nil {
+		t.Fatalf("RequestPlayDecision failed: %v", err)
+	}
+	if provider.Stats().Decisions != 1 {
+		t.Errorf("the bot should still answer after cancelled requests, got %+v", provider.Stats())
+	}
+}
+
+func TestProcessInputProvider_CrashFallsBack(t *testing.T) {
+	provider := startEchoBot(t, 5*time.Second, "GUANDAN_ECHO_BOT_CRASH_AFTER=1")
+
+	hand := testHand()
+	for i := 0; i < 3; i++ {
+		decision, err := provider.RequestPlayDecision(context.Background(), 0, hand, &sdk.TrickInfo{IsLeader: true})
+		if err != nil {
+			t.Fatalf("call %d should fall back instead of failing: %v", i, err)
+		}
+		if decision.Action != sdk.ActionPlay {
+			t.Fatalf("call %d: leader must play, got %+v", i, decision)
+		}
+	}
+
+	if provider.Alive() {
+		t.Error("crashed bot should be marked unavailable")
+	}
+	stats := provider.Stats()
+	if stats.Crashes != 1 || stats.Decisions != 1 || stats.Fallbacks != 2 {
+		t.Errorf("unexpected stats after crash: %+v", stats)
+	}
+}
+
+func TestProcessInputProvider_StalledStdin(t *testing.T) {
+	provider := startEchoBot(t, 100*time.Millisecond, "GUANDAN_ECHO_BOT_STALL=1")
+
+	// 机器人不读取输入，持续推送事件直到管道写满
+	event := &sdk.GameEvent{Type: sdk.EventTrickStarted}
+	start := time.Now()
+	for i := 0; i < 100000 && provider.Alive(); i++ {
+		provider.OnGameEvent(event)
+	}
+	if provider.Alive() {
+		t.Fatal("a bot that stops reading stdin should be marked unavailable")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("the blocked write should be bounded by the decision timeout, took %v", elapsed)
+	}
+
+	start = time.Now()
+	decision, err := provider.RequestPlayDecision(context.Background(), 0, testHand(), &sdk.TrickInfo{IsLeader: true})
+	if err != nil || decision.Action != sdk.ActionPlay {
+		t.Fatalf("expected a fallback play, got %+v (%v)", decision, err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("a dead bot should fall back at once, took %v", elapsed)
+	}
+	if stats := provider.Stats(); stats.Timeouts != 1 || stats.Fallbacks != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestProcessInputProvider_InvalidReply(t *testing.T) {
+	provider := startEchoBot(t, 5*time.Second, "GUANDAN_ECHO_BOT_GARBAGE=1")
+
+	hand := testHand()
+	decision, err := provider.RequestPlayDecision(context.Background(), 0, hand, &sdk.TrickInfo{IsLeader: true})
+	if err != nil {
+		t.Fatalf("RequestPlayDecision failed: %v", err)
+	}
+	if decision.Action != sdk.ActionPlay || len(decision.Cards) == 0 {
+		t.Errorf("fallback should produce a play, got %+v", decision)
+	}
+	if stats := provider.Stats(); stats.InvalidReplies != 1 || stats.Fallbacks != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestProcessInputProvider_FullMatch(t *testing.T) {
+	provider := startEchoBot(t, 5*time.Second)
+
+	engine := sdk.NewGameEngine()
+	driver := sdk.NewGameDriver(engine, sdk.DefaultGameDriverConfig())
+	driver.SetInputProvider(sdk.NewSeatInputRouter(provider))
+	driver.AddObserver(provider)
+
+	players := make([]sdk.Player, 4)
+	for i := 0; i < 4; i++ {
+		players[i] = sdk.Player{ID: fmt.Sprintf("bot_%d", i), Username: fmt.Sprintf("Bot %d", i), Seat: i, Online: true}
+	}
+
+	result, err := driver.RunMatch(players)
+	if err != nil {
+		t.Fatalf("match with external bot failed: %v", err)
+	}
+	if result.DealCount == 0 {
+		t.Error("expected at least one deal")
+	}
+	if stats := provider.Stats(); stats.Decisions == 0 || stats.Crashes != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,332 @@
+// Package remote 提供由外部机器人（独立进程或远程服务）做决策的 PlayerInputProvider
+//
+// 外部机器人通过一个基于 JSON 的协议与游戏交互，协议类似国际象棋的 UCI：
+//
+//	服务端 -> 机器人: {"type":"hello","protocol":"guandan-bot/1"}
+//	机器人 -> 服务端: {"type":"ready","name":"my-bot"}
+//	服务端 -> 机器人: {"type":"new_deal","level":2}
+//	服务端 -> 机器人: {"type":"event","event":"player_played","seat":1,"cards":["Spade_3"]}
+//	服务端 -> 机器人: {"type":"play","id":7,"view":{...},"time_ms":900}
+//	机器人 -> 服务端: {"type":"decision","id":7,"action":"play","cards":["Heart_5"]}
+//	服务端 -> 机器人: {"type":"quit"}
+//
+// 牌使用 sdk.Card.GetID() 的格式（"Color_Number"）编码。每个请求带有递增的 id，
+// 机器人的回复必须带回相同的 id，超时后迟到的回复会被丢弃。
+//
+// 同一套消息也可以通过网络传输：WebSocket 连接上每条消息是一个 JSON 文本帧；
+// HTTP 方式下每个请求（play、tribute_select、return_tribute、hello）是一次 POST，
+// 响应体为对应的回复，公开事件不推送，决策所需的公开信息都包含在 view 中。
+package remote
+
+import (
+	"fmt"
+	"sync"
+
+	"guandan-world/ai"
+	"guandan-world/sdk"
+)
+
+// ProtocolVersion 协议版本号
+const ProtocolVersion = "guandan-bot/1"
+
+// 消息类型
+const (
+	MsgHello         = "hello"
+	MsgReady         = "ready"
+	MsgNewDeal       = "new_deal"
+	MsgEvent         = "event"
+	MsgPlay          = "play"
+	MsgTributeSelect = "tribute_select"
+	MsgReturnTribute = "return_tribute"
+	MsgDecision      = "decision"
+	MsgCard          = "card"
+	MsgQuit          = "quit"
+)
+
+// PlayerView 发送给机器人的脱敏玩家视角：只包含该座位自己的手牌和公开信息
+type PlayerView struct {
+	Seat       int        `json:"seat"`
+	Level      int        `json:"level"`
+	Hand       []string   `json:"hand"`                  // 自己的手牌（或可选的牌）
+	IsLeader   bool       `json:"is_leader"`             // 是否为首出
+	Lead       []string   `json:"lead,omitempty"`        // 当前领先的牌
+	LeadType   string     `json:"lead_type,omitempty"`   // 领先牌型
+	LeadSeat   int        `json:"lead_seat"`             // 领先牌的出牌座位（未知时为-1）
+	LegalPlays [][]string `json:"legal_plays,omitempty"` // 合法出牌（不含过牌，跟牌时总是可以过牌）
+	HandSizes  [4]int     `json:"hand_sizes"`            // 各座位剩余手牌数
+}
+
+// Request 服务端发给机器人的消息
+type Request struct {
+	Type     string      `json:"type"`
+	ID       uint64      `json:"id,omitempty"`
+	Protocol string      `json:"protocol,omitempty"`
+	Level    int         `json:"level,omitempty"`
+	Event    string      `json:"event,omitempty"`
+	Seat     *int        `json:"seat,omitempty"`
+	Cards    []string    `json:"cards,omitempty"`
+	View     *PlayerView `json:"view,omitempty"`
+	TimeMS   int64       `json:"time_ms,omitempty"` // 本次决策的时间预算（毫秒）
+}
+
+// Reply 机器人发给服务端的消息
+type Reply struct {
+	Type   string   `json:"type"`
+	ID     uint64   `json:"id,omitempty"`
+	Name   string   `json:"name,omitempty"`
+	Action string   `json:"action,omitempty"` // "play" 或 "pass"
+	Cards  []string `json:"cards,omitempty"`
+	Card   string   `json:"card,omitempty"`
+}
+
+// EncodeCards 将牌编码为ID列表
+func EncodeCards(cards []*sdk.Card) []string {
+	ids := make([]string, 0, len(cards))
+	for _, card := range cards {
+		ids = append(ids, card.GetID())
+	}
+	return ids
+}
+
+// MatchCards 在 hand 中为每个ID找到一张未被使用的牌
+// 返回的是 hand 中的原始牌对象，保证引擎可以识别
+func MatchCards(ids []string, hand []*sdk.Card) ([]*sdk.Card, error) {
+	used := make([]bool, len(hand))
+	result := make([]*sdk.Card, 0, len(ids))
+	for _, id := range ids {
+		found := false
+		for i, card := range hand {
+			if !used[i] && card.GetID() == id {
+				used[i] = true
+				result = append(result, card)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("card %s not in hand", id)
+		}
+	}
+	return result, nil
+}
+
+// BuildPlayerView 根据手牌和轮次信息构造玩家视角
+func BuildPlayerView(playerSeat int, hand []*sdk.Card, trickInfo *sdk.TrickInfo, tracker *ViewTracker) *PlayerView {
+	view := &PlayerView{
+		Seat:     playerSeat,
+		Hand:     EncodeCards(hand),
+		LeadSeat: -1,
+	}
+	if len(hand) > 0 {
+		view.Level = hand[0].Level
+	}
+	if trickInfo != nil {
+		view.IsLeader = trickInfo.IsLeader
+		if !trickInfo.IsLeader && trickInfo.LeadComp != nil {
+			view.Lead = EncodeCards(trickInfo.LeadComp.GetCards())
+			view.LeadType = trickInfo.LeadComp.GetType().String()
+		}
+	}
+	for _, play := range ai.EnumerateLegalPlays(hand, trickInfo) {
+		view.LegalPlays = append(view.LegalPlays, EncodeCards(play))
+	}
+	if tracker != nil {
+		view.HandSizes, view.LeadSeat = tracker.snapshot()
+		if view.Level == 0 {
+			view.Level = tracker.level()
+		}
+	}
+	view.HandSizes[playerSeat] = len(hand)
+	return view
+}
+
+// ValidatePlayReply 把机器人的出牌回复转换为决策，并校验其合法性
+func ValidatePlayReply(reply *Reply, hand []*sdk.Card, trickInfo *sdk.TrickInfo) (*sdk.PlayDecision, error) {
+	switch reply.Action {
+	case "pass":
+		if trickInfo != nil && trickInfo.IsLeader {
+			return nil, fmt.Errorf("leader cannot pass")
+		}
+		return &sdk.PlayDecision{Action: sdk.ActionPass}, nil
+	case "play":
+		cards, err := MatchCards(reply.Cards, hand)
+		if err != nil {
+			return nil, err
+		}
+		if len(cards) == 0 {
+			return nil, fmt.Errorf("play decision without cards")
+		}
+		var prev sdk.CardComp
+		if trickInfo != nil && !trickInfo.IsLeader {
+			prev = trickInfo.LeadComp
+		}
+		comp := sdk.FromCardList(cards, prev)
+		if comp == nil || !comp.IsValid() {
+			return nil, fmt.Errorf("invalid card combination")
+		}
+		if prev != nil && !comp.GreaterThan(prev) {
+			return nil, fmt.Errorf("%s cannot beat %s", comp.String(), prev.String())
+		}
+		return &sdk.PlayDecision{Action: sdk.ActionPlay, Cards: cards}, nil
+	default:
+		return nil, fmt.Errorf("unknown action: %q", reply.Action)
+	}
+}
+
+// ValidateCardReply 把机器人的选牌回复转换为选项中的牌
+func ValidateCardReply(reply *Reply, options []*sdk.Card) (*sdk.Card, error) {
+	cards, err := MatchCards([]string{reply.Card}, options)
+	if err != nil {
+		return nil, err
+	}
+	return cards[0], nil
+}
+
+// ViewTracker 通过游戏事件跟踪构造玩家视角所需的公开信息
+type ViewTracker struct {
+	mutex     sync.Mutex
+	handSizes [4]int
+	leadSeat  int
+	dealLevel int
+}
+
+// NewViewTracker 创建公开信息跟踪器
+func NewViewTracker() *ViewTracker {
+	return &ViewTracker{handSizes: [4]int{27, 27, 27, 27}, leadSeat: -1}
+}
+
+// OnGameEvent 实现 sdk.EventObserver
+func (t *ViewTracker) OnGameEvent(event *sdk.GameEvent) {
+	data, _ := event.Data.(map[string]interface{})
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	switch event.Type {
+	case sdk.EventDealStarted:
+		t.handSizes = [4]int{27, 27, 27, 27}
+		t.leadSeat = -1
+		if level, ok := data["deal_level"].(int); ok {
+			t.dealLevel = level
+		}
+	case sdk.EventTrickStarted:
+		t.leadSeat = -1
+	case sdk.EventPlayerPlayed:
+		seat, ok := data["player_seat"].(int)
+		cards, _ := data["cards"].([]*sdk.Card)
+		if ok && seat >= 0 && seat < 4 {
+			t.handSizes[seat] -= len(cards)
+			t.leadSeat = seat
+		}
+	case sdk.EventTributeGiven:
+		giver, gok := data["giver"].(int)
+		receiver, rok := data["receiver"].(int)
+		t.transfer(giver, gok, receiver, rok)
+	case sdk.EventReturnTribute:
+		giver, gok := data["player"].(int)
+		receiver, rok := data["target_player"].(int)
+		t.transfer(giver, gok, receiver, rok)
+	}
+}
+
+// transfer 记录一张牌在两个座位之间转移
+func (t *ViewTracker) transfer(giver int, giverOK bool, receiver int, receiverOK bool) {
+	if giverOK && giver >= 0 && giver < 4 {
+		t.handSizes[giver]--
+	}
+	if receiverOK && receiver >= 0 && receiver < 4 {
+		t.handSizes[receiver]++
+	}
+}
+
+func (t *ViewTracker) snapshot() ([4]int, int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.handSizes, t.leadSeat
+}
+
+func (t *ViewTracker) level() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.dealLevel
+}
+
+// publicEventRequest 将公开的游戏事件转换为发给机器人的消息，非公开事件返回nil
+func publicEventRequest(event *sdk.GameEvent) *Request {
+	data, _ := event.Data.(map[string]interface{})
+
+	switch event.Type {
+	case sdk.EventDealStarted:
+		level, _ := data["deal_level"].(int)
+		return &Request{Type: MsgNewDeal, Level: level}
+	case sdk.EventTrickStarted, sdk.EventDealEnded, sdk.EventMatchEnded:
+		return &Request{Type: MsgEvent, Event: string(event.Type)}
+	case sdk.EventPlayerPlayed:
+		seat, _ := data["player_seat"].(int)
+		cards, _ := data["cards"].([]*sdk.Card)
+		return &Request{Type: MsgEvent, Event: string(event.Type), Seat: &seat, Cards: EncodeCards(cards)}
+	case sdk.EventPlayerPassed:
+		seat, _ := data["player_seat"].(int)
+		return &Request{Type: MsgEvent, Event: string(event.Type), Seat: &seat}
+	case sdk.EventTributeGiven:
+		giver, _ := data["giver"].(int)
+		var cards []string
+		if card, ok := data["card"].(*sdk.Card); ok && card != nil {
+			cards = []string{card.GetID()}
+		}
+		return &Request{Type: MsgEvent, Event: string(event.Type), Seat: &giver, Cards: cards}
+	case sdk.EventReturnTribute:
+		player, _ := data["player"].(int)
+		var cards []string
+		if card, ok := data["return_card"].(*sdk.Card); ok && card != nil {
+			cards = []string{card.GetID()}
+		}
+		return &Request{Type: MsgEvent, Event: string(event.Type), Seat: &player, Cards: cards}
+	}
+	return nil
+}
+
+// fallbackPlay 使用本地算法生成兜底出牌决策
+func fallbackPlay(algorithm ai.AutoPlayAlgorithm, hand []*sdk.Card, trickInfo *sdk.TrickInfo) *sdk.PlayDecision {
+	cards := algorithm.SelectCardsToPlay(hand, trickInfo)
+	if len(cards) > 0 {
+		return &sdk.PlayDecision{Action: sdk.ActionPlay, Cards: cards}
+	}
+	if trickInfo != nil && trickInfo.IsLeader && len(hand) > 0 {
+		// 首出不能过牌，出最小的一张
+		return &sdk.PlayDecision{Action: sdk.ActionPlay, Cards: []*sdk.Card{smallestCard(hand)}}
+	}
+	return &sdk.PlayDecision{Action: sdk.ActionPass}
+}
+
+// fallbackReturn 使用本地算法生成兜底还贡选择
+func fallbackReturn(algorithm ai.AutoPlayAlgorithm, hand []*sdk.Card) *sdk.Card {
+	if card := algorithm.SelectReturnTributeCard(hand, nil); card != nil {
+		return card
+	}
+	return smallestCard(hand)
+}
+
+// fallbackSelect 使用本地算法生成兜底贡牌选择
+func fallbackSelect(algorithm ai.AutoPlayAlgorithm, options []*sdk.Card) *sdk.Card {
+	if card := algorithm.SelectTributeCard(options, false); card != nil {
+		return card
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	return options[0]
+}
+
+func smallestCard(hand []*sdk.Card) *sdk.Card {
+	if len(hand) == 0 {
+		return nil
+	}
+	smallest := hand[0]
+	for _, card := range hand[1:] {
+		if smallest.GreaterThan(card) {
+			smallest = card
+		}
+	}
+	return smallest
+}
@@ -0,0 +1,264 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"guandan-world/ai"
+	"guandan-world/sdk"
+)
+
+// ErrCircuitOpen 机器人服务连续失败，熔断期间直接使用兜底算法
+var ErrCircuitOpen = errors.New("bot service circuit open")
+
+// Transport 远程机器人的传输层
+type Transport interface {
+	// RoundTrip 发送一条请求并等待回复，必须遵守 ctx 的截止时间
+	RoundTrip(ctx context.Context, req *Request) (*Reply, error)
+
+	// Notify 发送不需要回复的消息（new_deal、event），无状态传输可以忽略
+	Notify(req *Request) error
+
+	// Close 释放连接
+	Close() error
+}
+
+// RetryableError 标记可以重试的传输错误（网络错误、5xx等）
+type RetryableError struct {
+	Err error
+}
+
+func (e *RetryableError) Error() string { return e.Err.Error() }
+func (e *RetryableError) Unwrap() error { return e.Err }
+
+// RemoteConfig 远程机器人配置
+type RemoteConfig struct {
+	DecisionTimeout  time.Duration        // 单次决策的时间预算上限（ctx 的截止时间更早时以 ctx 为准）
+	MaxRetries       int                  // 可重试错误的最大重试次数
+	RetryBackoff     time.Duration        // 重试间隔
+	FailureThreshold int                  // 连续失败多少次后熔断
+	Cooldown         time.Duration        // 熔断持续时间
+	Fallback         ai.AutoPlayAlgorithm // 失败或熔断时使用的兜底算法
+}
+
+// DefaultRemoteConfig 返回默认配置
+func DefaultRemoteConfig() *RemoteConfig {
+	return &RemoteConfig{
+		DecisionTimeout:  10 * time.Second,
+		MaxRetries:       2,
+		RetryBackoff:     50 * time.Millisecond,
+		FailureThreshold: 3,
+		Cooldown:         30 * time.Second,
+	}
+}
+
+// RemoteInputProvider 通过远程机器人服务做决策的 PlayerInputProvider
+type RemoteInputProvider struct {
+	transport Transport
+	config    *RemoteConfig
+	breaker   *CircuitBreaker
+	tracker   *ViewTracker
+
+	requestMutex sync.Mutex
+	nextID       uint64
+
+	statsMutex sync.Mutex
+	stats      ProviderStats
+}
+
+// NewRemoteInputProvider 使用指定的传输层创建远程输入提供者
+func NewRemoteInputProvider(transport Transport, config *RemoteConfig) *RemoteInputProvider {
+	if config == nil {
+		config = DefaultRemoteConfig()
+	}
+	if config.DecisionTimeout <= 0 {
+		config.DecisionTimeout = 10 * time.Second
+	}
+	if config.Fallback == nil {
+		config.Fallback = ai.NewSmartAutoPlayAlgorithm(2)
+	}
+	return &RemoteInputProvider{
+		transport: transport,
+		config:    config,
+		breaker:   NewCircuitBreaker(config.FailureThreshold, config.Cooldown),
+		tracker:   NewViewTracker(),
+	}
+}
+
+// NewHTTPInputProvider 创建通过 HTTP 调用机器人服务的输入提供者
+func NewHTTPInputProvider(url string, config *RemoteConfig) *RemoteInputProvider {
+	return NewRemoteInputProvider(NewHTTPTransport(url, nil), config)
+}
+
+// NewWebSocketInputProvider 连接 WebSocket 机器人服务并完成握手
+func NewWebSocketInputProvider(url string, config *RemoteConfig) (*RemoteInputProvider, error) {
+	transport, err := DialWebSocketTransport(url)
+	if err != nil {
+		return nil, err
+	}
+	return NewRemoteInputProvider(transport, config), nil
+}
+
+// Stats 返回运行统计
+func (p *RemoteInputProvider) Stats() ProviderStats {
+	p.statsMutex.Lock()
+	defer p.statsMutex.Unlock()
+	stats := p.stats
+	stats.CircuitOpens = p.breaker.OpenCount()
+	return stats
+}
+
+// Breaker 返回熔断器（用于监控）
+func (p *RemoteInputProvider) Breaker() *CircuitBreaker {
+	return p.breaker
+}
+
+// Close 关闭传输层
+func (p *RemoteInputProvider) Close() error {
+	return p.transport.Close()
+}
+
+// OnGameEvent 实现 sdk.EventObserver，跟踪公开信息并转发给有状态的传输层
+func (p *RemoteInputProvider) OnGameEvent(event *sdk.GameEvent) {
+	p.tracker.OnGameEvent(event)
+	if p.breaker.IsOpen() {
+		return
+	}
+	if req := publicEventRequest(event); req != nil {
+		_ = p.transport.Notify(req)
+	}
+}
+
+// RequestPlayDecision 实现PlayerInputProvider接口
+func (p *RemoteInputProvider) RequestPlayDecision(ctx context.Context, playerSeat int, hand []*sdk.Card, trickInfo *sdk.TrickInfo) (*sdk.PlayDecision, error) {
+	view := BuildPlayerView(playerSeat, hand, trickInfo, p.tracker)
+	reply, err := p.call(ctx, &Request{Type: MsgPlay, View: view}, MsgDecision)
+	if err == nil {
+		decision, verr := ValidatePlayReply(reply, hand, trickInfo)
+		if verr == nil {
+			p.succeed()
+			return decision, nil
+		}
+		p.invalid()
+	}
+	p.recordFallback()
+	return fallbackPlay(p.config.Fallback, hand, trickInfo), nil
+}
+
+// RequestTributeSelection 实现PlayerInputProvider接口
+func (p *RemoteInputProvider) RequestTributeSelection(ctx context.Context, playerSeat int, options []*sdk.Card) (*sdk.Card, error) {
+	view := BuildPlayerView(playerSeat, options, nil, p.tracker)
+	view.LegalPlays = nil
+	reply, err := p.call(ctx, &Request{Type: MsgTributeSelect, View: view}, MsgCard)
+	if err == nil {
+		card, verr := ValidateCardReply(reply, options)
+		if verr == nil {
+			p.succeed()
+			return card, nil
+		}
+		p.invalid()
+	}
+	p.recordFallback()
+	return fallbackSelect(p.config.Fallback, options), nil
+}
+
+// RequestReturnTribute 实现PlayerInputProvider接口
+func (p *RemoteInputProvider) RequestReturnTribute(ctx context.Context, playerSeat int, hand []*sdk.Card) (*sdk.Card, error) {
+	view := BuildPlayerView(playerSeat, hand, nil, p.tracker)
+	view.LegalPlays = nil
+	reply, err := p.call(ctx, &Request{Type: MsgReturnTribute, View: view}, MsgCard)
+	if err == nil {
+		card, verr := ValidateCardReply(reply, hand)
+		if verr == nil {
+			p.succeed()
+			return card, nil
+		}
+		p.invalid()
+	}
+	p.recordFallback()
+	return fallbackReturn(p.config.Fallback, hand), nil
+}
+
+// call 在时间预算内发送请求，按需重试，并维护熔断器状态
+func (p *RemoteInputProvider) call(ctx context.Context, req *Request, replyType string) (*Reply, error) {
+	if !p.breaker.Allow() {
+		p.statsMutex.Lock()
+		p.stats.ShortCircuits++
+		p.statsMutex.Unlock()
+		return nil, ErrCircuitOpen
+	}
+
+	p.requestMutex.Lock()
+	defer p.requestMutex.Unlock()
+
+	callCtx, cancel := context.WithTimeout(ctx, p.config.DecisionTimeout)
+	defer cancel()
+
+	for attempt := 0; ; attempt++ {
+		p.nextID++
+		req.ID = p.nextID
+		if deadline, ok := callCtx.Deadline(); ok {
+			req.TimeMS = time.Until(deadline).Milliseconds()
+		}
+
+		reply, err := p.transport.RoundTrip(callCtx, req)
+		if err == nil {
+			if reply.Type != replyType || reply.ID != req.ID {
+				p.breaker.Failure()
+				return nil, errors.New("unexpected reply")
+			}
+			return reply, nil
+		}
+
+		if callCtx.Err() != nil {
+			p.statsMutex.Lock()
+			p.stats.Timeouts++
+			p.statsMutex.Unlock()
+			p.breaker.Failure()
+			return nil, callCtx.Err()
+		}
+
+		var retryable *RetryableError
+		if attempt >= p.config.MaxRetries || !errors.As(err, &retryable) {
+			p.breaker.Failure()
+			return nil, err
+		}
+
+		p.statsMutex.Lock()
+		p.stats.Retries++
+		p.statsMutex.Unlock()
+
+		select {
+		case <-time.After(p.config.RetryBackoff):
+		case <-callCtx.Done():
+			p.statsMutex.Lock()
+			p.stats.Timeouts++
+			p.statsMutex.Unlock()
+			p.breaker.Failure()
+			return nil, callCtx.Err()
+		}
+	}
+}
+
+func (p *RemoteInputProvider) succeed() {
+	p.breaker.Success()
+	p.statsMutex.Lock()
+	p.stats.Decisions++
+	p.statsMutex.Unlock()
+}
+
+// invalid 非法回复同样视为一次失败
+func (p *RemoteInputProvider) invalid() {
+	p.breaker.Failure()
+	p.statsMutex.Lock()
+	p.stats.InvalidReplies++
+	p.statsMutex.Unlock()
+}
+
+func (p *RemoteInputProvider) recordFallback() {
+	p.statsMutex.Lock()
+	p.stats.Fallbacks++
+	p.statsMutex.Unlock()
+}
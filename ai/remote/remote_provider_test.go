@@ -0,0 +1,300 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"guandan-world/sdk"
+)
+
+func testRemoteConfig() *RemoteConfig {
+	config := DefaultRemoteConfig()
+	config.DecisionTimeout = 5 * time.Second
+	config.RetryBackoff = time.Millisecond
+	return config
+}
+
+func TestHTTPInputProvider_Decisions(t *testing.T) {
+	var sawDeadline atomic.Bool
+	echo := NewEchoBotHandler(EchoBotOptions{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(DeadlineHeader) != "" {
+			sawDeadline.Store(true)
+		}
+		echo.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	provider := NewHTTPInputProvider(server.URL, testRemoteConfig())
+	hand := testHand()
+
+	decision, err := provider.RequestPlayDecision(context.Background(), 0, hand, &sdk.TrickInfo{IsLeader: true})
+	if err != nil {
+		t.Fatalf("RequestPlayDecision failed: %v", err)
+	}
+	if decision.Action != sdk.ActionPlay || decision.Cards[0] != hand[0] {
+		t.Errorf("expected the first legal play from the original hand, got %+v", decision)
+	}
+
+	card, err := provider.RequestTributeSelection(context.Background(), 0, hand[:2])
+	if err != nil || card != hand[0] {
+		t.Errorf("expected first option as tribute, got %v (%v)", card, err)
+	}
+
+	if !sawDeadline.Load() {
+		t.Error("requests should carry the decision deadline")
+	}
+	if stats := provider.Stats(); stats.Decisions != 2 || stats.Fallbacks != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestHTTPInputProvider_RedactedView(t *testing.T) {
+	var view *PlayerView
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		json.NewDecoder(r.Body).Decode(&req)
+		view = req.View
+		json.NewEncoder(w).Encode(EchoReply(&req, EchoBotOptions{}))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPInputProvider(server.URL, testRemoteConfig())
+	hand := testHand()
+	if _, err := provider.RequestPlayDecision(context.Background(), 1, hand, &sdk.TrickInfo{IsLeader: true}); err != nil {
+		t.Fatalf("RequestPlayDecision failed: %v", err)
+	}
+	if view == nil || view.Seat != 1 || len(view.Hand) != len(hand) {
+		t.Fatalf("unexpected view: %+v", view)
+	}
+	if len(view.HandSizes) != 4 {
+		t.Errorf("view should expose public hand sizes, got %v", view.HandSizes)
+	}
+}
+
+func TestHTTPInputProvider_ContextDeadline(t *testing.T) {
+	server := httptest.NewServer(NewEchoBotHandler(EchoBotOptions{Delay: 300 * time.Millisecond}))
+	defer server.Close()
+
+	provider := NewHTTPInputProvider(server.URL, testRemoteConfig())
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	decision, err := provider.RequestPlayDecision(ctx, 0, testHand(), &sdk.TrickInfo{IsLeader: true})
+	if err != nil {
+		t.Fatalf("RequestPlayDecision should fall back instead of failing: %v", err)
+	}
+	if decision.Action != sdk.ActionPlay {
+		t.Errorf("fallback leader decision should play, got %+v", decision)
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("ctx deadline should bound the wait, took %v", elapsed)
+	}
+	if stats := provider.Stats(); stats.Timeouts != 1 || stats.Fallbacks != 1 {
+		t.Errorf("expected one timeout and one fallback, got %+v", stats)
+	}
+}
+
+func TestHTTPInputProvider_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	echo := NewEchoBotHandler(EchoBotOptions{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		echo.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	provider := NewHTTPInputProvider(server.URL, testRemoteConfig())
+	if _, err := provider.RequestPlayDecision(context.Background(), 0, testHand(), &sdk.TrickInfo{IsLeader: true}); err != nil {
+		t.Fatalf("RequestPlayDecision failed: %v", err)
+	}
+	stats := provider.Stats()
+	if stats.Retries != 2 || stats.Decisions != 1 || stats.Fallbacks != 0 {
+		t.Errorf("expected two retries then success, got %+v", stats)
+	}
+	if provider.Breaker().IsOpen() {
+		t.Error("breaker should stay closed after a successful retry")
+	}
+}
+
+func TestHTTPInputProvider_CircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	config := testRemoteConfig()
+	config.FailureThreshold = 2
+	config.Cooldown = time.Hour
+	provider := NewHTTPInputProvider(server.URL, config)
+
+	for i := 0; i < 5; i++ {
+		decision, err := provider.RequestPlayDecision(context.Background(), 0, testHand(), &sdk.TrickInfo{IsLeader: true})
+		if err != nil || decision.Action != sdk.ActionPlay {
+			t.Fatalf("call %d should fall back, got %+v (%v)", i, decision, err)
+		}
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("open breaker should stop calling the service, got %d calls", calls.Load())
+	}
+	stats := provider.Stats()
+	if stats.ShortCircuits != 3 || stats.CircuitOpens != 1 || stats.Fallbacks != 5 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestHTTPInputProvider_InvalidReply(t *testing.T) {
+	server := httptest.NewServer(NewEchoBotHandler(EchoBotOptions{Garbage: true}))
+	defer server.Close()
+
+	provider := NewHTTPInputProvider(server.URL, testRemoteConfig())
+	hand := testHand()
+	card, err := provider.RequestReturnTribute(context.Background(), 0, hand)
+	if err != nil || card == nil {
+		t.Fatalf("RequestReturnTribute should fall back, got %v (%v)", card, err)
+	}
+	if stats := provider.Stats(); stats.InvalidReplies != 1 || stats.Fallbacks != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+// newWebSocketEchoServer 以 WebSocket 方式提供参考机器人，并记录收到的消息类型
+func newWebSocketEchoServer(t *testing.T, options EchoBotOptions, received chan<- string) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var req Request
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			if received != nil {
+				select {
+				case received <- req.Type:
+				default:
+				}
+			}
+			if req.Type == MsgQuit {
+				return
+			}
+			if reply := EchoReply(&req, options); reply != nil {
+				if err := conn.WriteJSON(reply); err != nil {
+					return
+				}
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func webSocketURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestWebSocketInputProvider_Decisions(t *testing.T) {
+	received := make(chan string, 64)
+	server := newWebSocketEchoServer(t, EchoBotOptions{Name: "ws-echo"}, received)
+
+	provider, err := NewWebSocketInputProvider(webSocketURL(server), testRemoteConfig())
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer provider.Close()
+
+	provider.OnGameEvent(&sdk.GameEvent{Type: sdk.EventPlayerPassed, Data: map[string]interface{}{"player_seat": 3}})
+
+	hand := testHand()
+	decision, err := provider.RequestPlayDecision(context.Background(), 0, hand, &sdk.TrickInfo{IsLeader: true})
+	if err != nil {
+		t.Fatalf("RequestPlayDecision failed: %v", err)
+	}
+	if decision.Action != sdk.ActionPlay || decision.Cards[0] != hand[0] {
+		t.Errorf("unexpected decision: %+v", decision)
+	}
+
+	var types []string
+	for len(received) > 0 {
+		types = append(types, <-received)
+	}
+	if strings.Join(types, ",") != "hello,event,play" {
+		t.Errorf("unexpected message sequence: %v", types)
+	}
+	if stats := provider.Stats(); stats.Decisions != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestWebSocketInputProvider_Reconnects(t *testing.T) {
+	server := newWebSocketEchoServer(t, EchoBotOptions{}, nil)
+
+	transport, err := DialWebSocketTransport(webSocketURL(server))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	provider := NewRemoteInputProvider(transport, testRemoteConfig())
+	defer provider.Close()
+
+	// 模拟连接断开：下一次请求应重连并成功
+	transport.mutex.Lock()
+	transport.conn.Close()
+	closed := transport.closed
+	transport.mutex.Unlock()
+	<-closed
+
+	if _, err := provider.RequestPlayDecision(context.Background(), 0, testHand(), &sdk.TrickInfo{IsLeader: true}); err != nil {
+		t.Fatalf("RequestPlayDecision failed: %v", err)
+	}
+	if stats := provider.Stats(); stats.Decisions != 1 || stats.Fallbacks != 0 {
+		t.Errorf("provider should reconnect transparently, got %+v", stats)
+	}
+}
+
+func TestHTTPInputProvider_FullMatch(t *testing.T) {
+	server := httptest.NewServer(NewEchoBotHandler(EchoBotOptions{}))
+	defer server.Close()
+
+	provider := NewHTTPInputProvider(server.URL, testRemoteConfig())
+
+	engine := sdk.NewGameEngine()
+	driver := sdk.NewGameDriver(engine, sdk.DefaultGameDriverConfig())
+	router := sdk.NewSeatInputRouter(provider)
+	driver.SetInputProvider(router)
+	driver.AddObserver(provider)
+
+	players := make([]sdk.Player, 4)
+	for i := 0; i < 4; i++ {
+		players[i] = sdk.Player{ID: fmt.Sprintf("bot_%d", i), Username: fmt.Sprintf("Bot %d", i), Seat: i, Online: true}
+	}
+
+	result, err := driver.RunMatch(players)
+	if err != nil {
+		t.Fatalf("match with remote bot failed: %v", err)
+	}
+	if result.DealCount == 0 {
+		t.Error("expected at least one deal")
+	}
+	if stats := provider.Stats(); stats.Decisions == 0 || stats.InvalidReplies != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,179 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocketTransport 通过 WebSocket 长连接与机器人服务交互，消息格式与进程协议相同
+// 连接断开后在下一次请求时自动重连并重新握手
+type WebSocketTransport struct {
+	url    string
+	dialer *websocket.Dialer
+
+	mutex   sync.Mutex
+	conn    *websocket.Conn
+	replies chan *Reply
+	closed  chan struct{}
+
+	writeMutex sync.Mutex
+	name       string
+}
+
+// DialWebSocketTransport 连接机器人服务并完成 hello/ready 握手
+func DialWebSocketTransport(url string) (*WebSocketTransport, error) {
+	t := &WebSocketTransport{
+		url:    url,
+		dialer: websocket.DefaultDialer,
+	}
+	if err := t.connect(context.Background()); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Name 返回机器人在握手时报告的名称
+func (t *WebSocketTransport) Name() string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.name
+}
+
+// connect 建立连接并握手
+func (t *WebSocketTransport) connect(ctx context.Context) error {
+	dialCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	conn, _, err := t.dialer.DialContext(dialCtx, t.url, nil)
+	if err != nil {
+		return &RetryableError{Err: fmt.Errorf("failed to connect bot service: %w", err)}
+	}
+
+	replies := make(chan *Reply, 16)
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			var reply Reply
+			if err := conn.ReadJSON(&reply); err != nil {
+				return
+			}
+			select {
+			case replies <- &reply:
+			default:
+			}
+		}
+	}()
+
+	if err := t.write(conn, &Request{Type: MsgHello, Protocol: ProtocolVersion}); err != nil {
+		conn.Close()
+		return &RetryableError{Err: err}
+	}
+	select {
+	case reply := <-replies:
+		if reply.Type != MsgReady {
+			conn.Close()
+			return fmt.Errorf("unexpected handshake reply %q", reply.Type)
+		}
+		t.mutex.Lock()
+		t.conn, t.replies, t.closed, t.name = conn, replies, closed, reply.Name
+		t.mutex.Unlock()
+		return nil
+	case <-closed:
+		return &RetryableError{Err: errors.New("bot service closed connection during handshake")}
+	case <-dialCtx.Done():
+		conn.Close()
+		return &RetryableError{Err: errors.New("bot service handshake timed out")}
+	}
+}
+
+// current 返回当前可用的连接，断开时重连
+func (t *WebSocketTransport) current(ctx context.Context) (*websocket.Conn, chan *Reply, chan struct{}, error) {
+	t.mutex.Lock()
+	conn, replies, closed := t.conn, t.replies, t.closed
+	t.mutex.Unlock()
+
+	if conn != nil {
+		select {
+		case <-closed:
+			conn = nil
+		default:
+			return conn, replies, closed, nil
+		}
+	}
+
+	if err := t.connect(ctx); err != nil {
+		return nil, nil, nil, err
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.conn, t.replies, t.closed, nil
+}
+
+func (t *WebSocketTransport) write(conn *websocket.Conn, req *Request) error {
+	t.writeMutex.Lock()
+	defer t.writeMutex.Unlock()
+	conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	return conn.WriteJSON(req)
+}
+
+// RoundTrip 实现 Transport
+func (t *WebSocketTransport) RoundTrip(ctx context.Context, req *Request) (*Reply, error) {
+	conn, replies, closed, err := t.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.write(conn, req); err != nil {
+		conn.Close()
+		return nil, &RetryableError{Err: fmt.Errorf("failed to send request: %w", err)}
+	}
+
+	for {
+		select {
+		case reply := <-replies:
+			if reply.ID != req.ID {
+				// 之前超时请求的迟到回复
+				continue
+			}
+			return reply, nil
+		case <-closed:
+			return nil, &RetryableError{Err: errors.New("bot service connection closed")}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Notify 实现 Transport，连接不可用时静默丢弃事件
+func (t *WebSocketTransport) Notify(req *Request) error {
+	t.mutex.Lock()
+	conn, closed := t.conn, t.closed
+	t.mutex.Unlock()
+	if conn == nil {
+		return nil
+	}
+	select {
+	case <-closed:
+		return nil
+	default:
+	}
+	return t.write(conn, req)
+}
+
+// Close 实现 Transport
+func (t *WebSocketTransport) Close() error {
+	t.mutex.Lock()
+	conn := t.conn
+	t.conn = nil
+	t.mutex.Unlock()
+	if conn == nil {
+		return nil
+	}
+	_ = t.write(conn, &Request{Type: MsgQuit})
+	return conn.Close()
+}
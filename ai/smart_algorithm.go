@@ -7,7 +7,9 @@ import (
 
 // SmartAutoPlayAlgorithm 智能自动出牌算法实现
 type SmartAutoPlayAlgorithm struct {
-	level int // 当前级别
+	level     int            // 当前级别
+	inference *HandInference // 对手手牌推断（可选）
+	weights   *SmartWeights  // 评分权重（为nil时使用 DefaultSmartWeights）
 }
 
 // NewSmartAutoPlayAlgorithm 创建智能算法实例
@@ -17,6 +19,11 @@ func NewSmartAutoPlayAlgorithm(level int) AutoPlayAlgorithm {
 	}
 }
 
+// SetHandInference 设置对手手牌推断器，用于跟牌时判断是否需要用炸弹拦截
+func (algo *SmartAutoPlayAlgorithm) SetHandInference(inference *HandInference) {
+	algo.inference = inference
+}
+
 // CardGroup 表示一个可能的牌组
 type CardGroup struct {
 	Cards       []*sdk.Card    // 牌组中的牌
@@ -357,8 +364,9 @@ func (algo *SmartAutoPlayAlgorithm) findAllStraightFlushes(hand []*sdk.Card) []*
 		}
 	}
 	
-	// 在每个花色组中找顺子
-	for _, cards := range colorGroups {
+	// 在每个花色组中找顺子（按固定的花色顺序遍历，保证结果顺序稳定）
+	for _, color := range sdk.Colors {
+		cards := colorGroups[color]
 		if len(cards) >= 5 {
 			straights := algo.findAllStraights(cards)
 			for _, straight := range straights {
@@ -404,7 +412,10 @@ func (algo *SmartAutoPlayAlgorithm) findAllFullHouses(patterns []CardPattern) []
 				// 选择对子
 				pairCards := pair.Cards[:2]
 				
-				cards := append(tripletCards, pairCards...)
+				// 新建切片，避免append覆盖原分组中的牌
+				cards := make([]*sdk.Card, 0, 5)
+				cards = append(cards, tripletCards...)
+				cards = append(cards, pairCards...)
 				comp := sdk.FromCardList(cards, nil)
 				if comp != nil && comp.GetType() == sdk.TypeFullHouse {
 					groups = append(groups, &CardGroup{
@@ -437,7 +448,7 @@ func (algo *SmartAutoPlayAlgorithm) findAllPlanes(patterns []CardPattern) []*Car
 	// 尝试找连续的三张（至少2个三张）
 	for i := 0; i < len(tripletPatterns)-1; i++ {
 		consecutiveCount := 1
-		cards := tripletPatterns[i].Cards[:3]
+		cards := append([]*sdk.Card{}, tripletPatterns[i].Cards[:3]...)
 		
 		for j := i + 1; j < len(tripletPatterns); j++ {
 			if tripletPatterns[j].Number == tripletPatterns[j-1].Number+1 {
@@ -567,6 +578,7 @@ func (algo *SmartAutoPlayAlgorithm) findAllTubes(patterns []CardPattern) []*Card
 
 // calculateDamageForGroups 计算每个牌组的破坏度
 func (algo *SmartAutoPlayAlgorithm) calculateDamageForGroups(groups []*CardGroup, hand []*sdk.Card) {
+	weights := algo.Weights()
 	for _, group := range groups {
 		damage := 0.0
 		
@@ -597,13 +609,12 @@ func (algo *SmartAutoPlayAlgorithm) calculateDamageForGroups(groups []*CardGroup
 				
 				// 炸弹被破坏的权重更高
 				if otherGroup.CompType == sdk.TypeNaiveBomb || otherGroup.CompType == sdk.TypeJokerBomb {
-					baseDamage *= 5.0
+					baseDamage *= weights.BombDamage
 				} else if otherGroup.CompType == sdk.TypeTube {
-					baseDamage *= 2.5
+					baseDamage *= weights.TubeDamage
 				} else if otherGroup.CompType == sdk.TypePlate ||
-					otherGroup.CompType == sdk.TypeTube ||
 					otherGroup.CompType == sdk.TypeStraightFlush {
-					baseDamage *= 2.0
+					baseDamage *= weights.PlateDamage
 				}
 				
 				damage += baseDamage
@@ -616,27 +627,23 @@ func (algo *SmartAutoPlayAlgorithm) calculateDamageForGroups(groups []*CardGroup
 
 // calculateScoresForGroups 计算每个牌组的综合评分
 func (algo *SmartAutoPlayAlgorithm) calculateScoresForGroups(groups []*CardGroup) {
-	// 权重参数
-	const (
-		cardCountWeight = 5.0  // 出牌数量权重
-		damageWeight    = -2.0 // 破坏度权重（负值）
-		strengthWeight  = -0.5 // 牌力权重（负值，因为越小越好）
-	)
+	// 权重参数（见 SmartWeights）
+	weights := algo.Weights()
 	
 	for _, group := range groups {
 		// 综合评分 = 出牌数量权重 + 破坏度权重 + 牌力权重
-		group.Score = cardCountWeight*float64(group.CardCount) +
-			damageWeight*group.Damage +
-			strengthWeight*group.Strength/10.0
+		group.Score = weights.CardCount*float64(group.CardCount) +
+			weights.Damage*group.Damage +
+			weights.Strength*group.Strength/10.0
 		
 		// 特殊牌型加分
 		switch group.CompType {
 		case sdk.TypeNaiveBomb:
 			// 普通炸弹不需要额外加分
 		case sdk.TypePlate:
-			group.Score += 5 // 钢板额外加分
+			group.Score += weights.PlateBonus // 钢板额外加分
 		case sdk.TypeTube:
-			group.Score += 3 // 钢管额外加分
+			group.Score += weights.TubeBonus // 钢管额外加分
 		}
 	}
 }
@@ -718,12 +725,42 @@ func (algo *SmartAutoPlayAlgorithm) tryToFollowSmart(hand []*sdk.Card, trickInfo
 		}
 		
 		// 如果没有非炸弹牌能跟，考虑是否使用炸弹
-		// 这里可以加入更复杂的判断逻辑
+		if bomb := algo.selectInterceptBomb(validGroups); bomb != nil {
+			return bomb.Cards
+		}
 	}
 	
 	return nil // 过牌
 }
 
+// interceptHandSize 对手剩余手牌不超过该数量时认为即将出完，值得用炸弹拦截
+const interceptHandSize = 6
+
+// selectInterceptBomb 根据手牌推断决定是否用最小的炸弹拦截即将出完的对手
+func (algo *SmartAutoPlayAlgorithm) selectInterceptBomb(validGroups []*CardGroup) *CardGroup {
+	if algo.inference == nil {
+		return nil
+	}
+	leadSeat := algo.inference.LeadSeat()
+	if leadSeat < 0 || leadSeat%2 == algo.inference.Seat()%2 {
+		return nil
+	}
+	if algo.inference.HandSize(leadSeat) > interceptHandSize {
+		return nil
+	}
+
+	var best *CardGroup
+	for _, group := range validGroups {
+		if group.CompType != sdk.TypeNaiveBomb && group.CompType != sdk.TypeJokerBomb {
+			continue
+		}
+		if best == nil || group.Strength < best.Strength {
+			best = group
+		}
+	}
+	return best
+}
+
 // findSmallestCard 找最小的牌
 func (algo *SmartAutoPlayAlgorithm) findSmallestCard(hand []*sdk.Card) *sdk.Card {
 	if len(hand) == 0 {
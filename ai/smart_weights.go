@@ -0,0 +1,100 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// SmartWeights 智能算法评估牌组时使用的权重
+// 评分 = CardCount*出牌数量 + Damage*破坏度 + Strength*牌力/10 + 钢板/钢管加分，
+// 破坏度中被拆散的炸弹、钢管、钢板和同花顺按各自的倍数计算
+type SmartWeights struct {
+	CardCount   float64 `json:"card_count"`   // 出牌数量权重
+	Damage      float64 `json:"damage"`       // 破坏度权重（负值）
+	Strength    float64 `json:"strength"`     // 牌力权重（负值，因为越小越好）
+	BombDamage  float64 `json:"bomb_damage"`  // 拆散炸弹的破坏度倍数
+	TubeDamage  float64 `json:"tube_damage"`  // 拆散钢管的破坏度倍数
+	PlateDamage float64 `json:"plate_damage"` // 拆散钢板或同花顺的破坏度倍数
+	PlateBonus  float64 `json:"plate_bonus"`  // 钢板额外加分
+	TubeBonus   float64 `json:"tube_bonus"`   // 钢管额外加分
+}
+
+// DefaultSmartWeights 返回智能算法原有的默认权重
+func DefaultSmartWeights() SmartWeights {
+	return SmartWeights{
+		CardCount:   5.0,
+		Damage:      -2.0,
+		Strength:    -0.5,
+		BombDamage:  5.0,
+		TubeDamage:  2.5,
+		PlateDamage: 2.0,
+		PlateBonus:  5,
+		TubeBonus:   3,
+	}
+}
+
+// SmartWeightNames 返回权重向量中各分量的名称，顺序与 Vector 一致
+func SmartWeightNames() []string {
+	return []string{"card_count", "damage", "strength", "bomb_damage", "tube_damage", "plate_damage", "plate_bonus", "tube_bonus"}
+}
+
+// Vector 把权重展开为向量，供优化器使用
+func (w SmartWeights) Vector() []float64 {
+	return []float64{w.CardCount, w.Damage, w.Strength, w.BombDamage, w.TubeDamage, w.PlateDamage, w.PlateBonus, w.TubeBonus}
+}
+
+// SmartWeightsFromVector 从向量还原权重
+func SmartWeightsFromVector(v []float64) (SmartWeights, error) {
+	if len(v) != len(SmartWeightNames()) {
+		return SmartWeights{}, fmt.Errorf("expected %d weights, got %d", len(SmartWeightNames()), len(v))
+	}
+	return SmartWeights{
+		CardCount:   v[0],
+		Damage:      v[1],
+		Strength:    v[2],
+		BombDamage:  v[3],
+		TubeDamage:  v[4],
+		PlateDamage: v[5],
+		PlateBonus:  v[6],
+		TubeBonus:   v[7],
+	}, nil
+}
+
+// LoadSmartWeights 从 JSON 文件加载权重，文件中缺少的字段使用默认值
+func LoadSmartWeights(path string) (SmartWeights, error) {
+	weights := DefaultSmartWeights()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return weights, err
+	}
+	if err := json.Unmarshal(data, &weights); err != nil {
+		return weights, fmt.Errorf("invalid smart weights %s: %w", path, err)
+	}
+	return weights, nil
+}
+
+// Save 把权重保存为 JSON 文件
+func (w SmartWeights) Save(path string) error {
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// NewSmartAutoPlayAlgorithmWithWeights 使用指定权重创建智能算法实例
+func NewSmartAutoPlayAlgorithmWithWeights(level int, weights SmartWeights) AutoPlayAlgorithm {
+	return &SmartAutoPlayAlgorithm{
+		level:   level,
+		weights: &weights,
+	}
+}
+
+// Weights 返回算法使用的权重
+func (algo *SmartAutoPlayAlgorithm) Weights() SmartWeights {
+	if algo.weights == nil {
+		return DefaultSmartWeights()
+	}
+	return *algo.weights
+}
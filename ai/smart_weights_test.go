@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"guandan-world/sdk"
+)
+
+func TestSmartWeightsVectorRoundTrip(t *testing.T) {
+	weights := DefaultSmartWeights()
+	vector := weights.Vector()
+	if len(vector) != len(SmartWeightNames()) {
+		t.Fatalf("vector has %d entries, names %d", len(vector), len(SmartWeightNames()))
+	}
+	restored, err := SmartWeightsFromVector(vector)
+	if err != nil || restored != weights {
+		t.Errorf("round trip failed: %+v, %v", restored, err)
+	}
+	if _, err := SmartWeightsFromVector(vector[:3]); err == nil {
+		t.Error("expected error for short vector")
+	}
+}
+
+func TestLoadSmartWeights(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "weights.json")
+
+	tuned := DefaultSmartWeights()
+	tuned.PlateBonus = 9
+	if err := tuned.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := LoadSmartWeights(path)
+	if err != nil || loaded != tuned {
+		t.Errorf("expected %+v, got %+v (%v)", tuned, loaded, err)
+	}
+
+	// 缺少的字段使用默认值
+	partial := filepath.Join(dir, "partial.json")
+	if err := os.WriteFile(partial, []byte(`{"damage": -3}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err = LoadSmartWeights(partial)
+	if err != nil || loaded.Damage != -3 || loaded.CardCount != DefaultSmartWeights().CardCount {
+		t.Errorf("partial file not merged with defaults: %+v (%v)", loaded, err)
+	}
+
+	factory, err := NewAlgorithmFactory("smart:" + path)
+	if err != nil {
+		t.Fatalf("NewAlgorithmFactory failed: %v", err)
+	}
+	algo, ok := factory(2, 0).(*SmartAutoPlayAlgorithm)
+	if !ok || algo.Weights() != tuned {
+		t.Errorf("factory did not use tuned weights: %+v", algo)
+	}
+	if _, err := NewAlgorithmFactory("smart:" + filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected error for missing weights file")
+	}
+}
+
+func TestSmartWeightsAffectScores(t *testing.T) {
+	plate := &CardGroup{CompType: sdk.TypePlate, CardCount: 6, Strength: 11}
+	tube := &CardGroup{CompType: sdk.TypeTube, CardCount: 6, Strength: 11}
+
+	// 默认权重与原来的常量一致：钢板比钢管多2分
+	NewSmartAutoPlayAlgorithm(2).(*SmartAutoPlayAlgorithm).calculateScoresForGroups([]*CardGroup{plate, tube})
+	if plate.Score-tube.Score != 2 {
+		t.Errorf("default weights: plate %.2f tube %.2f", plate.Score, tube.Score)
+	}
+
+	weights := DefaultSmartWeights()
+	weights.TubeBonus = 10
+	algo := NewSmartAutoPlayAlgorithmWithWeights(2, weights).(*SmartAutoPlayAlgorithm)
+	algo.calculateScoresForGroups([]*CardGroup{plate, tube})
+	if algo.selectBestGroup([]*CardGroup{plate, tube}) != tube {
+		t.Errorf("tube bonus should make the tube win: plate %.2f tube %.2f", plate.Score, tube.Score)
+	}
+}
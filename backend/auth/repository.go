@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrUserNotFound is returned when a user does not exist
+	ErrUserNotFound = errors.New("user not found")
+	// ErrUsernameTaken is returned when creating a user whose username is already used
+	ErrUsernameTaken = errors.New("username already exists")
+	// ErrTokenNotFound is returned when a token does not exist
+	ErrTokenNotFound = errors.New("token not found")
+)
+
+// UserRepository stores user accounts
+type UserRepository interface {
+	Create(user *User) error
+	GetByID(userID string) (*User, error)
+	GetByUsername(username string) (*User, error)
+	SetOnline(userID string, online bool) error
+}
+
+// TokenRepository stores issued authentication tokens
+type TokenRepository interface {
+	Save(token *AuthToken) error
+	Get(token string) (*AuthToken, error)
+	Delete(token string) error
+}
+
+// memoryUserRepository keeps users in memory, used by tests and NewAuthService
+type memoryUserRepository struct {
+	users       map[string]*User // userID -> User
+	usersByName map[string]*User // username -> User
+	mu          sync.RWMutex
+}
+
+// NewMemoryUserRepository creates an in-memory user repository
+func NewMemoryUserRepository() UserRepository {
+	return &memoryUserRepository{
+		users:       make(map[string]*User),
+		usersByName: make(map[string]*User),
+	}
+}
+
+// Create stores a new user
+func (r *memoryUserRepository) Create(user *User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.usersByName[user.Username]; exists {
+		return ErrUsernameTaken
+	}
+
+	stored := *user
+	r.users[user.ID] = &stored
+	r.usersByName[user.Username] = &stored
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *memoryUserRepository) GetByID(userID string) (*User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, exists := r.users[userID]
+	if !exists {
+		return nil, ErrUserNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+// GetByUsername retrieves a user by username
+func (r *memoryUserRepository) GetByUsername(username string) (*User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, exists := r.usersByName[username]
+	if !exists {
+		return nil, ErrUserNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+// SetOnline updates the online flag of a user
+func (r *memoryUserRepository) SetOnline(userID string, online bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, exists := r.users[userID]
+	if !exists {
+		return ErrUserNotFound
+	}
+	user.Online = online
+	return nil
+}
+
+// memoryTokenRepository keeps tokens in memory
+type memoryTokenRepository struct {
+	tokens map[string]*AuthToken // token -> AuthToken
+	mu     sync.RWMutex
+}
+
+// NewMemoryTokenRepository creates an in-memory token repository
+func NewMemoryTokenRepository() TokenRepository {
+	return &memoryTokenRepository{
+		tokens: make(map[string]*AuthToken),
+	}
+}
+
+// Save stores a token, replacing any token with the same value
+func (r *memoryTokenRepository) Save(token *AuthToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *token
+	r.tokens[token.Token] = &stored
+	return nil
+}
+
+// Get retrieves a token
+func (r *memoryTokenRepository) Get(token string) (*AuthToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	authToken, exists := r.tokens[token]
+	if !exists {
+		return nil, ErrTokenNotFound
+	}
+	copied := *authToken
+	return &copied, nil
+}
+
+// Delete removes a token
+func (r *memoryTokenRepository) Delete(token string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tokens[token]; !exists {
+		return ErrTokenNotFound
+	}
+	delete(r.tokens, token)
+	return nil
+}
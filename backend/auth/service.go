@@ -12,10 +12,11 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID       string `json:"id"`
-	Username string `json:"username"`
-	Password string `json:"-"` // Never expose password in JSON
-	Online   bool   `json:"online"`
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	Password  string    `json:"-"` // Never expose password in JSON
+	Online    bool      `json:"online"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // AuthToken represents an authentication token
@@ -43,20 +44,23 @@ type AuthService interface {
 
 // authService implements AuthService interface
 type authService struct {
-	users        map[string]*User // userID -> User
-	usersByName  map[string]*User // username -> User
-	tokens       map[string]*AuthToken // token -> AuthToken
-	jwtSecret    []byte
-	tokenExpiry  time.Duration
-	mu           sync.RWMutex
+	users       UserRepository
+	tokens      TokenRepository
+	jwtSecret   []byte
+	tokenExpiry time.Duration
+	mu          sync.RWMutex
 }
 
-// NewAuthService creates a new authentication service
+// NewAuthService creates a new authentication service backed by in-memory repositories
 func NewAuthService(jwtSecret string, tokenExpiry time.Duration) AuthService {
+	return NewAuthServiceWithRepositories(NewMemoryUserRepository(), NewMemoryTokenRepository(), jwtSecret, tokenExpiry)
+}
+
+// NewAuthServiceWithRepositories creates an authentication service that stores users and tokens in the given repositories
+func NewAuthServiceWithRepositories(users UserRepository, tokens TokenRepository, jwtSecret string, tokenExpiry time.Duration) AuthService {
 	return &authService{
-		users:       make(map[string]*User),
-		usersByName: make(map[string]*User),
-		tokens:      make(map[string]*AuthToken),
+		users:       users,
+		tokens:      tokens,
 		jwtSecret:   []byte(jwtSecret),
 		tokenExpiry: tokenExpiry,
 	}
@@ -79,8 +83,10 @@ func (s *authService) Register(username, password string) (*User, error) {
 	}
 
 	// Check if username already exists
-	if _, exists := s.usersByName[username]; exists {
-		return nil, errors.New("username already exists")
+	if _, err := s.users.GetByUsername(username); err == nil {
+		return nil, ErrUsernameTaken
+	} else if !errors.Is(err, ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to look up user: %w", err)
 	}
 
 	// Hash password
@@ -96,19 +102,25 @@ func (s *authService) Register(username, password string) (*User, error) {
 	user := &User{
 		ID:       userID,
 		Username: username,
-		Password: string(hashedPassword),
-		Online:   false,
+		Password:  string(hashedPassword),
+		Online:    false,
+		CreatedAt: time.Now(),
 	}
 
 	// Store user
-	s.users[userID] = user
-	s.usersByName[username] = user
+	if err := s.users.Create(user); err != nil {
+		if errors.Is(err, ErrUsernameTaken) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to store user: %w", err)
+	}
 
 	// Return user without password
 	return &User{
-		ID:       user.ID,
-		Username: user.Username,
-		Online:   user.Online,
+		ID:        user.ID,
+		Username:  user.Username,
+		Online:    user.Online,
+		CreatedAt: user.CreatedAt,
 	}, nil
 }
 
@@ -118,13 +130,13 @@ func (s *authService) Login(username, password string) (*AuthToken, error) {
 	defer s.mu.Unlock()
 
 	// Find user by username
-	user, exists := s.usersByName[username]
-	if !exists {
+	user, err := s.users.GetByUsername(username)
+	if err != nil {
 		return nil, errors.New("invalid username or password")
 	}
 
 	// Verify password
-	err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
 		return nil, errors.New("invalid username or password")
 	}
@@ -154,10 +166,14 @@ func (s *authService) Login(username, password string) (*AuthToken, error) {
 	}
 
 	// Store token
-	s.tokens[tokenString] = authToken
+	if err := s.tokens.Save(authToken); err != nil {
+		return nil, fmt.Errorf("failed to store token: %w", err)
+	}
 
 	// Mark user as online
-	user.Online = true
+	if err := s.users.SetOnline(user.ID, true); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
 
 	return authToken, nil
 }
@@ -168,15 +184,15 @@ func (s *authService) ValidateToken(tokenString string) (*User, error) {
 	defer s.mu.RUnlock()
 
 	// Check if token exists in our store
-	authToken, exists := s.tokens[tokenString]
-	if !exists {
+	authToken, err := s.tokens.Get(tokenString)
+	if err != nil {
 		return nil, errors.New("invalid token")
 	}
 
 	// Check if token is expired
 	if time.Now().After(authToken.ExpiresAt) {
 		// Clean up expired token
-		delete(s.tokens, tokenString)
+		_ = s.tokens.Delete(tokenString)
 		return nil, errors.New("token expired")
 	}
 
@@ -198,9 +214,9 @@ func (s *authService) ValidateToken(tokenString string) (*User, error) {
 	}
 
 	// Get user
-	user, exists := s.users[claims.UserID]
-	if !exists {
-		return nil, errors.New("user not found")
+	user, err := s.users.GetByID(claims.UserID)
+	if err != nil {
+		return nil, err
 	}
 
 	return user, nil
@@ -212,20 +228,18 @@ func (s *authService) Logout(tokenString string) error {
 	defer s.mu.Unlock()
 
 	// Find and remove token
-	authToken, exists := s.tokens[tokenString]
-	if !exists {
-		return errors.New("token not found")
+	authToken, err := s.tokens.Get(tokenString)
+	if err != nil {
+		return err
 	}
 
 	// Mark user as offline
-	if user, exists := s.users[authToken.UserID]; exists {
-		user.Online = false
+	if err := s.users.SetOnline(authToken.UserID, false); err != nil && !errors.Is(err, ErrUserNotFound) {
+		return fmt.Errorf("failed to update user: %w", err)
 	}
 
 	// Remove token
-	delete(s.tokens, tokenString)
-
-	return nil
+	return s.tokens.Delete(tokenString)
 }
 
 // GetUserByID retrieves a user by ID
@@ -233,10 +247,5 @@ func (s *authService) GetUserByID(userID string) (*User, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	user, exists := s.users[userID]
-	if !exists {
-		return nil, errors.New("user not found")
-	}
-
-	return user, nil
+	return s.users.GetByID(userID)
 }
\ No newline at end of file
@@ -0,0 +1,268 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"guandan-world/ai"
+	_ "guandan-world/ai/remote" // registers the process: algorithm
+	"guandan-world/sdk"
+)
+
+// DefaultBotAlgorithm is the ai algorithm used for bot seats that do not name one
+const DefaultBotAlgorithm = "smart"
+
+// Bot kinds that load something on the server: a weights file for smart and
+// neural bots, a command line for process bots. Clients name them as
+// "<kind>:<name>" and only bots registered with RegisterBot can be used, so
+// clients never choose the file the server reads or the command it runs.
+const (
+	BotKindSmart   = "smart"
+	BotKindNeural  = "neural"
+	BotKindProcess = "process"
+)
+
+// clientAlgorithms are the ai algorithms clients can name without registration
+var clientAlgorithms = map[string]bool{
+	"simple":       true,
+	"smart":        true,
+	"beginner":     true,
+	"intermediate": true,
+	"expert":       true,
+}
+
+var (
+	registeredBotsMu sync.RWMutex
+	registeredBots   = make(map[string]ai.AlgorithmFactory) // "<kind>:<name>" -> factory
+)
+
+// RegisterBot makes a server-side bot usable as "<kind>:<name>". arg is the
+// weights file of a smart or neural bot, or the command line of a process bot;
+// it is loaded once here, so a bad file or command fails at startup.
+func RegisterBot(kind, name, arg string) error {
+	switch kind {
+	case BotKindSmart, BotKindNeural, BotKindProcess:
+	default:
+		return fmt.Errorf("unknown bot kind: %s", kind)
+	}
+	if name == "" {
+		return fmt.Errorf("bot name is required")
+	}
+	factory, err := ai.NewAlgorithmFactory(kind + ":" + arg)
+	if err != nil {
+		return fmt.Errorf("failed to register %s bot %s: %w", kind, name, err)
+	}
+
+	registeredBotsMu.Lock()
+	defer registeredBotsMu.Unlock()
+	registeredBots[kind+":"+name] = factory
+	return nil
+}
+
+// registeredBot returns the factory of a bot registered with RegisterBot
+func registeredBot(name string) (ai.AlgorithmFactory, bool) {
+	registeredBotsMu.RLock()
+	defer registeredBotsMu.RUnlock()
+	factory, exists := registeredBots[name]
+	return factory, exists
+}
+
+// BotSpec describes the ai player of a bot seat
+type BotSpec struct {
+	// Algorithm is simple, smart, a difficulty name or a bot registered with
+	// RegisterBot as "<kind>:<name>"; empty uses DefaultBotAlgorithm
+	Algorithm string `json:"algorithm,omitempty"`
+	// Difficulty is beginner, intermediate or expert; it adds the noise and
+	// card memory of that level to the smart algorithm and cannot be combined
+	// with other algorithms
+	Difficulty string `json:"difficulty,omitempty"`
+}
+
+// NewBotAlgorithm creates the ai algorithm described by spec. Specs come from
+// clients, so names that would load a file or run a command on the server are
+// only accepted when registered.
+func NewBotAlgorithm(spec BotSpec) (ai.AutoPlayAlgorithm, error) {
+	name := spec.Algorithm
+	if name == "" {
+		name = DefaultBotAlgorithm
+	}
+	if spec.Difficulty == "" {
+		if factory, exists := registeredBot(name); exists {
+			return factory(2, 0), nil
+		}
+		if !clientAlgorithms[name] {
+			return nil, fmt.Errorf("unknown algorithm: %s", name)
+		}
+		return ai.NewAlgorithmByName(name, 2)
+	}
+
+	if name != DefaultBotAlgorithm {
+		return nil, fmt.Errorf("difficulty cannot be combined with the %s algorithm", name)
+	}
+	difficulty, err := ai.ParseDifficulty(spec.Difficulty)
+	if err != nil {
+		return nil, err
+	}
+	return ai.NewBotAutoPlayAlgorithmWithDifficulty(2, difficulty), nil
+}
+
+// MixedInputProvider implements sdk.PlayerInputProvider for a room where some
+// seats are played by bots. Bot seats are answered by their ai.AutoPlayAlgorithm;
+// every other seat is forwarded to the room's human input provider.
+// It is also an sdk.EventObserver so bots can follow the public game history.
+type MixedInputProvider struct {
+	humans     *RoomInputProvider
+	bots       map[int]ai.AutoPlayAlgorithm // seat -> algorithm
+	inferences map[int]*ai.HandInference    // seat -> hand inference, for algorithms that use it
+	tracker    *ai.ObservationTracker
+	mu         sync.RWMutex
+}
+
+// NewMixedInputProvider creates a provider that forwards every seat to humans until bots are set
+func NewMixedInputProvider(humans *RoomInputProvider) *MixedInputProvider {
+	return &MixedInputProvider{
+		humans:     humans,
+		bots:       make(map[int]ai.AutoPlayAlgorithm),
+		inferences: make(map[int]*ai.HandInference),
+		tracker:    ai.NewObservationTracker(),
+	}
+}
+
+// SetBot hands a seat to a bot algorithm
+func (mip *MixedInputProvider) SetBot(playerSeat int, algorithm ai.AutoPlayAlgorithm) {
+	mip.mu.Lock()
+	defer mip.mu.Unlock()
+
+	mip.bots[playerSeat] = algorithm
+	delete(mip.inferences, playerSeat)
+	if aware, ok := algorithm.(ai.InferenceAwareAlgorithm); ok {
+		inference := ai.NewHandInference(playerSeat, 2, nil)
+		mip.inferences[playerSeat] = inference
+		aware.SetHandInference(inference)
+	}
+	if aware, ok := algorithm.(ai.ObservationAwareAlgorithm); ok {
+		aware.SetObservationSource(playerSeat, mip.tracker)
+	}
+}
+
+// SetBotByName hands a seat to a bot created with ai.NewAlgorithmByName
+func (mip *MixedInputProvider) SetBotByName(playerSeat int, name string) error {
+	return mip.SetBotSpec(playerSeat, BotSpec{Algorithm: name})
+}
+
+// SetBotSpec hands a seat to a bot created with NewBotAlgorithm
+func (mip *MixedInputProvider) SetBotSpec(playerSeat int, spec BotSpec) error {
+	algorithm, err := NewBotAlgorithm(spec)
+	if err != nil {
+		return fmt.Errorf("invalid bot for seat %d: %w", playerSeat, err)
+	}
+	mip.SetBot(playerSeat, algorithm)
+	return nil
+}
+
+// IsBot reports whether a seat is played by a bot
+func (mip *MixedInputProvider) IsBot(playerSeat int) bool {
+	mip.mu.RLock()
+	defer mip.mu.RUnlock()
+	_, exists := mip.bots[playerSeat]
+	return exists
+}
+
+// bot returns the algorithm of a bot seat
+func (mip *MixedInputProvider) bot(playerSeat int) (ai.AutoPlayAlgorithm, *ai.HandInference, bool) {
+	mip.mu.RLock()
+	defer mip.mu.RUnlock()
+	algorithm, exists := mip.bots[playerSeat]
+	return algorithm, mip.inferences[playerSeat], exists
+}
+
+// OnGameEvent implements sdk.EventObserver, keeping the bots' view of the game current.
+// Algorithms that are event observers themselves, such as external process bots, get every event.
+func (mip *MixedInputProvider) OnGameEvent(event *sdk.GameEvent) {
+	mip.tracker.OnGameEvent(event)
+
+	mip.mu.RLock()
+	defer mip.mu.RUnlock()
+
+	if event.Type == sdk.EventDealStarted {
+		level := 2
+		if data, ok := event.Data.(map[string]interface{}); ok {
+			if l, ok := data["deal_level"].(int); ok {
+				level = l
+			}
+		}
+		for seat, inference := range mip.inferences {
+			inference.Reset(seat, level, nil)
+		}
+	}
+	for _, inference := range mip.inferences {
+		inference.OnGameEvent(event)
+	}
+	for _, algorithm := range mip.bots {
+		if observer, ok := algorithm.(sdk.EventObserver); ok {
+			observer.OnGameEvent(event)
+		}
+	}
+}
+
+// Close releases what the bots hold, such as external bot processes
+func (mip *MixedInputProvider) Close() {
+	mip.mu.RLock()
+	defer mip.mu.RUnlock()
+	for _, algorithm := range mip.bots {
+		if closer, ok := algorithm.(io.Closer); ok {
+			_ = closer.Close()
+		}
+	}
+}
+
+// RequestPlayDecision implements sdk.PlayerInputProvider
+func (mip *MixedInputProvider) RequestPlayDecision(ctx context.Context, playerSeat int, hand []*sdk.Card, trickInfo *sdk.TrickInfo) (*sdk.PlayDecision, error) {
+	algorithm, inference, isBot := mip.bot(playerSeat)
+	if !isBot {
+		return mip.humans.RequestPlayDecision(ctx, playerSeat, hand, trickInfo)
+	}
+
+	if inference != nil {
+		inference.SetHand(hand)
+	}
+	cards := algorithm.SelectCardsToPlay(hand, trickInfo)
+	if len(cards) == 0 {
+		return &sdk.PlayDecision{Action: sdk.ActionPass}, nil
+	}
+	return &sdk.PlayDecision{Action: sdk.ActionPlay, Cards: cards}, nil
+}
+
+// RequestTributeSelection implements sdk.PlayerInputProvider
+func (mip *MixedInputProvider) RequestTributeSelection(ctx context.Context, playerSeat int, options []*sdk.Card) (*sdk.Card, error) {
+	algorithm, _, isBot := mip.bot(playerSeat)
+	if !isBot {
+		return mip.humans.RequestTributeSelection(ctx, playerSeat, options)
+	}
+
+	if len(options) == 0 {
+		return nil, fmt.Errorf("no options available")
+	}
+	if selected := algorithm.SelectTributeCard(options, false); selected != nil {
+		return selected, nil
+	}
+	return options[0], nil
+}
+
+// RequestReturnTribute implements sdk.PlayerInputProvider
+func (mip *MixedInputProvider) RequestReturnTribute(ctx context.Context, playerSeat int, hand []*sdk.Card) (*sdk.Card, error) {
+	algorithm, _, isBot := mip.bot(playerSeat)
+	if !isBot {
+		return mip.humans.RequestReturnTribute(ctx, playerSeat, hand)
+	}
+
+	if len(hand) == 0 {
+		return nil, fmt.Errorf("no cards available")
+	}
+	if selected := algorithm.SelectReturnTributeCard(hand, nil); selected != nil {
+		return selected, nil
+	}
+	return hand[0], nil
+}
@@ -0,0 +1,143 @@
+package game
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"guandan-world/ai"
+	"guandan-world/ai/remote"
+	"guandan-world/sdk"
+)
+
+func TestMixedInputProvider_RoutesSeats(t *testing.T) {
+	wsManager := NewMockDriverWSManager()
+	mixed := NewMixedInputProvider(NewRoomInputProvider("test-room-mixed", wsManager))
+	if err := mixed.SetBotByName(1, ""); err != nil {
+		t.Fatalf("Failed to set bot: %v", err)
+	}
+	if err := mixed.SetBotByName(2, "no-such-bot"); err == nil {
+		t.Error("Expected error for an unknown bot algorithm")
+	}
+	if !mixed.IsBot(1) || mixed.IsBot(0) || mixed.IsBot(2) {
+		t.Fatal("Unexpected bot seats")
+	}
+
+	hand := []*sdk.Card{mustNewCard(t, 3, "Spade", 2), mustNewCard(t, 5, "Heart", 2)}
+	leader := &sdk.TrickInfo{IsLeader: true}
+
+	// Bots answer immediately without messaging the room
+	decision, err := mixed.RequestPlayDecision(context.Background(), 1, hand, leader)
+	if err != nil || decision.Action != sdk.ActionPlay || len(decision.Cards) == 0 {
+		t.Errorf("Expected the bot to lead, got %+v (%v)", decision, err)
+	}
+	if len(wsManager.GetBroadcasts("test-room-mixed")) != 0 {
+		t.Error("Expected no messages for a bot seat")
+	}
+
+	// Human seats are forwarded to the room provider, which asks the room
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := mixed.RequestPlayDecision(ctx, 0, hand, leader); err != nil {
+		t.Errorf("Expected the timeout default for a human seat, got %v", err)
+	}
+	if len(wsManager.GetBroadcasts("test-room-mixed")) != 1 {
+		t.Error("Expected a play request to be sent for the human seat")
+	}
+}
+
+func TestNewBotAlgorithm(t *testing.T) {
+	if _, ok := mustBotAlgorithm(t, BotSpec{Difficulty: "beginner"}).(*ai.BotAutoPlayAlgorithm); !ok {
+		t.Error("Expected a difficulty to select the bot algorithm")
+	}
+	if _, ok := mustBotAlgorithm(t, BotSpec{Algorithm: "simple"}).(*ai.SimpleAutoPlayAlgorithm); !ok {
+		t.Error("Expected the named algorithm")
+	}
+	if _, err := NewBotAlgorithm(BotSpec{Difficulty: "grandmaster"}); err == nil {
+		t.Error("Expected error for an unknown difficulty")
+	}
+	if _, err := NewBotAlgorithm(BotSpec{Algorithm: "simple", Difficulty: "expert"}); err == nil {
+		t.Error("Expected error for a difficulty on a non-smart bot")
+	}
+}
+
+func TestNewBotAlgorithm_RegisteredBots(t *testing.T) {
+	// Clients name a registered bot; files and commands are never taken from the spec
+	weights := filepath.Join(t.TempDir(), "weights.json")
+	if err := ai.DefaultSmartWeights().Save(weights); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{
+		"smart:" + weights,
+		"neural:" + weights,
+		"process:sh",
+		"smart:unregistered",
+		"unknown",
+	} {
+		if _, err := NewBotAlgorithm(BotSpec{Algorithm: name}); err == nil {
+			t.Errorf("Expected %q to be rejected", name)
+		}
+	}
+
+	if err := RegisterBot(BotKindSmart, "tuned", weights); err != nil {
+		t.Fatalf("Failed to register smart bot: %v", err)
+	}
+	if _, ok := mustBotAlgorithm(t, BotSpec{Algorithm: "smart:tuned"}).(*ai.SmartAutoPlayAlgorithm); !ok {
+		t.Error("Expected a registered smart bot to use its weights")
+	}
+	if err := RegisterBot(BotKindProcess, "test-echo", "sh"); err != nil {
+		t.Fatalf("Failed to register process bot: %v", err)
+	}
+	if _, ok := mustBotAlgorithm(t, BotSpec{Algorithm: "process:test-echo"}).(*remote.ProcessAlgorithm); !ok {
+		t.Error("Expected a registered external bot to use a process algorithm")
+	}
+
+	if err := RegisterBot(BotKindNeural, "missing", filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected a missing weights file to fail at registration")
+	}
+	if err := RegisterBot("simple", "plain", ""); err == nil {
+		t.Error("Expected error for a kind that takes no server argument")
+	}
+}
+
+func mustBotAlgorithm(t *testing.T, spec BotSpec) ai.AutoPlayAlgorithm {
+	t.Helper()
+	algorithm, err := NewBotAlgorithm(spec)
+	if err != nil {
+		t.Fatalf("Failed to create bot %+v: %v", spec, err)
+	}
+	return algorithm
+}
+
+func TestDriverService_BotSeats(t *testing.T) {
+	service := NewDriverService(NewMockDriverWSManager())
+	players := []sdk.Player{
+		{ID: "player1", Username: "Alice", Seat: 0},
+		{ID: "bot_1", Username: "Bot 2", Seat: 1},
+		{ID: "player3", Username: "Charlie", Seat: 2},
+		{ID: "bot_3", Username: "Bot 4", Seat: 3},
+	}
+	bots := map[int]BotSpec{1: {}, 3: {Algorithm: "simple"}}
+
+	if err := service.StartGameWithOptions("test-room-bots-ranked", players, GameOptions{Ranked: true, Bots: bots}); err == nil {
+		t.Error("Expected error for a ranked match with bots")
+	}
+	if err := service.StartGameWithOptions("test-room-bots-bad", players, GameOptions{Bots: map[int]BotSpec{4: {}}}); err == nil {
+		t.Error("Expected error for an invalid bot seat")
+	}
+	if err := service.StartGameWithOptions("test-room-bots-bad", players, GameOptions{Bots: map[int]BotSpec{1: {Algorithm: "simple", Difficulty: "expert"}}}); err == nil {
+		t.Error("Expected error for a difficulty on a non-smart bot")
+	}
+
+	roomID := "test-room-bots"
+	if err := service.StartGameWithOptions(roomID, players, GameOptions{Bots: bots}); err != nil {
+		t.Fatalf("Failed to start game with bots: %v", err)
+	}
+	defer service.StopGame(roomID)
+
+	status, err := service.GetGameStatus(roomID)
+	if err != nil || status["ranked"] != false {
+		t.Errorf("Expected a casual game with bots, got %v (%v)", status, err)
+	}
+}
@@ -2,15 +2,49 @@ package game
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"sync"
 	"time"
 
+	"guandan-world/backend/match"
+	"guandan-world/backend/rating"
 	"guandan-world/backend/websocket"
 	"guandan-world/sdk"
 )
 
+var (
+	// ErrHintsDisabled is returned when hints are requested in a match played without them
+	ErrHintsDisabled = errors.New("hints are disabled in this match")
+	// ErrPlayerNotSeated is returned when a player acts on a game they are not seated in
+	ErrPlayerNotSeated = errors.New("player is not seated in this game")
+)
+
+// GameOptions customizes a match started by the driver service
+type GameOptions struct {
+	// Ranked matches update player ratings when they finish. They are always
+	// played to the end under the full rules, and hints are unavailable.
+	Ranked bool `json:"ranked"`
+
+	// Bots maps seats played by the server to the ai player of the seat
+	Bots map[int]BotSpec `json:"bots,omitempty"`
+
+	// Rules of a casual match; zero values play the standard match to A
+	Variant        sdk.RuleVariant `json:"variant,omitempty"`
+	StartingLevels [2]int          `json:"starting_levels,omitempty"` // Zero starts both teams at 2
+	MaxDeals       int             `json:"max_deals,omitempty"`       // Stop after this many deals
+	TimeLimit      time.Duration   `json:"time_limit,omitempty"`      // Stop after the deal in progress when exceeded
+
+	// Time players have to decide; zero uses the SDK defaults
+	TurnTimeout    time.Duration `json:"turn_timeout,omitempty"`
+	TributeTimeout time.Duration `json:"tribute_timeout,omitempty"`
+
+	// NoHints turns hints off; ranked matches never have hints
+	NoHints bool `json:"no_hints,omitempty"`
+}
+
 // DriverService provides complete game management using SDK's GameDriver
 // This service encapsulates the full game flow including input handling and event observation
 type DriverService struct {
@@ -20,9 +54,21 @@ type DriverService struct {
 	// Input providers for each room
 	providers map[string]*RoomInputProvider
 
+	// Options each room's match was started with
+	options map[string]GameOptions
+
+	// Players each room's match was started with
+	players map[string][]sdk.Player
+
 	// WebSocket manager for real-time communication
 	wsManager WSManagerInterface
 
+	// Archive of finished matches
+	archive match.Archive
+
+	// Ratings updated by ranked matches; nil disables rating updates
+	ratings rating.Service
+
 	// Synchronization
 	mu sync.RWMutex
 }
@@ -32,12 +78,41 @@ func NewDriverService(wsManager WSManagerInterface) *DriverService {
 	return &DriverService{
 		drivers:   make(map[string]*sdk.GameDriver),
 		providers: make(map[string]*RoomInputProvider),
+		options:   make(map[string]GameOptions),
+		players:   make(map[string][]sdk.Player),
 		wsManager: wsManager,
+		archive:   match.NewMemoryArchive(),
 	}
 }
 
-// StartGameWithDriver starts a new game using the GameDriver architecture
+// SetMatchArchive sets where finished matches are archived
+func (ds *DriverService) SetMatchArchive(archive match.Archive) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.archive = archive
+}
+
+// MatchArchive returns the archive of finished matches
+func (ds *DriverService) MatchArchive() match.Archive {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	return ds.archive
+}
+
+// SetRatingService sets the service that ranked matches update
+func (ds *DriverService) SetRatingService(ratings rating.Service) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.ratings = ratings
+}
+
+// StartGameWithDriver starts a new casual game using the GameDriver architecture
 func (ds *DriverService) StartGameWithDriver(roomID string, players []sdk.Player) error {
+	return ds.StartGameWithOptions(roomID, players, GameOptions{})
+}
+
+// StartGameWithOptions starts a new game using the GameDriver architecture
+func (ds *DriverService) StartGameWithOptions(roomID string, players []sdk.Player, options GameOptions) error {
 	ds.mu.Lock()
 	defer ds.mu.Unlock()
 
@@ -55,29 +130,87 @@ func (ds *DriverService) StartGameWithDriver(roomID string, players []sdk.Player
 		return fmt.Errorf("game already exists for room %s", roomID)
 	}
 
+	// Ratings are per account, so a ranked match needs four distinct accounts
+	if options.Ranked && len(options.Bots) > 0 {
+		return fmt.Errorf("ranked matches cannot include bots")
+	}
+	if options.Ranked {
+		seen := make(map[string]bool)
+		for _, player := range players {
+			if player.ID == "" || seen[player.ID] {
+				return fmt.Errorf("ranked matches require 4 distinct players")
+			}
+			seen[player.ID] = true
+		}
+	}
+
+	// Ranked matches are always played to the end under the standard rules
+	if options.Ranked {
+		options.Variant = sdk.RuleVariantStandard
+		options.StartingLevels = [2]int{}
+		options.MaxDeals = 0
+		options.TimeLimit = 0
+		options.NoHints = true
+	}
+
 	// Create game engine
 	engine := sdk.NewGameEngine()
+	if err := engine.SetVariant(options.Variant); err != nil {
+		return err
+	}
+	if options.StartingLevels != [2]int{} {
+		if err := engine.SetStartingLevels(options.StartingLevels); err != nil {
+			return err
+		}
+	}
 
-	// Create game driver with shorter timeouts for testing
+	// Create game driver
 	config := sdk.DefaultGameDriverConfig()
-	config.PlayDecisionTimeout = 1 * time.Second // Short timeout for tests
-	config.TributeTimeout = 1 * time.Second
+	if options.TurnTimeout > 0 {
+		config.PlayDecisionTimeout = options.TurnTimeout
+	}
+	if options.TributeTimeout > 0 {
+		config.TributeTimeout = options.TributeTimeout
+	}
+	config.MaxDeals = options.MaxDeals
+	config.MaxDuration = options.TimeLimit
 	driver := sdk.NewGameDriver(engine, config)
 
-	// Create and set input provider for this room
+	// Create and set input provider for this room; bot seats are answered by ai algorithms
 	provider := NewRoomInputProvider(roomID, ds.wsManager)
-	driver.SetInputProvider(provider)
+	mixed := NewMixedInputProvider(provider)
+	for seat, spec := range options.Bots {
+		if seat < 0 || seat > 3 {
+			mixed.Close()
+			return fmt.Errorf("invalid bot seat %d", seat)
+		}
+		if err := mixed.SetBotSpec(seat, spec); err != nil {
+			// Release the bots already created for earlier seats
+			mixed.Close()
+			return err
+		}
+	}
+	driver.SetInputProvider(mixed)
+	driver.AddObserver(mixed)
 
 	// Add WebSocket observer for real-time events
 	observer := NewWebSocketObserver(roomID, ds.wsManager)
 	driver.AddObserver(observer)
 
+	// Record every deal for the match archive
+	recorder := NewMatchRecorder()
+	driver.AddObserver(recorder)
+
 	// Store driver and provider
 	ds.drivers[roomID] = driver
 	ds.providers[roomID] = provider
+	ds.options[roomID] = options
+	ds.players[roomID] = append([]sdk.Player(nil), players...)
 
 	// Start the match in a goroutine
+	startedAt := time.Now()
 	go func() {
+		defer mixed.Close()
 		log.Printf("Starting match for room %s with GameDriver", roomID)
 
 		result, err := driver.RunMatch(players)
@@ -95,18 +228,64 @@ func (ds *DriverService) StartGameWithDriver(roomID string, players []sdk.Player
 		} else {
 			log.Printf("Match completed for room %s, winner: team %d", roomID, result.Winner)
 			// Match completed event is already sent by the observer
+			if err := ds.archiveMatch(roomID, players, options, startedAt, result, recorder.Deals()); err != nil {
+				log.Printf("Failed to archive match for room %s: %v", roomID, err)
+			}
 		}
 
 		// Clean up after match
 		ds.mu.Lock()
 		delete(ds.drivers, roomID)
 		delete(ds.providers, roomID)
+		delete(ds.options, roomID)
+		delete(ds.players, roomID)
 		ds.mu.Unlock()
 	}()
 
 	return nil
 }
 
+// archiveMatch stores a finished match in the archive and, for ranked
+// matches, updates the players' ratings
+func (ds *DriverService) archiveMatch(roomID string, players []sdk.Player, options GameOptions, startedAt time.Time, result *sdk.GameDriverResult, deals []*match.DealRecord) error {
+	if result == nil || result.MatchResult == nil {
+		return fmt.Errorf("match result is missing")
+	}
+	record := &match.Record{
+		ID:          fmt.Sprintf("match_%d", time.Now().UnixNano()),
+		RoomID:      roomID,
+		Ranked:      options.Ranked,
+		Winner:      result.Winner,
+		FinalLevels: result.FinalLevels,
+		StartedAt:   startedAt,
+		FinishedAt:  time.Now(),
+		Result:      result.MatchResult,
+		Deals:       deals,
+	}
+	for _, player := range players {
+		_, isBot := options.Bots[player.Seat]
+		record.Players = append(record.Players, match.Player{
+			ID:       player.ID,
+			Username: player.Username,
+			Seat:     player.Seat,
+			Bot:      isBot,
+		})
+	}
+	if err := ds.MatchArchive().Save(record); err != nil {
+		return err
+	}
+
+	ds.mu.RLock()
+	ratings := ds.ratings
+	ds.mu.RUnlock()
+	if record.Ranked && ratings != nil {
+		if _, err := ratings.RecordMatch(record); err != nil {
+			return fmt.Errorf("failed to update ratings: %w", err)
+		}
+	}
+	return nil
+}
+
 // SubmitPlayDecision submits a player's play decision to the driver
 func (ds *DriverService) SubmitPlayDecision(roomID string, playerSeat int, decision *sdk.PlayDecision) error {
 	ds.mu.RLock()
@@ -183,8 +362,14 @@ func (ds *DriverService) GetGameStatus(roomID string) (map[string]interface{}, e
 	turnInfo := engine.GetCurrentTurnInfo()
 	matchDetails := engine.GetMatchDetails()
 
+	ds.mu.RLock()
+	options := ds.options[roomID]
+	ds.mu.RUnlock()
+
 	status := map[string]interface{}{
 		"room_id":     roomID,
+		"ranked":      options.Ranked,
+		"hints":       !options.NoHints,
 		"game_status": gameState.Status,
 		"deal_status": dealStatus,
 		"timestamp":   time.Now(),
@@ -201,6 +386,147 @@ func (ds *DriverService) GetGameStatus(roomID string) (map[string]interface{}, e
 	return status, nil
 }
 
+// PlayInterpretation describes one way a set of cards can be played when wildcards are involved
+type PlayInterpretation struct {
+	Type          string   `json:"type"`                     // Combination type, e.g. "FullHouse"
+	SubstituteIDs []string `json:"substitute_ids,omitempty"` // Card IDs the wildcards stand for; empty means let the engine decide
+	Default       bool     `json:"default"`                  // Whether this is what the engine picks without a declaration
+}
+
+// GetPlayInterpretations lists every valid interpretation of the given cards for the current trick
+func (ds *DriverService) GetPlayInterpretations(roomID string, cardIDs []string) ([]PlayInterpretation, error) {
+	engine, err := ds.getEngine(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := engine.GetGameState()
+	if state == nil || state.CurrentMatch == nil || state.CurrentMatch.CurrentDeal == nil {
+		return nil, fmt.Errorf("no active deal for room %s", roomID)
+	}
+	level := state.CurrentMatch.CurrentDeal.Level
+
+	cards := make([]*sdk.Card, len(cardIDs))
+	for i, cardID := range cardIDs {
+		card, err := sdk.ParseCardFromID(cardID, level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid card ID %s: %w", cardID, err)
+		}
+		cards[i] = card
+	}
+
+	var lead sdk.CardComp
+	if turnInfo := engine.GetCurrentTurnInfo(); turnInfo != nil {
+		lead = turnInfo.LeadComp
+	}
+
+	interpretations := sdk.InterpretCards(cards, lead)
+	result := make([]PlayInterpretation, 0, len(interpretations))
+	for i, interpretation := range interpretations {
+		item := PlayInterpretation{
+			Type:    interpretation.Comp.GetType().String(),
+			Default: i == 0,
+		}
+		for _, substitute := range interpretation.Substitutes {
+			item.SubstituteIDs = append(item.SubstituteIDs, substitute.GetID())
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// GetHints returns ranked play suggestions for the player on turn
+func (ds *DriverService) GetHints(roomID string, playerSeat int) (*sdk.HintResult, error) {
+	if ds.hintsDisabled(roomID) {
+		return nil, ErrHintsDisabled
+	}
+	engine, err := ds.getEngine(roomID)
+	if err != nil {
+		return nil, err
+	}
+	return engine.GetHints(playerSeat)
+}
+
+// GetPlayerHints returns the hints of the seat held by playerID, so that a
+// player can only ever see suggestions computed from their own hand
+func (ds *DriverService) GetPlayerHints(roomID, playerID string) (*sdk.HintResult, error) {
+	playerSeat, err := ds.PlayerSeat(roomID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	return ds.GetHints(roomID, playerSeat)
+}
+
+// PlayerSeat returns the seat playerID holds in the room's game
+func (ds *DriverService) PlayerSeat(roomID, playerID string) (int, error) {
+	ds.mu.RLock()
+	players, exists := ds.players[roomID]
+	ds.mu.RUnlock()
+
+	if !exists {
+		return -1, fmt.Errorf("no active game for room %s", roomID)
+	}
+	for _, player := range players {
+		if player.ID == playerID {
+			return player.Seat, nil
+		}
+	}
+	return -1, fmt.Errorf("%w: player %s in room %s", ErrPlayerNotSeated, playerID, roomID)
+}
+
+// HandleGetHints answers a get_hints WebSocket request with a hints message
+// It is registered on the WebSocket manager as the handler for websocket.MSG_GET_HINTS
+func (ds *DriverService) HandleGetHints(conn *websocket.WSConnection, message *websocket.WSMessage) error {
+	var data websocket.GetHintsData
+	if message.Data != nil {
+		if err := message.DecodeData(&data); err != nil {
+			return fmt.Errorf("invalid get hints data: %w", err)
+		}
+	}
+	roomID := data.RoomID
+	if roomID == "" {
+		roomID = conn.RoomID()
+	}
+	if roomID == "" {
+		return fmt.Errorf("player is not in a room")
+	}
+
+	result, err := ds.GetPlayerHints(roomID, conn.PlayerID())
+	if err != nil {
+		return err
+	}
+
+	return ds.wsManager.SendToPlayer(conn.PlayerID(), &websocket.WSMessage{
+		Type:      websocket.MSG_HINTS,
+		Data:      result,
+		Timestamp: time.Now(),
+	})
+}
+
+// hintsDisabled reports whether the room's match is played without hints
+func (ds *DriverService) hintsDisabled(roomID string) bool {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	return ds.options[roomID].NoHints
+}
+
+// getEngine returns the game engine driving the given room
+func (ds *DriverService) getEngine(roomID string) (sdk.GameEngineInterface, error) {
+	ds.mu.RLock()
+	driver, exists := ds.drivers[roomID]
+	ds.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("no active game for room %s", roomID)
+	}
+
+	engine := driver.GetEngine()
+	if engine == nil {
+		return nil, fmt.Errorf("no game engine for room %s", roomID)
+	}
+	return engine, nil
+}
+
 // StopGame stops the game for a room
 func (ds *DriverService) StopGame(roomID string) error {
 	ds.mu.Lock()
@@ -219,6 +545,8 @@ func (ds *DriverService) StopGame(roomID string) error {
 	// Clean up
 	delete(ds.drivers, roomID)
 	delete(ds.providers, roomID)
+	delete(ds.options, roomID)
+	delete(ds.players, roomID)
 
 	// Notify clients
 	ds.wsManager.BroadcastToRoom(roomID, &websocket.WSMessage{
@@ -302,7 +630,7 @@ func (rip *RoomInputProvider) RequestPlayDecision(ctx context.Context, playerSea
 			"player_seat": playerSeat,
 			"hand":        hand,
 			"trick_info":  trickInfo,
-			"timeout":     30, // seconds
+			"timeout":     timeoutSeconds(ctx, 30),
 		},
 		Timestamp: time.Now(),
 	}
@@ -348,6 +676,19 @@ func (rip *RoomInputProvider) RequestPlayDecision(ctx context.Context, playerSea
 	}
 }
 
+// timeoutSeconds is how many seconds are left before ctx expires, for
+// telling players how long they have to decide
+func timeoutSeconds(ctx context.Context, fallback int) int {
+	if ctx == nil {
+		return fallback
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return fallback
+	}
+	return int(math.Ceil(time.Until(deadline).Seconds()))
+}
+
 // RequestTributeSelection implements sdk.PlayerInputProvider
 func (rip *RoomInputProvider) RequestTributeSelection(ctx context.Context, playerSeat int, options []*sdk.Card) (*sdk.Card, error) {
 	// Store options for lookup
@@ -370,7 +711,7 @@ func (rip *RoomInputProvider) RequestTributeSelection(ctx context.Context, playe
 			"action_type": "tribute_selection_required",
 			"player_seat": playerSeat,
 			"options":     options,
-			"timeout":     20, // seconds
+			"timeout":     timeoutSeconds(ctx, 20),
 		},
 		Timestamp: time.Now(),
 	}
@@ -427,7 +768,7 @@ func (rip *RoomInputProvider) RequestReturnTribute(ctx context.Context, playerSe
 			"action_type": "return_tribute_required",
 			"player_seat": playerSeat,
 			"hand":        hand,
-			"timeout":     20, // seconds
+			"timeout":     timeoutSeconds(ctx, 20),
 		},
 		Timestamp: time.Now(),
 	}
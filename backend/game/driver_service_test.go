@@ -2,9 +2,12 @@ package game
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"guandan-world/backend/match"
+	"guandan-world/backend/rating"
 	"guandan-world/backend/websocket"
 	"guandan-world/sdk"
 )
@@ -138,6 +141,239 @@ func TestDriverService_SubmitPlayDecision(t *testing.T) {
 	// to request input, which would need more complex test setup
 }
 
+func TestDriverService_GetPlayInterpretations(t *testing.T) {
+	wsManager := NewMockDriverWSManager()
+	service := NewDriverService(wsManager)
+	
+	players := []sdk.Player{
+		{ID: "player1", Username: "Alice", Seat: 0},
+		{ID: "player2", Username: "Bob", Seat: 1},
+		{ID: "player3", Username: "Charlie", Seat: 2},
+		{ID: "player4", Username: "David", Seat: 3},
+	}
+	
+	roomID := "test-room-interpretations"
+	if err := service.StartGameWithDriver(roomID, players); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	defer service.StopGame(roomID)
+	
+	// The first deal is played at level 2, so Heart_2 is the wildcard
+	cardIDs := []string{"Spade_6", "Club_6", "Spade_7", "Diamond_7", "Heart_2"}
+	var interpretations []PlayInterpretation
+	var err error
+	for i := 0; i < 20; i++ {
+		interpretations, err = service.GetPlayInterpretations(roomID, cardIDs)
+		if err == nil {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("Failed to get interpretations: %v", err)
+	}
+	
+	if len(interpretations) != 2 {
+		t.Fatalf("Expected 2 interpretations, got %+v", interpretations)
+	}
+	for i, interpretation := range interpretations {
+		if interpretation.Type != sdk.TypeFullHouse.String() {
+			t.Errorf("Expected full house, got %s", interpretation.Type)
+		}
+		if len(interpretation.SubstituteIDs) != 1 {
+			t.Errorf("Expected one substitute, got %v", interpretation.SubstituteIDs)
+		}
+		if interpretation.Default != (i == 0) {
+			t.Errorf("Only the first interpretation should be the default, got %+v", interpretations)
+		}
+	}
+	
+	if _, err := service.GetPlayInterpretations("non-existent-room", cardIDs); err == nil {
+		t.Error("Expected error for non-existent room")
+	}
+	if _, err := service.GetPlayInterpretations(roomID, []string{"bad"}); err == nil {
+		t.Error("Expected error for invalid card ID")
+	}
+}
+
+func TestDriverService_GetHints(t *testing.T) {
+	wsManager := NewMockDriverWSManager()
+	service := NewDriverService(wsManager)
+	
+	players := []sdk.Player{
+		{ID: "player1", Username: "Alice", Seat: 0},
+		{ID: "player2", Username: "Bob", Seat: 1},
+		{ID: "player3", Username: "Charlie", Seat: 2},
+		{ID: "player4", Username: "David", Seat: 3},
+	}
+	
+	roomID := "test-room-hints"
+	if err := service.StartGameWithDriver(roomID, players); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	defer service.StopGame(roomID)
+	
+	// Wait for the first trick to start
+	var turn *sdk.TurnInfo
+	for i := 0; i < 20; i++ {
+		engine, err := service.getEngine(roomID)
+		if err != nil {
+			t.Fatalf("Failed to get engine: %v", err)
+		}
+		if turn = engine.GetCurrentTurnInfo(); turn != nil && turn.HasActiveTrick {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if turn == nil || !turn.HasActiveTrick {
+		t.Fatal("Expected an active trick")
+	}
+	
+	result, err := service.GetHints(roomID, turn.CurrentPlayer)
+	if err != nil {
+		t.Fatalf("Failed to get hints: %v", err)
+	}
+	if !result.IsLeader || !result.HasLegalPlay || len(result.Hints) == 0 {
+		t.Errorf("Expected the leader to have hints, got %+v", result)
+	}
+	
+	if _, err := service.GetHints(roomID, (turn.CurrentPlayer+1)%4); err == nil {
+		t.Error("Expected error for a player who is not on turn")
+	}
+	if _, err := service.GetPlayerHints(roomID, players[turn.CurrentPlayer].ID); err != nil {
+		t.Errorf("Expected the player on turn to get their hints: %v", err)
+	}
+	if _, err := service.GetPlayerHints(roomID, "outsider"); !errors.Is(err, ErrPlayerNotSeated) {
+		t.Errorf("Expected ErrPlayerNotSeated, got %v", err)
+	}
+	if _, err := service.GetHints("non-existent-room", 0); err == nil {
+		t.Error("Expected error for non-existent room")
+	}
+}
+
+func TestDriverService_ArchiveMatch(t *testing.T) {
+	service := NewDriverService(NewMockDriverWSManager())
+	archive := match.NewMemoryArchive()
+	service.SetMatchArchive(archive)
+
+	players := []sdk.Player{
+		{ID: "player1", Username: "Alice", Seat: 0},
+		{ID: "player2", Username: "Bob", Seat: 1},
+		{ID: "player3", Username: "Charlie", Seat: 2},
+		{ID: "player4", Username: "David", Seat: 3},
+	}
+	result := &sdk.GameDriverResult{
+		MatchResult: &sdk.MatchResult{Winner: 1, FinalLevels: [2]int{9, 14}},
+	}
+	if err := service.archiveMatch("test-room-archive", players, GameOptions{}, time.Now(), result, nil); err != nil {
+		t.Fatalf("Failed to archive match: %v", err)
+	}
+	
+	records, err := archive.List("player2", 0, 0)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Expected one archived match, got %v (%v)", records, err)
+	}
+	record := records[0]
+	if record.RoomID != "test-room-archive" || record.Winner != 1 || record.FinalLevels != [2]int{9, 14} {
+		t.Errorf("Unexpected record %+v", record)
+	}
+	if len(record.Players) != 4 || record.Players[1].Username != "Bob" || record.Players[1].Seat != 1 {
+		t.Errorf("Unexpected players %+v", record.Players)
+	}
+	
+	if err := service.archiveMatch("test-room-archive", players, GameOptions{}, time.Now(), &sdk.GameDriverResult{}, nil); err == nil {
+		t.Error("Expected error for a missing match result")
+	}
+}
+
+func TestDriverService_ArchiveBackfilledMatch(t *testing.T) {
+	service := NewDriverService(NewMockDriverWSManager())
+	archive := match.NewMemoryArchive()
+	service.SetMatchArchive(archive)
+
+	// Matchmaking backfill seats bots under room-specific IDs
+	players := []sdk.Player{
+		{ID: "player1", Username: "Alice", Seat: 0},
+		{ID: "bot_room_1", Username: "Bot 2", Seat: 1},
+		{ID: "player3", Username: "Charlie", Seat: 2},
+		{ID: "bot_room_3", Username: "Bot 4", Seat: 3},
+	}
+	options := GameOptions{Bots: map[int]BotSpec{1: {}, 3: {}}}
+	result := &sdk.GameDriverResult{MatchResult: &sdk.MatchResult{Winner: 0}}
+	if err := service.archiveMatch("room", players, options, time.Now(), result, nil); err != nil {
+		t.Fatalf("Failed to archive match: %v", err)
+	}
+
+	records, err := archive.List("player1", 0, 0)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Expected one archived match, got %v (%v)", records, err)
+	}
+	for _, player := range records[0].Players {
+		if player.Bot != (player.Seat%2 == 1) {
+			t.Errorf("Unexpected bot flag for %+v", player)
+		}
+	}
+}
+
+func TestDriverService_RankedMatch(t *testing.T) {
+	service := NewDriverService(NewMockDriverWSManager())
+	ratings := rating.NewService(rating.NewMemoryRepository())
+	service.SetRatingService(ratings)
+
+	players := []sdk.Player{
+		{ID: "player1", Username: "Alice", Seat: 0},
+		{ID: "player2", Username: "Bob", Seat: 1},
+		{ID: "player3", Username: "Charlie", Seat: 2},
+		{ID: "player4", Username: "David", Seat: 3},
+	}
+	ranked := GameOptions{Ranked: true}
+
+	duplicated := append([]sdk.Player{}, players...)
+	duplicated[3].ID = "player1"
+	if err := service.StartGameWithOptions("test-room-ranked-dup", duplicated, ranked); err == nil {
+		t.Error("Expected error for a ranked match with a repeated player")
+	}
+
+	roomID := "test-room-ranked"
+	if err := service.StartGameWithOptions(roomID, players, ranked); err != nil {
+		t.Fatalf("Failed to start ranked game: %v", err)
+	}
+	defer service.StopGame(roomID)
+
+	status, err := service.GetGameStatus(roomID)
+	if err != nil || status["ranked"] != true {
+		t.Errorf("Expected ranked status, got %v (%v)", status, err)
+	}
+	if _, err := service.GetHints(roomID, 0); err != ErrHintsDisabled {
+		t.Errorf("Expected hints to be disabled, got %v", err)
+	}
+
+	// Finishing a ranked match archives it and updates every player's rating
+	result := &sdk.GameDriverResult{
+		MatchResult: &sdk.MatchResult{Winner: 0, FinalLevels: [2]int{14, 8}},
+	}
+	if err := service.archiveMatch(roomID, players, ranked, time.Now(), result, nil); err != nil {
+		t.Fatalf("Failed to archive ranked match: %v", err)
+	}
+	records, _ := service.MatchArchive().List("player1", 0, 0)
+	if len(records) != 1 || !records[0].Ranked {
+		t.Fatalf("Expected one ranked record, got %v", records)
+	}
+	winner, _ := ratings.GetRating("player1")
+	loser, _ := ratings.GetRating("player2")
+	if winner.Matches != 1 || loser.Matches != 1 || winner.Mu <= loser.Mu {
+		t.Errorf("Unexpected ratings after ranked match: %+v %+v", winner, loser)
+	}
+
+	// Casual matches leave ratings alone
+	if err := service.archiveMatch("test-room-casual", players, GameOptions{}, time.Now(), result, nil); err != nil {
+		t.Fatalf("Failed to archive casual match: %v", err)
+	}
+	if history, _ := ratings.History("player1", 0); len(history) != 1 {
+		t.Errorf("Expected one rating change, got %d", len(history))
+	}
+}
+
 func TestDriverService_StopGame(t *testing.T) {
 	// Create mock WebSocket manager
 	wsManager := NewMockDriverWSManager()
@@ -302,4 +538,65 @@ func mustNewCard(t *testing.T, number int, color string, level int) *sdk.Card {
 		t.Fatalf("Failed to create card: %v", err)
 	}
 	return card
-}
\ No newline at end of file
+}
+func TestDriverService_GameOptions(t *testing.T) {
+	service := NewDriverService(NewMockDriverWSManager())
+	players := []sdk.Player{
+		{ID: "player1", Username: "Alice", Seat: 0},
+		{ID: "player2", Username: "Bob", Seat: 1},
+		{ID: "player3", Username: "Charlie", Seat: 2},
+		{ID: "player4", Username: "David", Seat: 3},
+	}
+
+	if err := service.StartGameWithOptions("test-room-options-bad", players, GameOptions{StartingLevels: [2]int{2, 14}}); err == nil {
+		t.Error("Expected error for an invalid starting level")
+	}
+	if err := service.StartGameWithOptions("test-room-options-bad", players, GameOptions{Variant: "no_such_variant"}); err == nil {
+		t.Error("Expected error for an unknown variant")
+	}
+
+	roomID := "test-room-options"
+	options := GameOptions{
+		Variant:        sdk.RuleVariantNoTribute,
+		StartingLevels: [2]int{5, 3},
+		MaxDeals:       3,
+		TurnTimeout:    45 * time.Second,
+		NoHints:        true,
+	}
+	if err := service.StartGameWithOptions(roomID, players, options); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	defer service.StopGame(roomID)
+
+	engine, err := service.getEngine(roomID)
+	if err != nil {
+		t.Fatalf("Failed to get engine: %v", err)
+	}
+	var details *sdk.MatchDetails
+	for i := 0; i < 20 && details == nil; i++ {
+		details = engine.GetMatchDetails()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if details == nil || details.TeamLevels != [2]int{5, 3} {
+		t.Errorf("Expected the match to start at levels 5 and 3, got %+v", details)
+	}
+
+	status, err := service.GetGameStatus(roomID)
+	if err != nil || status["hints"] != false {
+		t.Errorf("Expected hints to be off, got %v (%v)", status, err)
+	}
+	if _, err := service.GetHints(roomID, 0); err != ErrHintsDisabled {
+		t.Errorf("Expected hints to be disabled, got %v", err)
+	}
+}
+
+func TestTimeoutSeconds(t *testing.T) {
+	if got := timeoutSeconds(nil, 30); got != 30 {
+		t.Errorf("Expected the fallback without a context, got %d", got)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
+	defer cancel()
+	if got := timeoutSeconds(ctx, 30); got != 45 {
+		t.Errorf("Expected 45 seconds, got %d", got)
+	}
+}
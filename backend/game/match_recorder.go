@@ -0,0 +1,178 @@
+package game
+
+import (
+	"sort"
+	"sync"
+
+	"guandan-world/backend/match"
+	"guandan-world/sdk"
+)
+
+// MatchRecorder observes a running match and records every deal for the archive
+type MatchRecorder struct {
+	deals []*match.DealRecord
+	mu    sync.Mutex
+}
+
+// NewMatchRecorder creates a new match recorder
+func NewMatchRecorder() *MatchRecorder {
+	return &MatchRecorder{}
+}
+
+// OnGameEvent implements sdk.EventObserver
+func (mr *MatchRecorder) OnGameEvent(event *sdk.GameEvent) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if event.Type == sdk.EventDealStarted {
+		mr.startDeal(event)
+		return
+	}
+	if len(mr.deals) == 0 {
+		return
+	}
+	current := mr.deals[len(mr.deals)-1]
+
+	switch event.Type {
+	case sdk.EventTributeCompleted:
+		if phase, ok := event.Data.(*sdk.TributePhase); ok {
+			current.Actions = append(current.Actions, tributeActions(phase, event)...)
+		}
+	case sdk.EventPlayerPlayed:
+		data, _ := event.Data.(map[string]interface{})
+		cards, _ := data["cards"].([]*sdk.Card)
+		substitutes, _ := data["substitutes"].([]*sdk.Card)
+		comp, err := sdk.DeclareComp(cards, substitutes)
+		current.Actions = append(current.Actions, match.Action{
+			Type:        match.ActionPlay,
+			Seat:        event.PlayerSeat,
+			Target:      -1,
+			Cards:       cardIDs(cards),
+			Substitutes: cardIDs(substitutes),
+			Bomb:        err == nil && comp.IsBomb(),
+			At:          event.Timestamp,
+		})
+	case sdk.EventPlayerPassed:
+		current.Actions = append(current.Actions, match.Action{
+			Type:   match.ActionPass,
+			Seat:   event.PlayerSeat,
+			Target: -1,
+			At:     event.Timestamp,
+		})
+	case sdk.EventTrickEnded:
+		data, _ := event.Data.(map[string]interface{})
+		if winner, ok := data["winner"].(int); ok {
+			current.Actions = append(current.Actions, match.Action{
+				Type:   match.ActionTrickWon,
+				Seat:   winner,
+				Target: -1,
+				At:     event.Timestamp,
+			})
+		}
+	case sdk.EventDealEnded:
+		data, _ := event.Data.(map[string]interface{})
+		if result, ok := data["result"].(*sdk.DealResult); ok {
+			current.Result = result
+		}
+	}
+}
+
+// Deals returns the deals recorded so far
+func (mr *MatchRecorder) Deals() []*match.DealRecord {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	return append([]*match.DealRecord(nil), mr.deals...)
+}
+
+// startDeal begins a new deal record with the hands as dealt
+func (mr *MatchRecorder) startDeal(event *sdk.GameEvent) {
+	data, _ := event.Data.(map[string]interface{})
+	record := &match.DealRecord{
+		Number:    len(mr.deals) + 1,
+		StartedAt: event.Timestamp,
+	}
+	record.Level, _ = data["deal_level"].(int)
+	record.TeamLevels[0], _ = data["team0_level"].(int)
+	record.TeamLevels[1], _ = data["team1_level"].(int)
+	if deal, ok := data["deal"].(*sdk.Deal); ok && deal != nil {
+		for seat, hand := range deal.PlayerCards {
+			record.Hands[seat] = cardIDs(hand)
+		}
+	}
+	mr.deals = append(mr.deals, record)
+}
+
+// tributeActions lists the tribute and return-tribute exchanges of a finished tribute phase
+func tributeActions(phase *sdk.TributePhase, event *sdk.GameEvent) []match.Action {
+	if phase.IsImmune {
+		return nil
+	}
+
+	// In a double-down the tribute goes to a pool and receivers pick from it
+	receivers := make(map[int]int, len(phase.TributeMap))
+	for giver, receiver := range phase.TributeMap {
+		receivers[giver] = receiver
+	}
+	for receiver, giver := range phase.SelectionResults {
+		receivers[giver] = receiver
+	}
+
+	var actions []match.Action
+	givers := make([]int, 0, len(phase.TributeCards))
+	for giver := range phase.TributeCards {
+		givers = append(givers, giver)
+	}
+	sort.Ints(givers)
+	for _, giver := range givers {
+		card := phase.TributeCards[giver]
+		if card == nil {
+			continue
+		}
+		actions = append(actions, match.Action{
+			Type:   match.ActionTribute,
+			Seat:   giver,
+			Target: receivers[giver],
+			Cards:  []string{card.GetID()},
+			At:     event.Timestamp,
+		})
+	}
+
+	returners := make([]int, 0, len(phase.ReturnCards))
+	for receiver := range phase.ReturnCards {
+		returners = append(returners, receiver)
+	}
+	sort.Ints(returners)
+	for _, receiver := range returners {
+		card := phase.ReturnCards[receiver]
+		if card == nil {
+			continue
+		}
+		target := -1
+		for giver, r := range receivers {
+			if r == receiver {
+				target = giver
+				break
+			}
+		}
+		actions = append(actions, match.Action{
+			Type:   match.ActionReturnTribute,
+			Seat:   receiver,
+			Target: target,
+			Cards:  []string{card.GetID()},
+			At:     event.Timestamp,
+		})
+	}
+	return actions
+}
+
+// cardIDs converts cards to their IDs
+func cardIDs(cards []*sdk.Card) []string {
+	if len(cards) == 0 {
+		return nil
+	}
+	ids := make([]string, len(cards))
+	for i, card := range cards {
+		ids[i] = card.GetID()
+	}
+	return ids
+}
@@ -0,0 +1,112 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"guandan-world/backend/match"
+	"guandan-world/sdk"
+)
+
+// hintInputProvider plays the first hint, or passes when there is none
+type hintInputProvider struct{}
+
+func (hintInputProvider) RequestPlayDecision(ctx context.Context, playerSeat int, hand []*sdk.Card, trickInfo *sdk.TrickInfo) (*sdk.PlayDecision, error) {
+	var lead sdk.CardComp
+	if trickInfo != nil && !trickInfo.IsLeader {
+		lead = trickInfo.LeadComp
+	}
+	hints := sdk.SuggestPlays(hand, lead)
+	if len(hints) == 0 {
+		return &sdk.PlayDecision{Action: sdk.ActionPass}, nil
+	}
+	return &sdk.PlayDecision{Action: sdk.ActionPlay, Cards: hints[0].Cards}, nil
+}
+
+func (hintInputProvider) RequestTributeSelection(ctx context.Context, playerSeat int, options []*sdk.Card) (*sdk.Card, error) {
+	return options[0], nil
+}
+
+func (hintInputProvider) RequestReturnTribute(ctx context.Context, playerSeat int, hand []*sdk.Card) (*sdk.Card, error) {
+	smallest := hand[0]
+	for _, card := range hand {
+		if card.LessThan(smallest) {
+			smallest = card
+		}
+	}
+	return smallest, nil
+}
+
+func TestMatchRecorder_RecordsReplayableDeals(t *testing.T) {
+	engine := sdk.NewGameEngine()
+	engine.SetRandomSeed(42)
+	config := sdk.DefaultGameDriverConfig()
+	config.MaxDeals = 4
+	driver := sdk.NewGameDriver(engine, config)
+	driver.SetInputProvider(hintInputProvider{})
+	recorder := NewMatchRecorder()
+	driver.AddObserver(recorder)
+
+	players := make([]sdk.Player, 4)
+	for seat := range players {
+		players[seat] = sdk.Player{ID: fmt.Sprintf("player%d", seat), Username: fmt.Sprintf("Player%d", seat), Seat: seat}
+	}
+	if _, err := driver.RunMatch(players); err != nil {
+		t.Fatalf("RunMatch failed: %v", err)
+	}
+
+	deals := recorder.Deals()
+	if len(deals) == 0 {
+		t.Fatal("Expected recorded deals")
+	}
+	sawTribute := false
+	for _, deal := range deals {
+		if deal.Result == nil {
+			t.Fatalf("Deal %d has no result", deal.Number)
+		}
+
+		// Replaying the log must only ever use cards the seat holds
+		hands := make([]map[string]int, 4)
+		for seat, hand := range deal.Hands {
+			if len(hand) != 27 {
+				t.Fatalf("Deal %d seat %d was dealt %d cards", deal.Number, seat, len(hand))
+			}
+			hands[seat] = make(map[string]int)
+			for _, id := range hand {
+				hands[seat][id]++
+			}
+		}
+		take := func(action match.Action) {
+			for _, id := range action.Cards {
+				if hands[action.Seat][id] == 0 {
+					t.Fatalf("Deal %d: seat %d does not hold %s for %s", deal.Number, action.Seat, id, action.Type)
+				}
+				hands[action.Seat][id]--
+			}
+		}
+
+		tricks := 0
+		for _, action := range deal.Actions {
+			switch action.Type {
+			case match.ActionTribute, match.ActionReturnTribute:
+				sawTribute = true
+				if action.Target < 0 || action.Target > 3 || len(action.Cards) != 1 {
+					t.Fatalf("Deal %d: malformed tribute action %+v", deal.Number, action)
+				}
+				take(action)
+				hands[action.Target][action.Cards[0]]++
+			case match.ActionPlay:
+				take(action)
+			case match.ActionTrickWon:
+				tricks++
+			}
+		}
+		if tricks == 0 {
+			t.Errorf("Deal %d recorded no finished tricks", deal.Number)
+		}
+	}
+	if !sawTribute {
+		t.Error("Expected a tribute in the recorded deals")
+	}
+}
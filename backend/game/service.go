@@ -92,6 +92,12 @@ func (gs *GameService) StartGame(roomID string, players []sdk.Player) error {
 
 // PlayCards delegates player card play to SDK without any validation or processing
 func (gs *GameService) PlayCards(roomID string, playerSeat int, cardIDs []string) error {
+	return gs.PlayCardsAs(roomID, playerSeat, cardIDs, nil)
+}
+
+// PlayCardsAs delegates player card play with declared wildcard substitutes to SDK
+// substituteIDs lists the card each wildcard stands for, in the order the wildcards appear in cardIDs
+func (gs *GameService) PlayCardsAs(roomID string, playerSeat int, cardIDs []string, substituteIDs []string) error {
 	gs.mu.RLock()
 	engine, exists := gs.engines[roomID]
 	gs.mu.RUnlock()
@@ -106,8 +112,23 @@ func (gs *GameService) PlayCards(roomID string, playerSeat int, cardIDs []string
 		return fmt.Errorf("failed to convert card IDs: %w", err)
 	}
 	
+	var substitutes []*sdk.Card
+	if len(substituteIDs) > 0 {
+		substitutes, err = gs.convertCardIDs(substituteIDs)
+		if err != nil {
+			return fmt.Errorf("failed to convert substitute card IDs: %w", err)
+		}
+	}
+	
+	// Wildcards depend on the deal level, which the card IDs do not carry
+	if state := engine.GetGameState(); state != nil && state.CurrentMatch != nil && state.CurrentMatch.CurrentDeal != nil {
+		for _, card := range append(cards, substitutes...) {
+			card.Level = state.CurrentMatch.CurrentDeal.Level
+		}
+	}
+	
 	// Delegate directly to SDK - no validation or processing here
-	_, err = engine.PlayCards(playerSeat, cards)
+	_, err = engine.PlayCardsAs(playerSeat, cards, substitutes)
 	if err != nil {
 		return fmt.Errorf("SDK rejected play: %w", err)
 	}
@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"guandan-world/backend/game"
+	"guandan-world/backend/room"
 	"guandan-world/sdk"
 
 	"github.com/gin-gonic/gin"
@@ -12,6 +16,7 @@ import (
 // GameDriverHandler handles game operations using the GameDriver architecture
 type GameDriverHandler struct {
 	driverService *game.DriverService
+	roomService   room.RoomService // optional, supplies the bots and settings of a room
 }
 
 // NewGameDriverHandler creates a new game driver handler
@@ -21,24 +26,83 @@ func NewGameDriverHandler(driverService *game.DriverService) *GameDriverHandler
 	}
 }
 
+// SetRoomService lets games started for a room use its bots and settings
+func (h *GameDriverHandler) SetRoomService(roomService room.RoomService) {
+	h.roomService = roomService
+}
+
+// roomPlayers returns the players seated in a full room, or false if a seat is empty
+func roomPlayers(gameRoom *room.Room) ([]sdk.Player, bool) {
+	players := make([]sdk.Player, 0, len(gameRoom.Players))
+	for _, player := range gameRoom.Players {
+		if player == nil {
+			return nil, false
+		}
+		players = append(players, sdk.Player{
+			ID:       player.ID,
+			Username: player.Username,
+			Seat:     player.Seat,
+			Online:   player.Online,
+		})
+	}
+	return players, true
+}
+
+// roomOptions returns the game options set up in a room: its bots and settings
+func roomOptions(gameRoom *room.Room) game.GameOptions {
+	var options game.GameOptions
+	options.Bots = make(map[int]game.BotSpec)
+	for _, player := range gameRoom.Players {
+		if player != nil && player.Bot {
+			options.Bots[player.Seat] = game.BotSpec{
+				Algorithm:  player.BotAlgorithm,
+				Difficulty: player.BotDifficulty,
+			}
+		}
+	}
+
+	settings := gameRoom.Settings
+	options.Variant = settings.Variant
+	options.StartingLevels = settings.StartingLevels
+	options.TurnTimeout = time.Duration(settings.TurnTimeout) * time.Second
+	options.TributeTimeout = time.Duration(settings.TributeTimeout) * time.Second
+	options.NoHints = !settings.AllowHints
+	switch settings.MatchLength {
+	case room.MatchLengthDeals:
+		options.MaxDeals = settings.Deals
+	case room.MatchLengthTimed:
+		options.TimeLimit = time.Duration(settings.TimeLimit) * time.Minute
+	}
+	return options
+}
+
 // StartGameWithDriverRequest represents the request to start a game with driver
+// The players are the ones seated in the room. Games started here are casual;
+// ranked games are only started by matchmaking.
 type StartGameWithDriverRequest struct {
-	RoomID  string       `json:"room_id" binding:"required"`
-	Players []sdk.Player `json:"players" binding:"required,len=4"`
+	RoomID string `json:"room_id" binding:"required"`
 }
 
 // StartGameWithDriver starts a new game using the GameDriver
 // @Summary Start a new game with GameDriver
-// @Description Starts a new game for a room using the SDK's GameDriver architecture
+// @Description Starts a casual game for a full room whose players are all ready, using the SDK's GameDriver architecture (room owner only)
 // @Tags game-driver
 // @Accept json
 // @Produce json
 // @Param request body StartGameWithDriverRequest true "Start game request"
 // @Success 200 {object} map[string]interface{} "Game started successfully"
 // @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 403 {object} ErrorResponse "Not room owner"
+// @Failure 404 {object} ErrorResponse "Room not found"
+// @Failure 409 {object} ErrorResponse "Room is not full, players are not ready or the game has started"
 // @Failure 500 {object} ErrorResponse "Server error"
 // @Router /api/game/driver/start [post]
 func (h *GameDriverHandler) StartGameWithDriver(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	var req StartGameWithDriverRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
@@ -47,8 +111,56 @@ func (h *GameDriverHandler) StartGameWithDriver(c *gin.Context) {
 		return
 	}
 
+	if h.roomService == nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "room service is not configured",
+		})
+		return
+	}
+	gameRoom, err := h.roomService.GetRoom(req.RoomID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if gameRoom.Owner != userID {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: room.ErrNotRoomOwner.Error(),
+		})
+		return
+	}
+	players, full := roomPlayers(gameRoom)
+	if !full {
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "insufficient_players",
+			Message: "room must have 4 players to start game",
+		})
+		return
+	}
+
+	// Check the bots before the room starts playing; a bot registered on the
+	// server may be gone after a restart
+	options := roomOptions(gameRoom)
+	for seat, spec := range options.Bots {
+		if _, err := game.NewBotAlgorithm(spec); err != nil {
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "invalid_bot",
+				Message: fmt.Sprintf("seat %d: %v", seat, err),
+			})
+			return
+		}
+	}
+
+	// The room starts playing first: it checks that every player is ready and
+	// locks the seats the game is started with
+	if err := h.roomService.StartGame(req.RoomID, userID); err != nil {
+		respondStartGameError(c, err)
+		return
+	}
+
 	// Start game using driver service
-	err := h.driverService.StartGameWithDriver(req.RoomID, req.Players)
+	err = h.driverService.StartGameWithOptions(req.RoomID, players, options)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error: err.Error(),
@@ -69,6 +181,8 @@ type PlayDecisionRequest struct {
 	PlayerSeat int      `json:"player_seat" binding:"min=0,max=3"`
 	Action     string   `json:"action" binding:"required,oneof=play pass"`
 	CardIDs    []string `json:"card_ids,omitempty"`
+	// SubstituteIDs declares the card each wildcard in CardIDs stands for, in order (optional)
+	SubstituteIDs []string `json:"substitute_ids,omitempty"`
 }
 
 // SubmitPlayDecision submits a player's play decision
@@ -133,10 +247,25 @@ func (h *GameDriverHandler) SubmitPlayDecision(c *gin.Context) {
 		}
 	}
 
+	var substitutes []*sdk.Card
+	if req.Action == "play" {
+		for _, cardID := range req.SubstituteIDs {
+			card, parseErr := sdk.ParseCardFromID(cardID, level)
+			if parseErr != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Error: "Invalid substitute card ID: " + cardID,
+				})
+				return
+			}
+			substitutes = append(substitutes, card)
+		}
+	}
+
 	// Create play decision
 	decision := &sdk.PlayDecision{
-		Action: sdk.ActionType(req.Action),
-		Cards:  cards,
+		Action:      sdk.ActionType(req.Action),
+		Cards:       cards,
+		Substitutes: substitutes,
 	}
 
 	// Submit to driver service
@@ -154,6 +283,112 @@ func (h *GameDriverHandler) SubmitPlayDecision(c *gin.Context) {
 	})
 }
 
+// PlayInterpretationsRequest represents a request to list the interpretations of a set of cards
+type PlayInterpretationsRequest struct {
+	RoomID  string   `json:"room_id" binding:"required"`
+	CardIDs []string `json:"card_ids" binding:"required,min=1"`
+}
+
+// GetPlayInterpretations lists every way the selected cards can be played
+// @Summary List play interpretations
+// @Description Lists every valid combination the selected cards can form when wildcards are involved, so the player can declare one
+// @Tags game-driver
+// @Accept json
+// @Produce json
+// @Param request body PlayInterpretationsRequest true "Selected cards"
+// @Success 200 {object} map[string]interface{} "Interpretations, the engine default first"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Router /api/game/driver/interpretations [post]
+func (h *GameDriverHandler) GetPlayInterpretations(c *gin.Context) {
+	var req PlayInterpretationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	interpretations, err := h.driverService.GetPlayInterpretations(req.RoomID, req.CardIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":         true,
+		"interpretations": interpretations,
+	})
+}
+
+// HintsRequest represents a request for play hints
+// The seat is taken from the authenticated player; PlayerSeat is only checked against it
+type HintsRequest struct {
+	RoomID     string `json:"room_id" binding:"required"`
+	PlayerSeat *int   `json:"player_seat,omitempty" binding:"omitempty,min=0,max=3"`
+}
+
+// GetHints returns ranked play suggestions for the authenticated player
+// @Summary Get play hints
+// @Description Returns candidate plays for the caller's seat in the current turn, from the smallest play that beats the lead to bombs
+// @Tags game-driver
+// @Accept json
+// @Produce json
+// @Param request body HintsRequest true "Hints request"
+// @Success 200 {object} map[string]interface{} "Hints for the current turn"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 403 {object} ErrorResponse "Hints are disabled, or the caller is not seated in the game"
+// @Router /api/game/driver/hints [post]
+func (h *GameDriverHandler) GetHints(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req HintsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	// Hints are computed from a hand, so only its owner may ask for them
+	playerSeat, err := h.driverService.PlayerSeat(req.RoomID, userID)
+	if errors.Is(err, game.ErrPlayerNotSeated) || (err == nil && req.PlayerSeat != nil && *req.PlayerSeat != playerSeat) {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "player is not seated at the requested seat",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.driverService.GetHints(req.RoomID, playerSeat)
+	if errors.Is(err, game.ErrHintsDisabled) {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"hints":   result,
+	})
+}
+
 // TributeSelectionRequest represents a tribute selection request
 type TributeSelectionRequest struct {
 	RoomID     string `json:"room_id" binding:"required"`
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"guandan-world/backend/game"
+	"guandan-world/backend/room"
+	"guandan-world/backend/websocket"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// discardWSManager drops every message sent by the driver service
+type discardWSManager struct{}
+
+func (discardWSManager) BroadcastToRoom(roomID string, message *websocket.WSMessage) {}
+
+func (discardWSManager) SendToPlayer(playerID string, message *websocket.WSMessage) error {
+	return nil
+}
+
+func TestGameDriverHandler_StartAndHints(t *testing.T) {
+	router, authHandler, _, _, roomService := setupRoomTestRouter()
+	driverService := game.NewDriverService(discardWSManager{})
+	driverHandler := NewGameDriverHandler(driverService)
+	driverHandler.SetRoomService(roomService)
+	driverRoutes := router.Group("/api/game/driver", authHandler.JWTMiddleware())
+	driverRoutes.POST("/start", driverHandler.StartGameWithDriver)
+	driverRoutes.POST("/hints", driverHandler.GetHints)
+
+	ownerToken, owner := createTestUserAndLogin(t, router, "driverowner")
+	guestToken, guest := createTestUserAndLogin(t, router, "driverguest")
+	outsiderToken, _ := createTestUserAndLogin(t, router, "driveroutsider")
+
+	createdRoom, err := roomService.CreateRoom(owner.ID)
+	assert.NoError(t, err)
+	start := StartGameWithDriverRequest{RoomID: createdRoom.ID}
+
+	w := roomBotRequest(router, "POST", "/api/game/driver/start", ownerToken, StartGameWithDriverRequest{RoomID: "missing"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = roomBotRequest(router, "POST", "/api/game/driver/start", ownerToken, start)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	_, err = roomService.JoinRoom(createdRoom.ID, guest.ID)
+	assert.NoError(t, err)
+	for seat := 2; seat < 4; seat++ {
+		_, err := roomService.AddBot(createdRoom.ID, owner.ID, seat, "", "")
+		assert.NoError(t, err)
+	}
+
+	// Only the owner starts the game, always with the players seated in the room
+	w = roomBotRequest(router, "POST", "/api/game/driver/start", guestToken, start)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Every other player must be ready, as when starting from the room
+	w = roomBotRequest(router, "POST", "/api/game/driver/start", ownerToken, start)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "players_not_ready")
+
+	_, err = roomService.SetReady(createdRoom.ID, guest.ID, true)
+	assert.NoError(t, err)
+	w = roomBotRequest(router, "POST", "/api/game/driver/start", ownerToken, start)
+	assert.Equal(t, http.StatusOK, w.Code)
+	defer driverService.StopGame(createdRoom.ID)
+
+	// The room is playing, so its seats stay as the game was started with
+	startedRoom, err := roomService.GetRoom(createdRoom.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, room.RoomStatusPlaying, startedRoom.Status)
+	_, err = roomService.KickPlayer(createdRoom.ID, owner.ID, guest.ID)
+	assert.Error(t, err)
+	w = roomBotRequest(router, "POST", "/api/game/driver/start", ownerToken, start)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	seat, err := driverService.PlayerSeat(createdRoom.ID, guest.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, seat)
+
+	// Hints are only given for the caller's own seat
+	w = roomBotRequest(router, "POST", "/api/game/driver/hints", outsiderToken, HintsRequest{RoomID: createdRoom.ID})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	otherSeat := 2
+	w = roomBotRequest(router, "POST", "/api/game/driver/hints", ownerToken, HintsRequest{RoomID: createdRoom.ID, PlayerSeat: &otherSeat})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"guandan-world/backend/match"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MatchHandler serves the history of finished matches and deal replays
+type MatchHandler struct {
+	archive match.Archive
+}
+
+// NewMatchHandler creates a new match handler
+func NewMatchHandler(archive match.Archive) *MatchHandler {
+	return &MatchHandler{
+		archive: archive,
+	}
+}
+
+// MatchListResponse represents a page of archived matches
+type MatchListResponse struct {
+	Matches []*match.Record `json:"matches"`
+	Page    int             `json:"page"`
+	Limit   int             `json:"limit"`
+}
+
+// MatchResponse represents a single archived match
+type MatchResponse struct {
+	Match *match.Record `json:"match"`
+}
+
+// ReplayResponse represents the replay of one deal
+type ReplayResponse struct {
+	Replay *match.Replay `json:"replay"`
+}
+
+// ListMatches handles GET /api/matches?player=&page=&limit=
+// Without a player the authenticated user's own matches are listed.
+func (h *MatchHandler) ListMatches(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	playerID := c.Query("player")
+	if playerID == "" {
+		playerID = userID
+	}
+
+	page := 1
+	limit := 20
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 50 {
+			limit = l
+		}
+	}
+
+	records, err := h.archive.List(playerID, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "match_list_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	matches := make([]*match.Record, len(records))
+	for i, record := range records {
+		matches[i] = record.Overview()
+	}
+
+	c.JSON(http.StatusOK, MatchListResponse{
+		Matches: matches,
+		Page:    page,
+		Limit:   limit,
+	})
+}
+
+// GetMatch handles GET /api/matches/:id
+func (h *MatchHandler) GetMatch(c *gin.Context) {
+	if _, ok := currentUserID(c); !ok {
+		return
+	}
+
+	record, ok := h.getRecord(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, MatchResponse{
+		Match: record.Overview(),
+	})
+}
+
+// GetDealReplay handles GET /api/matches/:id/deals/:n/replay
+// Participants see every hand; other users only see the cards that were played openly.
+func (h *MatchHandler) GetDealReplay(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("n"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_deal_number",
+			Message: "Deal number must be an integer",
+		})
+		return
+	}
+
+	record, ok := h.getRecord(c)
+	if !ok {
+		return
+	}
+
+	replay, err := record.Replay(number, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "deal_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ReplayResponse{
+		Replay: replay,
+	})
+}
+
+// getRecord loads the match named in the URL, writing the error response when it fails
+func (h *MatchHandler) getRecord(c *gin.Context) (*match.Record, bool) {
+	record, err := h.archive.Get(c.Param("id"))
+	if errors.Is(err, match.ErrMatchNotFound) {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "match_not_found",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "match_lookup_failed",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	return record, true
+}
+
+// currentUserID returns the authenticated user's ID, writing the error response when it is missing
+func currentUserID(c *gin.Context) (string, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+		})
+		return "", false
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "internal_error",
+			Message: "Invalid user ID in context",
+		})
+		return "", false
+	}
+	return userIDStr, true
+}
+
+// RegisterRoutes registers match routes
+func (h *MatchHandler) RegisterRoutes(router *gin.Engine, authHandler *AuthHandler) {
+	matches := router.Group("/api/matches")
+	{
+		matches.Use(authHandler.JWTMiddleware())
+
+		matches.GET("", h.ListMatches)                       // GET /api/matches?player= - list finished matches
+		matches.GET("/:id", h.GetMatch)                      // GET /api/matches/:id - match summary with deal results
+		matches.GET("/:id/deals/:n/replay", h.GetDealReplay) // GET /api/matches/:id/deals/:n/replay - full deal log
+	}
+}
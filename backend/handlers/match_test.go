@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"guandan-world/backend/auth"
+	"guandan-world/backend/match"
+	"guandan-world/sdk"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMatchTestRouter() (*gin.Engine, match.Archive) {
+	gin.SetMode(gin.TestMode)
+
+	authService := auth.NewAuthService("test-secret", 24*time.Hour)
+	archive := match.NewMemoryArchive()
+
+	authHandler := NewAuthHandler(authService)
+	matchHandler := NewMatchHandler(archive)
+
+	router := gin.New()
+	authHandler.RegisterRoutes(router)
+	matchHandler.RegisterRoutes(router, authHandler)
+
+	return router, archive
+}
+
+func archiveTestMatch(t *testing.T, archive match.Archive, players ...*auth.User) *match.Record {
+	record := &match.Record{
+		ID:          "match_1",
+		RoomID:      "room_1",
+		Winner:      0,
+		FinalLevels: [2]int{14, 8},
+		FinishedAt:  time.Now(),
+		Deals: []*match.DealRecord{{
+			Number:     1,
+			Level:      2,
+			TeamLevels: [2]int{2, 2},
+			Hands:      [4][]string{{"Spade_3"}, {"Heart_4"}, {"Club_5"}, {"Diamond_6"}},
+			Actions: []match.Action{
+				{Type: match.ActionReturnTribute, Seat: 1, Target: 0, Cards: []string{"Heart_4"}},
+				{Type: match.ActionPlay, Seat: 0, Target: -1, Cards: []string{"Spade_3"}},
+				{Type: match.ActionPass, Seat: 1, Target: -1},
+			},
+			Result: &sdk.DealResult{Rankings: []int{0, 2, 1, 3}, WinningTeam: 0},
+		}},
+	}
+	for seat, user := range players {
+		record.Players = append(record.Players, match.Player{ID: user.ID, Username: user.Username, Seat: seat})
+	}
+	require.NoError(t, archive.Save(record))
+	return record
+}
+
+func getJSON(t *testing.T, router *gin.Engine, path, token string, target interface{}) int {
+	req := httptest.NewRequest("GET", path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if target != nil && w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), target))
+	}
+	return w.Code
+}
+
+func TestMatchHandler_ListAndGet(t *testing.T) {
+	router, archive := setupMatchTestRouter()
+	token, player := createTestUserAndLogin(t, router, "matchplayer")
+	otherToken, _ := createTestUserAndLogin(t, router, "matchother")
+	archiveTestMatch(t, archive, player)
+
+	var mine MatchListResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/matches", token, &mine))
+	require.Len(t, mine.Matches, 1)
+	assert.Equal(t, "match_1", mine.Matches[0].ID)
+	assert.Nil(t, mine.Matches[0].Deals[0].Actions, "listings should not carry action logs")
+
+	var others MatchListResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/matches", otherToken, &others))
+	assert.Empty(t, others.Matches)
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/matches?player="+player.ID, otherToken, &others))
+	assert.Len(t, others.Matches, 1)
+
+	var detail MatchResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/matches/match_1", otherToken, &detail))
+	require.Len(t, detail.Match.Deals, 1)
+	assert.Equal(t, 0, detail.Match.Deals[0].Result.WinningTeam)
+	assert.Nil(t, detail.Match.Deals[0].Hands[0])
+
+	assert.Equal(t, http.StatusNotFound, getJSON(t, router, "/api/matches/missing", token, nil))
+	assert.Equal(t, http.StatusUnauthorized, getJSON(t, router, "/api/matches", "bad-token", nil))
+}
+
+func TestMatchHandler_ReplayAccess(t *testing.T) {
+	router, archive := setupMatchTestRouter()
+	token, player := createTestUserAndLogin(t, router, "replayplayer")
+	otherToken, _ := createTestUserAndLogin(t, router, "replayother")
+	archiveTestMatch(t, archive, player)
+
+	var participant ReplayResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/matches/match_1/deals/1/replay", token, &participant))
+	assert.True(t, participant.Replay.HandsVisible)
+	assert.Equal(t, []string{"Heart_4"}, participant.Replay.Hands[1])
+	require.Len(t, participant.Replay.Actions, 3)
+	assert.Equal(t, []string{"Heart_4"}, participant.Replay.Actions[0].Cards)
+
+	var outsider ReplayResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/matches/match_1/deals/1/replay", otherToken, &outsider))
+	assert.False(t, outsider.Replay.HandsVisible)
+	for seat := range outsider.Replay.Hands {
+		assert.Nil(t, outsider.Replay.Hands[seat])
+	}
+	require.Len(t, outsider.Replay.Actions, 3)
+	assert.Nil(t, outsider.Replay.Actions[0].Cards, "returned tribute cards are hidden")
+	assert.Equal(t, []string{"Spade_3"}, outsider.Replay.Actions[1].Cards, "played cards stay visible")
+
+	assert.Equal(t, http.StatusNotFound, getJSON(t, router, "/api/matches/match_1/deals/2/replay", token, nil))
+	assert.Equal(t, http.StatusBadRequest, getJSON(t, router, "/api/matches/match_1/deals/x/replay", token, nil))
+}
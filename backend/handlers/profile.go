@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"guandan-world/backend/auth"
+	"guandan-world/backend/profile"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProfileHandler serves player profiles and leaderboards
+type ProfileHandler struct {
+	profileService profile.ProfileService
+}
+
+// NewProfileHandler creates a new profile handler
+func NewProfileHandler(profileService profile.ProfileService) *ProfileHandler {
+	return &ProfileHandler{
+		profileService: profileService,
+	}
+}
+
+// ProfileResponse represents a profile response
+type ProfileResponse struct {
+	Profile *profile.Profile `json:"profile"`
+}
+
+// LeaderboardResponse represents a leaderboard response
+type LeaderboardResponse struct {
+	*profile.Leaderboard
+}
+
+// GetProfile handles GET /api/users/:id/profile; "me" stands for the authenticated user
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	targetID := c.Param("id")
+	if targetID == "me" {
+		targetID = userID
+	}
+
+	p, err := h.profileService.GetProfile(targetID)
+	if errors.Is(err, auth.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "user_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "profile_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ProfileResponse{
+		Profile: p,
+	})
+}
+
+// GetLeaderboard handles GET /api/leaderboard?period=overall|weekly&page=&limit=
+func (h *ProfileHandler) GetLeaderboard(c *gin.Context) {
+	period := profile.Period(c.DefaultQuery("period", string(profile.PeriodOverall)))
+	if period != profile.PeriodOverall && period != profile.PeriodWeekly {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_period",
+			Message: "Period must be overall or weekly",
+		})
+		return
+	}
+
+	page := 1
+	limit := 20
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 50 {
+			limit = l
+		}
+	}
+
+	leaderboard, err := h.profileService.GetLeaderboard(period, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "leaderboard_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, LeaderboardResponse{
+		Leaderboard: leaderboard,
+	})
+}
+
+// RegisterRoutes registers profile and leaderboard routes
+func (h *ProfileHandler) RegisterRoutes(router *gin.Engine, authHandler *AuthHandler) {
+	api := router.Group("/api")
+	{
+		api.Use(authHandler.JWTMiddleware())
+
+		api.GET("/users/:id/profile", h.GetProfile) // GET /api/users/:id/profile - profile with lifetime stats
+		api.GET("/leaderboard", h.GetLeaderboard)   // GET /api/leaderboard?period= - paginated leaderboard
+	}
+}
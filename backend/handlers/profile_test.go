@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"guandan-world/backend/auth"
+	"guandan-world/backend/match"
+	"guandan-world/backend/profile"
+	"guandan-world/backend/rating"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupProfileTestRouter() (*gin.Engine, match.Archive) {
+	gin.SetMode(gin.TestMode)
+
+	authService := auth.NewAuthService("test-secret", 24*time.Hour)
+	archive := match.NewMemoryArchive()
+
+	authHandler := NewAuthHandler(authService)
+	profileHandler := NewProfileHandler(profile.NewProfileService(authService, archive, rating.NewService(rating.NewMemoryRepository())))
+
+	router := gin.New()
+	authHandler.RegisterRoutes(router)
+	profileHandler.RegisterRoutes(router, authHandler)
+
+	return router, archive
+}
+
+func TestProfileHandler_GetProfile(t *testing.T) {
+	router, archive := setupProfileTestRouter()
+	token, player := createTestUserAndLogin(t, router, "profileplayer")
+	archiveTestMatch(t, archive, player)
+
+	var mine ProfileResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/users/me/profile", token, &mine))
+	require.NotNil(t, mine.Profile)
+	assert.Equal(t, player.ID, mine.Profile.ID)
+	assert.Equal(t, 1, mine.Profile.Stats.GamesPlayed)
+	assert.Equal(t, 1, mine.Profile.Stats.GamesWon)
+	assert.Equal(t, 1, mine.Profile.Stats.DealsPlayed)
+	require.NotNil(t, mine.Profile.Rating)
+	assert.Equal(t, 0, mine.Profile.Rating.Matches)
+
+	otherToken, _ := createTestUserAndLogin(t, router, "profileother")
+	var theirs ProfileResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/users/"+player.ID+"/profile", otherToken, &theirs))
+	assert.Equal(t, "profileplayer", theirs.Profile.Username)
+
+	assert.Equal(t, http.StatusNotFound, getJSON(t, router, "/api/users/missing/profile", token, nil))
+}
+
+func TestProfileHandler_GetLeaderboard(t *testing.T) {
+	router, archive := setupProfileTestRouter()
+	token, player := createTestUserAndLogin(t, router, "leaderplayer")
+	archiveTestMatch(t, archive, player)
+
+	var overall LeaderboardResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/leaderboard", token, &overall))
+	assert.Equal(t, profile.PeriodOverall, overall.Period)
+	require.Len(t, overall.Entries, 1)
+	assert.Equal(t, player.ID, overall.Entries[0].PlayerID)
+	assert.Equal(t, 1, overall.Entries[0].Rank)
+
+	var weekly LeaderboardResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/leaderboard?period=weekly&limit=5", token, &weekly))
+	assert.NotNil(t, weekly.Since)
+	assert.Equal(t, 5, weekly.Limit)
+
+	assert.Equal(t, http.StatusBadRequest, getJSON(t, router, "/api/leaderboard?period=monthly", token, nil))
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"guandan-world/backend/auth"
+	"guandan-world/backend/matchmaking"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueueHandler serves the matchmaking queue
+type QueueHandler struct {
+	matchmakingService matchmaking.Service
+}
+
+// NewQueueHandler creates a new queue handler
+func NewQueueHandler(matchmakingService matchmaking.Service) *QueueHandler {
+	return &QueueHandler{
+		matchmakingService: matchmakingService,
+	}
+}
+
+// JoinQueueRequest represents a request to join the matchmaking queue
+type JoinQueueRequest struct {
+	PartnerID string `json:"partner_id,omitempty"` // Invite this player to queue as a pre-made pair
+}
+
+// PairInviteRequest represents an answer to a pair invite
+type PairInviteRequest struct {
+	InviterID string `json:"inviter_id" binding:"required"`
+}
+
+// QueueStatusResponse represents a queue status response
+type QueueStatusResponse struct {
+	Status *matchmaking.Status `json:"status"`
+}
+
+// JoinQueue handles POST /api/queue
+func (h *QueueHandler) JoinQueue(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req JoinQueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	status, err := h.matchmakingService.Enqueue(userID, req.PartnerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, matchmaking.ErrAlreadyQueued):
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "already_queued",
+				Message: err.Error(),
+			})
+		case errors.Is(err, matchmaking.ErrPlayerInRoom):
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "player_in_room",
+				Message: err.Error(),
+			})
+		case errors.Is(err, matchmaking.ErrPartnerOffline):
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "partner_offline",
+				Message: err.Error(),
+			})
+		case errors.Is(err, auth.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "user_not_found",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "queue_failed",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, QueueStatusResponse{
+		Status: status,
+	})
+}
+
+// AcceptPairInvite handles POST /api/queue/accept
+func (h *QueueHandler) AcceptPairInvite(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req PairInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	status, err := h.matchmakingService.AcceptPair(userID, req.InviterID)
+	if err != nil {
+		switch {
+		case errors.Is(err, matchmaking.ErrInviteNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "invite_not_found",
+				Message: err.Error(),
+			})
+		case errors.Is(err, matchmaking.ErrAlreadyQueued):
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "already_queued",
+				Message: err.Error(),
+			})
+		case errors.Is(err, matchmaking.ErrPlayerInRoom):
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "player_in_room",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "queue_failed",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, QueueStatusResponse{
+		Status: status,
+	})
+}
+
+// DeclinePairInvite handles POST /api/queue/decline
+func (h *QueueHandler) DeclinePairInvite(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req PairInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.matchmakingService.DeclinePair(userID, req.InviterID); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "invite_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Pair invite declined",
+	})
+}
+
+// GetQueueStatus handles GET /api/queue
+func (h *QueueHandler) GetQueueStatus(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.matchmakingService.Status(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "not_queued",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, QueueStatusResponse{
+		Status: status,
+	})
+}
+
+// LeaveQueue handles DELETE /api/queue
+func (h *QueueHandler) LeaveQueue(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.matchmakingService.Leave(userID); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "not_queued",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Left the queue",
+	})
+}
+
+// RegisterRoutes registers matchmaking queue routes
+func (h *QueueHandler) RegisterRoutes(router *gin.Engine, authHandler *AuthHandler) {
+	api := router.Group("/api")
+	{
+		api.Use(authHandler.JWTMiddleware())
+
+		api.POST("/queue", h.JoinQueue)                 // POST /api/queue - join the queue or invite a partner
+		api.POST("/queue/accept", h.AcceptPairInvite)   // POST /api/queue/accept - accept a pair invite
+		api.POST("/queue/decline", h.DeclinePairInvite) // POST /api/queue/decline - decline a pair invite
+		api.GET("/queue", h.GetQueueStatus)             // GET /api/queue - current queue status
+		api.DELETE("/queue", h.LeaveQueue)              // DELETE /api/queue - leave the queue or withdraw an invite
+	}
+}
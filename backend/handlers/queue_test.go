@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"guandan-world/backend/auth"
+	"guandan-world/backend/game"
+	"guandan-world/backend/matchmaking"
+	"guandan-world/backend/room"
+	"guandan-world/sdk"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubGameStarter accepts every matched game without running it
+type stubGameStarter struct{}
+
+func (stubGameStarter) StartGameWithOptions(roomID string, players []sdk.Player, options game.GameOptions) error {
+	return nil
+}
+
+func setupQueueTestRouter() (*gin.Engine, room.RoomService, *recordingNotifier) {
+	gin.SetMode(gin.TestMode)
+
+	authService := auth.NewAuthService("test-secret", 24*time.Hour)
+	roomService := room.NewRoomService(authService)
+	notifier := &recordingNotifier{offline: map[string]bool{}}
+	matchmakingService := matchmaking.NewService(matchmaking.DefaultConfig(), roomService, authService, nil, stubGameStarter{}, notifier)
+
+	authHandler := NewAuthHandler(authService)
+	queueHandler := NewQueueHandler(matchmakingService)
+
+	router := gin.New()
+	authHandler.RegisterRoutes(router)
+	queueHandler.RegisterRoutes(router, authHandler)
+
+	return router, roomService, notifier
+}
+
+func queueRequest(t *testing.T, router *gin.Engine, method, token string, body interface{}, target interface{}) int {
+	return queuePathRequest(t, router, method, "/api/queue", token, body, target)
+}
+
+func queuePathRequest(t *testing.T, router *gin.Engine, method, path, token string, body interface{}, target interface{}) int {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if target != nil && w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), target))
+	}
+	return w.Code
+}
+
+func TestQueueHandler_JoinAndLeave(t *testing.T) {
+	router, _, _ := setupQueueTestRouter()
+	token, player := createTestUserAndLogin(t, router, "queueplayer")
+	partnerToken, partner := createTestUserAndLogin(t, router, "queuepartner")
+
+	assert.Equal(t, http.StatusNotFound, queueRequest(t, router, "GET", token, nil, nil))
+
+	// The partner has to accept before the pair is queued
+	var invited QueueStatusResponse
+	assert.Equal(t, http.StatusOK, queueRequest(t, router, "POST", token, JoinQueueRequest{PartnerID: partner.ID}, &invited))
+	require.NotNil(t, invited.Status)
+	assert.Equal(t, matchmaking.StateInvited, invited.Status.State)
+	assert.Equal(t, http.StatusBadRequest, queuePathRequest(t, router, "POST", "/api/queue/accept", partnerToken, nil, nil))
+	assert.Equal(t, http.StatusNotFound, queuePathRequest(t, router, "POST", "/api/queue/accept", token, PairInviteRequest{InviterID: partner.ID}, nil))
+
+	var joined QueueStatusResponse
+	assert.Equal(t, http.StatusOK, queuePathRequest(t, router, "POST", "/api/queue/accept", partnerToken, PairInviteRequest{InviterID: player.ID}, &joined))
+	require.NotNil(t, joined.Status)
+	assert.Equal(t, matchmaking.StateQueued, joined.Status.State)
+	assert.Equal(t, []string{player.ID, partner.ID}, joined.Status.Players)
+
+	assert.Equal(t, http.StatusConflict, queueRequest(t, router, "POST", token, nil, nil))
+
+	var status QueueStatusResponse
+	assert.Equal(t, http.StatusOK, queueRequest(t, router, "GET", token, nil, &status))
+	assert.Equal(t, joined.Status.TicketID, status.Status.TicketID)
+
+	assert.Equal(t, http.StatusOK, queueRequest(t, router, "DELETE", token, nil, nil))
+	assert.Equal(t, http.StatusNotFound, queueRequest(t, router, "DELETE", token, nil, nil))
+}
+
+func TestQueueHandler_JoinErrors(t *testing.T) {
+	router, roomService, notifier := setupQueueTestRouter()
+	token, player := createTestUserAndLogin(t, router, "queueerrors")
+	partnerToken, partner := createTestUserAndLogin(t, router, "queueoffline")
+
+	assert.Equal(t, http.StatusNotFound, queueRequest(t, router, "POST", token, JoinQueueRequest{PartnerID: "missing"}, nil))
+	notifier.offline[partner.ID] = true
+	assert.Equal(t, http.StatusConflict, queueRequest(t, router, "POST", token, JoinQueueRequest{PartnerID: partner.ID}, nil))
+
+	notifier.offline[partner.ID] = false
+	assert.Equal(t, http.StatusOK, queueRequest(t, router, "POST", token, JoinQueueRequest{PartnerID: partner.ID}, nil))
+	assert.Equal(t, http.StatusOK, queuePathRequest(t, router, "POST", "/api/queue/decline", partnerToken, PairInviteRequest{InviterID: player.ID}, nil))
+	assert.Equal(t, http.StatusNotFound, queuePathRequest(t, router, "POST", "/api/queue/decline", partnerToken, PairInviteRequest{InviterID: player.ID}, nil))
+
+	_, err := roomService.CreateRoom(player.ID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, queueRequest(t, router, "POST", token, nil, nil))
+}
@@ -1,20 +1,34 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"guandan-world/backend/auth"
+	"guandan-world/backend/game"
 	"guandan-world/backend/room"
+	"guandan-world/backend/websocket"
 
 	"github.com/gin-gonic/gin"
 )
 
+// RoomNotifier pushes messages to online players; *websocket.WSManager implements it
+type RoomNotifier interface {
+	SendToPlayer(playerID string, message *websocket.WSMessage) error
+	// RemoveFromRoom stops a kicked or banned player's connection receiving room broadcasts
+	RemoveFromRoom(roomID, playerID string)
+}
+
 // RoomHandler handles room-related HTTP requests
 type RoomHandler struct {
 	roomService room.RoomService
 	authService auth.AuthService
+	notifier    RoomNotifier // optional, pushes room updates and invites to players
 }
 
 // NewRoomHandler creates a new room handler
@@ -25,15 +39,69 @@ func NewRoomHandler(roomService room.RoomService, authService auth.AuthService)
 	}
 }
 
+// SetNotifier sets where invite notifications are pushed
+func (h *RoomHandler) SetNotifier(notifier RoomNotifier) {
+	h.notifier = notifier
+}
+
 // CreateRoomRequest represents a room creation request
 type CreateRoomRequest struct {
-	// Room creation doesn't need additional parameters for now
 	// The owner is determined from the authenticated user
+	Settings *room.Settings `json:"settings,omitempty"` // Fields left out keep their defaults
+	Passcode string         `json:"passcode,omitempty"` // Players must give it to join
 }
 
 // JoinRoomRequest represents a room join request
 type JoinRoomRequest struct {
-	// Room ID is in the URL path, player ID comes from auth context
+	// Player ID comes from auth context
+	RoomID   string `json:"room_id,omitempty"`  // Used when the URL path has no room ID
+	Passcode string `json:"passcode,omitempty"` // Private and passcode rooms need the passcode
+	Invite   string `json:"invite,omitempty"`   // or an invite token
+}
+
+// SetPasscodeRequest represents a request to set or remove a room passcode
+type SetPasscodeRequest struct {
+	Passcode string `json:"passcode"` // Empty removes the passcode
+}
+
+// CreateInviteRequest represents a request to invite players to a room
+type CreateInviteRequest struct {
+	ExpiresIn int      `json:"expires_in,omitempty"`                  // Seconds; defaults to 24 hours, at most 7 days
+	PlayerIDs []string `json:"player_ids,omitempty" binding:"max=20"` // Online players to notify
+}
+
+// ChooseSeatRequest represents a request to move to a seat or swap into it
+type ChooseSeatRequest struct {
+	Seat *int `json:"seat" binding:"required,min=0,max=3"`
+}
+
+// SeatSwapResponseRequest represents an answer to a seat swap request
+type SeatSwapResponseRequest struct {
+	RequesterID string `json:"requester_id" binding:"required"`
+	Accept      bool   `json:"accept"`
+}
+
+// MoveSeatRequest represents an owner moving a player or bot to another seat
+type MoveSeatRequest struct {
+	From *int `json:"from" binding:"required,min=0,max=3"`
+	To   *int `json:"to" binding:"required,min=0,max=3"`
+}
+
+// ReadyRequest represents a change of a player's ready flag
+type ReadyRequest struct {
+	Ready *bool `json:"ready,omitempty"` // Defaults to true
+}
+
+// ModerationRequest represents a room owner acting on another player
+type ModerationRequest struct {
+	PlayerID string `json:"player_id" binding:"required"`
+}
+
+// InviteResponse represents a created invite
+type InviteResponse struct {
+	Invite   *room.Invite `json:"invite"`
+	Link     string       `json:"link"`     // Path of the invite link in the web client
+	Notified []string     `json:"notified"` // Invited players who were online and notified
 }
 
 // RoomResponse represents a room response
@@ -41,6 +109,13 @@ type RoomResponse struct {
 	Room *room.Room `json:"room"`
 }
 
+// AddBotRequest represents a request to seat a bot
+type AddBotRequest struct {
+	Seat       *int   `json:"seat,omitempty"`       // Seat to fill; the first empty seat when omitted
+	Algorithm  string `json:"algorithm,omitempty"`  // ai algorithm name, see game.BotSpec
+	Difficulty string `json:"difficulty,omitempty"` // beginner, intermediate or expert
+}
+
 // RoomListResponse represents a room list response
 type RoomListResponse struct {
 	*room.RoomListResponse
@@ -67,8 +142,29 @@ func (h *RoomHandler) CreateRoom(c *gin.Context) {
 		return
 	}
 
+	// Start from the defaults so a partial settings object only overrides what it names
+	settings := room.DefaultSettings()
+	req := CreateRoomRequest{Settings: &settings}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+	if req.Settings == nil {
+		req.Settings = &settings
+	}
+	if err := room.ValidatePasscode(req.Passcode); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_settings",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	// Create room
-	newRoom, err := h.roomService.CreateRoom(userIDStr)
+	newRoom, err := h.roomService.CreateRoomWithSettings(userIDStr, *req.Settings)
 	if err != nil {
 		statusCode := http.StatusBadRequest
 		errorCode := "room_creation_failed"
@@ -77,6 +173,8 @@ func (h *RoomHandler) CreateRoom(c *gin.Context) {
 		if strings.Contains(err.Error(), "player is already in room") {
 			statusCode = http.StatusConflict
 			errorCode = "already_in_room"
+		} else if errors.Is(err, room.ErrInvalidSettings) {
+			errorCode = "invalid_settings"
 		}
 
 		c.JSON(statusCode, ErrorResponse{
@@ -86,6 +184,16 @@ func (h *RoomHandler) CreateRoom(c *gin.Context) {
 		return
 	}
 
+	if req.Passcode != "" {
+		if newRoom, err = h.roomService.SetPasscode(newRoom.ID, userIDStr, req.Passcode); err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "room_creation_failed",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
 	c.JSON(http.StatusCreated, RoomResponse{
 		Room: newRoom,
 	})
@@ -173,7 +281,19 @@ func (h *RoomHandler) GetRoom(c *gin.Context) {
 
 // JoinRoom handles room joining
 func (h *RoomHandler) JoinRoom(c *gin.Context) {
+	var req JoinRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	roomID := c.Param("id")
+	if roomID == "" {
+		roomID = req.RoomID
+	}
 	if roomID == "" {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "invalid_request",
@@ -202,22 +322,37 @@ func (h *RoomHandler) JoinRoom(c *gin.Context) {
 	}
 
 	// Join room
-	updatedRoom, err := h.roomService.JoinRoom(roomID, userIDStr)
+	updatedRoom, err := h.roomService.JoinRoomWithAccess(roomID, userIDStr, room.Access{
+		Passcode: req.Passcode,
+		Invite:   req.Invite,
+	})
 	if err != nil {
 		statusCode := http.StatusBadRequest
 		errorCode := "join_room_failed"
 
 		// Handle specific error cases
-		switch err.Error() {
-		case "room not found":
+		switch {
+		case err.Error() == "room not found":
 			statusCode = http.StatusNotFound
 			errorCode = "room_not_found"
-		case "room is full":
+		case err.Error() == "room is full":
 			statusCode = http.StatusConflict
 			errorCode = "room_full"
-		case "room is not accepting new players":
+		case err.Error() == "room is not accepting new players":
 			statusCode = http.StatusConflict
 			errorCode = "room_not_accepting"
+		case errors.Is(err, room.ErrAccessDenied):
+			statusCode = http.StatusForbidden
+			errorCode = "access_denied"
+		case errors.Is(err, room.ErrInvalidPasscode):
+			statusCode = http.StatusForbidden
+			errorCode = "invalid_passcode"
+		case errors.Is(err, room.ErrInvalidInvite):
+			statusCode = http.StatusForbidden
+			errorCode = "invalid_invite"
+		case errors.Is(err, room.ErrPlayerBanned):
+			statusCode = http.StatusForbidden
+			errorCode = "player_banned"
 		}
 
 		c.JSON(statusCode, ErrorResponse{
@@ -227,6 +362,8 @@ func (h *RoomHandler) JoinRoom(c *gin.Context) {
 		return
 	}
 
+	h.broadcastRoomUpdate("player_joined", updatedRoom, userIDStr)
+
 	c.JSON(http.StatusOK, RoomResponse{
 		Room: updatedRoom,
 	})
@@ -293,6 +430,8 @@ func (h *RoomHandler) LeaveRoom(c *gin.Context) {
 		return
 	}
 
+	h.broadcastRoomUpdate("player_left", updatedRoom, userIDStr)
+
 	c.JSON(http.StatusOK, RoomResponse{
 		Room: updatedRoom,
 	})
@@ -331,29 +470,7 @@ func (h *RoomHandler) StartGame(c *gin.Context) {
 	// Start game
 	err := h.roomService.StartGame(roomID, userIDStr)
 	if err != nil {
-		statusCode := http.StatusBadRequest
-		errorCode := "start_game_failed"
-
-		// Handle specific error cases
-		switch {
-		case err.Error() == "room not found":
-			statusCode = http.StatusNotFound
-			errorCode = "room_not_found"
-		case err.Error() == "only room owner can start the game":
-			statusCode = http.StatusForbidden
-			errorCode = "not_room_owner"
-		case err.Error() == "room is not ready to start game":
-			statusCode = http.StatusConflict
-			errorCode = "room_not_ready"
-		case err.Error() == "room must have 4 players to start game":
-			statusCode = http.StatusConflict
-			errorCode = "insufficient_players"
-		}
-
-		c.JSON(statusCode, ErrorResponse{
-			Error:   errorCode,
-			Message: err.Error(),
-		})
+		respondStartGameError(c, err)
 		return
 	}
 
@@ -367,11 +484,43 @@ func (h *RoomHandler) StartGame(c *gin.Context) {
 		return
 	}
 
+	h.broadcastRoomUpdate("game_started", updatedRoom, userIDStr)
+
 	c.JSON(http.StatusOK, RoomResponse{
 		Room: updatedRoom,
 	})
 }
 
+// respondStartGameError writes the response for an error from RoomService.StartGame
+func respondStartGameError(c *gin.Context, err error) {
+	statusCode := http.StatusBadRequest
+	errorCode := "start_game_failed"
+
+	// Handle specific error cases
+	switch {
+	case err.Error() == "room not found":
+		statusCode = http.StatusNotFound
+		errorCode = "room_not_found"
+	case err.Error() == "only room owner can start the game":
+		statusCode = http.StatusForbidden
+		errorCode = "not_room_owner"
+	case err.Error() == "room is not ready to start game":
+		statusCode = http.StatusConflict
+		errorCode = "room_not_ready"
+	case err.Error() == "room must have 4 players to start game":
+		statusCode = http.StatusConflict
+		errorCode = "insufficient_players"
+	case errors.Is(err, room.ErrPlayersNotReady):
+		statusCode = http.StatusConflict
+		errorCode = "players_not_ready"
+	}
+
+	c.JSON(statusCode, ErrorResponse{
+		Error:   errorCode,
+		Message: err.Error(),
+	})
+}
+
 // GetMyRoom handles getting the current user's room
 func (h *RoomHandler) GetMyRoom(c *gin.Context) {
 	// Get authenticated user
@@ -416,6 +565,491 @@ func (h *RoomHandler) GetMyRoom(c *gin.Context) {
 	})
 }
 
+// AddBot handles seating a bot in an empty seat
+func (h *RoomHandler) AddBot(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req AddBotRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Reject unknown algorithms now rather than when the game starts
+	if _, err := game.NewBotAlgorithm(game.BotSpec{Algorithm: req.Algorithm, Difficulty: req.Difficulty}); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_bot",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	seat := -1
+	if req.Seat != nil {
+		seat = *req.Seat
+	}
+
+	updatedRoom, err := h.roomService.AddBot(c.Param("id"), userID, seat, req.Algorithm, req.Difficulty)
+	if err != nil {
+		respondRoomActionError(c, err, "add_bot_failed")
+		return
+	}
+
+	h.broadcastRoomUpdate("bot_added", updatedRoom, userID)
+
+	c.JSON(http.StatusOK, RoomResponse{
+		Room: updatedRoom,
+	})
+}
+
+// RemoveBot handles taking a bot out of its seat
+func (h *RoomHandler) RemoveBot(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	seat, err := strconv.Atoi(c.Param("seat"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Seat must be a number",
+		})
+		return
+	}
+
+	updatedRoom, err := h.roomService.RemoveBot(c.Param("id"), userID, seat)
+	if err != nil {
+		respondRoomActionError(c, err, "remove_bot_failed")
+		return
+	}
+
+	h.broadcastRoomUpdate("bot_removed", updatedRoom, userID)
+
+	c.JSON(http.StatusOK, RoomResponse{
+		Room: updatedRoom,
+	})
+}
+
+// UpdateSettings handles changing the game settings of a room
+func (h *RoomHandler) UpdateSettings(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	roomData, err := h.roomService.GetRoom(c.Param("id"))
+	if err != nil {
+		respondRoomActionError(c, err, "update_settings_failed")
+		return
+	}
+
+	// Fields left out of the body keep their current values
+	settings := roomData.Settings
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	updatedRoom, err := h.roomService.UpdateSettings(roomData.ID, userID, settings)
+	if err != nil {
+		respondRoomActionError(c, err, "update_settings_failed")
+		return
+	}
+
+	h.broadcastRoomUpdate("settings_updated", updatedRoom, userID)
+
+	c.JSON(http.StatusOK, RoomResponse{
+		Room: updatedRoom,
+	})
+}
+
+// SetPasscode handles setting or removing the passcode of a room
+func (h *RoomHandler) SetPasscode(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req SetPasscodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	updatedRoom, err := h.roomService.SetPasscode(c.Param("id"), userID, req.Passcode)
+	if err != nil {
+		respondRoomActionError(c, err, "set_passcode_failed")
+		return
+	}
+
+	h.broadcastRoomUpdate("passcode_updated", updatedRoom, userID)
+
+	c.JSON(http.StatusOK, RoomResponse{
+		Room: updatedRoom,
+	})
+}
+
+// CreateInvite handles creating an invite link and notifying invited players
+func (h *RoomHandler) CreateInvite(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	invite, err := h.roomService.CreateInvite(c.Param("id"), userID, time.Duration(req.ExpiresIn)*time.Second)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		errorCode := "create_invite_failed"
+
+		switch {
+		case errors.Is(err, room.ErrRoomNotFound):
+			statusCode = http.StatusNotFound
+			errorCode = "room_not_found"
+		case err.Error() == "player is not in this room":
+			statusCode = http.StatusForbidden
+			errorCode = "not_in_room"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   errorCode,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	link := "/room/" + url.PathEscape(invite.RoomID) + "?invite=" + url.QueryEscape(invite.Token)
+	notified := []string{}
+	if h.notifier != nil {
+		inviterName := ""
+		if inviter, err := h.authService.GetUserByID(userID); err == nil {
+			inviterName = inviter.Username
+		}
+		message := &websocket.WSMessage{
+			Type: websocket.MSG_ROOM_INVITE,
+			Data: map[string]interface{}{
+				"invite":       invite,
+				"link":         link,
+				"inviter_name": inviterName,
+			},
+			Timestamp: time.Now(),
+		}
+		// Players who are not connected are skipped
+		for _, playerID := range req.PlayerIDs {
+			if playerID != userID && h.notifier.SendToPlayer(playerID, message) == nil {
+				notified = append(notified, playerID)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, InviteResponse{
+		Invite:   invite,
+		Link:     link,
+		Notified: notified,
+	})
+}
+
+// ChooseSeat handles moving to an empty seat or asking its player to swap
+func (h *RoomHandler) ChooseSeat(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ChooseSeatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	updatedRoom, err := h.roomService.ChooseSeat(c.Param("id"), userID, *req.Seat)
+	if err != nil {
+		respondRoomActionError(c, err, "choose_seat_failed")
+		return
+	}
+
+	action := "seat_changed"
+	if player := updatedRoom.Players[*req.Seat]; player == nil || player.ID != userID {
+		action = "swap_requested"
+	}
+	h.broadcastRoomUpdate(action, updatedRoom, userID)
+
+	c.JSON(http.StatusOK, RoomResponse{
+		Room: updatedRoom,
+	})
+}
+
+// RespondSeatSwap handles accepting or declining a seat swap request
+func (h *RoomHandler) RespondSeatSwap(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req SeatSwapResponseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	updatedRoom, err := h.roomService.RespondSeatSwap(c.Param("id"), userID, req.RequesterID, req.Accept)
+	if err != nil {
+		respondRoomActionError(c, err, "seat_swap_failed")
+		return
+	}
+
+	action := "swap_declined"
+	if req.Accept {
+		action = "seats_swapped"
+	}
+	h.broadcastRoomUpdate(action, updatedRoom, userID)
+
+	c.JSON(http.StatusOK, RoomResponse{
+		Room: updatedRoom,
+	})
+}
+
+// MoveSeat handles the owner moving a player or bot to another seat
+func (h *RoomHandler) MoveSeat(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req MoveSeatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	updatedRoom, err := h.roomService.MoveSeat(c.Param("id"), userID, *req.From, *req.To)
+	if err != nil {
+		respondRoomActionError(c, err, "move_seat_failed")
+		return
+	}
+
+	h.broadcastRoomUpdate("seat_moved", updatedRoom, userID)
+
+	c.JSON(http.StatusOK, RoomResponse{
+		Room: updatedRoom,
+	})
+}
+
+// SetReady handles a player marking themselves ready or not ready
+func (h *RoomHandler) SetReady(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ReadyRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+	ready := req.Ready == nil || *req.Ready
+
+	updatedRoom, err := h.roomService.SetReady(c.Param("id"), userID, ready)
+	if err != nil {
+		respondRoomActionError(c, err, "set_ready_failed")
+		return
+	}
+
+	action := "player_ready"
+	if !ready {
+		action = "player_not_ready"
+	}
+	h.broadcastRoomUpdate(action, updatedRoom, userID)
+
+	c.JSON(http.StatusOK, RoomResponse{
+		Room: updatedRoom,
+	})
+}
+
+// KickPlayer handles the owner removing a player before the game starts
+func (h *RoomHandler) KickPlayer(c *gin.Context) {
+	h.moderate(c, "player_kicked", h.roomService.KickPlayer)
+}
+
+// BanPlayer handles the owner banning a player from the room
+func (h *RoomHandler) BanPlayer(c *gin.Context) {
+	h.moderate(c, "player_banned", h.roomService.BanPlayer)
+}
+
+// TransferOwnership handles the owner handing the room to another player
+func (h *RoomHandler) TransferOwnership(c *gin.Context) {
+	h.moderate(c, "owner_changed", h.roomService.TransferOwnership)
+}
+
+// UnbanPlayer handles the owner lifting a ban
+func (h *RoomHandler) UnbanPlayer(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	updatedRoom, err := h.roomService.UnbanPlayer(c.Param("id"), userID, c.Param("player_id"))
+	if err != nil {
+		respondRoomActionError(c, err, "unban_failed")
+		return
+	}
+
+	h.broadcastRoomUpdate("player_unbanned", updatedRoom, c.Param("player_id"))
+
+	c.JSON(http.StatusOK, RoomResponse{
+		Room: updatedRoom,
+	})
+}
+
+// moderate runs an owner action on the player named in the request body and
+// tells the room, and the player if they were removed from it
+func (h *RoomHandler) moderate(c *gin.Context, action string, apply func(roomID, ownerID, playerID string) (*room.Room, error)) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ModerationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	updatedRoom, err := apply(c.Param("id"), userID, req.PlayerID)
+	if err != nil {
+		respondRoomActionError(c, err, action+"_failed")
+		return
+	}
+
+	h.broadcastRoomUpdate(action, updatedRoom, req.PlayerID)
+	if h.notifier != nil && !isSeated(updatedRoom, req.PlayerID) {
+		h.notifier.RemoveFromRoom(updatedRoom.ID, req.PlayerID)
+		_ = h.notifier.SendToPlayer(req.PlayerID, roomUpdateMessage(action, updatedRoom, req.PlayerID))
+	}
+
+	c.JSON(http.StatusOK, RoomResponse{
+		Room: updatedRoom,
+	})
+}
+
+// isSeated reports whether a player has a seat in the room
+func isSeated(r *room.Room, playerID string) bool {
+	for _, player := range r.Players {
+		if player != nil && player.ID == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+// roomUpdateMessage builds a room_update message
+func roomUpdateMessage(action string, updatedRoom *room.Room, playerID string) *websocket.WSMessage {
+	return &websocket.WSMessage{
+		Type: websocket.MSG_ROOM_UPDATE,
+		Data: map[string]interface{}{
+			"action":    action,
+			"room":      updatedRoom,
+			"player_id": playerID,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// broadcastRoomUpdate pushes a room_update message to the players seated in the room
+func (h *RoomHandler) broadcastRoomUpdate(action string, updatedRoom *room.Room, playerID string) {
+	if h.notifier == nil || updatedRoom == nil {
+		return
+	}
+
+	message := roomUpdateMessage(action, updatedRoom, playerID)
+	for _, player := range updatedRoom.Players {
+		if player != nil && !player.Bot {
+			// Players without a connection catch up when they fetch the room
+			_ = h.notifier.SendToPlayer(player.ID, message)
+		}
+	}
+}
+
+// respondRoomActionError maps errors of room management actions to responses
+func respondRoomActionError(c *gin.Context, err error, errorCode string) {
+	statusCode := http.StatusBadRequest
+
+	switch {
+	case errors.Is(err, room.ErrRoomNotFound):
+		statusCode = http.StatusNotFound
+		errorCode = "room_not_found"
+	case errors.Is(err, room.ErrNotRoomOwner):
+		statusCode = http.StatusForbidden
+		errorCode = "not_room_owner"
+	case errors.Is(err, room.ErrRoomFull):
+		statusCode = http.StatusConflict
+		errorCode = "room_full"
+	case errors.Is(err, room.ErrSeatTaken):
+		statusCode = http.StatusConflict
+		errorCode = "seat_taken"
+	case errors.Is(err, room.ErrNotBot):
+		statusCode = http.StatusNotFound
+		errorCode = "bot_not_found"
+	case errors.Is(err, room.ErrGameInProgress):
+		statusCode = http.StatusConflict
+		errorCode = "game_in_progress"
+	case errors.Is(err, room.ErrInvalidSettings):
+		errorCode = "invalid_settings"
+	case errors.Is(err, room.ErrNotInRoom):
+		statusCode = http.StatusForbidden
+		errorCode = "not_in_room"
+	case errors.Is(err, room.ErrSwapRequestNotFound):
+		statusCode = http.StatusNotFound
+		errorCode = "swap_request_not_found"
+	case errors.Is(err, room.ErrOwnerTarget):
+		errorCode = "invalid_target"
+	case errors.Is(err, auth.ErrUserNotFound):
+		statusCode = http.StatusNotFound
+		errorCode = "user_not_found"
+	}
+
+	c.JSON(statusCode, ErrorResponse{
+		Error:   errorCode,
+		Message: err.Error(),
+	})
+}
+
 // RegisterRoutes registers all room routes
 func (h *RoomHandler) RegisterRoutes(router *gin.Engine, authHandler *AuthHandler) {
 	rooms := router.Group("/api/rooms")
@@ -423,12 +1057,25 @@ func (h *RoomHandler) RegisterRoutes(router *gin.Engine, authHandler *AuthHandle
 		// Public routes (with authentication)
 		rooms.Use(authHandler.JWTMiddleware())
 		
-		rooms.GET("", h.GetRooms)                    // GET /api/rooms - list rooms with pagination
-		rooms.POST("", h.CreateRoom)                 // POST /api/rooms - create room
-		rooms.GET("/my", h.GetMyRoom)                // GET /api/rooms/my - get current user's room
-		rooms.GET("/:id", h.GetRoom)                 // GET /api/rooms/:id - get specific room
-		rooms.POST("/:id/join", h.JoinRoom)          // POST /api/rooms/:id/join - join room
-		rooms.POST("/:id/leave", h.LeaveRoom)        // POST /api/rooms/:id/leave - leave room
-		rooms.POST("/:id/start", h.StartGame)        // POST /api/rooms/:id/start - start game
+		rooms.GET("", h.GetRooms)                          // GET /api/rooms - list rooms with pagination
+		rooms.POST("", h.CreateRoom)                       // POST /api/rooms - create room
+		rooms.GET("/my", h.GetMyRoom)                      // GET /api/rooms/my - get current user's room
+		rooms.GET("/:id", h.GetRoom)                       // GET /api/rooms/:id - get specific room
+		rooms.POST("/:id/join", h.JoinRoom)                // POST /api/rooms/:id/join - join room
+		rooms.POST("/:id/leave", h.LeaveRoom)              // POST /api/rooms/:id/leave - leave room
+		rooms.POST("/:id/start", h.StartGame)              // POST /api/rooms/:id/start - start game
+		rooms.POST("/:id/bots", h.AddBot)                  // POST /api/rooms/:id/bots - seat a bot
+		rooms.DELETE("/:id/bots/:seat", h.RemoveBot)       // DELETE /api/rooms/:id/bots/:seat - remove a bot
+		rooms.PUT("/:id/settings", h.UpdateSettings)       // PUT /api/rooms/:id/settings - change game settings
+		rooms.PUT("/:id/passcode", h.SetPasscode)          // PUT /api/rooms/:id/passcode - set or remove the passcode
+		rooms.POST("/:id/invite", h.CreateInvite)          // POST /api/rooms/:id/invite - create an invite link
+		rooms.POST("/:id/seat", h.ChooseSeat)              // POST /api/rooms/:id/seat - take a seat or ask to swap
+		rooms.POST("/:id/swap", h.RespondSeatSwap)         // POST /api/rooms/:id/swap - answer a seat swap request
+		rooms.POST("/:id/move", h.MoveSeat)                // POST /api/rooms/:id/move - owner moves a seat
+		rooms.POST("/:id/ready", h.SetReady)               // POST /api/rooms/:id/ready - set the ready flag
+		rooms.POST("/:id/kick", h.KickPlayer)              // POST /api/rooms/:id/kick - owner kicks a player
+		rooms.POST("/:id/ban", h.BanPlayer)                // POST /api/rooms/:id/ban - owner bans a player
+		rooms.DELETE("/:id/ban/:player_id", h.UnbanPlayer) // DELETE /api/rooms/:id/ban/:player_id - lift a ban
+		rooms.POST("/:id/transfer", h.TransferOwnership)   // POST /api/rooms/:id/transfer - hand over ownership
 	}
 }
\ No newline at end of file
@@ -10,7 +10,10 @@ import (
 	"time"
 
 	"guandan-world/backend/auth"
+	"guandan-world/backend/game"
 	"guandan-world/backend/room"
+	"guandan-world/backend/websocket"
+	"guandan-world/sdk"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -409,6 +412,17 @@ func TestRoomHandler_StartGame(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	}
 	
+	// Everyone but the owner has to be ready
+	req = httptest.NewRequest("POST", fmt.Sprintf("/api/rooms/%s/start", roomID), nil)
+	req.Header.Set("Authorization", "Bearer "+tokens[0])
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	for i := 1; i < 4; i++ {
+		w = roomBotRequest(router, "POST", fmt.Sprintf("/api/rooms/%s/ready", roomID), tokens[i], nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	
 	// Start game
 	req = httptest.NewRequest("POST", fmt.Sprintf("/api/rooms/%s/start", roomID), nil)
 	req.Header.Set("Authorization", "Bearer "+tokens[0])
@@ -546,4 +560,323 @@ func TestRoomHandler_GetMyRoom_NotInRoom(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &errorResp)
 	assert.NoError(t, err)
 	assert.Equal(t, "not_in_room", errorResp.Error)
-}
\ No newline at end of file
+}
+func roomBotRequest(router *gin.Engine, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRoomHandler_AddRemoveBot(t *testing.T) {
+	router, _, _, _, roomService := setupRoomTestRouter()
+	ownerToken, owner := createTestUserAndLogin(t, router, "botowner")
+	guestToken, guest := createTestUserAndLogin(t, router, "botguest")
+
+	createdRoom, err := roomService.CreateRoom(owner.ID)
+	assert.NoError(t, err)
+	_, err = roomService.JoinRoom(createdRoom.ID, guest.ID)
+	assert.NoError(t, err)
+	botsPath := "/api/rooms/" + createdRoom.ID + "/bots"
+
+	w := roomBotRequest(router, "POST", botsPath, guestToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = roomBotRequest(router, "POST", botsPath, ownerToken, AddBotRequest{Algorithm: "no-such-bot"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Weight files and commands on the server can only be named when registered
+	for _, algorithm := range []string{"smart:/etc/passwd", "neural:/dev/zero", "process:sh"} {
+		w = roomBotRequest(router, "POST", botsPath, ownerToken, AddBotRequest{Algorithm: algorithm})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.NotContains(t, w.Body.String(), "failed to load")
+	}
+
+	seat := 1
+	w = roomBotRequest(router, "POST", botsPath, ownerToken, AddBotRequest{Seat: &seat})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Without a body the default bot takes the first empty seat
+	w = roomBotRequest(router, "POST", botsPath, ownerToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	seat = 3
+	w = roomBotRequest(router, "POST", botsPath, ownerToken, AddBotRequest{Seat: &seat, Difficulty: "beginner"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response RoomResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, room.RoomStatusReady, response.Room.Status)
+	assert.True(t, response.Room.Players[2].Bot)
+	assert.Equal(t, "beginner", response.Room.Players[3].BotDifficulty)
+
+	w = roomBotRequest(router, "DELETE", botsPath+"/1", ownerToken, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = roomBotRequest(router, "DELETE", botsPath+"/3", ownerToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Nil(t, response.Room.Players[3])
+	assert.Equal(t, room.RoomStatusWaiting, response.Room.Status)
+
+	// Games started for the room play the remaining bot on the server
+	gameRoom, err := roomService.GetRoom(createdRoom.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]game.BotSpec{2: {}}, roomOptions(gameRoom).Bots)
+}
+
+func TestRoomHandler_Settings(t *testing.T) {
+	router, _, _, _, roomService := setupRoomTestRouter()
+	ownerToken, _ := createTestUserAndLogin(t, router, "settingsowner")
+	guestToken, guest := createTestUserAndLogin(t, router, "settingsguest")
+
+	w := roomBotRequest(router, "POST", "/api/rooms", ownerToken, map[string]interface{}{
+		"settings": map[string]interface{}{"turn_timeout": 1},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Fields left out keep their defaults
+	w = roomBotRequest(router, "POST", "/api/rooms", ownerToken, map[string]interface{}{
+		"settings": map[string]interface{}{"starting_levels": []int{5, 3}, "private": true},
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response RoomResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, [2]int{5, 3}, response.Room.Settings.StartingLevels)
+	assert.Equal(t, 30, response.Room.Settings.TurnTimeout)
+	assert.True(t, response.Room.Settings.Private)
+	settingsPath := "/api/rooms/" + response.Room.ID + "/settings"
+
+	invite, err := roomService.CreateInvite(response.Room.ID, response.Room.Owner, 0)
+	assert.NoError(t, err)
+	_, err = roomService.JoinRoomWithAccess(response.Room.ID, guest.ID, room.Access{Invite: invite.Token})
+	assert.NoError(t, err)
+	w = roomBotRequest(router, "PUT", settingsPath, guestToken, map[string]interface{}{"allow_hints": false})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = roomBotRequest(router, "PUT", settingsPath, ownerToken, map[string]interface{}{"match_length": "fixed_deals"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = roomBotRequest(router, "PUT", settingsPath, ownerToken, map[string]interface{}{
+		"variant":      "no_tribute",
+		"match_length": "fixed_deals",
+		"deals":        4,
+		"allow_hints":  false,
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, [2]int{5, 3}, response.Room.Settings.StartingLevels)
+
+	// Games started for the room are played with its settings
+	options := roomOptions(response.Room)
+	assert.Equal(t, sdk.RuleVariantNoTribute, options.Variant)
+	assert.Equal(t, 4, options.MaxDeals)
+	assert.Equal(t, 30*time.Second, options.TurnTimeout)
+	assert.True(t, options.NoHints)
+}
+
+// recordingNotifier records pushed messages; players in offline are not connected
+type recordingNotifier struct {
+	offline  map[string]bool
+	messages map[string][]*websocket.WSMessage
+	removed  []string // roomID/playerID of connections taken out of a room
+}
+
+func (n *recordingNotifier) RemoveFromRoom(roomID, playerID string) {
+	n.removed = append(n.removed, roomID+"/"+playerID)
+}
+
+func (n *recordingNotifier) IsPlayerConnected(playerID string) bool {
+	return !n.offline[playerID]
+}
+
+func (n *recordingNotifier) SendToPlayer(playerID string, message *websocket.WSMessage) error {
+	if n.offline[playerID] {
+		return fmt.Errorf("player %s not connected", playerID)
+	}
+	if n.messages == nil {
+		n.messages = make(map[string][]*websocket.WSMessage)
+	}
+	n.messages[playerID] = append(n.messages[playerID], message)
+	return nil
+}
+
+func TestRoomHandler_PrivateRooms(t *testing.T) {
+	router, _, roomHandler, _, _ := setupRoomTestRouter()
+	notifier := &recordingNotifier{offline: map[string]bool{}}
+	roomHandler.SetNotifier(notifier)
+	ownerToken, _ := createTestUserAndLogin(t, router, "privateowner")
+	guestToken, _ := createTestUserAndLogin(t, router, "privateguest")
+	friendToken, friend := createTestUserAndLogin(t, router, "privatefriend")
+	_, offline := createTestUserAndLogin(t, router, "privateoffline")
+	notifier.offline[offline.ID] = true
+
+	w := roomBotRequest(router, "POST", "/api/rooms", ownerToken, CreateRoomRequest{Passcode: "abc"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	settings := room.DefaultSettings()
+	settings.Private = true
+	w = roomBotRequest(router, "POST", "/api/rooms", ownerToken, CreateRoomRequest{Settings: &settings, Passcode: "letmein"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "passcode\":\"")
+	var response RoomResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Room.HasPasscode)
+	roomPath := "/api/rooms/" + response.Room.ID
+
+	w = roomBotRequest(router, "POST", roomPath+"/join", guestToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = roomBotRequest(router, "POST", roomPath+"/join", guestToken, JoinRoomRequest{Passcode: "wrong"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = roomBotRequest(router, "POST", roomPath+"/join", guestToken, JoinRoomRequest{Passcode: "letmein"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = roomBotRequest(router, "POST", roomPath+"/invite", friendToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = roomBotRequest(router, "POST", roomPath+"/invite", guestToken, CreateInviteRequest{
+		ExpiresIn: 3600,
+		PlayerIDs: []string{friend.ID, offline.ID},
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var invite InviteResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &invite))
+	assert.Equal(t, []string{friend.ID}, invite.Notified)
+	assert.Contains(t, invite.Link, "/room/"+response.Room.ID+"?invite=")
+	if assert.Len(t, notifier.messages[friend.ID], 1) {
+		assert.Equal(t, websocket.MSG_ROOM_INVITE, notifier.messages[friend.ID][0].Type)
+	}
+
+	w = roomBotRequest(router, "POST", roomPath+"/join", friendToken, JoinRoomRequest{Invite: invite.Invite.Token})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Only the owner changes the passcode
+	w = roomBotRequest(router, "PUT", roomPath+"/passcode", guestToken, SetPasscodeRequest{})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = roomBotRequest(router, "PUT", roomPath+"/passcode", ownerToken, SetPasscodeRequest{})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Room.HasPasscode)
+}
+
+func TestRoomHandler_SeatsAndReady(t *testing.T) {
+	router, _, roomHandler, _, roomService := setupRoomTestRouter()
+	notifier := &recordingNotifier{}
+	roomHandler.SetNotifier(notifier)
+	ownerToken, owner := createTestUserAndLogin(t, router, "seatowner")
+	guestToken, guest := createTestUserAndLogin(t, router, "seatguest")
+	friendToken, friend := createTestUserAndLogin(t, router, "seatfriend")
+
+	createdRoom, err := roomService.CreateRoom(owner.ID)
+	assert.NoError(t, err)
+	roomPath := "/api/rooms/" + createdRoom.ID
+	for _, token := range []string{guestToken, friendToken} {
+		w := roomBotRequest(router, "POST", roomPath+"/join", token, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	assert.Equal(t, "player_joined", notifier.messages[owner.ID][1].Data.(map[string]interface{})["action"])
+
+	w := roomBotRequest(router, "POST", roomPath+"/seat", guestToken, map[string]int{"seat": 7})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	seat := 3
+	w = roomBotRequest(router, "POST", roomPath+"/seat", guestToken, ChooseSeatRequest{Seat: &seat})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// The friend in seat 2 is asked to swap with the guest
+	seat = 2
+	w = roomBotRequest(router, "POST", roomPath+"/seat", guestToken, ChooseSeatRequest{Seat: &seat})
+	assert.Equal(t, http.StatusOK, w.Code)
+	last := notifier.messages[friend.ID][len(notifier.messages[friend.ID])-1]
+	assert.Equal(t, websocket.MSG_ROOM_UPDATE, last.Type)
+	assert.Equal(t, "swap_requested", last.Data.(map[string]interface{})["action"])
+
+	w = roomBotRequest(router, "POST", roomPath+"/swap", ownerToken, SeatSwapResponseRequest{RequesterID: guest.ID, Accept: true})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = roomBotRequest(router, "POST", roomPath+"/swap", friendToken, SeatSwapResponseRequest{RequesterID: guest.ID, Accept: true})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response RoomResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, guest.ID, response.Room.Players[2].ID)
+	assert.Equal(t, friend.ID, response.Room.Players[3].ID)
+
+	from, to := 3, 1
+	w = roomBotRequest(router, "POST", roomPath+"/move", guestToken, MoveSeatRequest{From: &from, To: &to})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = roomBotRequest(router, "POST", roomPath+"/move", ownerToken, MoveSeatRequest{From: &from, To: &to})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, friend.ID, response.Room.Players[1].ID)
+
+	notReady := false
+	w = roomBotRequest(router, "POST", roomPath+"/ready", guestToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = roomBotRequest(router, "POST", roomPath+"/ready", guestToken, ReadyRequest{Ready: &notReady})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Room.Players[2].Ready)
+	last = notifier.messages[owner.ID][len(notifier.messages[owner.ID])-1]
+	assert.Equal(t, "player_not_ready", last.Data.(map[string]interface{})["action"])
+}
+
+func TestRoomHandler_Moderation(t *testing.T) {
+	router, _, roomHandler, _, roomService := setupRoomTestRouter()
+	notifier := &recordingNotifier{}
+	roomHandler.SetNotifier(notifier)
+	ownerToken, owner := createTestUserAndLogin(t, router, "modowner")
+	guestToken, guest := createTestUserAndLogin(t, router, "modguest")
+	friendToken, friend := createTestUserAndLogin(t, router, "modfriend")
+
+	createdRoom, err := roomService.CreateRoom(owner.ID)
+	assert.NoError(t, err)
+	roomPath := "/api/rooms/" + createdRoom.ID
+	for _, token := range []string{guestToken, friendToken} {
+		w := roomBotRequest(router, "POST", roomPath+"/join", token, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	w := roomBotRequest(router, "POST", roomPath+"/kick", ownerToken, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = roomBotRequest(router, "POST", roomPath+"/kick", guestToken, ModerationRequest{PlayerID: friend.ID})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = roomBotRequest(router, "POST", roomPath+"/kick", ownerToken, ModerationRequest{PlayerID: owner.ID})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// The kicked player hears about it even though they left the room
+	w = roomBotRequest(router, "POST", roomPath+"/kick", ownerToken, ModerationRequest{PlayerID: guest.ID})
+	assert.Equal(t, http.StatusOK, w.Code)
+	last := notifier.messages[guest.ID][len(notifier.messages[guest.ID])-1]
+	assert.Equal(t, "player_kicked", last.Data.(map[string]interface{})["action"])
+	assert.Equal(t, []string{createdRoom.ID + "/" + guest.ID}, notifier.removed)
+	last = notifier.messages[friend.ID][len(notifier.messages[friend.ID])-1]
+	assert.Equal(t, "player_kicked", last.Data.(map[string]interface{})["action"])
+
+	w = roomBotRequest(router, "POST", roomPath+"/ban", ownerToken, ModerationRequest{PlayerID: "nobody"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = roomBotRequest(router, "POST", roomPath+"/ban", ownerToken, ModerationRequest{PlayerID: guest.ID})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, notifier.removed, 2)
+	w = roomBotRequest(router, "POST", roomPath+"/join", guestToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "player_banned")
+
+	w = roomBotRequest(router, "DELETE", roomPath+"/ban/"+guest.ID, guestToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = roomBotRequest(router, "DELETE", roomPath+"/ban/"+guest.ID, ownerToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = roomBotRequest(router, "POST", roomPath+"/join", guestToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = roomBotRequest(router, "POST", roomPath+"/transfer", ownerToken, ModerationRequest{PlayerID: friend.ID})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response RoomResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, friend.ID, response.Room.Owner)
+	last = notifier.messages[guest.ID][len(notifier.messages[guest.ID])-1]
+	assert.Equal(t, "owner_changed", last.Data.(map[string]interface{})["action"])
+
+	w = roomBotRequest(router, "POST", roomPath+"/kick", ownerToken, ModerationRequest{PlayerID: guest.ID})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
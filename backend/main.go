@@ -1,14 +1,21 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"guandan-world/backend/auth"
 	"guandan-world/backend/game"
 	"guandan-world/backend/handlers"
+	"guandan-world/backend/matchmaking"
+	"guandan-world/backend/profile"
+	"guandan-world/backend/rating"
 	"guandan-world/backend/room"
+	"guandan-world/backend/store"
 	"guandan-world/backend/websocket"
 
 	"github.com/gin-gonic/gin"
@@ -22,7 +29,7 @@ func main() {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -31,25 +38,79 @@ func main() {
 		c.Next()
 	})
 
+	// 打开数据库（GUANDAN_DB_PATH 指定路径，默认 guandan.db）
+	dbPath := os.Getenv("GUANDAN_DB_PATH")
+	if dbPath == "" {
+		dbPath = "guandan.db"
+	}
+	db, err := store.Open(dbPath)
+	if err != nil {
+		log.Fatalf("Failed to open database %s: %v", dbPath, err)
+	}
+	defer db.Close()
+
+	// 登录令牌的签名密钥
+	secret := "your-secret-key-change-in-production"
+
 	// 初始化认证服务
-	authService := auth.NewAuthService("your-secret-key-change-in-production", 24*time.Hour)
+	authService := auth.NewAuthServiceWithRepositories(db.Users(), db.Tokens(), secret, 24*time.Hour)
 	authHandler := handlers.NewAuthHandler(authService)
 
 	// 初始化房间服务
-	roomService := room.NewRoomService(authService)
+	roomService, err := room.NewRoomServiceWithRepository(authService, db.Rooms())
+	if err != nil {
+		log.Fatalf("Failed to load rooms: %v", err)
+	}
+	// 房间邀请使用独立的签名密钥（GUANDAN_INVITE_SECRET），生产环境（GIN_MODE=release）必须设置；
+	// 未设置时使用进程启动时随机生成的密钥，重启后已发出的邀请失效
+	if inviteSecret := os.Getenv("GUANDAN_INVITE_SECRET"); inviteSecret != "" {
+		roomService.SetInviteSecret(inviteSecret)
+	} else if gin.Mode() == gin.ReleaseMode {
+		log.Fatal("GUANDAN_INVITE_SECRET must be set in production")
+	} else {
+		log.Println("GUANDAN_INVITE_SECRET is not set, signing invites with a random key")
+	}
 
 	// 初始化 WebSocket 管理器
 	wsManager := websocket.NewWSManager(authService, roomService)
 
 	// 初始化房间处理器
 	roomHandler := handlers.NewRoomHandler(roomService, authService)
+	roomHandler.SetNotifier(wsManager)
 
 	// 初始化游戏服务（保留以备将来使用）
 	_ = game.NewGameService(wsManager)
 
 	// 初始化游戏驱动服务
 	driverService := game.NewDriverService(wsManager)
+	driverService.SetMatchArchive(db.Matches())
+	ratingService := rating.NewService(db.Ratings())
+	driverService.SetRatingService(ratingService)
+	matchHandler := handlers.NewMatchHandler(db.Matches())
+	profileHandler := handlers.NewProfileHandler(profile.NewProfileService(authService, db.Matches(), ratingService))
 	gameDriverHandler := handlers.NewGameDriverHandler(driverService)
+	gameDriverHandler.SetRoomService(roomService)
+	wsManager.RegisterHandler(websocket.MSG_GET_HINTS, driverService.HandleGetHints)
+
+	// 注册服务端机器人，房间中以 "<类型>:<名称>" 使用；客户端不能指定服务端读取的文件或执行的命令
+	// 各变量格式为 "名称=参数;名称=参数"：GUANDAN_SMART_BOTS、GUANDAN_NEURAL_BOTS 的参数为权重文件，
+	// GUANDAN_PROCESS_BOTS 的参数为外部机器人命令行
+	registerBots(game.BotKindSmart, "GUANDAN_SMART_BOTS")
+	registerBots(game.BotKindNeural, "GUANDAN_NEURAL_BOTS")
+	registerBots(game.BotKindProcess, "GUANDAN_PROCESS_BOTS")
+
+	// 初始化匹配队列（GUANDAN_BOT_BACKFILL_AFTER 指定等待多久后用机器人补位，如 30s；0 表示不补位）
+	matchmakingConfig := matchmaking.DefaultConfig()
+	if backfill := os.Getenv("GUANDAN_BOT_BACKFILL_AFTER"); backfill != "" {
+		after, err := time.ParseDuration(backfill)
+		if err != nil {
+			log.Fatalf("Invalid GUANDAN_BOT_BACKFILL_AFTER %q: %v", backfill, err)
+		}
+		matchmakingConfig.BotBackfillAfter = after
+	}
+	matchmakingService := matchmaking.NewService(matchmakingConfig, roomService, authService, ratingService, driverService, wsManager)
+	queueHandler := handlers.NewQueueHandler(matchmakingService)
+	go matchmakingService.Run(context.Background())
 
 	// 启动 WebSocket 管理器
 	go wsManager.Run()
@@ -72,6 +133,19 @@ func main() {
 				roomRoutes.GET("/", roomHandler.GetRooms)
 				roomRoutes.GET("/:id", roomHandler.GetRoom)
 				roomRoutes.POST("/:id/start", roomHandler.StartGame)
+				roomRoutes.POST("/:id/bots", roomHandler.AddBot)
+				roomRoutes.DELETE("/:id/bots/:seat", roomHandler.RemoveBot)
+				roomRoutes.PUT("/:id/settings", roomHandler.UpdateSettings)
+				roomRoutes.PUT("/:id/passcode", roomHandler.SetPasscode)
+				roomRoutes.POST("/:id/invite", roomHandler.CreateInvite)
+				roomRoutes.POST("/:id/seat", roomHandler.ChooseSeat)
+				roomRoutes.POST("/:id/swap", roomHandler.RespondSeatSwap)
+				roomRoutes.POST("/:id/move", roomHandler.MoveSeat)
+				roomRoutes.POST("/:id/ready", roomHandler.SetReady)
+				roomRoutes.POST("/:id/kick", roomHandler.KickPlayer)
+				roomRoutes.POST("/:id/ban", roomHandler.BanPlayer)
+				roomRoutes.DELETE("/:id/ban/:player_id", roomHandler.UnbanPlayer)
+				roomRoutes.POST("/:id/transfer", roomHandler.TransferOwnership)
 			}
 
 			// 游戏驱动路由
@@ -79,11 +153,32 @@ func main() {
 			{
 				driverRoutes.POST("/start", gameDriverHandler.StartGameWithDriver)
 				driverRoutes.POST("/play-decision", gameDriverHandler.SubmitPlayDecision)
+				driverRoutes.POST("/interpretations", gameDriverHandler.GetPlayInterpretations)
+				driverRoutes.POST("/hints", gameDriverHandler.GetHints)
 				driverRoutes.POST("/tribute-select", gameDriverHandler.SubmitTributeSelection)
 				driverRoutes.POST("/tribute-return", gameDriverHandler.SubmitReturnTribute)
 				driverRoutes.GET("/status/:room_id", gameDriverHandler.GetGameStatus)
 				driverRoutes.POST("/stop/:room_id", gameDriverHandler.StopGame)
 			}
+
+			// 对局历史与回放路由
+			matchRoutes := protected.Group("/matches")
+			{
+				matchRoutes.GET("", matchHandler.ListMatches)
+				matchRoutes.GET("/:id", matchHandler.GetMatch)
+				matchRoutes.GET("/:id/deals/:n/replay", matchHandler.GetDealReplay)
+			}
+
+			// 玩家资料与排行榜路由
+			protected.GET("/users/:id/profile", profileHandler.GetProfile)
+			protected.GET("/leaderboard", profileHandler.GetLeaderboard)
+
+			// 匹配队列路由
+			protected.POST("/queue", queueHandler.JoinQueue)
+			protected.POST("/queue/accept", queueHandler.AcceptPairInvite)
+			protected.POST("/queue/decline", queueHandler.DeclinePairInvite)
+			protected.GET("/queue", queueHandler.GetQueueStatus)
+			protected.DELETE("/queue", queueHandler.LeaveQueue)
 		}
 	}
 
@@ -122,3 +217,20 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// registerBots 注册环境变量 envName 中配置的 kind 类型机器人，配置无效时终止启动
+func registerBots(kind, envName string) {
+	bots := os.Getenv(envName)
+	if bots == "" {
+		return
+	}
+	for _, entry := range strings.Split(bots, ";") {
+		name, arg, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || strings.TrimSpace(arg) == "" {
+			log.Fatalf("Invalid %s entry %q", envName, entry)
+		}
+		if err := game.RegisterBot(kind, strings.TrimSpace(name), strings.TrimSpace(arg)); err != nil {
+			log.Fatalf("Invalid %s entry %q: %v", envName, entry, err)
+		}
+	}
+}
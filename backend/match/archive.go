@@ -0,0 +1,164 @@
+package match
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"guandan-world/sdk"
+)
+
+// ErrMatchNotFound is returned when an archived match does not exist
+var ErrMatchNotFound = errors.New("match not found")
+
+// Player is a participant of an archived match
+type Player struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Seat     int    `json:"seat"`
+	Bot      bool   `json:"bot,omitempty"` // Seat played by a server bot, such as a matchmaking backfill
+}
+
+// Record is a finished match kept in the archive
+type Record struct {
+	ID          string           `json:"id"`
+	RoomID      string           `json:"room_id"`
+	Ranked      bool             `json:"ranked"` // Counts towards player ratings
+	Players     []Player         `json:"players"`
+	Winner      int              `json:"winner"`       // Winning team (0 or 1)
+	FinalLevels [2]int           `json:"final_levels"` // Final levels of both teams
+	StartedAt   time.Time        `json:"started_at"`
+	FinishedAt  time.Time        `json:"finished_at"`
+	Result      *sdk.MatchResult `json:"result,omitempty"`
+	Deals       []*DealRecord    `json:"deals,omitempty"`
+}
+
+// HasPlayer reports whether the user took part in the match
+func (r *Record) HasPlayer(playerID string) bool {
+	for _, player := range r.Players {
+		if player.ID == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary is the outcome of an archived match without its deals, enough to rank players
+type Summary struct {
+	ID         string    `json:"id"`
+	Players    []Player  `json:"players"`
+	Winner     int       `json:"winner"` // Winning team (0 or 1)
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// Archive stores finished matches
+type Archive interface {
+	Save(record *Record) error
+	Get(matchID string) (*Record, error)
+	// List returns matches newest first; an empty playerID lists every match
+	List(playerID string, limit, offset int) ([]*Record, error)
+	// Summaries returns the outcome of every match finished at or after since,
+	// newest first, without loading the deals; a zero since covers every match
+	Summaries(since time.Time) ([]*Summary, error)
+}
+
+// memoryArchive keeps matches in memory, used by tests and when no database is configured
+type memoryArchive struct {
+	records map[string]*Record // matchID -> Record
+	mu      sync.RWMutex
+}
+
+// NewMemoryArchive creates an in-memory match archive
+func NewMemoryArchive() Archive {
+	return &memoryArchive{
+		records: make(map[string]*Record),
+	}
+}
+
+// Save inserts or replaces a match
+func (a *memoryArchive) Save(record *Record) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.records[record.ID] = record.clone()
+	return nil
+}
+
+// Get retrieves a match by ID
+func (a *memoryArchive) Get(matchID string) (*Record, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	record, exists := a.records[matchID]
+	if !exists {
+		return nil, ErrMatchNotFound
+	}
+	return record.clone(), nil
+}
+
+// List returns matches newest first, optionally only those the player took part in
+func (a *memoryArchive) List(playerID string, limit, offset int) ([]*Record, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var records []*Record
+	for _, record := range a.records {
+		if playerID == "" || record.HasPlayer(playerID) {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].FinishedAt.Equal(records[j].FinishedAt) {
+			return records[i].FinishedAt.After(records[j].FinishedAt)
+		}
+		return records[i].ID > records[j].ID
+	})
+
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(records) {
+		return []*Record{}, nil
+	}
+	records = records[offset:]
+	if limit > 0 && limit < len(records) {
+		records = records[:limit]
+	}
+
+	result := make([]*Record, len(records))
+	for i, record := range records {
+		result[i] = record.clone()
+	}
+	return result, nil
+}
+
+// Summaries returns the outcome of the matches finished at or after since, newest first
+func (a *memoryArchive) Summaries(since time.Time) ([]*Summary, error) {
+	records, err := a.List("", 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []*Summary{}
+	for _, record := range records {
+		if record.FinishedAt.Before(since) {
+			continue
+		}
+		summaries = append(summaries, &Summary{
+			ID:         record.ID,
+			Players:    record.Players,
+			Winner:     record.Winner,
+			FinishedAt: record.FinishedAt,
+		})
+	}
+	return summaries, nil
+}
+
+// clone copies the record so callers cannot change the stored player and deal lists
+func (r *Record) clone() *Record {
+	copied := *r
+	copied.Players = append([]Player(nil), r.Players...)
+	copied.Deals = append([]*DealRecord(nil), r.Deals...)
+	return &copied
+}
@@ -0,0 +1,93 @@
+package match
+
+import (
+	"errors"
+	"time"
+
+	"guandan-world/sdk"
+)
+
+// ErrDealNotFound is returned when a match has no deal with the requested number
+var ErrDealNotFound = errors.New("deal not found")
+
+// ActionType is the kind of an action in a deal's log
+type ActionType string
+
+const (
+	ActionTribute       ActionType = "tribute"        // Seat gives a tribute card to Target
+	ActionReturnTribute ActionType = "return_tribute" // Seat returns a card to Target
+	ActionPlay          ActionType = "play"           // Seat plays cards
+	ActionPass          ActionType = "pass"           // Seat passes
+	ActionTrickWon      ActionType = "trick_won"      // The trick ended and Seat won it
+)
+
+// Action is one step of a deal, in the order it happened
+type Action struct {
+	Type        ActionType `json:"type"`
+	Seat        int        `json:"seat"`
+	Target      int        `json:"target"`                // Receiving seat for tribute actions, -1 otherwise
+	Cards       []string   `json:"cards,omitempty"`       // Card IDs
+	Substitutes []string   `json:"substitutes,omitempty"` // Declared wildcard substitutes, as card IDs
+	Bomb        bool       `json:"bomb,omitempty"`        // Whether a play was a bomb
+	At          time.Time  `json:"at"`
+}
+
+// DealRecord is one archived deal of a match
+type DealRecord struct {
+	Number     int             `json:"number"`      // 1-based deal number
+	Level      int             `json:"level"`       // Level played in this deal
+	TeamLevels [2]int          `json:"team_levels"` // Team levels when the deal started
+	Hands      [4][]string     `json:"hands"`       // Card IDs dealt to each seat, before tribute
+	Actions    []Action        `json:"actions,omitempty"`
+	Result     *sdk.DealResult `json:"result,omitempty"`
+	StartedAt  time.Time       `json:"started_at"`
+}
+
+// Replay is a deal as shown to one viewer
+type Replay struct {
+	MatchID      string   `json:"match_id"`
+	Players      []Player `json:"players"`
+	HandsVisible bool     `json:"hands_visible"` // false when the viewer did not take part and hidden cards are removed
+	*DealRecord
+}
+
+// Overview returns a copy of the match without hands and action logs, for listings
+func (r *Record) Overview() *Record {
+	overview := r.clone()
+	for i, deal := range overview.Deals {
+		summary := *deal
+		summary.Hands = [4][]string{}
+		summary.Actions = nil
+		overview.Deals[i] = &summary
+	}
+	return overview
+}
+
+// Replay returns a deal of the match as seen by viewerID.
+// Participants see every hand; everyone else only sees the cards that were played openly.
+func (r *Record) Replay(number int, viewerID string) (*Replay, error) {
+	if number < 1 || number > len(r.Deals) {
+		return nil, ErrDealNotFound
+	}
+
+	deal := *r.Deals[number-1]
+	visible := r.HasPlayer(viewerID)
+	if !visible {
+		deal.Hands = [4][]string{}
+		deal.Actions = make([]Action, len(r.Deals[number-1].Actions))
+		for i, action := range r.Deals[number-1].Actions {
+			// Only the two players involved know the returned card
+			if action.Type == ActionReturnTribute {
+				action.Cards = nil
+			}
+			deal.Actions[i] = action
+		}
+	}
+
+	return &Replay{
+		MatchID:      r.ID,
+		Players:      append([]Player(nil), r.Players...),
+		HandsVisible: visible,
+		DealRecord:   &deal,
+	}, nil
+}
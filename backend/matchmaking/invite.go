@@ -0,0 +1,160 @@
+package matchmaking
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrPartnerOffline is returned when inviting a partner who is not connected
+	ErrPartnerOffline = errors.New("partner is not online")
+	// ErrInviteNotFound is returned when answering a pair invite that was withdrawn or has expired
+	ErrInviteNotFound = errors.New("pair invite not found")
+)
+
+// pairInvite asks a partner to queue with the inviter as a pre-made pair
+type pairInvite struct {
+	id        string
+	inviterID string
+	partnerID string
+	status    *Status // Queue status when it was sent, with the expiry
+}
+
+// invite asks partnerID to queue with playerID. Nothing is queued until the
+// partner accepts, so nobody can be put into a match without agreeing to it.
+func (s *service) invite(playerID, partnerID string) (*Status, error) {
+	if partnerID == playerID {
+		return nil, errors.New("cannot queue with yourself")
+	}
+	players := []string{playerID, partnerID}
+	total, err := s.eligible(players)
+	if err != nil {
+		return nil, err
+	}
+	if s.notifier == nil || !s.notifier.IsPlayerConnected(partnerID) {
+		return nil, fmt.Errorf("%w: %s", ErrPartnerOffline, partnerID)
+	}
+
+	s.mu.Lock()
+	if err := s.queuedLocked(players); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	now := s.now()
+	expiresAt := now.Add(s.config.PairInviteTTL)
+	s.nextID++
+	inv := &pairInvite{
+		id:        fmt.Sprintf("invite_%d_%d", now.Unix(), s.nextID),
+		inviterID: playerID,
+		partnerID: partnerID,
+		status: &Status{
+			State:      StateInvited,
+			Players:    players,
+			Rating:     total / float64(len(players)),
+			EnqueuedAt: now,
+			ExpiresAt:  &expiresAt,
+		},
+	}
+	inv.status.TicketID = inv.id
+	s.invites[playerID] = inv
+	for _, id := range players {
+		delete(s.results, id)
+	}
+	status := inv.snapshot()
+	s.mu.Unlock()
+
+	// The partner is told through the same queue_status push as the inviter
+	s.notify(status)
+	return status, nil
+}
+
+// AcceptPair accepts a pair invite and queues the pair
+func (s *service) AcceptPair(playerID, inviterID string) (*Status, error) {
+	s.mu.Lock()
+	inv, err := s.takeInviteLocked(playerID, inviterID)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// The players may have joined a room since the invite was sent
+	status, err := s.enqueue([]string{inv.inviterID, inv.partnerID})
+	if err != nil {
+		failed := inv.snapshot()
+		failed.State = StateFailed
+		failed.Error = err.Error()
+		s.notify(failed)
+		return nil, err
+	}
+	return status, nil
+}
+
+// DeclinePair declines a pair invite
+func (s *service) DeclinePair(playerID, inviterID string) error {
+	s.mu.Lock()
+	inv, err := s.takeInviteLocked(playerID, inviterID)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	status := inv.snapshot()
+	status.State = StateDeclined
+	s.notify(status)
+	return nil
+}
+
+// takeInviteLocked removes and returns the live invite inviterID sent playerID
+func (s *service) takeInviteLocked(playerID, inviterID string) (*pairInvite, error) {
+	inv, exists := s.invites[inviterID]
+	if !exists || inv.partnerID != playerID || s.expiredLocked(inv) {
+		return nil, ErrInviteNotFound
+	}
+	delete(s.invites, inviterID)
+	return inv, nil
+}
+
+// pendingInviteLocked returns a live invite the player sent or received
+func (s *service) pendingInviteLocked(playerID string) *pairInvite {
+	if inv, exists := s.invites[playerID]; exists && !s.expiredLocked(inv) {
+		return inv
+	}
+	for _, inv := range s.invites {
+		if inv.partnerID == playerID && !s.expiredLocked(inv) {
+			return inv
+		}
+	}
+	return nil
+}
+
+// expiredLocked reports whether the partner ran out of time to answer
+func (s *service) expiredLocked(inv *pairInvite) bool {
+	return !s.now().Before(*inv.status.ExpiresAt)
+}
+
+// expireInvites drops the invites nobody answered and tells both players
+func (s *service) expireInvites() {
+	s.mu.Lock()
+	var expired []*Status
+	for inviterID, inv := range s.invites {
+		if s.expiredLocked(inv) {
+			delete(s.invites, inviterID)
+			status := inv.snapshot()
+			status.State = StateCancelled
+			status.Error = "pair invite expired"
+			expired = append(expired, status)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, status := range expired {
+		s.notify(status)
+	}
+}
+
+// snapshot returns a copy of the status of an invite
+func (inv *pairInvite) snapshot() *Status {
+	status := *inv.status
+	status.Players = append([]string(nil), inv.status.Players...)
+	return &status
+}
@@ -0,0 +1,555 @@
+// Package matchmaking pairs queued players into matches by rating and waiting time.
+package matchmaking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"guandan-world/backend/auth"
+	"guandan-world/backend/game"
+	"guandan-world/backend/rating"
+	"guandan-world/backend/room"
+	"guandan-world/backend/websocket"
+	"guandan-world/sdk"
+)
+
+var (
+	// ErrAlreadyQueued is returned when a player who is waiting joins the queue again
+	ErrAlreadyQueued = errors.New("player is already queued")
+	// ErrNotQueued is returned for players who are not waiting in the queue
+	ErrNotQueued = errors.New("player is not queued")
+	// ErrPlayerInRoom is returned when a player must leave their room before queueing
+	ErrPlayerInRoom = errors.New("player is already in a room")
+)
+
+// State is where a queue entry stands
+type State string
+
+const (
+	StateQueued    State = "queued"    // Waiting for a match
+	StateMatched   State = "matched"   // A room was created and the game started
+	StateCancelled State = "cancelled" // Left the queue
+	StateFailed    State = "failed"    // A match was found but could not be started
+	StateInvited   State = "invited"   // A pair invite waits for the partner to accept it
+	StateDeclined  State = "declined"  // The partner declined the pair invite
+)
+
+// Status is a player's view of their queue entry, also pushed as queue_status messages
+type Status struct {
+	State       State      `json:"state"`
+	TicketID    string     `json:"ticket_id"`
+	Players     []string   `json:"players"` // The player and their pre-made partner, if any
+	Rating      float64    `json:"rating"`  // Average skill of the entry
+	EnqueuedAt  time.Time  `json:"enqueued_at"`
+	WaitSeconds int        `json:"wait_seconds"`
+	QueueSize   int        `json:"queue_size"`            // Players currently waiting
+	BackfillAt  *time.Time `json:"backfill_at,omitempty"` // When empty seats will be filled with bots
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`  // When a pair invite lapses
+	RoomID      string     `json:"room_id,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// Config holds the matchmaking parameters
+type Config struct {
+	TickInterval   time.Duration // How often the queue is matched
+	StatusInterval time.Duration // How often waiting players get a status update
+
+	// RatingWindow is the largest skill difference accepted right away; it
+	// widens by WindowGrowth per second of waiting
+	RatingWindow float64
+	WindowGrowth float64
+
+	// BotBackfillAfter is how long the longest-waiting entry waits before the
+	// empty seats of its match are filled with bots; zero never adds bots
+	BotBackfillAfter time.Duration
+	BotAlgorithm     string // ai algorithm for bots, see game.BotSpec
+
+	// Ranked makes matches of four humans ranked
+	Ranked bool
+
+	// PairInviteTTL is how long a partner has to accept a pair invite
+	PairInviteTTL time.Duration
+}
+
+// DefaultConfig returns the default matchmaking parameters
+func DefaultConfig() Config {
+	return Config{
+		TickInterval:     time.Second,
+		StatusInterval:   5 * time.Second,
+		RatingWindow:     3,
+		WindowGrowth:     0.1,
+		BotBackfillAfter: 60 * time.Second,
+		BotAlgorithm:     game.DefaultBotAlgorithm,
+		Ranked:           true,
+		PairInviteTTL:    60 * time.Second,
+	}
+}
+
+// GameStarter starts the game of a matched room; *game.DriverService implements it
+type GameStarter interface {
+	StartGameWithOptions(roomID string, players []sdk.Player, options game.GameOptions) error
+}
+
+// Notifier pushes queue updates to players; *websocket.WSManager implements it
+type Notifier interface {
+	SendToPlayer(playerID string, message *websocket.WSMessage) error
+	IsPlayerConnected(playerID string) bool
+}
+
+// Service is the matchmaking queue
+type Service interface {
+	// Enqueue adds a player to the queue. With partnerID set it invites that
+	// player instead; the pair is queued once they accept with AcceptPair.
+	Enqueue(playerID, partnerID string) (*Status, error)
+	// AcceptPair accepts the pair invite inviterID sent the player and queues the pair
+	AcceptPair(playerID, inviterID string) (*Status, error)
+	// DeclinePair declines the pair invite inviterID sent the player
+	DeclinePair(playerID, inviterID string) error
+	// Leave removes the player's entry, including their partner, from the
+	// queue, or withdraws the pair invite they sent
+	Leave(playerID string) error
+	// Status returns the player's queue entry, or the match it last produced
+	Status(playerID string) (*Status, error)
+	// Run matches the queue every tick until ctx is done
+	Run(ctx context.Context)
+}
+
+// ticket is one queue entry: a single player or a pre-made pair
+type ticket struct {
+	id         string
+	players    []string
+	rating     float64
+	enqueuedAt time.Time
+}
+
+// service implements Service interface
+type service struct {
+	config   Config
+	rooms    room.RoomService
+	users    auth.AuthService
+	ratings  rating.Service // nil treats everyone as equally rated
+	games    GameStarter
+	notifier Notifier
+
+	queue    []*ticket              // Oldest first
+	byPlayer map[string]*ticket     // playerID -> waiting ticket
+	invites  map[string]*pairInvite // inviterID -> invite waiting for the partner
+	results  map[string]*Status     // playerID -> last match result
+	nextID   int
+	now      func() time.Time
+	mu       sync.Mutex
+}
+
+// NewService creates a matchmaking queue; ratings may be nil
+func NewService(config Config, rooms room.RoomService, users auth.AuthService, ratings rating.Service, games GameStarter, notifier Notifier) Service {
+	return &service{
+		config:   config,
+		rooms:    rooms,
+		users:    users,
+		ratings:  ratings,
+		games:    games,
+		notifier: notifier,
+		byPlayer: make(map[string]*ticket),
+		invites:  make(map[string]*pairInvite),
+		results:  make(map[string]*Status),
+		now:      time.Now,
+	}
+}
+
+// Enqueue adds a player to the queue, or invites their partner
+func (s *service) Enqueue(playerID, partnerID string) (*Status, error) {
+	if partnerID != "" {
+		return s.invite(playerID, partnerID)
+	}
+	return s.enqueue([]string{playerID})
+}
+
+// eligible checks that the players can queue and returns their total skill
+func (s *service) eligible(players []string) (float64, error) {
+	var total float64
+	for _, id := range players {
+		if _, err := s.users.GetUserByID(id); err != nil {
+			return 0, err
+		}
+		if _, err := s.rooms.GetPlayerRoom(id); err == nil {
+			return 0, fmt.Errorf("%w: %s", ErrPlayerInRoom, id)
+		}
+		skill, err := s.skill(id)
+		if err != nil {
+			return 0, err
+		}
+		total += skill
+	}
+	return total, nil
+}
+
+// queuedLocked returns ErrAlreadyQueued if one of the players is waiting or
+// has a pair invite out
+func (s *service) queuedLocked(players []string) error {
+	for _, id := range players {
+		if _, queued := s.byPlayer[id]; queued {
+			return fmt.Errorf("%w: %s", ErrAlreadyQueued, id)
+		}
+		if _, inviting := s.invites[id]; inviting {
+			return fmt.Errorf("%w: %s", ErrAlreadyQueued, id)
+		}
+	}
+	return nil
+}
+
+// enqueue adds a single player or an agreed pair to the queue
+func (s *service) enqueue(players []string) (*Status, error) {
+	total, err := s.eligible(players)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if err := s.queuedLocked(players); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	now := s.now()
+	s.nextID++
+	t := &ticket{
+		id:         fmt.Sprintf("ticket_%d_%d", now.Unix(), s.nextID),
+		players:    players,
+		rating:     total / float64(len(players)),
+		enqueuedAt: now,
+	}
+	s.queue = append(s.queue, t)
+	for _, id := range players {
+		s.byPlayer[id] = t
+		delete(s.results, id)
+	}
+	status := s.statusLocked(t, now)
+	s.mu.Unlock()
+
+	s.notify(status)
+	return status, nil
+}
+
+// Leave removes the player's entry from the queue
+func (s *service) Leave(playerID string) error {
+	s.mu.Lock()
+	if inv, inviting := s.invites[playerID]; inviting {
+		delete(s.invites, playerID)
+		status := inv.snapshot()
+		status.State = StateCancelled
+		s.mu.Unlock()
+
+		s.notify(status)
+		return nil
+	}
+	t, queued := s.byPlayer[playerID]
+	if !queued {
+		delete(s.results, playerID)
+		s.mu.Unlock()
+		return ErrNotQueued
+	}
+	s.removeLocked(t)
+	status := s.statusLocked(t, s.now())
+	status.State = StateCancelled
+	s.mu.Unlock()
+
+	s.notify(status)
+	return nil
+}
+
+// Status returns the player's queue entry or last match result
+func (s *service) Status(playerID string) (*Status, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, queued := s.byPlayer[playerID]; queued {
+		return s.statusLocked(t, s.now()), nil
+	}
+	if inv := s.pendingInviteLocked(playerID); inv != nil {
+		return inv.snapshot(), nil
+	}
+	if result, exists := s.results[playerID]; exists {
+		copied := *result
+		return &copied, nil
+	}
+	return nil, ErrNotQueued
+}
+
+// Run matches the queue every tick until ctx is done
+func (s *service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.TickInterval)
+	defer ticker.Stop()
+
+	var lastStatus time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.expireInvites()
+			s.matchOnce()
+
+			if now := s.now(); now.Sub(lastStatus) >= s.config.StatusInterval {
+				lastStatus = now
+				s.pushStatuses()
+			}
+		}
+	}
+}
+
+// matchOnce forms every match the queue allows right now and starts them
+func (s *service) matchOnce() {
+	s.mu.Lock()
+	now := s.now()
+	used := make(map[*ticket]bool)
+	var groups [][]*ticket
+
+	// The longest-waiting entry picks first, from entries close enough in rating
+	for _, anchor := range s.queue {
+		if used[anchor] {
+			continue
+		}
+		group := []*ticket{anchor}
+		seats := len(anchor.players)
+		for _, other := range s.queue {
+			if seats == 4 {
+				break
+			}
+			if other == anchor || used[other] || seats+len(other.players) > 4 {
+				continue
+			}
+			window := math.Max(s.window(anchor, now), s.window(other, now))
+			if math.Abs(anchor.rating-other.rating) > window {
+				continue
+			}
+			group = append(group, other)
+			seats += len(other.players)
+		}
+
+		backfill := s.config.BotBackfillAfter > 0 && now.Sub(anchor.enqueuedAt) >= s.config.BotBackfillAfter
+		if seats < 4 && !backfill {
+			continue
+		}
+		for _, t := range group {
+			used[t] = true
+		}
+		groups = append(groups, group)
+	}
+	for t := range used {
+		s.removeLocked(t)
+	}
+	s.mu.Unlock()
+
+	for _, group := range groups {
+		s.startMatch(group)
+	}
+}
+
+// window is the rating difference a ticket accepts after waiting until now
+func (s *service) window(t *ticket, now time.Time) float64 {
+	return s.config.RatingWindow + s.config.WindowGrowth*now.Sub(t.enqueuedAt).Seconds()
+}
+
+// startMatch seats a group, creates its room and starts the game
+func (s *service) startMatch(group []*ticket) {
+	teams := assignTeams(group)
+	var seats [4]string
+	for team, members := range teams {
+		for i, playerID := range members {
+			seats[team+2*i] = playerID
+		}
+	}
+
+	results := make(map[string]*Status)
+	now := s.now()
+	s.mu.Lock()
+	for _, t := range group {
+		status := s.statusLocked(t, now)
+		for _, id := range t.players {
+			results[id] = status
+		}
+	}
+	s.mu.Unlock()
+
+	roomID, err := s.createGame(seats)
+	for _, status := range results {
+		if err != nil {
+			status.State = StateFailed
+			status.Error = err.Error()
+		} else {
+			status.State = StateMatched
+			status.RoomID = roomID
+		}
+	}
+	if err != nil {
+		log.Printf("Failed to start matched game: %v", err)
+	}
+
+	s.mu.Lock()
+	for id, status := range results {
+		s.results[id] = status
+	}
+	s.mu.Unlock()
+
+	notified := make(map[*Status]bool)
+	for _, status := range results {
+		if !notified[status] {
+			notified[status] = true
+			s.notify(status)
+		}
+	}
+}
+
+// createGame creates the room of a match and starts its game
+func (s *service) createGame(seats [4]string) (string, error) {
+	matched, err := s.rooms.CreateMatchedRoom(seats)
+	if err != nil {
+		return "", err
+	}
+
+	options := game.GameOptions{Bots: make(map[int]game.BotSpec)}
+	players := make([]sdk.Player, 0, 4)
+	for _, player := range matched.Players {
+		players = append(players, sdk.Player{
+			ID:       player.ID,
+			Username: player.Username,
+			Seat:     player.Seat,
+			Online:   player.Online,
+		})
+		if player.Bot {
+			options.Bots[player.Seat] = game.BotSpec{Algorithm: s.config.BotAlgorithm}
+		}
+	}
+	options.Ranked = s.config.Ranked && len(options.Bots) == 0
+
+	if err := s.games.StartGameWithOptions(matched.ID, players, options); err != nil {
+		if closeErr := s.rooms.CloseRoom(matched.ID); closeErr != nil {
+			log.Printf("Failed to close room %s: %v", matched.ID, closeErr)
+		}
+		return "", err
+	}
+	return matched.ID, nil
+}
+
+// assignTeams splits a group into two teams of at most two players. Pairs stay
+// together; single players go, strongest first, to the team with fewer
+// players, or with the lower total rating when both have as many. Missing
+// seats are left for bots.
+func assignTeams(group []*ticket) [2][]string {
+	var teams [2][]string
+	var totals [2]float64
+
+	var singles []*ticket
+	for _, t := range group {
+		if len(t.players) == 2 {
+			team := 0
+			if len(teams[0]) > 0 {
+				team = 1
+			}
+			teams[team] = append(teams[team], t.players...)
+			totals[team] += 2 * t.rating
+		} else {
+			singles = append(singles, t)
+		}
+	}
+
+	sort.SliceStable(singles, func(i, j int) bool {
+		return singles[i].rating > singles[j].rating
+	})
+	for _, t := range singles {
+		team := 0
+		switch {
+		case len(teams[0]) == 2:
+			team = 1
+		case len(teams[1]) == 2:
+			team = 0
+		case len(teams[0]) != len(teams[1]):
+			if len(teams[1]) < len(teams[0]) {
+				team = 1
+			}
+		case totals[1] < totals[0]:
+			team = 1
+		}
+		teams[team] = append(teams[team], t.players[0])
+		totals[team] += t.rating
+	}
+	return teams
+}
+
+// skill is the rating a player queues with
+func (s *service) skill(playerID string) (float64, error) {
+	if s.ratings == nil {
+		return 0, nil
+	}
+	r, err := s.ratings.GetRating(playerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load rating: %w", err)
+	}
+	return r.Skill, nil
+}
+
+// removeLocked takes a ticket out of the queue
+func (s *service) removeLocked(t *ticket) {
+	for i, queued := range s.queue {
+		if queued == t {
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			break
+		}
+	}
+	for _, id := range t.players {
+		delete(s.byPlayer, id)
+	}
+}
+
+// statusLocked describes a waiting ticket
+func (s *service) statusLocked(t *ticket, now time.Time) *Status {
+	status := &Status{
+		State:       StateQueued,
+		TicketID:    t.id,
+		Players:     append([]string(nil), t.players...),
+		Rating:      t.rating,
+		EnqueuedAt:  t.enqueuedAt,
+		WaitSeconds: int(now.Sub(t.enqueuedAt).Seconds()),
+		QueueSize:   len(s.byPlayer),
+	}
+	if s.config.BotBackfillAfter > 0 {
+		backfillAt := t.enqueuedAt.Add(s.config.BotBackfillAfter)
+		status.BackfillAt = &backfillAt
+	}
+	return status
+}
+
+// pushStatuses sends every waiting player their current status
+func (s *service) pushStatuses() {
+	s.mu.Lock()
+	now := s.now()
+	statuses := make([]*Status, 0, len(s.queue))
+	for _, t := range s.queue {
+		statuses = append(statuses, s.statusLocked(t, now))
+	}
+	s.mu.Unlock()
+
+	for _, status := range statuses {
+		s.notify(status)
+	}
+}
+
+// notify pushes a status to every player of the entry
+func (s *service) notify(status *Status) {
+	if s.notifier == nil {
+		return
+	}
+	for _, playerID := range status.Players {
+		// Players without a WebSocket connection simply miss the push
+		_ = s.notifier.SendToPlayer(playerID, &websocket.WSMessage{
+			Type:      websocket.MSG_QUEUE_STATUS,
+			Data:      status,
+			Timestamp: time.Now(),
+		})
+	}
+}
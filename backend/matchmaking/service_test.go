@@ -0,0 +1,426 @@
+package matchmaking
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"guandan-world/backend/auth"
+	"guandan-world/backend/game"
+	"guandan-world/backend/rating"
+	"guandan-world/backend/room"
+	"guandan-world/backend/websocket"
+	"guandan-world/sdk"
+)
+
+// fakeGames records the games started by the queue
+type fakeGames struct {
+	mu      sync.Mutex
+	started map[string]game.GameOptions
+	players map[string][]sdk.Player
+	err     error
+}
+
+func newFakeGames() *fakeGames {
+	return &fakeGames{
+		started: make(map[string]game.GameOptions),
+		players: make(map[string][]sdk.Player),
+	}
+}
+
+func (g *fakeGames) StartGameWithOptions(roomID string, players []sdk.Player, options game.GameOptions) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.err != nil {
+		return g.err
+	}
+	g.started[roomID] = options
+	g.players[roomID] = players
+	return nil
+}
+
+// fakeNotifier records the statuses pushed to each player
+type fakeNotifier struct {
+	mu       sync.Mutex
+	messages map[string][]*Status
+	offline  map[string]bool
+}
+
+func (n *fakeNotifier) IsPlayerConnected(playerID string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return !n.offline[playerID]
+}
+
+func (n *fakeNotifier) SendToPlayer(playerID string, message *websocket.WSMessage) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.messages == nil {
+		n.messages = make(map[string][]*Status)
+	}
+	n.messages[playerID] = append(n.messages[playerID], message.Data.(*Status))
+	return nil
+}
+
+func (n *fakeNotifier) last(playerID string) *Status {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	messages := n.messages[playerID]
+	if len(messages) == 0 {
+		return nil
+	}
+	return messages[len(messages)-1]
+}
+
+type testEnv struct {
+	service  *service
+	auth     auth.AuthService
+	rooms    room.RoomService
+	ratings  rating.Repository
+	games    *fakeGames
+	notifier *fakeNotifier
+	clock    time.Time
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	env := &testEnv{
+		auth:     auth.NewAuthService("test-secret", time.Hour),
+		ratings:  rating.NewMemoryRepository(),
+		games:    newFakeGames(),
+		notifier: &fakeNotifier{},
+		clock:    time.Now(),
+	}
+	env.rooms = room.NewRoomService(env.auth)
+	env.service = NewService(DefaultConfig(), env.rooms, env.auth, rating.NewService(env.ratings), env.games, env.notifier).(*service)
+	env.service.now = func() time.Time { return env.clock }
+	return env
+}
+
+// user registers a player, optionally with a stored skill
+func (env *testEnv) user(t *testing.T, username string, skill float64) string {
+	user, err := env.auth.Register(username, "password")
+	if err != nil {
+		t.Fatalf("Failed to register %s: %v", username, err)
+	}
+	if skill != 0 {
+		err := env.ratings.Save(&rating.Rating{
+			PlayerID:     user.ID,
+			Mu:           skill + 3,
+			Sigma:        1,
+			Skill:        skill,
+			Matches:      10,
+			LastPlayedAt: time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("Failed to save rating: %v", err)
+		}
+	}
+	return user.ID
+}
+
+// enqueue queues a player, or a pair whose partner accepts the invite
+func (env *testEnv) enqueue(t *testing.T, playerID, partnerID string) {
+	if _, err := env.service.Enqueue(playerID, partnerID); err != nil {
+		t.Fatalf("Failed to enqueue %s: %v", playerID, err)
+	}
+	if partnerID != "" {
+		if _, err := env.service.AcceptPair(partnerID, playerID); err != nil {
+			t.Fatalf("Failed to accept the pair invite of %s: %v", playerID, err)
+		}
+	}
+}
+
+func TestService_EnqueueErrors(t *testing.T) {
+	env := newTestEnv(t)
+	alice := env.user(t, "alice", 0)
+	bob := env.user(t, "bob", 0)
+
+	if _, err := env.service.Enqueue("missing", ""); !errors.Is(err, auth.ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+	if _, err := env.service.Enqueue(alice, alice); err == nil {
+		t.Error("Expected error when queueing with yourself")
+	}
+
+	status, err := env.service.Enqueue(alice, "")
+	if err != nil {
+		t.Fatalf("Failed to enqueue: %v", err)
+	}
+	if status.State != StateQueued || status.QueueSize != 1 || status.BackfillAt == nil {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	if env.notifier.last(alice) == nil {
+		t.Error("Expected a queue_status push on enqueue")
+	}
+	if _, err := env.service.Enqueue(bob, alice); !errors.Is(err, ErrAlreadyQueued) {
+		t.Errorf("Expected ErrAlreadyQueued, got %v", err)
+	}
+
+	if _, err := env.rooms.CreateRoom(bob); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	if _, err := env.service.Enqueue(bob, ""); !errors.Is(err, ErrPlayerInRoom) {
+		t.Errorf("Expected ErrPlayerInRoom, got %v", err)
+	}
+}
+
+func TestService_PairInvites(t *testing.T) {
+	env := newTestEnv(t)
+	alice := env.user(t, "alice", 0)
+	bob := env.user(t, "bob", 0)
+	carol := env.user(t, "carol", 0)
+
+	env.notifier.offline = map[string]bool{carol: true}
+	if _, err := env.service.Enqueue(alice, carol); !errors.Is(err, ErrPartnerOffline) {
+		t.Errorf("Expected ErrPartnerOffline, got %v", err)
+	}
+	if _, err := env.service.Enqueue(alice, "missing"); !errors.Is(err, auth.ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+
+	// Inviting queues nobody until the partner agrees
+	status, err := env.service.Enqueue(alice, bob)
+	if err != nil || status.State != StateInvited || status.ExpiresAt == nil {
+		t.Fatalf("Expected a pending invite, got %+v (%v)", status, err)
+	}
+	if last := env.notifier.last(bob); last == nil || last.State != StateInvited {
+		t.Errorf("Expected the partner to be invited, got %+v", last)
+	}
+	if status, _ := env.service.Status(bob); status == nil || status.State != StateInvited {
+		t.Errorf("Expected the partner to see the invite, got %+v", status)
+	}
+	if len(env.service.queue) != 0 {
+		t.Errorf("Expected an empty queue, got %d entries", len(env.service.queue))
+	}
+	if _, err := env.service.Enqueue(alice, ""); !errors.Is(err, ErrAlreadyQueued) {
+		t.Errorf("Expected ErrAlreadyQueued while the invite is pending, got %v", err)
+	}
+	if _, err := env.service.AcceptPair(carol, alice); !errors.Is(err, ErrInviteNotFound) {
+		t.Errorf("Expected only the invited partner to accept, got %v", err)
+	}
+
+	if err := env.service.DeclinePair(bob, alice); err != nil {
+		t.Fatalf("Failed to decline: %v", err)
+	}
+	if last := env.notifier.last(alice); last == nil || last.State != StateDeclined {
+		t.Errorf("Expected the inviter to be told, got %+v", last)
+	}
+
+	// Invites lapse when the partner does not answer
+	env.service.Enqueue(alice, bob)
+	env.clock = env.clock.Add(DefaultConfig().PairInviteTTL)
+	if _, err := env.service.AcceptPair(bob, alice); !errors.Is(err, ErrInviteNotFound) {
+		t.Errorf("Expected an expired invite to be refused, got %v", err)
+	}
+	env.service.expireInvites()
+	if last := env.notifier.last(bob); last == nil || last.State != StateCancelled {
+		t.Errorf("Expected the partner to be told the invite expired, got %+v", last)
+	}
+
+	// The inviter withdraws an invite by leaving
+	env.service.Enqueue(alice, bob)
+	if err := env.service.Leave(alice); err != nil {
+		t.Fatalf("Failed to withdraw the invite: %v", err)
+	}
+	if _, err := env.service.AcceptPair(bob, alice); !errors.Is(err, ErrInviteNotFound) {
+		t.Errorf("Expected a withdrawn invite to be refused, got %v", err)
+	}
+
+	env.service.Enqueue(alice, bob)
+	status, err = env.service.AcceptPair(bob, alice)
+	if err != nil || status.State != StateQueued || len(status.Players) != 2 {
+		t.Fatalf("Expected the pair to be queued, got %+v (%v)", status, err)
+	}
+}
+
+func TestService_LeaveAndStatus(t *testing.T) {
+	env := newTestEnv(t)
+	alice := env.user(t, "alice", 0)
+	bob := env.user(t, "bob", 0)
+
+	env.enqueue(t, alice, bob)
+	env.clock = env.clock.Add(10 * time.Second)
+
+	status, err := env.service.Status(bob)
+	if err != nil || status.WaitSeconds != 10 || len(status.Players) != 2 {
+		t.Errorf("Unexpected status %+v (%v)", status, err)
+	}
+
+	if err := env.service.Leave(bob); err != nil {
+		t.Fatalf("Failed to leave: %v", err)
+	}
+	if last := env.notifier.last(alice); last == nil || last.State != StateCancelled {
+		t.Errorf("Expected the partner to be told the entry was cancelled, got %+v", last)
+	}
+	if _, err := env.service.Status(alice); !errors.Is(err, ErrNotQueued) {
+		t.Errorf("Expected ErrNotQueued, got %v", err)
+	}
+	if err := env.service.Leave(alice); !errors.Is(err, ErrNotQueued) {
+		t.Errorf("Expected ErrNotQueued, got %v", err)
+	}
+}
+
+func TestService_MatchesFourSingles(t *testing.T) {
+	env := newTestEnv(t)
+	players := []string{
+		env.user(t, "p1", 20),
+		env.user(t, "p2", 19),
+		env.user(t, "p3", 18),
+		env.user(t, "p4", 17),
+	}
+
+	for i, id := range players[:3] {
+		env.enqueue(t, id, "")
+		env.service.matchOnce()
+		if _, err := env.service.Status(players[i]); err != nil {
+			t.Fatalf("Expected %s to keep waiting: %v", id, err)
+		}
+	}
+	env.enqueue(t, players[3], "")
+	env.service.matchOnce()
+
+	status, err := env.service.Status(players[0])
+	if err != nil || status.State != StateMatched || status.RoomID == "" {
+		t.Fatalf("Expected a match, got %+v (%v)", status, err)
+	}
+	options, started := env.games.started[status.RoomID]
+	if !started || !options.Ranked || len(options.Bots) != 0 {
+		t.Errorf("Expected a ranked game without bots, got %+v", options)
+	}
+
+	// Strongest and weakest play together against the middle two
+	seats := env.games.players[status.RoomID]
+	if len(seats) != 4 || seats[0].ID != players[0] || seats[2].ID != players[3] {
+		t.Errorf("Unexpected seating %+v", seats)
+	}
+	if _, err := env.rooms.GetPlayerRoom(players[1]); err != nil {
+		t.Errorf("Expected matched players to be in the room: %v", err)
+	}
+	if last := env.notifier.last(players[3]); last == nil || last.State != StateMatched {
+		t.Errorf("Expected a matched push, got %+v", last)
+	}
+}
+
+func TestService_KeepsPairsTogether(t *testing.T) {
+	env := newTestEnv(t)
+	alice := env.user(t, "alice", 0)
+	bob := env.user(t, "bob", 0)
+	carol := env.user(t, "carol", 0)
+	dave := env.user(t, "dave", 0)
+
+	env.enqueue(t, carol, "")
+	env.enqueue(t, alice, bob)
+	env.enqueue(t, dave, "")
+	env.service.matchOnce()
+
+	status, err := env.service.Status(alice)
+	if err != nil || status.State != StateMatched {
+		t.Fatalf("Expected a match, got %+v (%v)", status, err)
+	}
+	seats := env.games.players[status.RoomID]
+	seatOf := make(map[string]int)
+	for _, player := range seats {
+		seatOf[player.ID] = player.Seat
+	}
+	if seatOf[alice]%2 != seatOf[bob]%2 || seatOf[carol]%2 != seatOf[dave]%2 || seatOf[alice]%2 == seatOf[carol]%2 {
+		t.Errorf("Expected the pair to be partners, got seats %v", seatOf)
+	}
+}
+
+func TestService_RatingWindow(t *testing.T) {
+	env := newTestEnv(t)
+	strong := env.user(t, "strong", 30)
+	weak := []string{env.user(t, "weak1", 10), env.user(t, "weak2", 10), env.user(t, "weak3", 10)}
+
+	env.enqueue(t, strong, "")
+	for _, id := range weak {
+		env.enqueue(t, id, "")
+	}
+	env.service.matchOnce()
+	if status, _ := env.service.Status(strong); status.State != StateQueued {
+		t.Fatalf("Expected no match across a 20 point gap, got %+v", status)
+	}
+
+	// The window widens by 0.1 per second: 3 + 0.1*170 = 20
+	env.service.config.BotBackfillAfter = 0
+	env.clock = env.clock.Add(170 * time.Second)
+	env.service.matchOnce()
+	if status, _ := env.service.Status(strong); status.State != StateMatched {
+		t.Errorf("Expected a match once the window widened, got %+v", status)
+	}
+}
+
+func TestService_BotBackfill(t *testing.T) {
+	env := newTestEnv(t)
+	alice := env.user(t, "alice", 0)
+	bob := env.user(t, "bob", 0)
+
+	env.enqueue(t, alice, bob)
+	env.service.matchOnce()
+	if status, _ := env.service.Status(alice); status.State != StateQueued {
+		t.Fatalf("Expected the pair to wait, got %+v", status)
+	}
+
+	env.clock = env.clock.Add(DefaultConfig().BotBackfillAfter)
+	env.service.matchOnce()
+
+	status, err := env.service.Status(bob)
+	if err != nil || status.State != StateMatched {
+		t.Fatalf("Expected a backfilled match, got %+v (%v)", status, err)
+	}
+	options := env.games.started[status.RoomID]
+	if options.Ranked || len(options.Bots) != 2 {
+		t.Errorf("Expected a casual game with two bots, got %+v", options)
+	}
+	for seat, spec := range options.Bots {
+		if seat%2 != 1 || spec.Algorithm != game.DefaultBotAlgorithm {
+			t.Errorf("Unexpected bot %+v at seat %d", spec, seat)
+		}
+	}
+}
+
+func TestService_StartFailure(t *testing.T) {
+	env := newTestEnv(t)
+	env.games.err = errors.New("driver unavailable")
+	alice := env.user(t, "alice", 0)
+
+	env.enqueue(t, alice, "")
+	env.clock = env.clock.Add(DefaultConfig().BotBackfillAfter)
+	env.service.matchOnce()
+
+	status, err := env.service.Status(alice)
+	if err != nil || status.State != StateFailed || status.Error == "" {
+		t.Errorf("Expected a failed status, got %+v (%v)", status, err)
+	}
+	if _, err := env.rooms.GetPlayerRoom(alice); err == nil {
+		t.Error("Expected the room of a failed match to be closed")
+	}
+	if _, err := env.service.Enqueue(alice, ""); err != nil {
+		t.Errorf("Expected to be able to queue again: %v", err)
+	}
+}
+
+func TestAssignTeams(t *testing.T) {
+	group := []*ticket{
+		{players: []string{"a"}, rating: 5},
+		{players: []string{"b", "c"}, rating: 1},
+		{players: []string{"d"}, rating: -2},
+	}
+	teams := assignTeams(group)
+	if len(teams[0]) != 2 || teams[0][0] != "b" || teams[0][1] != "c" {
+		t.Errorf("Expected the pair to form a team, got %v", teams)
+	}
+	if len(teams[1]) != 2 {
+		t.Errorf("Expected the singles to form the other team, got %v", teams)
+	}
+
+	singles := assignTeams([]*ticket{
+		{players: []string{"a"}, rating: -1},
+		{players: []string{"b"}, rating: -4},
+		{players: []string{"c"}, rating: -2},
+	})
+	if len(singles[0]) != 1 || len(singles[1]) != 2 || singles[0][0] != "a" || singles[1][0] != "c" {
+		t.Errorf("Unexpected teams %v", singles)
+	}
+}
@@ -0,0 +1,207 @@
+package profile
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"guandan-world/backend/auth"
+	"guandan-world/backend/match"
+	"guandan-world/backend/rating"
+)
+
+// Period selects which matches a leaderboard counts
+type Period string
+
+const (
+	PeriodOverall Period = "overall" // Every archived match
+	PeriodWeekly  Period = "weekly"  // Matches finished since Monday 00:00 of the current week
+)
+
+// ratingHistoryLimit is how many rating changes a profile shows
+const ratingHistoryLimit = 20
+
+// Profile is a player's public profile
+type Profile struct {
+	ID            string                 `json:"id"`
+	Username      string                 `json:"username"`
+	Online        bool                   `json:"online"`
+	CreatedAt     time.Time              `json:"created_at"`
+	Stats         *Stats                 `json:"stats"`
+	Rating        *rating.Rating         `json:"rating,omitempty"`         // Ranked rating with inactivity decay applied
+	RatingHistory []*rating.HistoryEntry `json:"rating_history,omitempty"` // Most recent ranked matches first
+}
+
+// LeaderboardEntry is one row of a leaderboard
+type LeaderboardEntry struct {
+	Rank        int     `json:"rank"`
+	PlayerID    string  `json:"player_id"`
+	Username    string  `json:"username"`
+	GamesPlayed int     `json:"games_played"`
+	GamesWon    int     `json:"games_won"`
+	WinRate     float64 `json:"win_rate"`
+}
+
+// Leaderboard is a page of a leaderboard
+type Leaderboard struct {
+	Period     Period              `json:"period"`
+	Since      *time.Time          `json:"since,omitempty"` // Start of the counted window, nil for overall
+	Entries    []*LeaderboardEntry `json:"entries"`
+	TotalCount int                 `json:"total_count"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+}
+
+// ProfileService serves player profiles and leaderboards computed from the match archive
+type ProfileService interface {
+	GetProfile(userID string) (*Profile, error)
+	GetLeaderboard(period Period, page, limit int) (*Leaderboard, error)
+}
+
+// profileService implements ProfileService interface
+type profileService struct {
+	authService auth.AuthService
+	archive     match.Archive
+	ratings     rating.Service
+	now         func() time.Time
+}
+
+// NewProfileService creates a new profile service; ratings may be nil when
+// ranked play is not available
+func NewProfileService(authService auth.AuthService, archive match.Archive, ratings rating.Service) ProfileService {
+	return &profileService{
+		authService: authService,
+		archive:     archive,
+		ratings:     ratings,
+		now:         time.Now,
+	}
+}
+
+// GetProfile returns the user's profile with lifetime statistics
+func (s *profileService) GetProfile(userID string) (*Profile, error) {
+	user, err := s.authService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.archive.List(userID, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load matches: %w", err)
+	}
+
+	profile := &Profile{
+		ID:        user.ID,
+		Username:  user.Username,
+		Online:    user.Online,
+		CreatedAt: user.CreatedAt,
+		Stats:     ComputeStats(userID, records),
+	}
+
+	if s.ratings != nil {
+		if profile.Rating, err = s.ratings.GetRating(userID); err != nil {
+			return nil, err
+		}
+		if profile.RatingHistory, err = s.ratings.History(userID, ratingHistoryLimit); err != nil {
+			return nil, err
+		}
+	}
+	return profile, nil
+}
+
+// GetLeaderboard ranks players by games won, then win rate, then games played.
+// Bot seats are left out.
+func (s *profileService) GetLeaderboard(period Period, page, limit int) (*Leaderboard, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	var since *time.Time
+	switch period {
+	case PeriodOverall:
+	case PeriodWeekly:
+		start := startOfWeek(s.now())
+		since = &start
+	default:
+		return nil, fmt.Errorf("unknown leaderboard period: %s", period)
+	}
+
+	// Only the outcomes are needed, not the deals of every match
+	var from time.Time
+	if since != nil {
+		from = *since
+	}
+	summaries, err := s.archive.Summaries(from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load matches: %w", err)
+	}
+
+	entries := make(map[string]*LeaderboardEntry)
+	for _, record := range summaries {
+		for _, player := range record.Players {
+			// Bots fill seats in many rooms under room-specific IDs; they are not ranked
+			if player.Bot {
+				continue
+			}
+			entry, exists := entries[player.ID]
+			if !exists {
+				entry = &LeaderboardEntry{PlayerID: player.ID, Username: player.Username}
+				entries[player.ID] = entry
+			}
+			entry.GamesPlayed++
+			if record.Winner == player.Seat%2 {
+				entry.GamesWon++
+			}
+		}
+	}
+
+	ranked := make([]*LeaderboardEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry.WinRate = float64(entry.GamesWon) / float64(entry.GamesPlayed)
+		ranked = append(ranked, entry)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.GamesWon != b.GamesWon {
+			return a.GamesWon > b.GamesWon
+		}
+		if a.WinRate != b.WinRate {
+			return a.WinRate > b.WinRate
+		}
+		if a.GamesPlayed != b.GamesPlayed {
+			return a.GamesPlayed > b.GamesPlayed
+		}
+		return a.Username < b.Username
+	})
+	for i, entry := range ranked {
+		entry.Rank = i + 1
+	}
+
+	totalCount := len(ranked)
+	start := (page - 1) * limit
+	if start > totalCount {
+		start = totalCount
+	}
+	end := start + limit
+	if end > totalCount {
+		end = totalCount
+	}
+
+	return &Leaderboard{
+		Period:     period,
+		Since:      since,
+		Entries:    ranked[start:end],
+		TotalCount: totalCount,
+		Page:       page,
+		Limit:      limit,
+	}, nil
+}
+
+// startOfWeek returns Monday 00:00 of the week containing t, in t's location
+func startOfWeek(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	year, month, day := t.AddDate(0, 0, -daysSinceMonday).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
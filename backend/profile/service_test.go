@@ -0,0 +1,203 @@
+package profile
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"guandan-world/backend/auth"
+	"guandan-world/backend/match"
+	"guandan-world/backend/rating"
+	"guandan-world/sdk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testMatch builds a finished match where players[i] sits at seat i
+func testMatch(id string, winner int, finishedAt time.Time, players []string, deals ...*match.DealRecord) *match.Record {
+	record := &match.Record{ID: id, Winner: winner, FinishedAt: finishedAt, Deals: deals}
+	for seat, player := range players {
+		record.Players = append(record.Players, match.Player{ID: player, Username: "name_" + player, Seat: seat})
+	}
+	return record
+}
+
+func TestComputeStats(t *testing.T) {
+	alice := []string{"alice", "bob", "carol", "dave"}
+	swapped := []string{"alice", "dave", "erin", "bob"}
+	records := []*match.Record{
+		testMatch("m1", 0, time.Now(), alice,
+			&match.DealRecord{
+				Number: 1,
+				Result: &sdk.DealResult{Rankings: []int{0, 2, 1, 3}, WinningTeam: 0, VictoryType: sdk.VictoryTypeDoubleDown},
+				Actions: []match.Action{
+					{Type: match.ActionPlay, Seat: 0, Target: -1, Bomb: true},
+					{Type: match.ActionPlay, Seat: 1, Target: -1, Bomb: true},
+					{Type: match.ActionPlay, Seat: 0, Target: -1},
+				},
+			},
+			&match.DealRecord{
+				Number: 2,
+				Result: &sdk.DealResult{Rankings: []int{1, 3, 0}, WinningTeam: 1, VictoryType: sdk.VictoryTypeSingleLast},
+				Actions: []match.Action{
+					{Type: match.ActionTribute, Seat: 1, Target: 0},
+					{Type: match.ActionTribute, Seat: 3, Target: 2},
+					{Type: match.ActionReturnTribute, Seat: 0, Target: 1},
+				},
+			},
+		),
+		testMatch("m2", 1, time.Now(), alice),
+		testMatch("m3", 0, time.Now(), swapped,
+			&match.DealRecord{
+				Number:  1,
+				Result:  &sdk.DealResult{Rankings: []int{3, 1, 0, 2}, WinningTeam: 1, VictoryType: sdk.VictoryTypeDoubleDown},
+				Actions: []match.Action{{Type: match.ActionTribute, Seat: 0, Target: 3}},
+			},
+			// An unfinished deal is not counted
+			&match.DealRecord{Number: 2},
+		),
+		testMatch("m4", 0, time.Now(), []string{"x", "y", "z", "w"}),
+	}
+
+	stats := ComputeStats("alice", records)
+	assert.Equal(t, 3, stats.GamesPlayed)
+	assert.Equal(t, 2, stats.GamesWon)
+	assert.InDelta(t, 2.0/3, stats.WinRate, 1e-9)
+	assert.Equal(t, 3, stats.DealsPlayed)
+	assert.InDelta(t, 1.0/3, stats.DoubleDownRate, 1e-9)
+	assert.InDelta(t, (1.0+3+3)/3, stats.AverageFinishRank, 1e-9)
+	assert.InDelta(t, 1.0/3, stats.BombsPerDeal, 1e-9)
+	assert.Equal(t, 1, stats.TributesPaid)
+	assert.Equal(t, 1, stats.TributesReceived)
+	require.NotNil(t, stats.FavouritePartner)
+	assert.Equal(t, "carol", stats.FavouritePartner.ID)
+	assert.Equal(t, 2, stats.FavouritePartner.GamesTogether)
+	assert.Equal(t, 1, stats.FavouritePartner.WinsTogether)
+
+	empty := ComputeStats("nobody", records)
+	assert.Equal(t, 0, empty.GamesPlayed)
+	assert.Zero(t, empty.WinRate)
+	assert.Nil(t, empty.FavouritePartner)
+}
+
+func TestProfileService_GetProfile(t *testing.T) {
+	authService := auth.NewAuthService("test-secret", time.Hour)
+	user, err := authService.Register("alice", "password123")
+	require.NoError(t, err)
+
+	archive := match.NewMemoryArchive()
+	require.NoError(t, archive.Save(testMatch("m1", 0, time.Now(), []string{user.ID, "b", "c", "d"})))
+	require.NoError(t, archive.Save(testMatch("m2", 0, time.Now(), []string{"a", "b", "c", "d"})))
+
+	ratings := rating.NewService(rating.NewMemoryRepository())
+	ranked := testMatch("m3", 0, time.Now(), []string{user.ID, "b", "c", "d"})
+	ranked.Ranked = true
+	_, err = ratings.RecordMatch(ranked)
+	require.NoError(t, err)
+
+	service := NewProfileService(authService, archive, ratings)
+	profile, err := service.GetProfile(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", profile.Username)
+	assert.Equal(t, 1, profile.Stats.GamesPlayed)
+	assert.Equal(t, 1, profile.Stats.GamesWon)
+	require.NotNil(t, profile.Rating)
+	assert.Equal(t, 1, profile.Rating.Matches)
+	require.Len(t, profile.RatingHistory, 1)
+	assert.Equal(t, "m3", profile.RatingHistory[0].MatchID)
+
+	// Without ranked play the profile has no rating
+	unrated, err := NewProfileService(authService, archive, nil).GetProfile(user.ID)
+	require.NoError(t, err)
+	assert.Nil(t, unrated.Rating)
+
+	_, err = service.GetProfile("missing")
+	assert.Error(t, err)
+}
+
+func TestProfileService_GetLeaderboard(t *testing.T) {
+	archive := match.NewMemoryArchive()
+	// Wednesday; the week started on Monday 2024-01-01
+	now := time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)
+	lastWeek := now.AddDate(0, 0, -7)
+
+	require.NoError(t, archive.Save(testMatch("m1", 0, lastWeek, []string{"a", "b", "c", "d"})))
+	require.NoError(t, archive.Save(testMatch("m2", 0, lastWeek, []string{"a", "b", "c", "d"})))
+	require.NoError(t, archive.Save(testMatch("m3", 1, now, []string{"a", "b", "c", "d"})))
+	require.NoError(t, archive.Save(testMatch("m4", 0, now, []string{"e", "b", "f", "d"})))
+
+	service := NewProfileService(auth.NewAuthService("test-secret", time.Hour), archive, nil).(*profileService)
+	service.now = func() time.Time { return now }
+
+	overall, err := service.GetLeaderboard(PeriodOverall, 1, 10)
+	require.NoError(t, err)
+	assert.Nil(t, overall.Since)
+	assert.Equal(t, 6, overall.TotalCount)
+	var order []string
+	for _, entry := range overall.Entries {
+		order = append(order, fmt.Sprintf("%s:%d/%d", entry.PlayerID, entry.GamesWon, entry.GamesPlayed))
+	}
+	// a and c won 2 of 3; e and f won 1 of 1; b and d won 1 of 4
+	assert.Equal(t, []string{"a:2/3", "c:2/3", "e:1/1", "f:1/1", "b:1/4", "d:1/4"}, order)
+	assert.Equal(t, 1, overall.Entries[0].Rank)
+	assert.Equal(t, "name_a", overall.Entries[0].Username)
+
+	weekly, err := service.GetLeaderboard(PeriodWeekly, 1, 2)
+	require.NoError(t, err)
+	require.NotNil(t, weekly.Since)
+	assert.True(t, weekly.Since.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 6, weekly.TotalCount)
+	require.Len(t, weekly.Entries, 2)
+	// Only this week's games count: e won 1 of 1 while b won 1 of 2
+	assert.Equal(t, "e", weekly.Entries[0].PlayerID)
+	assert.Equal(t, 1, weekly.Entries[0].GamesWon)
+	assert.Equal(t, 1, weekly.Entries[0].GamesPlayed)
+
+	page, err := service.GetLeaderboard(PeriodWeekly, 4, 2)
+	require.NoError(t, err)
+	assert.Empty(t, page.Entries)
+
+	_, err = service.GetLeaderboard("monthly", 1, 10)
+	assert.Error(t, err)
+}
+
+func TestProfileService_BackfilledMatches(t *testing.T) {
+	// Matchmaking backfill seats a new bot ID in every room
+	backfilled := func(id string, winner int, room string) *match.Record {
+		record := testMatch(id, winner, time.Now(), []string{"a", "bot_" + room + "_1", "bot_" + room + "_2", "d"})
+		record.Players[1].Bot = true
+		record.Players[2].Bot = true
+		return record
+	}
+	archive := match.NewMemoryArchive()
+	require.NoError(t, archive.Save(backfilled("m1", 0, "r1")))
+	require.NoError(t, archive.Save(backfilled("m2", 0, "r2")))
+	require.NoError(t, archive.Save(testMatch("m3", 1, time.Now(), []string{"a", "b", "c", "d"})))
+
+	service := NewProfileService(auth.NewAuthService("test-secret", time.Hour), archive, nil)
+	leaderboard, err := service.GetLeaderboard(PeriodOverall, 1, 10)
+	require.NoError(t, err)
+	var players []string
+	for _, entry := range leaderboard.Entries {
+		players = append(players, entry.PlayerID)
+	}
+	assert.ElementsMatch(t, []string{"a", "b", "c", "d"}, players)
+	assert.Equal(t, 4, leaderboard.TotalCount)
+
+	records, err := archive.List("a", 0, 0)
+	require.NoError(t, err)
+	stats := ComputeStats("a", records)
+	assert.Equal(t, 3, stats.GamesPlayed)
+	// The bots partnered a twice under different IDs, but only c is a partner
+	require.NotNil(t, stats.FavouritePartner)
+	assert.Equal(t, "c", stats.FavouritePartner.ID)
+	assert.Equal(t, 1, stats.FavouritePartner.GamesTogether)
+}
+
+func TestStartOfWeek(t *testing.T) {
+	sunday := time.Date(2024, 1, 7, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), startOfWeek(sunday))
+	monday := time.Date(2024, 1, 8, 0, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), startOfWeek(monday))
+}
@@ -0,0 +1,136 @@
+package profile
+
+import (
+	"sort"
+
+	"guandan-world/backend/match"
+	"guandan-world/sdk"
+)
+
+// Partner is the teammate a player has played with the most
+type Partner struct {
+	ID            string `json:"id"`
+	Username      string `json:"username"`
+	GamesTogether int    `json:"games_together"`
+	WinsTogether  int    `json:"wins_together"`
+}
+
+// Stats are a player's lifetime statistics computed from archived matches
+type Stats struct {
+	GamesPlayed       int      `json:"games_played"`
+	GamesWon          int      `json:"games_won"`
+	WinRate           float64  `json:"win_rate"`            // GamesWon / GamesPlayed
+	DealsPlayed       int      `json:"deals_played"`        // Finished deals
+	DoubleDownRate    float64  `json:"double_down_rate"`    // Share of deals the player's team won with a double-down
+	AverageFinishRank float64  `json:"average_finish_rank"` // 1 (first out) to 4
+	BombsPerDeal      float64  `json:"bombs_per_deal"`
+	TributesPaid      int      `json:"tributes_paid"`
+	TributesReceived  int      `json:"tributes_received"`
+	FavouritePartner  *Partner `json:"favourite_partner,omitempty"`
+}
+
+// ComputeStats aggregates the statistics of playerID over the given matches.
+// Matches the player did not take part in are ignored.
+func ComputeStats(playerID string, records []*match.Record) *Stats {
+	stats := &Stats{}
+	partners := make(map[string]*Partner)
+	var doubleDowns, bombs, rankSum int
+
+	for _, record := range records {
+		seat := -1
+		for _, player := range record.Players {
+			if player.ID == playerID {
+				seat = player.Seat
+			}
+		}
+		if seat < 0 {
+			continue
+		}
+		team := seat % 2
+		won := record.Winner == team
+
+		stats.GamesPlayed++
+		if won {
+			stats.GamesWon++
+		}
+
+		for _, player := range record.Players {
+			if player.Seat == (seat+2)%4 && !player.Bot {
+				partner, exists := partners[player.ID]
+				if !exists {
+					partner = &Partner{ID: player.ID, Username: player.Username}
+					partners[player.ID] = partner
+				}
+				partner.GamesTogether++
+				if won {
+					partner.WinsTogether++
+				}
+			}
+		}
+
+		for _, deal := range record.Deals {
+			if deal.Result == nil {
+				continue
+			}
+			stats.DealsPlayed++
+			if deal.Result.WinningTeam == team && deal.Result.VictoryType == sdk.VictoryTypeDoubleDown {
+				doubleDowns++
+			}
+			rankSum += finishRank(deal.Result.Rankings, seat)
+
+			for _, action := range deal.Actions {
+				switch {
+				case action.Type == match.ActionPlay && action.Seat == seat && action.Bomb:
+					bombs++
+				case action.Type == match.ActionTribute && action.Seat == seat:
+					stats.TributesPaid++
+				case action.Type == match.ActionTribute && action.Target == seat:
+					stats.TributesReceived++
+				}
+			}
+		}
+	}
+
+	if stats.GamesPlayed > 0 {
+		stats.WinRate = float64(stats.GamesWon) / float64(stats.GamesPlayed)
+	}
+	if stats.DealsPlayed > 0 {
+		stats.DoubleDownRate = float64(doubleDowns) / float64(stats.DealsPlayed)
+		stats.AverageFinishRank = float64(rankSum) / float64(stats.DealsPlayed)
+		stats.BombsPerDeal = float64(bombs) / float64(stats.DealsPlayed)
+	}
+	stats.FavouritePartner = favouritePartner(partners)
+	return stats
+}
+
+// finishRank is the 1-based finishing position of seat; a seat missing from
+// the rankings was still holding cards and finished last
+func finishRank(rankings []int, seat int) int {
+	for i, s := range rankings {
+		if s == seat {
+			return i + 1
+		}
+	}
+	return 4
+}
+
+// favouritePartner picks the partner with the most games together, then the most wins
+func favouritePartner(partners map[string]*Partner) *Partner {
+	if len(partners) == 0 {
+		return nil
+	}
+	list := make([]*Partner, 0, len(partners))
+	for _, partner := range partners {
+		list = append(list, partner)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].GamesTogether != list[j].GamesTogether {
+			return list[i].GamesTogether > list[j].GamesTogether
+		}
+		if list[i].WinsTogether != list[j].WinsTogether {
+			return list[i].WinsTogether > list[j].WinsTogether
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list[0]
+}
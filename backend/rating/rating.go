@@ -0,0 +1,156 @@
+// Package rating maintains TrueSkill-style skill ratings for ranked 2v2 matches.
+package rating
+
+import (
+	"math"
+	"time"
+)
+
+// Rating is a player's skill estimate: a normal distribution with mean Mu and
+// deviation Sigma. Skill is the conservative estimate Mu - 3*Sigma shown to players.
+type Rating struct {
+	PlayerID     string    `json:"player_id"`
+	Mu           float64   `json:"mu"`
+	Sigma        float64   `json:"sigma"`
+	Skill        float64   `json:"skill"`
+	Matches      int       `json:"matches"`        // Ranked matches played
+	LastPlayedAt time.Time `json:"last_played_at"` // Zero until the first ranked match
+}
+
+// HistoryEntry records how one ranked match changed a player's rating
+type HistoryEntry struct {
+	PlayerID    string    `json:"player_id"`
+	MatchID     string    `json:"match_id"`
+	Won         bool      `json:"won"`
+	Margin      int       `json:"margin"` // Levels the winners gained over the losers
+	MuBefore    float64   `json:"mu_before"`
+	SigmaBefore float64   `json:"sigma_before"`
+	SkillBefore float64   `json:"skill_before"`
+	MuAfter     float64   `json:"mu_after"`
+	SigmaAfter  float64   `json:"sigma_after"`
+	SkillAfter  float64   `json:"skill_after"`
+	At          time.Time `json:"at"`
+}
+
+// Config holds the rating model parameters
+type Config struct {
+	Mu    float64 // Mean of a new player
+	Sigma float64 // Deviation of a new player, also the ceiling for decay
+	Beta  float64 // Performance variance of a single match
+	Tau   float64 // Deviation added before every match so ratings keep moving
+
+	// MarginWeight scales the mean update by the upgrade margin: a sweep of
+	// MaxMargin levels moves ratings (1 + MarginWeight) times as far as a
+	// narrow win. Zero ignores the margin.
+	MarginWeight float64
+	MaxMargin    int
+
+	// DecayGrace is how long a player may be inactive before decay starts;
+	// afterwards Sigma grows by DecayPerDay per day (in quadrature), which
+	// lowers the displayed Skill until the player plays again.
+	DecayGrace  time.Duration
+	DecayPerDay float64
+}
+
+// DefaultConfig returns the standard TrueSkill parameters with margin and decay enabled
+func DefaultConfig() Config {
+	return Config{
+		Mu:           25,
+		Sigma:        25.0 / 3,
+		Beta:         25.0 / 6,
+		Tau:          25.0 / 300,
+		MarginWeight: 0.5,
+		MaxMargin:    12, // From level 2 to A
+		DecayGrace:   14 * 24 * time.Hour,
+		DecayPerDay:  0.3,
+	}
+}
+
+// conservative is the displayed skill of a distribution
+func conservative(mu, sigma float64) float64 {
+	return mu - 3*sigma
+}
+
+// newRating is the rating of a player who has not played a ranked match
+func (c Config) newRating(playerID string) *Rating {
+	return &Rating{
+		PlayerID: playerID,
+		Mu:       c.Mu,
+		Sigma:    c.Sigma,
+		Skill:    conservative(c.Mu, c.Sigma),
+	}
+}
+
+// decay returns a copy of r with Sigma grown for the inactivity up to now
+func (c Config) decay(r *Rating, now time.Time) *Rating {
+	decayed := *r
+	if !r.LastPlayedAt.IsZero() && c.DecayPerDay > 0 {
+		inactive := now.Sub(r.LastPlayedAt) - c.DecayGrace
+		if inactive > 0 {
+			days := inactive.Hours() / 24
+			sigma := math.Sqrt(r.Sigma*r.Sigma + days*c.DecayPerDay*c.DecayPerDay)
+			decayed.Sigma = math.Min(sigma, math.Max(c.Sigma, r.Sigma))
+		}
+	}
+	decayed.Skill = conservative(decayed.Mu, decayed.Sigma)
+	return &decayed
+}
+
+// marginFactor scales the mean update for an upgrade margin
+func (c Config) marginFactor(margin int) float64 {
+	if c.MarginWeight <= 0 || c.MaxMargin <= 0 || margin <= 0 {
+		return 1
+	}
+	if margin > c.MaxMargin {
+		margin = c.MaxMargin
+	}
+	return 1 + c.MarginWeight*float64(margin)/float64(c.MaxMargin)
+}
+
+// update applies a two-team TrueSkill update where winners beat losers.
+// The ratings are modified in place.
+func (c Config) update(winners, losers []*Rating, margin int) {
+	all := append(append([]*Rating{}, winners...), losers...)
+
+	var variance, winMu, loseMu float64
+	for _, r := range all {
+		r.Sigma = math.Sqrt(r.Sigma*r.Sigma + c.Tau*c.Tau)
+		variance += r.Sigma*r.Sigma + c.Beta*c.Beta
+	}
+	for _, r := range winners {
+		winMu += r.Mu
+	}
+	for _, r := range losers {
+		loseMu += r.Mu
+	}
+
+	cc := math.Sqrt(variance)
+	t := (winMu - loseMu) / cc
+	v := -t // Limit of the ratio for a huge upset, where the CDF underflows
+	if cdf := normCDF(t); cdf > 1e-300 {
+		v = normPDF(t) / cdf
+	}
+	w := v * (v + t)
+	factor := c.marginFactor(margin)
+
+	apply := func(r *Rating, sign float64) {
+		s2 := r.Sigma * r.Sigma
+		r.Mu += sign * factor * s2 / cc * v
+		r.Sigma = math.Sqrt(s2 * math.Max(1-s2/variance*w, 1e-4))
+		r.Skill = conservative(r.Mu, r.Sigma)
+	}
+	for _, r := range winners {
+		apply(r, 1)
+	}
+	for _, r := range losers {
+		apply(r, -1)
+	}
+}
+
+func normPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
+
+func normCDF(x float64) float64 {
+	return math.Erfc(-x/math.Sqrt2) / 2
+}
@@ -0,0 +1,83 @@
+package rating
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrRatingNotFound is returned when a player has no stored rating
+var ErrRatingNotFound = errors.New("rating not found")
+
+// Repository stores ratings and their history
+type Repository interface {
+	Get(playerID string) (*Rating, error)
+	Save(rating *Rating) error
+	AppendHistory(entry *HistoryEntry) error
+	// History returns the player's entries newest first; limit <= 0 returns all
+	History(playerID string, limit int) ([]*HistoryEntry, error)
+}
+
+// memoryRepository keeps ratings in memory, used by tests and when no database is configured
+type memoryRepository struct {
+	ratings map[string]*Rating         // playerID -> Rating
+	history map[string][]*HistoryEntry // playerID -> entries in insertion order
+	mu      sync.RWMutex
+}
+
+// NewMemoryRepository creates an in-memory rating repository
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		ratings: make(map[string]*Rating),
+		history: make(map[string][]*HistoryEntry),
+	}
+}
+
+// Get retrieves a player's rating
+func (r *memoryRepository) Get(playerID string) (*Rating, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rating, exists := r.ratings[playerID]
+	if !exists {
+		return nil, ErrRatingNotFound
+	}
+	copied := *rating
+	return &copied, nil
+}
+
+// Save inserts or replaces a player's rating
+func (r *memoryRepository) Save(rating *Rating) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *rating
+	r.ratings[rating.PlayerID] = &copied
+	return nil
+}
+
+// AppendHistory adds an entry to a player's history
+func (r *memoryRepository) AppendHistory(entry *HistoryEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *entry
+	r.history[entry.PlayerID] = append(r.history[entry.PlayerID], &copied)
+	return nil
+}
+
+// History returns the player's entries newest first
+func (r *memoryRepository) History(playerID string, limit int) ([]*HistoryEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored := r.history[playerID]
+	entries := make([]*HistoryEntry, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		copied := *stored[i]
+		entries = append(entries, &copied)
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
@@ -0,0 +1,167 @@
+package rating
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"guandan-world/backend/match"
+)
+
+// Service reads ratings and updates them from ranked matches
+type Service interface {
+	// GetRating returns the player's current rating with inactivity decay applied;
+	// players without ranked matches get the initial rating
+	GetRating(playerID string) (*Rating, error)
+	History(playerID string, limit int) ([]*HistoryEntry, error)
+	// RecordMatch updates the ratings of all four players of a finished ranked match
+	RecordMatch(record *match.Record) ([]*HistoryEntry, error)
+}
+
+// service implements Service interface
+type service struct {
+	repo   Repository
+	config Config
+	now    func() time.Time
+	mu     sync.Mutex // Serializes read-modify-write of ratings
+}
+
+// NewService creates a rating service with the default configuration
+func NewService(repo Repository) Service {
+	return NewServiceWithConfig(repo, DefaultConfig())
+}
+
+// NewServiceWithConfig creates a rating service with custom model parameters
+func NewServiceWithConfig(repo Repository, config Config) Service {
+	return &service{
+		repo:   repo,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// GetRating returns the player's current rating
+func (s *service) GetRating(playerID string) (*Rating, error) {
+	stored, err := s.load(playerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.config.decay(stored, s.now()), nil
+}
+
+// History returns the player's rating changes, newest first
+func (s *service) History(playerID string, limit int) ([]*HistoryEntry, error) {
+	return s.repo.History(playerID, limit)
+}
+
+// RecordMatch applies the result of a ranked match to its players' ratings
+func (s *service) RecordMatch(record *match.Record) ([]*HistoryEntry, error) {
+	if record == nil {
+		return nil, fmt.Errorf("match record is required")
+	}
+	if !record.Ranked {
+		return nil, fmt.Errorf("match %s is not ranked", record.ID)
+	}
+	if len(record.Players) != 4 {
+		return nil, fmt.Errorf("ranked match %s needs 4 players, got %d", record.ID, len(record.Players))
+	}
+	if record.Winner != 0 && record.Winner != 1 {
+		return nil, fmt.Errorf("ranked match %s has no winner", record.ID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	at := record.FinishedAt
+	if at.IsZero() {
+		at = now
+	}
+
+	before := make([]*Rating, len(record.Players))
+	after := make([]*Rating, len(record.Players))
+	var winners, losers []*Rating
+	for i, player := range record.Players {
+		stored, err := s.load(player.ID)
+		if err != nil {
+			return nil, err
+		}
+		before[i] = s.config.decay(stored, at)
+		updated := *before[i]
+		after[i] = &updated
+		if player.Seat%2 == record.Winner {
+			winners = append(winners, after[i])
+		} else {
+			losers = append(losers, after[i])
+		}
+	}
+	if len(winners) != 2 || len(losers) != 2 {
+		return nil, fmt.Errorf("ranked match %s does not have two teams of two", record.ID)
+	}
+
+	margin := upgradeMargin(record)
+	s.config.update(winners, losers, margin)
+
+	entries := make([]*HistoryEntry, 0, len(record.Players))
+	for i, player := range record.Players {
+		after[i].Matches++
+		after[i].LastPlayedAt = at
+		if err := s.repo.Save(after[i]); err != nil {
+			return nil, fmt.Errorf("failed to save rating: %w", err)
+		}
+
+		entry := &HistoryEntry{
+			PlayerID:    player.ID,
+			MatchID:     record.ID,
+			Won:         player.Seat%2 == record.Winner,
+			Margin:      margin,
+			MuBefore:    before[i].Mu,
+			SigmaBefore: before[i].Sigma,
+			SkillBefore: before[i].Skill,
+			MuAfter:     after[i].Mu,
+			SigmaAfter:  after[i].Sigma,
+			SkillAfter:  after[i].Skill,
+			At:          at,
+		}
+		if err := s.repo.AppendHistory(entry); err != nil {
+			return nil, fmt.Errorf("failed to save rating history: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// load returns the stored rating, or the initial rating for a new player
+func (s *service) load(playerID string) (*Rating, error) {
+	stored, err := s.repo.Get(playerID)
+	if errors.Is(err, ErrRatingNotFound) {
+		return s.config.newRating(playerID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rating: %w", err)
+	}
+	return stored, nil
+}
+
+// upgradeMargin is how many more levels the winners gained than the losers
+// over the deals of the match, falling back to the final levels when no deal
+// results were recorded
+func upgradeMargin(record *match.Record) int {
+	margin := 0
+	counted := false
+	for _, deal := range record.Deals {
+		if deal.Result == nil {
+			continue
+		}
+		counted = true
+		margin += deal.Result.Upgrades[record.Winner] - deal.Result.Upgrades[1-record.Winner]
+	}
+	if !counted {
+		margin = record.FinalLevels[record.Winner] - record.FinalLevels[1-record.Winner]
+	}
+	if margin < 0 {
+		return 0
+	}
+	return margin
+}
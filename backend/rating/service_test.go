@@ -0,0 +1,145 @@
+package rating
+
+import (
+	"testing"
+	"time"
+
+	"guandan-world/backend/match"
+	"guandan-world/sdk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rankedMatch builds a ranked match where players[i] sits at seat i
+func rankedMatch(id string, winner int, finishedAt time.Time, players []string, upgrades ...[2]int) *match.Record {
+	record := &match.Record{ID: id, Ranked: true, Winner: winner, FinishedAt: finishedAt}
+	for seat, player := range players {
+		record.Players = append(record.Players, match.Player{ID: player, Seat: seat})
+	}
+	for i, up := range upgrades {
+		record.Deals = append(record.Deals, &match.DealRecord{Number: i + 1, Result: &sdk.DealResult{Upgrades: up}})
+	}
+	return record
+}
+
+func TestService_RecordMatch(t *testing.T) {
+	repo := NewMemoryRepository()
+	service := NewService(repo)
+	now := time.Now()
+	players := []string{"a", "b", "c", "d"}
+
+	initial, err := service.GetRating("a")
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, initial.Mu)
+	assert.InDelta(t, 0, initial.Skill, 1e-9)
+
+	entries, err := service.RecordMatch(rankedMatch("m1", 0, now, players, [2]int{2, 0}, [2]int{0, 1}))
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+
+	for _, entry := range entries {
+		assert.Equal(t, "m1", entry.MatchID)
+		assert.Equal(t, 1, entry.Margin)
+		assert.Less(t, entry.SigmaAfter, entry.SigmaBefore)
+		if entry.Won {
+			assert.Greater(t, entry.MuAfter, entry.MuBefore)
+		} else {
+			assert.Less(t, entry.MuAfter, entry.MuBefore)
+		}
+	}
+
+	// Seats 0 and 2 won; equal starting ratings move symmetrically
+	a, _ := service.GetRating("a")
+	b, _ := service.GetRating("b")
+	c, _ := service.GetRating("c")
+	assert.InDelta(t, a.Mu, c.Mu, 1e-9)
+	assert.InDelta(t, a.Mu-25, 25-b.Mu, 1e-9)
+	assert.Equal(t, 1, a.Matches)
+	assert.True(t, a.LastPlayedAt.Equal(now))
+
+	history, err := service.History("a", 0)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+
+	_, err = service.RecordMatch(&match.Record{ID: "casual", Players: rankedMatch("x", 0, now, players).Players})
+	assert.Error(t, err)
+	_, err = service.RecordMatch(rankedMatch("short", 0, now, players[:3]))
+	assert.Error(t, err)
+	_, err = service.RecordMatch(rankedMatch("m2", 0, now, players))
+	require.NoError(t, err)
+	history, _ = service.History("a", 1)
+	require.Len(t, history, 1)
+	assert.Equal(t, "m2", history[0].MatchID)
+}
+
+func TestService_UpsetMovesFurther(t *testing.T) {
+	service := NewService(NewMemoryRepository())
+	now := time.Now()
+
+	// a and c become favourites
+	for i := 0; i < 3; i++ {
+		_, err := service.RecordMatch(rankedMatch("warmup", 0, now, []string{"a", "x", "c", "y"}))
+		require.NoError(t, err)
+	}
+
+	expected, err := service.RecordMatch(rankedMatch("expected", 0, now, []string{"a", "b", "c", "d"}))
+	require.NoError(t, err)
+	fresh := NewService(NewMemoryRepository())
+	for i := 0; i < 3; i++ {
+		_, err := fresh.RecordMatch(rankedMatch("warmup", 0, now, []string{"a", "x", "c", "y"}))
+		require.NoError(t, err)
+	}
+	upset, err := fresh.RecordMatch(rankedMatch("upset", 1, now, []string{"a", "b", "c", "d"}))
+	require.NoError(t, err)
+
+	// b gains more for beating the favourites than it loses for losing to them
+	gain := upset[1].MuAfter - upset[1].MuBefore
+	loss := expected[1].MuBefore - expected[1].MuAfter
+	assert.Greater(t, gain, loss)
+}
+
+func TestConfig_MarginFactor(t *testing.T) {
+	config := DefaultConfig()
+	players := []string{"a", "b", "c", "d"}
+
+	narrow := NewServiceWithConfig(NewMemoryRepository(), config)
+	narrowEntries, err := narrow.RecordMatch(rankedMatch("m", 0, time.Now(), players, [2]int{1, 0}))
+	require.NoError(t, err)
+
+	sweep := NewServiceWithConfig(NewMemoryRepository(), config)
+	sweepEntries, err := sweep.RecordMatch(rankedMatch("m", 0, time.Now(), players, [2]int{3, 0}, [2]int{3, 0}, [2]int{3, 0}, [2]int{3, 0}))
+	require.NoError(t, err)
+	assert.Equal(t, 12, sweepEntries[0].Margin)
+
+	narrowGain := narrowEntries[0].MuAfter - narrowEntries[0].MuBefore
+	sweepGain := sweepEntries[0].MuAfter - sweepEntries[0].MuBefore
+	// A sweep of 12 levels moves the mean 1.5x; a margin of 1 only 1/24 further
+	assert.InDelta(t, 1.5/(1+config.MarginWeight/12), sweepGain/narrowGain, 1e-9)
+
+	config.MarginWeight = 0
+	assert.Equal(t, 1.0, config.marginFactor(12))
+}
+
+func TestConfig_Decay(t *testing.T) {
+	config := DefaultConfig()
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	r := &Rating{PlayerID: "a", Mu: 30, Sigma: 2, LastPlayedAt: now}
+
+	// Within the grace period nothing changes
+	fresh := config.decay(r, now.Add(config.DecayGrace))
+	assert.Equal(t, 2.0, fresh.Sigma)
+	assert.InDelta(t, 24, fresh.Skill, 1e-9)
+
+	stale := config.decay(r, now.Add(config.DecayGrace+30*24*time.Hour))
+	assert.Greater(t, stale.Sigma, 2.0)
+	assert.Less(t, stale.Skill, fresh.Skill)
+	assert.Equal(t, 30.0, stale.Mu)
+
+	// Decay never makes a player less certain than a newcomer
+	abandoned := config.decay(r, now.AddDate(10, 0, 0))
+	assert.InDelta(t, config.Sigma, abandoned.Sigma, 1e-9)
+
+	// The stored rating is untouched
+	assert.Equal(t, 2.0, r.Sigma)
+}
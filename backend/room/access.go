@@ -0,0 +1,135 @@
+package room
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrAccessDenied is returned when joining a private or passcode room without a passcode or invite
+	ErrAccessDenied = errors.New("a passcode or invite is required to join this room")
+	// ErrInvalidPasscode is returned for a wrong passcode
+	ErrInvalidPasscode = errors.New("invalid room passcode")
+	// ErrInvalidInvite is returned for invite tokens that are forged, expired or for another room
+	ErrInvalidInvite = errors.New("invite is invalid or has expired")
+)
+
+const (
+	minPasscodeLength = 4
+	maxPasscodeLength = 32
+
+	// DefaultInviteTTL is how long invites last when no lifetime is given
+	DefaultInviteTTL = 24 * time.Hour
+	// MaxInviteTTL is the longest lifetime an invite can have
+	MaxInviteTTL = 7 * 24 * time.Hour
+)
+
+// Access is what a player presents to join a locked room
+type Access struct {
+	Passcode string `json:"passcode,omitempty"`
+	Invite   string `json:"invite,omitempty"` // Token from an invite link
+}
+
+// Invite is a signed, expiring token that lets its holder join a room
+type Invite struct {
+	RoomID    string    `json:"room_id"`
+	InviterID string    `json:"inviter_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// inviteClaims are the JWT claims of an invite token
+type inviteClaims struct {
+	RoomID    string `json:"room_id"`
+	InviterID string `json:"inviter_id"`
+	jwt.RegisteredClaims
+}
+
+// ValidatePasscode checks the length of a new passcode; an empty passcode removes it
+func ValidatePasscode(passcode string) error {
+	if passcode == "" {
+		return nil
+	}
+	if len(passcode) < minPasscodeLength || len(passcode) > maxPasscodeLength {
+		return fmt.Errorf("%w: passcode must be %d to %d characters", ErrInvalidSettings, minPasscodeLength, maxPasscodeLength)
+	}
+	return nil
+}
+
+// Locked reports whether joining the room needs a passcode or invite
+func (r *Room) Locked() bool {
+	return r.Settings.Private || r.HasPasscode
+}
+
+// newInviteSecret returns a random key for signing invites until SetInviteSecret is called
+func newInviteSecret() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(fmt.Sprintf("failed to generate invite secret: %v", err))
+	}
+	return secret
+}
+
+// hashPasscode hashes a passcode for storage
+func hashPasscode(passcode string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(passcode), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash passcode: %w", err)
+	}
+	return string(hashed), nil
+}
+
+// signInvite creates an invite token for roomID
+func signInvite(secret []byte, roomID, inviterID string, expiresAt time.Time) (string, error) {
+	claims := &inviteClaims{
+		RoomID:    roomID,
+		InviterID: inviterID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign invite: %w", err)
+	}
+	return token, nil
+}
+
+// checkAccess verifies the passcode or invite presented to join a locked room
+func checkAccess(secret []byte, room *Room, access Access) error {
+	if !room.Locked() {
+		return nil
+	}
+
+	if access.Invite != "" {
+		token, err := jwt.ParseWithClaims(access.Invite, &inviteClaims{}, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return secret, nil
+		})
+		if err != nil {
+			return ErrInvalidInvite
+		}
+		claims, ok := token.Claims.(*inviteClaims)
+		if !ok || !token.Valid || claims.RoomID != room.ID {
+			return ErrInvalidInvite
+		}
+		return nil
+	}
+
+	if access.Passcode != "" {
+		if !room.HasPasscode || bcrypt.CompareHashAndPassword([]byte(room.Passcode), []byte(access.Passcode)) != nil {
+			return ErrInvalidPasscode
+		}
+		return nil
+	}
+
+	return ErrAccessDenied
+}
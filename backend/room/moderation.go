@@ -0,0 +1,161 @@
+package room
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrPlayerBanned is returned when a banned player tries to join the room
+	ErrPlayerBanned = errors.New("player is banned from this room")
+	// ErrOwnerTarget is returned when the owner kicks, bans or hands the room to themselves
+	ErrOwnerTarget = errors.New("the owner cannot target themselves")
+)
+
+// isBanned reports whether a player is banned from the room
+func (r *Room) isBanned(playerID string) bool {
+	for _, id := range r.Banned {
+		if id == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+// unseat removes a human player from a seat of the room
+func (s *roomService) unseat(room *Room, seat int) {
+	delete(s.playerRooms, room.Players[seat].ID)
+	room.Players[seat] = nil
+	room.PlayerCount--
+	room.dropSwapRequests(seat)
+	if room.PlayerCount < 4 && room.Status == RoomStatusReady {
+		room.Status = RoomStatusWaiting
+	}
+}
+
+// moderatedRoom returns a room owned by ownerID, for an action on another player
+func (s *roomService) moderatedRoom(roomID, ownerID, playerID string) (*Room, error) {
+	room, exists := s.rooms[roomID]
+	if !exists {
+		return nil, ErrRoomNotFound
+	}
+	if room.Owner != ownerID {
+		return nil, ErrNotRoomOwner
+	}
+	if playerID == ownerID {
+		return nil, ErrOwnerTarget
+	}
+	return room, nil
+}
+
+// KickPlayer removes a player from the room before the game starts
+func (s *roomService) KickPlayer(roomID, ownerID, playerID string) (*Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, err := s.moderatedRoom(roomID, ownerID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	seat := room.seatOf(playerID)
+	if seat < 0 || room.Players[seat].Bot {
+		return nil, ErrNotInRoom
+	}
+	if room.Status == RoomStatusPlaying {
+		return nil, ErrGameInProgress
+	}
+
+	s.unseat(room, seat)
+	room.UpdatedAt = time.Now()
+
+	if err := s.save(room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// BanPlayer bans a player from the room, kicking them if they are seated in it
+func (s *roomService) BanPlayer(roomID, ownerID, playerID string) (*Room, error) {
+	if _, err := s.authService.GetUserByID(playerID); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, err := s.moderatedRoom(roomID, ownerID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	if seat := room.seatOf(playerID); seat >= 0 {
+		if room.Status == RoomStatusPlaying {
+			return nil, ErrGameInProgress
+		}
+		s.unseat(room, seat)
+	}
+	if !room.isBanned(playerID) {
+		room.Banned = append(room.Banned, playerID)
+	}
+	room.UpdatedAt = time.Now()
+
+	if err := s.save(room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// UnbanPlayer lets a banned player join the room again
+func (s *roomService) UnbanPlayer(roomID, ownerID, playerID string) (*Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, err := s.moderatedRoom(roomID, ownerID, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	banned := room.Banned[:0]
+	for _, id := range room.Banned {
+		if id != playerID {
+			banned = append(banned, id)
+		}
+	}
+	if len(banned) == 0 {
+		banned = nil
+	}
+	room.Banned = banned
+	room.UpdatedAt = time.Now()
+
+	if err := s.save(room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// TransferOwnership hands the room to another player seated in it
+func (s *roomService) TransferOwnership(roomID, ownerID, newOwnerID string) (*Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, err := s.moderatedRoom(roomID, ownerID, newOwnerID)
+	if err != nil {
+		return nil, err
+	}
+	// Bots cannot own a room
+	seat := room.seatOf(newOwnerID)
+	if seat < 0 || room.Players[seat].Bot {
+		return nil, ErrNotInRoom
+	}
+
+	room.Owner = newOwnerID
+	room.UpdatedAt = time.Now()
+
+	if err := s.save(room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
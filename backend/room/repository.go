@@ -0,0 +1,100 @@
+package room
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrRoomNotFound is returned when a room does not exist
+var ErrRoomNotFound = errors.New("room not found")
+
+// RoomRepository stores rooms and their seated players
+type RoomRepository interface {
+	Save(room *Room) error
+	Get(roomID string) (*Room, error)
+	Delete(roomID string) error
+	List() ([]*Room, error)
+}
+
+// memoryRoomRepository keeps rooms in memory, used by tests and NewRoomService
+type memoryRoomRepository struct {
+	rooms map[string]*Room // roomID -> Room
+	mu    sync.RWMutex
+}
+
+// NewMemoryRoomRepository creates an in-memory room repository
+func NewMemoryRoomRepository() RoomRepository {
+	return &memoryRoomRepository{
+		rooms: make(map[string]*Room),
+	}
+}
+
+// Save inserts or replaces a room
+func (r *memoryRoomRepository) Save(room *Room) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rooms[room.ID] = room.Clone()
+	return nil
+}
+
+// Get retrieves a room by ID
+func (r *memoryRoomRepository) Get(roomID string) (*Room, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	room, exists := r.rooms[roomID]
+	if !exists {
+		return nil, ErrRoomNotFound
+	}
+	return room.Clone(), nil
+}
+
+// Delete removes a room
+func (r *memoryRoomRepository) Delete(roomID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.rooms[roomID]; !exists {
+		return ErrRoomNotFound
+	}
+	delete(r.rooms, roomID)
+	return nil
+}
+
+// List returns all rooms ordered by creation time
+func (r *memoryRoomRepository) List() ([]*Room, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rooms := make([]*Room, 0, len(r.rooms))
+	for _, room := range r.rooms {
+		rooms = append(rooms, room.Clone())
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		if !rooms[i].CreatedAt.Equal(rooms[j].CreatedAt) {
+			return rooms[i].CreatedAt.Before(rooms[j].CreatedAt)
+		}
+		return rooms[i].ID < rooms[j].ID
+	})
+	return rooms, nil
+}
+
+// Clone returns a deep copy of the room
+func (r *Room) Clone() *Room {
+	copied := *r
+	for seat, player := range r.Players {
+		if player != nil {
+			p := *player
+			copied.Players[seat] = &p
+		}
+	}
+	if r.SwapRequests != nil {
+		copied.SwapRequests = append([]SeatSwapRequest(nil), r.SwapRequests...)
+	}
+	if r.Banned != nil {
+		copied.Banned = append([]string(nil), r.Banned...)
+	}
+	return &copied
+}
@@ -0,0 +1,235 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNotInRoom is returned when a player acts on a room they are not seated in
+	ErrNotInRoom = errors.New("player is not in this room")
+	// ErrPlayersNotReady is returned when the owner starts a game before everyone is ready
+	ErrPlayersNotReady = errors.New("all players must be ready to start the game")
+	// ErrSwapRequestNotFound is returned when answering a seat swap nobody asked for
+	ErrSwapRequestNotFound = errors.New("seat swap request not found")
+)
+
+// SeatSwapRequest asks the player in ToSeat to trade seats with the player in
+// FromSeat. Seats 0/2 and 1/3 are partners, so swapping seats changes teams.
+type SeatSwapRequest struct {
+	FromID    string    `json:"from_id"`
+	FromSeat  int       `json:"from_seat"`
+	ToID      string    `json:"to_id"`
+	ToSeat    int       `json:"to_seat"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// seatOf returns the seat of a player in the room, or -1
+func (r *Room) seatOf(playerID string) int {
+	for seat, player := range r.Players {
+		if player != nil && player.ID == playerID {
+			return seat
+		}
+	}
+	return -1
+}
+
+// swapSeats swaps the occupants of two seats, either of which may be empty.
+// Moved players have to confirm they are ready again.
+func (r *Room) swapSeats(a, b int) {
+	r.Players[a], r.Players[b] = r.Players[b], r.Players[a]
+	for _, seat := range []int{a, b} {
+		if player := r.Players[seat]; player != nil {
+			player.Seat = seat
+			if player.Bot {
+				// Bot IDs follow their seat so a bot added later cannot reuse one
+				player.ID = newBotPlayer(r.ID, seat).ID
+			} else {
+				player.Ready = false
+			}
+		}
+	}
+	r.dropSwapRequests(a, b)
+}
+
+// dropSwapRequests removes the swap requests involving the given seats
+func (r *Room) dropSwapRequests(seats ...int) {
+	kept := r.SwapRequests[:0]
+	for _, request := range r.SwapRequests {
+		involved := false
+		for _, seat := range seats {
+			if request.FromSeat == seat || request.ToSeat == seat {
+				involved = true
+				break
+			}
+		}
+		if !involved {
+			kept = append(kept, request)
+		}
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+	r.SwapRequests = kept
+}
+
+// allReady reports whether every human player other than the owner is ready
+func (r *Room) allReady() bool {
+	for _, player := range r.Players {
+		if player != nil && !player.Bot && player.ID != r.Owner && !player.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+// seatedRoom returns a room the player is seated in that has not started playing
+func (s *roomService) seatedRoom(roomID, playerID string) (*Room, int, error) {
+	room, exists := s.rooms[roomID]
+	if !exists {
+		return nil, -1, ErrRoomNotFound
+	}
+	seat := room.seatOf(playerID)
+	if seat < 0 || room.Players[seat].Bot {
+		return nil, -1, ErrNotInRoom
+	}
+	if room.Status == RoomStatusPlaying {
+		return nil, -1, ErrGameInProgress
+	}
+	return room, seat, nil
+}
+
+// ChooseSeat moves a player to an empty seat, or asks the player in it to swap
+func (s *roomService) ChooseSeat(roomID, playerID string, seat int) (*Room, error) {
+	if seat < 0 || seat > 3 {
+		return nil, fmt.Errorf("invalid seat %d", seat)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, current, err := s.seatedRoom(roomID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	if current == seat {
+		return room, nil
+	}
+
+	target := room.Players[seat]
+	switch {
+	case target == nil:
+		room.swapSeats(current, seat)
+	case target.Bot:
+		// Bots are only moved by the owner
+		return nil, ErrSeatTaken
+	default:
+		// A player has one open request at a time
+		room.dropSwapRequests(current)
+		room.SwapRequests = append(room.SwapRequests, SeatSwapRequest{
+			FromID:    playerID,
+			FromSeat:  current,
+			ToID:      target.ID,
+			ToSeat:    seat,
+			CreatedAt: time.Now(),
+		})
+	}
+	room.UpdatedAt = time.Now()
+
+	if err := s.save(room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// RespondSeatSwap accepts or declines the swap requesterID asked playerID for
+func (s *roomService) RespondSeatSwap(roomID, playerID, requesterID string, accept bool) (*Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, seat, err := s.seatedRoom(roomID, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var request *SeatSwapRequest
+	for i := range room.SwapRequests {
+		if room.SwapRequests[i].FromID == requesterID && room.SwapRequests[i].ToID == playerID {
+			request = &room.SwapRequests[i]
+			break
+		}
+	}
+	if request == nil || request.ToSeat != seat || room.seatOf(requesterID) != request.FromSeat {
+		return nil, ErrSwapRequestNotFound
+	}
+
+	if accept {
+		room.swapSeats(request.FromSeat, request.ToSeat)
+	} else {
+		room.dropSwapRequests(request.FromSeat)
+	}
+	room.UpdatedAt = time.Now()
+
+	if err := s.save(room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// MoveSeat lets the owner move whoever sits in from to seat to, swapping with its occupant
+func (s *roomService) MoveSeat(roomID, ownerID string, from, to int) (*Room, error) {
+	if from < 0 || from > 3 || to < 0 || to > 3 {
+		return nil, fmt.Errorf("invalid seat move %d -> %d", from, to)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, exists := s.rooms[roomID]
+	if !exists {
+		return nil, ErrRoomNotFound
+	}
+	if room.Owner != ownerID {
+		return nil, ErrNotRoomOwner
+	}
+	if room.Status == RoomStatusPlaying {
+		return nil, ErrGameInProgress
+	}
+	if room.Players[from] == nil {
+		return nil, fmt.Errorf("seat %d is empty", from)
+	}
+
+	if from != to {
+		room.swapSeats(from, to)
+		room.UpdatedAt = time.Now()
+	}
+
+	if err := s.save(room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// SetReady sets the ready flag of a player
+func (s *roomService) SetReady(roomID, playerID string, ready bool) (*Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, seat, err := s.seatedRoom(roomID, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	room.Players[seat].Ready = ready
+	room.UpdatedAt = time.Now()
+
+	if err := s.save(room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
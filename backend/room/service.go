@@ -42,6 +42,12 @@ type Player struct {
 	Username string `json:"username"`
 	Seat     int    `json:"seat"`
 	Online   bool   `json:"online"`
+	Bot      bool   `json:"bot,omitempty"` // Seat is played by the server
+	Ready    bool   `json:"ready"`         // Confirmed ready to start; bots always are
+
+	// Bot settings chosen by the room owner; see game.BotSpec
+	BotAlgorithm  string `json:"bot_algorithm,omitempty"`
+	BotDifficulty string `json:"bot_difficulty,omitempty"`
 }
 
 // Room represents a game room
@@ -51,8 +57,14 @@ type Room struct {
 	Players     [4]*Player `json:"players"`     // Fixed 4 seats (0-3)
 	Owner       string     `json:"owner"`       // Owner user ID
 	PlayerCount int        `json:"player_count"` // Number of players currently in room
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	Settings    Settings   `json:"settings"`
+	Passcode    string     `json:"-"` // bcrypt hash, never exposed in JSON
+	HasPasscode bool       `json:"has_passcode"`
+	// Open seat swap requests between players
+	SwapRequests []SeatSwapRequest `json:"swap_requests,omitempty"`
+	Banned       []string          `json:"banned,omitempty"` // Players the owner banned from the room
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
 }
 
 // RoomInfo provides room information for listing
@@ -63,6 +75,8 @@ type RoomInfo struct {
 	Players     []*Player  `json:"players"`
 	Owner       string     `json:"owner"`
 	CanJoin     bool       `json:"can_join"`
+	HasPasscode bool       `json:"has_passcode"`
+	Settings    Settings   `json:"settings"`
 	CreatedAt   time.Time  `json:"created_at"`
 }
 
@@ -77,34 +91,154 @@ type RoomListResponse struct {
 // RoomService interface defines room management operations
 type RoomService interface {
 	CreateRoom(ownerID string) (*Room, error)
+	// CreateRoomWithSettings creates a room with the given game settings
+	CreateRoomWithSettings(ownerID string, settings Settings) (*Room, error)
+	// UpdateSettings lets the room owner change the settings before the game starts
+	UpdateSettings(roomID, ownerID string, settings Settings) (*Room, error)
 	JoinRoom(roomID, playerID string) (*Room, error)
+	// JoinRoomWithAccess joins a room, presenting a passcode or invite for
+	// private and passcode rooms
+	JoinRoomWithAccess(roomID, playerID string, access Access) (*Room, error)
+	// SetPasscode lets the room owner set the room passcode; an empty one removes it
+	SetPasscode(roomID, ownerID, passcode string) (*Room, error)
+	// CreateInvite creates an invite to the room for a player seated in it.
+	// A ttl of zero uses DefaultInviteTTL.
+	CreateInvite(roomID, inviterID string, ttl time.Duration) (*Invite, error)
+	// SetInviteSecret sets the key invites are signed with. Until it is set a
+	// random key is used, so invites do not survive a restart.
+	SetInviteSecret(secret string)
 	LeaveRoom(roomID, playerID string) (*Room, error)
 	GetRoom(roomID string) (*Room, error)
 	GetRoomList(page, limit int, statusFilter *RoomStatus) (*RoomListResponse, error)
 	StartGame(roomID, playerID string) error
 	CloseRoom(roomID string) error
 	GetPlayerRoom(playerID string) (*Room, error)
+	// CreateMatchedRoom creates a room for a match made by the server and puts it
+	// straight into the playing state. seats[i] is the user at seat i; an empty
+	// ID seats a bot there.
+	CreateMatchedRoom(seats [4]string) (*Room, error)
+	// AddBot lets the room owner seat a bot before the game starts. A negative
+	// seat picks the first empty one.
+	AddBot(roomID, ownerID string, seat int, algorithm, difficulty string) (*Room, error)
+	// RemoveBot lets the room owner take a bot out of its seat before the game starts
+	RemoveBot(roomID, ownerID string, seat int) (*Room, error)
+	// ChooseSeat moves a player to an empty seat, or asks the player sitting
+	// there to swap seats
+	ChooseSeat(roomID, playerID string, seat int) (*Room, error)
+	// RespondSeatSwap accepts or declines a seat swap requested by requesterID
+	RespondSeatSwap(roomID, playerID, requesterID string, accept bool) (*Room, error)
+	// MoveSeat lets the room owner move a player or bot to another seat,
+	// swapping with whoever sits there
+	MoveSeat(roomID, ownerID string, from, to int) (*Room, error)
+	// SetReady sets whether a player is ready; the owner can only start the
+	// game when every other player is
+	SetReady(roomID, playerID string, ready bool) (*Room, error)
+	// KickPlayer lets the room owner remove a player before the game starts
+	KickPlayer(roomID, ownerID, playerID string) (*Room, error)
+	// BanPlayer lets the room owner kick a player and keep them from rejoining
+	BanPlayer(roomID, ownerID, playerID string) (*Room, error)
+	// UnbanPlayer lets the room owner lift a ban
+	UnbanPlayer(roomID, ownerID, playerID string) (*Room, error)
+	// TransferOwnership lets the room owner hand the room to another player in it
+	TransferOwnership(roomID, ownerID, newOwnerID string) (*Room, error)
 }
 
+var (
+	// ErrNotRoomOwner is returned when a player other than the owner manages the room
+	ErrNotRoomOwner = errors.New("only room owner can manage the room")
+	// ErrRoomFull is returned when every seat is taken
+	ErrRoomFull = errors.New("room is full")
+	// ErrSeatTaken is returned when a seat is already occupied
+	ErrSeatTaken = errors.New("seat is already taken")
+	// ErrNotBot is returned when a seat is not played by a bot
+	ErrNotBot = errors.New("seat is not played by a bot")
+	// ErrGameInProgress is returned for changes that are only allowed before the game starts
+	ErrGameInProgress = errors.New("game is already in progress")
+	// ErrInvalidSettings is returned for room settings that cannot be played
+	ErrInvalidSettings = errors.New("invalid room settings")
+)
+
 // roomService implements RoomService interface
 type roomService struct {
 	rooms       map[string]*Room    // roomID -> Room
 	playerRooms map[string]string   // playerID -> roomID
+	repo        RoomRepository      // persistent copy of rooms
 	authService auth.AuthService
+	inviteKey   []byte // signs invite tokens
 	mu          sync.RWMutex
 }
 
-// NewRoomService creates a new room service
+// NewRoomService creates a new room service backed by an in-memory repository
 func NewRoomService(authService auth.AuthService) RoomService {
 	return &roomService{
 		rooms:       make(map[string]*Room),
 		playerRooms: make(map[string]string),
+		repo:        NewMemoryRoomRepository(),
 		authService: authService,
+		inviteKey:   newInviteSecret(),
+	}
+}
+
+// NewRoomServiceWithRepository creates a room service that persists rooms in repo
+// and restores the rooms already stored there. Games do not survive a restart, so
+// rooms that were playing are put back into the ready state.
+func NewRoomServiceWithRepository(authService auth.AuthService, repo RoomRepository) (RoomService, error) {
+	s := &roomService{
+		rooms:       make(map[string]*Room),
+		playerRooms: make(map[string]string),
+		repo:        repo,
+		authService: authService,
+		inviteKey:   newInviteSecret(),
+	}
+
+	rooms, err := repo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rooms: %w", err)
 	}
+	for _, room := range rooms {
+		if room.Status == RoomStatusClosed {
+			continue
+		}
+		if room.Settings == (Settings{}) {
+			// Rooms stored before settings existed
+			room.Settings = DefaultSettings()
+		}
+		if room.Status == RoomStatusPlaying {
+			room.Status = RoomStatusReady
+			if err := repo.Save(room); err != nil {
+				return nil, fmt.Errorf("failed to save room: %w", err)
+			}
+		}
+		s.rooms[room.ID] = room
+		for _, player := range room.Players {
+			if player != nil && !player.Bot {
+				s.playerRooms[player.ID] = room.ID
+			}
+		}
+	}
+
+	return s, nil
 }
 
-// CreateRoom creates a new room with the specified owner
+// save writes a room through to the repository
+func (s *roomService) save(room *Room) error {
+	if err := s.repo.Save(room); err != nil {
+		return fmt.Errorf("failed to save room: %w", err)
+	}
+	return nil
+}
+
+// CreateRoom creates a new room with the specified owner and default settings
 func (s *roomService) CreateRoom(ownerID string) (*Room, error) {
+	return s.CreateRoomWithSettings(ownerID, DefaultSettings())
+}
+
+// CreateRoomWithSettings creates a new room with the specified owner and settings
+func (s *roomService) CreateRoomWithSettings(ownerID string, settings Settings) (*Room, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -129,6 +263,7 @@ func (s *roomService) CreateRoom(ownerID string) (*Room, error) {
 		Players:     [4]*Player{},
 		Owner:       ownerID,
 		PlayerCount: 1,
+		Settings:    settings,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -142,6 +277,9 @@ func (s *roomService) CreateRoom(ownerID string) (*Room, error) {
 	}
 
 	// Store room and player mapping
+	if err := s.save(room); err != nil {
+		return nil, err
+	}
 	s.rooms[roomID] = room
 	s.playerRooms[ownerID] = roomID
 
@@ -150,6 +288,12 @@ func (s *roomService) CreateRoom(ownerID string) (*Room, error) {
 
 // JoinRoom adds a player to an existing room
 func (s *roomService) JoinRoom(roomID, playerID string) (*Room, error) {
+	return s.JoinRoomWithAccess(roomID, playerID, Access{})
+}
+
+// JoinRoomWithAccess adds a player to an existing room, checking the passcode
+// or invite of locked rooms
+func (s *roomService) JoinRoomWithAccess(roomID, playerID string, access Access) (*Room, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -171,7 +315,17 @@ func (s *roomService) JoinRoom(roomID, playerID string) (*Room, error) {
 	// Get room
 	room, exists := s.rooms[roomID]
 	if !exists {
-		return nil, errors.New("room not found")
+		return nil, ErrRoomNotFound
+	}
+
+	// Banned players stay out, invite or not
+	if room.isBanned(playerID) {
+		return nil, ErrPlayerBanned
+	}
+
+	// Private and passcode rooms need a passcode or invite
+	if err := checkAccess(s.inviteKey, room, access); err != nil {
+		return nil, err
 	}
 
 	// Check room status
@@ -181,7 +335,7 @@ func (s *roomService) JoinRoom(roomID, playerID string) (*Room, error) {
 
 	// Check if room is full
 	if room.PlayerCount >= 4 {
-		return nil, errors.New("room is full")
+		return nil, ErrRoomFull
 	}
 
 	// Find available seat
@@ -213,6 +367,10 @@ func (s *roomService) JoinRoom(roomID, playerID string) (*Room, error) {
 		room.Status = RoomStatusReady
 	}
 
+	if err := s.save(room); err != nil {
+		return nil, err
+	}
+
 	// Store player mapping
 	s.playerRooms[playerID] = roomID
 
@@ -227,13 +385,13 @@ func (s *roomService) LeaveRoom(roomID, playerID string) (*Room, error) {
 	// Get room
 	room, exists := s.rooms[roomID]
 	if !exists {
-		return nil, errors.New("room not found")
+		return nil, ErrRoomNotFound
 	}
 
 	// Check if player is in this room
 	currentRoomID, inRoom := s.playerRooms[playerID]
 	if !inRoom || currentRoomID != roomID {
-		return nil, errors.New("player is not in this room")
+		return nil, ErrNotInRoom
 	}
 
 	// Find and remove player
@@ -249,20 +407,16 @@ func (s *roomService) LeaveRoom(roomID, playerID string) (*Room, error) {
 		return nil, errors.New("player not found in room")
 	}
 
-	// Remove player
-	room.Players[playerSeat] = nil
-	room.PlayerCount--
+	// Remove player and player mapping
+	s.unseat(room, playerSeat)
 	room.UpdatedAt = time.Now()
 
-	// Remove player mapping
-	delete(s.playerRooms, playerID)
-
 	// Handle owner leaving
 	if room.Owner == playerID {
-		// Find new owner from remaining players
+		// Find new owner from remaining players; bots cannot own a room
 		newOwner := ""
 		for i := 0; i < 4; i++ {
-			if room.Players[i] != nil {
+			if room.Players[i] != nil && !room.Players[i].Bot {
 				newOwner = room.Players[i].ID
 				break
 			}
@@ -274,13 +428,15 @@ func (s *roomService) LeaveRoom(roomID, playerID string) (*Room, error) {
 			// No players left, close room
 			room.Status = RoomStatusClosed
 			delete(s.rooms, roomID)
+			if err := s.repo.Delete(roomID); err != nil && !errors.Is(err, ErrRoomNotFound) {
+				return nil, fmt.Errorf("failed to delete room: %w", err)
+			}
 			return nil, nil // Room closed
 		}
 	}
 
-	// Update room status
-	if room.PlayerCount < 4 && room.Status == RoomStatusReady {
-		room.Status = RoomStatusWaiting
+	if err := s.save(room); err != nil {
+		return nil, err
 	}
 
 	return room, nil
@@ -293,7 +449,7 @@ func (s *roomService) GetRoom(roomID string) (*Room, error) {
 
 	room, exists := s.rooms[roomID]
 	if !exists {
-		return nil, errors.New("room not found")
+		return nil, ErrRoomNotFound
 	}
 
 	return room, nil
@@ -315,8 +471,8 @@ func (s *roomService) GetRoomList(page, limit int, statusFilter *RoomStatus) (*R
 	// Collect and filter rooms
 	var allRooms []*Room
 	for _, room := range s.rooms {
-		// Skip closed rooms
-		if room.Status == RoomStatusClosed {
+		// Skip closed and private rooms
+		if room.Status == RoomStatusClosed || room.Settings.Private {
 			continue
 		}
 
@@ -392,6 +548,8 @@ func (s *roomService) GetRoomList(page, limit int, statusFilter *RoomStatus) (*R
 			Players:     players,
 			Owner:       room.Owner,
 			CanJoin:     canJoin,
+			HasPasscode: room.HasPasscode,
+			Settings:    room.Settings,
 			CreatedAt:   room.CreatedAt,
 		}
 	}
@@ -412,7 +570,7 @@ func (s *roomService) StartGame(roomID, playerID string) error {
 	// Get room
 	room, exists := s.rooms[roomID]
 	if !exists {
-		return errors.New("room not found")
+		return ErrRoomNotFound
 	}
 
 	// Check if player is the owner
@@ -430,11 +588,15 @@ func (s *roomService) StartGame(roomID, playerID string) error {
 		return errors.New("room must have 4 players to start game")
 	}
 
+	if !room.allReady() {
+		return ErrPlayersNotReady
+	}
+
 	// Update room status
 	room.Status = RoomStatusPlaying
 	room.UpdatedAt = time.Now()
 
-	return nil
+	return s.save(room)
 }
 
 // CloseRoom closes a room
@@ -444,7 +606,7 @@ func (s *roomService) CloseRoom(roomID string) error {
 
 	room, exists := s.rooms[roomID]
 	if !exists {
-		return errors.New("room not found")
+		return ErrRoomNotFound
 	}
 
 	// Remove all player mappings
@@ -457,9 +619,283 @@ func (s *roomService) CloseRoom(roomID string) error {
 	// Remove room
 	delete(s.rooms, roomID)
 
+	if err := s.repo.Delete(roomID); err != nil && !errors.Is(err, ErrRoomNotFound) {
+		return fmt.Errorf("failed to delete room: %w", err)
+	}
+
 	return nil
 }
 
+// CreateMatchedRoom creates a full room for a server-made match
+func (s *roomService) CreateMatchedRoom(seats [4]string) (*Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	roomID := fmt.Sprintf("room_%d", time.Now().UnixNano())
+	room := &Room{
+		ID:          roomID,
+		Status:      RoomStatusPlaying,
+		Players:     [4]*Player{},
+		PlayerCount: 4,
+		Settings:    DefaultSettings(),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	for seat, playerID := range seats {
+		if playerID == "" {
+			room.Players[seat] = newBotPlayer(roomID, seat)
+			continue
+		}
+
+		player, err := s.authService.GetUserByID(playerID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid player: %w", err)
+		}
+		if existingRoomID, exists := s.playerRooms[playerID]; exists {
+			return nil, fmt.Errorf("player is already in room %s", existingRoomID)
+		}
+		room.Players[seat] = &Player{
+			ID:       player.ID,
+			Username: player.Username,
+			Seat:     seat,
+			Online:   player.Online,
+		}
+		if room.Owner == "" {
+			room.Owner = player.ID
+		}
+	}
+
+	if room.Owner == "" {
+		return nil, errors.New("a room needs at least one human player")
+	}
+
+	if err := s.save(room); err != nil {
+		return nil, err
+	}
+	s.rooms[roomID] = room
+	for _, player := range room.Players {
+		if !player.Bot {
+			s.playerRooms[player.ID] = roomID
+		}
+	}
+
+	return room, nil
+}
+
+// UpdateSettings changes the settings of a room that has not started playing
+func (s *roomService) UpdateSettings(roomID, ownerID string, settings Settings) (*Room, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, exists := s.rooms[roomID]
+	if !exists {
+		return nil, ErrRoomNotFound
+	}
+	if room.Owner != ownerID {
+		return nil, ErrNotRoomOwner
+	}
+	if room.Status == RoomStatusPlaying {
+		return nil, ErrGameInProgress
+	}
+
+	room.Settings = settings
+	room.UpdatedAt = time.Now()
+
+	if err := s.save(room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// SetPasscode sets or removes the passcode of a room
+func (s *roomService) SetPasscode(roomID, ownerID, passcode string) (*Room, error) {
+	if err := ValidatePasscode(passcode); err != nil {
+		return nil, err
+	}
+	hashed := ""
+	if passcode != "" {
+		var err error
+		if hashed, err = hashPasscode(passcode); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, exists := s.rooms[roomID]
+	if !exists {
+		return nil, ErrRoomNotFound
+	}
+	if room.Owner != ownerID {
+		return nil, ErrNotRoomOwner
+	}
+
+	room.Passcode = hashed
+	room.HasPasscode = hashed != ""
+	room.UpdatedAt = time.Now()
+
+	if err := s.save(room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// CreateInvite signs an invite to a room on behalf of one of its players
+func (s *roomService) CreateInvite(roomID, inviterID string, ttl time.Duration) (*Invite, error) {
+	if ttl == 0 {
+		ttl = DefaultInviteTTL
+	}
+	if ttl < 0 || ttl > MaxInviteTTL {
+		return nil, fmt.Errorf("invite lifetime must be at most %v", MaxInviteTTL)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	room, exists := s.rooms[roomID]
+	if !exists {
+		return nil, ErrRoomNotFound
+	}
+	if s.playerRooms[inviterID] != roomID {
+		return nil, ErrNotInRoom
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	token, err := signInvite(s.inviteKey, room.ID, inviterID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Invite{
+		RoomID:    room.ID,
+		InviterID: inviterID,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// SetInviteSecret sets the key invites are signed with
+func (s *roomService) SetInviteSecret(secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inviteKey = []byte(secret)
+}
+
+// AddBot seats a bot chosen by the room owner
+func (s *roomService) AddBot(roomID, ownerID string, seat int, algorithm, difficulty string) (*Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, exists := s.rooms[roomID]
+	if !exists {
+		return nil, ErrRoomNotFound
+	}
+	if room.Owner != ownerID {
+		return nil, ErrNotRoomOwner
+	}
+	if room.Status != RoomStatusWaiting {
+		if room.Status == RoomStatusPlaying {
+			return nil, ErrGameInProgress
+		}
+		return nil, ErrRoomFull
+	}
+
+	if seat < 0 {
+		for i := 0; i < 4; i++ {
+			if room.Players[i] == nil {
+				seat = i
+				break
+			}
+		}
+	}
+	if seat < 0 {
+		return nil, ErrRoomFull
+	}
+	if seat > 3 {
+		return nil, fmt.Errorf("invalid seat %d", seat)
+	}
+	if room.Players[seat] != nil {
+		return nil, ErrSeatTaken
+	}
+
+	bot := newBotPlayer(roomID, seat)
+	bot.BotAlgorithm = algorithm
+	bot.BotDifficulty = difficulty
+	room.Players[seat] = bot
+	room.PlayerCount++
+	room.UpdatedAt = time.Now()
+
+	// Update room status if full
+	if room.PlayerCount == 4 {
+		room.Status = RoomStatusReady
+	}
+
+	if err := s.save(room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// RemoveBot takes a bot out of its seat
+func (s *roomService) RemoveBot(roomID, ownerID string, seat int) (*Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, exists := s.rooms[roomID]
+	if !exists {
+		return nil, ErrRoomNotFound
+	}
+	if room.Owner != ownerID {
+		return nil, ErrNotRoomOwner
+	}
+	if room.Status == RoomStatusPlaying {
+		return nil, ErrGameInProgress
+	}
+	if seat < 0 || seat > 3 {
+		return nil, fmt.Errorf("invalid seat %d", seat)
+	}
+	if room.Players[seat] == nil || !room.Players[seat].Bot {
+		return nil, ErrNotBot
+	}
+
+	room.Players[seat] = nil
+	room.PlayerCount--
+	room.UpdatedAt = time.Now()
+
+	// Update room status
+	if room.Status == RoomStatusReady {
+		room.Status = RoomStatusWaiting
+	}
+
+	if err := s.save(room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// newBotPlayer creates the bot player of a seat
+func newBotPlayer(roomID string, seat int) *Player {
+	return &Player{
+		ID:       fmt.Sprintf("bot_%s_%d", roomID, seat),
+		Username: fmt.Sprintf("Bot %d", seat+1),
+		Seat:     seat,
+		Online:   true,
+		Bot:      true,
+		Ready:    true,
+	}
+}
+
 // GetPlayerRoom finds the room that a player is currently in
 func (s *roomService) GetPlayerRoom(playerID string) (*Room, error) {
 	s.mu.RLock()
@@ -474,7 +910,7 @@ func (s *roomService) GetPlayerRoom(playerID string) (*Room, error) {
 	if !exists {
 		// Clean up stale mapping
 		delete(s.playerRooms, playerID)
-		return nil, errors.New("room not found")
+		return nil, ErrRoomNotFound
 	}
 
 	return room, nil
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"guandan-world/backend/auth"
+	"guandan-world/sdk"
 )
 
 // Mock auth service for testing
@@ -401,6 +402,14 @@ func TestRoomService_StartGame(t *testing.T) {
 		roomSvc.JoinRoom(room.ID, users[i].ID)
 	}
 
+	// Everyone but the owner has to be ready
+	if err := roomSvc.StartGame(room.ID, users[0].ID); !errors.Is(err, ErrPlayersNotReady) {
+		t.Errorf("Expected ErrPlayersNotReady, got %v", err)
+	}
+	for i := 1; i < 4; i++ {
+		roomSvc.SetReady(room.ID, users[i].ID, true)
+	}
+
 	// Start game
 	err := roomSvc.StartGame(room.ID, users[0].ID)
 	if err != nil {
@@ -483,4 +492,437 @@ func TestRoomService_GetPlayerRoom_NotInRoom(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error when getting room for user not in any room")
 	}
-}
\ No newline at end of file
+}
+func TestRoomService_CreateMatchedRoom(t *testing.T) {
+	authSvc := newMockAuthService()
+	roomSvc := NewRoomService(authSvc)
+
+	alice, _ := authSvc.Register("alice", "password")
+	bob, _ := authSvc.Register("bob", "password")
+
+	// Bots take the empty seats
+	room, err := roomSvc.CreateMatchedRoom([4]string{"", alice.ID, "", bob.ID})
+	if err != nil {
+		t.Fatalf("Failed to create matched room: %v", err)
+	}
+
+	if room.Status != RoomStatusPlaying || room.PlayerCount != 4 {
+		t.Errorf("Expected a full playing room, got status %v with %d players", room.Status, room.PlayerCount)
+	}
+	if room.Owner != alice.ID {
+		t.Errorf("Expected the first human to own the room, got %s", room.Owner)
+	}
+	if !room.Players[0].Bot || !room.Players[2].Bot || room.Players[1].Bot || room.Players[3].ID != bob.ID {
+		t.Errorf("Unexpected seating %+v %+v %+v %+v", room.Players[0], room.Players[1], room.Players[2], room.Players[3])
+	}
+
+	playerRoom, err := roomSvc.GetPlayerRoom(bob.ID)
+	if err != nil || playerRoom.ID != room.ID {
+		t.Errorf("Expected bob to be mapped to the matched room, got %v (%v)", playerRoom, err)
+	}
+	if _, err := roomSvc.GetPlayerRoom(room.Players[0].ID); err == nil {
+		t.Error("Expected bots not to be mapped to rooms")
+	}
+
+	// Players already seated elsewhere cannot be matched again
+	if _, err := roomSvc.CreateMatchedRoom([4]string{alice.ID, "", "", ""}); err == nil {
+		t.Error("Expected error for a player who is already in a room")
+	}
+	if _, err := roomSvc.CreateMatchedRoom([4]string{}); err == nil {
+		t.Error("Expected error for a room without humans")
+	}
+}
+
+func TestRoomService_AddRemoveBot(t *testing.T) {
+	authSvc := newMockAuthService()
+	roomSvc := NewRoomService(authSvc)
+
+	alice, _ := authSvc.Register("alice", "password")
+	bob, _ := authSvc.Register("bob", "password")
+
+	room, _ := roomSvc.CreateRoom(alice.ID)
+	roomSvc.JoinRoom(room.ID, bob.ID)
+
+	if _, err := roomSvc.AddBot(room.ID, bob.ID, -1, "", ""); !errors.Is(err, ErrNotRoomOwner) {
+		t.Errorf("Expected ErrNotRoomOwner, got %v", err)
+	}
+	if _, err := roomSvc.AddBot(room.ID, alice.ID, 1, "", ""); !errors.Is(err, ErrSeatTaken) {
+		t.Errorf("Expected ErrSeatTaken, got %v", err)
+	}
+
+	room, err := roomSvc.AddBot(room.ID, alice.ID, 3, "simple", "")
+	if err != nil {
+		t.Fatalf("Failed to add bot: %v", err)
+	}
+	if bot := room.Players[3]; bot == nil || !bot.Bot || bot.BotAlgorithm != "simple" {
+		t.Errorf("Expected a simple bot at seat 3, got %+v", bot)
+	}
+
+	// A negative seat takes the first empty one and fills the room
+	room, err = roomSvc.AddBot(room.ID, alice.ID, -1, "", "expert")
+	if err != nil {
+		t.Fatalf("Failed to add bot: %v", err)
+	}
+	if room.Players[2] == nil || room.Players[2].BotDifficulty != "expert" {
+		t.Errorf("Expected an expert bot at seat 2, got %+v", room.Players[2])
+	}
+	if room.Status != RoomStatusReady || room.PlayerCount != 4 {
+		t.Errorf("Expected a ready room, got status %v with %d players", room.Status, room.PlayerCount)
+	}
+	if _, err := roomSvc.AddBot(room.ID, alice.ID, -1, "", ""); !errors.Is(err, ErrRoomFull) {
+		t.Errorf("Expected ErrRoomFull, got %v", err)
+	}
+
+	if _, err := roomSvc.RemoveBot(room.ID, alice.ID, 1); !errors.Is(err, ErrNotBot) {
+		t.Errorf("Expected ErrNotBot, got %v", err)
+	}
+	room, err = roomSvc.RemoveBot(room.ID, alice.ID, 2)
+	if err != nil {
+		t.Fatalf("Failed to remove bot: %v", err)
+	}
+	if room.Players[2] != nil || room.Status != RoomStatusWaiting || room.PlayerCount != 3 {
+		t.Errorf("Expected seat 2 to be free again, got status %v with %d players", room.Status, room.PlayerCount)
+	}
+
+	// Bots are only managed before the game starts
+	roomSvc.AddBot(room.ID, alice.ID, 2, "", "")
+	roomSvc.SetReady(room.ID, bob.ID, true)
+	if err := roomSvc.StartGame(room.ID, alice.ID); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	if _, err := roomSvc.RemoveBot(room.ID, alice.ID, 3); !errors.Is(err, ErrGameInProgress) {
+		t.Errorf("Expected ErrGameInProgress, got %v", err)
+	}
+}
+
+func TestRoomService_LeaveRoomWithBots(t *testing.T) {
+	authSvc := newMockAuthService()
+	roomSvc := NewRoomService(authSvc)
+
+	alice, _ := authSvc.Register("alice", "password")
+	bob, _ := authSvc.Register("bob", "password")
+
+	room, _ := roomSvc.CreateRoom(alice.ID)
+	roomSvc.AddBot(room.ID, alice.ID, 1, "", "")
+	roomSvc.JoinRoom(room.ID, bob.ID)
+
+	// Ownership passes to the next human, never to a bot
+	room, err := roomSvc.LeaveRoom(room.ID, alice.ID)
+	if err != nil {
+		t.Fatalf("Failed to leave room: %v", err)
+	}
+	if room.Owner != bob.ID {
+		t.Errorf("Expected bob to own the room, got %s", room.Owner)
+	}
+
+	// A room left with only bots is closed
+	roomID := room.ID
+	room, err = roomSvc.LeaveRoom(roomID, bob.ID)
+	if err != nil || room != nil {
+		t.Errorf("Expected the room to close, got %v (%v)", room, err)
+	}
+	if _, err := roomSvc.GetRoom(roomID); !errors.Is(err, ErrRoomNotFound) {
+		t.Errorf("Expected ErrRoomNotFound, got %v", err)
+	}
+}
+
+func TestRoomService_Settings(t *testing.T) {
+	authSvc := newMockAuthService()
+	roomSvc := NewRoomService(authSvc)
+
+	alice, _ := authSvc.Register("alice", "password")
+	bob, _ := authSvc.Register("bob", "password")
+
+	invalid := DefaultSettings()
+	invalid.MatchLength = MatchLengthTimed
+	if _, err := roomSvc.CreateRoomWithSettings(alice.ID, invalid); !errors.Is(err, ErrInvalidSettings) {
+		t.Errorf("Expected ErrInvalidSettings, got %v", err)
+	}
+
+	settings := DefaultSettings()
+	settings.Private = true
+	settings.StartingLevels = [2]int{10, 2}
+	room, err := roomSvc.CreateRoomWithSettings(alice.ID, settings)
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	if room.Settings != settings {
+		t.Errorf("Expected settings %+v, got %+v", settings, room.Settings)
+	}
+
+	// Private rooms are left out of the room list
+	if _, err := roomSvc.CreateRoom(bob.ID); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	list, _ := roomSvc.GetRoomList(1, 12, nil)
+	if list.TotalCount != 1 || list.Rooms[0].Owner != bob.ID {
+		t.Errorf("Expected only the public room to be listed, got %+v", list)
+	}
+
+	settings.Variant = sdk.RuleVariantNoTribute
+	if _, err := roomSvc.UpdateSettings(room.ID, bob.ID, settings); !errors.Is(err, ErrNotRoomOwner) {
+		t.Errorf("Expected ErrNotRoomOwner, got %v", err)
+	}
+	if _, err := roomSvc.UpdateSettings(room.ID, alice.ID, invalid); !errors.Is(err, ErrInvalidSettings) {
+		t.Errorf("Expected ErrInvalidSettings, got %v", err)
+	}
+	room, err = roomSvc.UpdateSettings(room.ID, alice.ID, settings)
+	if err != nil || room.Settings.Variant != sdk.RuleVariantNoTribute {
+		t.Errorf("Expected the variant to change, got %+v (%v)", room, err)
+	}
+
+	// Settings are fixed once the game starts
+	for i := 0; i < 3; i++ {
+		roomSvc.AddBot(room.ID, alice.ID, -1, "", "")
+	}
+	if err := roomSvc.StartGame(room.ID, alice.ID); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	if _, err := roomSvc.UpdateSettings(room.ID, alice.ID, DefaultSettings()); !errors.Is(err, ErrGameInProgress) {
+		t.Errorf("Expected ErrGameInProgress, got %v", err)
+	}
+}
+
+func TestRoomService_PrivateRooms(t *testing.T) {
+	authSvc := newMockAuthService()
+	roomSvc := NewRoomService(authSvc)
+
+	alice, _ := authSvc.Register("alice", "password")
+	bob, _ := authSvc.Register("bob", "password")
+	carol, _ := authSvc.Register("carol", "password")
+	dave, _ := authSvc.Register("dave", "password")
+
+	settings := DefaultSettings()
+	settings.Private = true
+	private, _ := roomSvc.CreateRoomWithSettings(alice.ID, settings)
+
+	if _, err := roomSvc.JoinRoom(private.ID, bob.ID); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("Expected ErrAccessDenied, got %v", err)
+	}
+	if _, err := roomSvc.JoinRoomWithAccess(private.ID, bob.ID, Access{Passcode: "1234"}); !errors.Is(err, ErrInvalidPasscode) {
+		t.Errorf("Expected ErrInvalidPasscode for a room without a passcode, got %v", err)
+	}
+	if _, err := roomSvc.CreateInvite(private.ID, bob.ID, 0); err == nil {
+		t.Error("Expected only players in the room to invite")
+	}
+	if _, err := roomSvc.CreateInvite(private.ID, alice.ID, MaxInviteTTL+time.Hour); err == nil {
+		t.Error("Expected error for an invite lifetime over the maximum")
+	}
+
+	invite, err := roomSvc.CreateInvite(private.ID, alice.ID, 0)
+	if err != nil {
+		t.Fatalf("Failed to create invite: %v", err)
+	}
+	if invite.RoomID != private.ID || time.Until(invite.ExpiresAt) < DefaultInviteTTL-time.Minute {
+		t.Errorf("Unexpected invite %+v", invite)
+	}
+	if _, err := roomSvc.JoinRoomWithAccess(private.ID, bob.ID, Access{Invite: invite.Token}); err != nil {
+		t.Errorf("Expected the invite to let bob join: %v", err)
+	}
+
+	// Invites are bound to their room and expire
+	public, _ := roomSvc.CreateRoom(carol.ID)
+	if _, err := roomSvc.SetPasscode(public.ID, alice.ID, "secret"); !errors.Is(err, ErrNotRoomOwner) {
+		t.Errorf("Expected ErrNotRoomOwner, got %v", err)
+	}
+	if _, err := roomSvc.SetPasscode(public.ID, carol.ID, "abc"); !errors.Is(err, ErrInvalidSettings) {
+		t.Errorf("Expected a short passcode to be rejected, got %v", err)
+	}
+	public, err = roomSvc.SetPasscode(public.ID, carol.ID, "secret")
+	if err != nil || !public.HasPasscode || public.Passcode == "secret" {
+		t.Fatalf("Expected a hashed passcode, got %+v (%v)", public, err)
+	}
+	if _, err := roomSvc.JoinRoomWithAccess(public.ID, dave.ID, Access{Invite: invite.Token}); !errors.Is(err, ErrInvalidInvite) {
+		t.Errorf("Expected ErrInvalidInvite for another room's invite, got %v", err)
+	}
+	expired, _ := signInvite(roomSvc.(*roomService).inviteKey, public.ID, carol.ID, time.Now().Add(-time.Minute))
+	if _, err := roomSvc.JoinRoomWithAccess(public.ID, dave.ID, Access{Invite: expired}); !errors.Is(err, ErrInvalidInvite) {
+		t.Errorf("Expected ErrInvalidInvite for an expired invite, got %v", err)
+	}
+	if _, err := roomSvc.JoinRoomWithAccess(public.ID, dave.ID, Access{Passcode: "wrong!"}); !errors.Is(err, ErrInvalidPasscode) {
+		t.Errorf("Expected ErrInvalidPasscode, got %v", err)
+	}
+	if _, err := roomSvc.JoinRoomWithAccess(public.ID, dave.ID, Access{Passcode: "secret"}); err != nil {
+		t.Errorf("Expected the passcode to let dave join: %v", err)
+	}
+
+	// Changing the key invalidates outstanding invites
+	invite, _ = roomSvc.CreateInvite(public.ID, carol.ID, time.Hour)
+	roomSvc.SetInviteSecret("another-key")
+	roomSvc.LeaveRoom(public.ID, dave.ID)
+	if _, err := roomSvc.JoinRoomWithAccess(public.ID, dave.ID, Access{Invite: invite.Token}); !errors.Is(err, ErrInvalidInvite) {
+		t.Errorf("Expected ErrInvalidInvite after the key changed, got %v", err)
+	}
+}
+
+func TestRoomService_SeatsAndReady(t *testing.T) {
+	authSvc := newMockAuthService()
+	roomSvc := NewRoomService(authSvc)
+
+	alice, _ := authSvc.Register("alice", "password")
+	bob, _ := authSvc.Register("bob", "password")
+	carol, _ := authSvc.Register("carol", "password")
+	outsider, _ := authSvc.Register("outsider", "password")
+
+	room, _ := roomSvc.CreateRoom(alice.ID)
+	roomSvc.JoinRoom(room.ID, bob.ID)   // seat 1
+	roomSvc.JoinRoom(room.ID, carol.ID) // seat 2
+
+	if _, err := roomSvc.ChooseSeat(room.ID, outsider.ID, 3); !errors.Is(err, ErrNotInRoom) {
+		t.Errorf("Expected ErrNotInRoom, got %v", err)
+	}
+	if _, err := roomSvc.ChooseSeat(room.ID, bob.ID, 4); err == nil {
+		t.Error("Expected error for an invalid seat")
+	}
+
+	// An empty seat is taken straight away, and the move clears the ready flag
+	roomSvc.SetReady(room.ID, bob.ID, true)
+	room, err := roomSvc.ChooseSeat(room.ID, bob.ID, 3)
+	if err != nil {
+		t.Fatalf("Failed to choose seat: %v", err)
+	}
+	if room.Players[1] != nil || room.Players[3].ID != bob.ID || room.Players[3].Seat != 3 || room.Players[3].Ready {
+		t.Errorf("Expected bob to move to seat 3 and not be ready, got %+v", room.Players)
+	}
+
+	// Bob asks carol to swap so that he partners alice
+	room, err = roomSvc.ChooseSeat(room.ID, bob.ID, 2)
+	if err != nil || len(room.SwapRequests) != 1 || room.Players[2].ID != carol.ID {
+		t.Fatalf("Expected a pending swap request, got %+v (%v)", room.SwapRequests, err)
+	}
+	if _, err := roomSvc.RespondSeatSwap(room.ID, alice.ID, bob.ID, true); !errors.Is(err, ErrSwapRequestNotFound) {
+		t.Errorf("Expected ErrSwapRequestNotFound, got %v", err)
+	}
+	room, err = roomSvc.RespondSeatSwap(room.ID, carol.ID, bob.ID, false)
+	if err != nil || len(room.SwapRequests) != 0 || room.Players[2].ID != carol.ID {
+		t.Errorf("Expected the request to be declined, got %+v (%v)", room.SwapRequests, err)
+	}
+	roomSvc.ChooseSeat(room.ID, bob.ID, 2)
+	room, err = roomSvc.RespondSeatSwap(room.ID, carol.ID, bob.ID, true)
+	if err != nil || room.Players[2].ID != bob.ID || room.Players[3].ID != carol.ID || len(room.SwapRequests) != 0 {
+		t.Errorf("Expected bob and carol to swap, got %+v (%v)", room.Players, err)
+	}
+
+	// Requests go stale when the requester leaves
+	roomSvc.ChooseSeat(room.ID, carol.ID, 2)
+	room, _ = roomSvc.LeaveRoom(room.ID, carol.ID)
+	if len(room.SwapRequests) != 0 {
+		t.Errorf("Expected the request of a leaving player to be dropped, got %+v", room.SwapRequests)
+	}
+
+	// The owner moves anyone, bots included
+	roomSvc.AddBot(room.ID, alice.ID, 3, "", "")
+	if _, err := roomSvc.ChooseSeat(room.ID, bob.ID, 3); !errors.Is(err, ErrSeatTaken) {
+		t.Errorf("Expected ErrSeatTaken for a bot seat, got %v", err)
+	}
+	if _, err := roomSvc.MoveSeat(room.ID, bob.ID, 3, 1); !errors.Is(err, ErrNotRoomOwner) {
+		t.Errorf("Expected ErrNotRoomOwner, got %v", err)
+	}
+	room, err = roomSvc.MoveSeat(room.ID, alice.ID, 3, 1)
+	if err != nil || room.Players[3] != nil || !room.Players[1].Bot || room.Players[1].Seat != 1 {
+		t.Errorf("Expected the bot to move to seat 1, got %+v (%v)", room.Players, err)
+	}
+	roomSvc.AddBot(room.ID, alice.ID, 3, "", "")
+	if room.Players[1].ID == room.Players[3].ID {
+		t.Errorf("Expected bots to keep distinct IDs, got %s", room.Players[1].ID)
+	}
+
+	if err := roomSvc.StartGame(room.ID, alice.ID); !errors.Is(err, ErrPlayersNotReady) {
+		t.Errorf("Expected ErrPlayersNotReady, got %v", err)
+	}
+	roomSvc.SetReady(room.ID, bob.ID, true)
+	if err := roomSvc.StartGame(room.ID, alice.ID); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	if _, err := roomSvc.SetReady(room.ID, bob.ID, false); !errors.Is(err, ErrGameInProgress) {
+		t.Errorf("Expected ErrGameInProgress, got %v", err)
+	}
+}
+
+func TestRoomService_Moderation(t *testing.T) {
+	authSvc := newMockAuthService()
+	roomSvc := NewRoomService(authSvc)
+
+	alice, _ := authSvc.Register("alice", "password")
+	bob, _ := authSvc.Register("bob", "password")
+	carol, _ := authSvc.Register("carol", "password")
+	dave, _ := authSvc.Register("dave", "password")
+
+	room, _ := roomSvc.CreateRoom(alice.ID)
+	roomSvc.JoinRoom(room.ID, bob.ID)   // seat 1
+	roomSvc.JoinRoom(room.ID, carol.ID) // seat 2
+	roomSvc.AddBot(room.ID, alice.ID, 3, "", "")
+
+	if _, err := roomSvc.KickPlayer(room.ID, bob.ID, carol.ID); !errors.Is(err, ErrNotRoomOwner) {
+		t.Errorf("Expected ErrNotRoomOwner, got %v", err)
+	}
+	if _, err := roomSvc.KickPlayer(room.ID, alice.ID, alice.ID); !errors.Is(err, ErrOwnerTarget) {
+		t.Errorf("Expected ErrOwnerTarget, got %v", err)
+	}
+	if _, err := roomSvc.KickPlayer(room.ID, alice.ID, room.Players[3].ID); !errors.Is(err, ErrNotInRoom) {
+		t.Errorf("Expected bots to be removed rather than kicked, got %v", err)
+	}
+
+	// A kicked player is free to come back
+	roomSvc.SetReady(room.ID, bob.ID, true)
+	room, err := roomSvc.KickPlayer(room.ID, alice.ID, bob.ID)
+	if err != nil || room.Players[1] != nil || room.PlayerCount != 3 || room.Status != RoomStatusWaiting {
+		t.Fatalf("Expected bob to be kicked, got %+v (%v)", room, err)
+	}
+	if current, _ := roomSvc.GetPlayerRoom(bob.ID); current != nil {
+		t.Errorf("Expected bob to have no room, got %s", current.ID)
+	}
+	if _, err := roomSvc.JoinRoom(room.ID, bob.ID); err != nil {
+		t.Errorf("Expected a kicked player to rejoin: %v", err)
+	}
+
+	// A banned player cannot come back, not even with an invite
+	if _, err := roomSvc.BanPlayer(room.ID, alice.ID, "nobody"); err == nil {
+		t.Error("Expected error for banning an unknown user")
+	}
+	room, err = roomSvc.BanPlayer(room.ID, alice.ID, bob.ID)
+	if err != nil || room.Players[1] != nil || len(room.Banned) != 1 || room.Banned[0] != bob.ID {
+		t.Fatalf("Expected bob to be banned, got %+v (%v)", room, err)
+	}
+	if _, err := roomSvc.BanPlayer(room.ID, alice.ID, dave.ID); err != nil {
+		t.Errorf("Expected players outside the room to be banned: %v", err)
+	}
+	invite, _ := roomSvc.CreateInvite(room.ID, alice.ID, 0)
+	if _, err := roomSvc.JoinRoomWithAccess(room.ID, bob.ID, Access{Invite: invite.Token}); !errors.Is(err, ErrPlayerBanned) {
+		t.Errorf("Expected ErrPlayerBanned, got %v", err)
+	}
+	room, err = roomSvc.UnbanPlayer(room.ID, alice.ID, bob.ID)
+	if err != nil || len(room.Banned) != 1 || room.Banned[0] != dave.ID {
+		t.Errorf("Expected only dave to stay banned, got %+v (%v)", room.Banned, err)
+	}
+	if _, err := roomSvc.JoinRoom(room.ID, bob.ID); err != nil {
+		t.Errorf("Expected an unbanned player to rejoin: %v", err)
+	}
+
+	// Ownership only goes to humans in the room
+	if _, err := roomSvc.TransferOwnership(room.ID, alice.ID, dave.ID); !errors.Is(err, ErrNotInRoom) {
+		t.Errorf("Expected ErrNotInRoom, got %v", err)
+	}
+	if _, err := roomSvc.TransferOwnership(room.ID, alice.ID, room.Players[3].ID); !errors.Is(err, ErrNotInRoom) {
+		t.Errorf("Expected ErrNotInRoom for a bot, got %v", err)
+	}
+	room, err = roomSvc.TransferOwnership(room.ID, alice.ID, carol.ID)
+	if err != nil || room.Owner != carol.ID {
+		t.Fatalf("Expected carol to own the room, got %+v (%v)", room, err)
+	}
+	if _, err := roomSvc.KickPlayer(room.ID, alice.ID, bob.ID); !errors.Is(err, ErrNotRoomOwner) {
+		t.Errorf("Expected the old owner to lose moderation rights, got %v", err)
+	}
+
+	roomSvc.SetReady(room.ID, alice.ID, true)
+	roomSvc.SetReady(room.ID, bob.ID, true)
+	if err := roomSvc.StartGame(room.ID, carol.ID); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	if _, err := roomSvc.KickPlayer(room.ID, carol.ID, bob.ID); !errors.Is(err, ErrGameInProgress) {
+		t.Errorf("Expected ErrGameInProgress, got %v", err)
+	}
+	if _, err := roomSvc.BanPlayer(room.ID, carol.ID, bob.ID); !errors.Is(err, ErrGameInProgress) {
+		t.Errorf("Expected ErrGameInProgress, got %v", err)
+	}
+}
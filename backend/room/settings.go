@@ -0,0 +1,78 @@
+package room
+
+import (
+	"fmt"
+
+	"guandan-world/sdk"
+)
+
+// MatchLength is how long the match of a room lasts
+type MatchLength string
+
+const (
+	MatchLengthToAce MatchLength = "to_ace"      // Until a team wins at level A
+	MatchLengthDeals MatchLength = "fixed_deals" // A fixed number of deals
+	MatchLengthTimed MatchLength = "timed"       // New deals start until the time limit has passed
+)
+
+// Settings are the game settings chosen by the room owner
+type Settings struct {
+	Variant        sdk.RuleVariant `json:"variant"`
+	TurnTimeout    int             `json:"turn_timeout"`    // Seconds a player has to play or pass
+	TributeTimeout int             `json:"tribute_timeout"` // Seconds to pick a tribute or return card
+	StartingLevels [2]int          `json:"starting_levels"` // Team 0 (seats 0, 2) and team 1 (seats 1, 3)
+
+	MatchLength MatchLength `json:"match_length"`
+	Deals       int         `json:"deals,omitempty"`      // Number of deals for fixed_deals
+	TimeLimit   int         `json:"time_limit,omitempty"` // Minutes for timed
+
+	Private         bool `json:"private"` // Private rooms are left out of the room list
+	AllowSpectators bool `json:"allow_spectators"`
+	AllowHints      bool `json:"allow_hints"`
+}
+
+// DefaultSettings returns the settings of rooms created without any
+func DefaultSettings() Settings {
+	return Settings{
+		Variant:         sdk.RuleVariantStandard,
+		TurnTimeout:     30,
+		TributeTimeout:  20,
+		StartingLevels:  [2]int{2, 2},
+		MatchLength:     MatchLengthToAce,
+		AllowSpectators: true,
+		AllowHints:      true,
+	}
+}
+
+// Validate checks that the settings can be played
+func (s Settings) Validate() error {
+	if s.Variant == "" || !s.Variant.Valid() {
+		return fmt.Errorf("%w: unknown rule variant %q", ErrInvalidSettings, s.Variant)
+	}
+	if s.TurnTimeout < 5 || s.TurnTimeout > 300 {
+		return fmt.Errorf("%w: turn timeout must be between 5 and 300 seconds", ErrInvalidSettings)
+	}
+	if s.TributeTimeout < 5 || s.TributeTimeout > 120 {
+		return fmt.Errorf("%w: tribute timeout must be between 5 and 120 seconds", ErrInvalidSettings)
+	}
+	for team, level := range s.StartingLevels {
+		if level < 2 || level > 13 {
+			return fmt.Errorf("%w: starting level of team %d must be between 2 and 13", ErrInvalidSettings, team)
+		}
+	}
+
+	switch s.MatchLength {
+	case MatchLengthToAce:
+	case MatchLengthDeals:
+		if s.Deals < 1 || s.Deals > 50 {
+			return fmt.Errorf("%w: deals must be between 1 and 50", ErrInvalidSettings)
+		}
+	case MatchLengthTimed:
+		if s.TimeLimit < 5 || s.TimeLimit > 240 {
+			return fmt.Errorf("%w: time limit must be between 5 and 240 minutes", ErrInvalidSettings)
+		}
+	default:
+		return fmt.Errorf("%w: unknown match length %q", ErrInvalidSettings, s.MatchLength)
+	}
+	return nil
+}
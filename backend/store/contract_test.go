@@ -0,0 +1,435 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"guandan-world/backend/auth"
+	"guandan-world/backend/match"
+	"guandan-world/backend/rating"
+	"guandan-world/backend/room"
+	"guandan-world/sdk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// backend creates fresh repositories of one implementation
+type backend struct {
+	name    string
+	users   func(t *testing.T) auth.UserRepository
+	tokens  func(t *testing.T) auth.TokenRepository
+	rooms   func(t *testing.T) room.RoomRepository
+	matches func(t *testing.T) match.Archive
+	ratings func(t *testing.T) rating.Repository
+}
+
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func backends() []backend {
+	return []backend{
+		{
+			name:    "memory",
+			users:   func(t *testing.T) auth.UserRepository { return auth.NewMemoryUserRepository() },
+			tokens:  func(t *testing.T) auth.TokenRepository { return auth.NewMemoryTokenRepository() },
+			rooms:   func(t *testing.T) room.RoomRepository { return room.NewMemoryRoomRepository() },
+			matches: func(t *testing.T) match.Archive { return match.NewMemoryArchive() },
+			ratings: func(t *testing.T) rating.Repository { return rating.NewMemoryRepository() },
+		},
+		{
+			name:    "sqlite",
+			users:   func(t *testing.T) auth.UserRepository { return openTestDB(t).Users() },
+			tokens:  func(t *testing.T) auth.TokenRepository { return openTestDB(t).Tokens() },
+			rooms:   func(t *testing.T) room.RoomRepository { return openTestDB(t).Rooms() },
+			matches: func(t *testing.T) match.Archive { return openTestDB(t).Matches() },
+			ratings: func(t *testing.T) rating.Repository { return openTestDB(t).Ratings() },
+		},
+	}
+}
+
+func TestUserRepositoryContract(t *testing.T) {
+	for _, b := range backends() {
+		t.Run(b.name, func(t *testing.T) {
+			repo := b.users(t)
+			created := time.Now()
+			user := &auth.User{ID: "user_1", Username: "alice", Password: "hash", CreatedAt: created}
+			require.NoError(t, repo.Create(user))
+
+			err := repo.Create(&auth.User{ID: "user_2", Username: "alice", Password: "hash"})
+			assert.True(t, errors.Is(err, auth.ErrUsernameTaken), "duplicate username: %v", err)
+
+			found, err := repo.GetByID("user_1")
+			require.NoError(t, err)
+			assert.Equal(t, "alice", found.Username)
+			assert.Equal(t, "hash", found.Password)
+			assert.False(t, found.Online)
+			assert.True(t, found.CreatedAt.Equal(created))
+
+			// 返回的是副本
+			found.Username = "changed"
+			found, err = repo.GetByUsername("alice")
+			require.NoError(t, err)
+			assert.Equal(t, "user_1", found.ID)
+
+			require.NoError(t, repo.SetOnline("user_1", true))
+			found, err = repo.GetByID("user_1")
+			require.NoError(t, err)
+			assert.True(t, found.Online)
+
+			_, err = repo.GetByID("missing")
+			assert.True(t, errors.Is(err, auth.ErrUserNotFound))
+			_, err = repo.GetByUsername("missing")
+			assert.True(t, errors.Is(err, auth.ErrUserNotFound))
+			assert.True(t, errors.Is(repo.SetOnline("missing", true), auth.ErrUserNotFound))
+		})
+	}
+}
+
+func TestTokenRepositoryContract(t *testing.T) {
+	for _, b := range backends() {
+		t.Run(b.name, func(t *testing.T) {
+			repo := b.tokens(t)
+			expires := time.Now().Add(time.Hour)
+			require.NoError(t, repo.Save(&auth.AuthToken{Token: "t1", UserID: "user_1", ExpiresAt: expires}))
+
+			found, err := repo.Get("t1")
+			require.NoError(t, err)
+			assert.Equal(t, "user_1", found.UserID)
+			assert.True(t, found.ExpiresAt.Equal(expires))
+
+			require.NoError(t, repo.Delete("t1"))
+			_, err = repo.Get("t1")
+			assert.True(t, errors.Is(err, auth.ErrTokenNotFound))
+			assert.True(t, errors.Is(repo.Delete("t1"), auth.ErrTokenNotFound))
+		})
+	}
+}
+
+func TestRoomRepositoryContract(t *testing.T) {
+	for _, b := range backends() {
+		t.Run(b.name, func(t *testing.T) {
+			repo := b.rooms(t)
+			base := time.Now()
+			for i := 0; i < 3; i++ {
+				rm := &room.Room{
+					ID:          fmt.Sprintf("room_%d", i),
+					Status:      room.RoomStatusWaiting,
+					Owner:       "user_1",
+					PlayerCount: 1,
+					CreatedAt:   base.Add(time.Duration(2-i) * time.Second),
+					UpdatedAt:   base,
+				}
+				rm.Players[0] = &room.Player{ID: "user_1", Username: "alice", Seat: 0, Online: true}
+				require.NoError(t, repo.Save(rm))
+			}
+
+			found, err := repo.Get("room_1")
+			require.NoError(t, err)
+			assert.Equal(t, "user_1", found.Owner)
+			require.NotNil(t, found.Players[0])
+			assert.Equal(t, "alice", found.Players[0].Username)
+			assert.Nil(t, found.Players[1])
+			assert.True(t, found.CreatedAt.Equal(base.Add(time.Second)))
+
+			// 修改后再保存会覆盖原来的房间
+			found.Players[2] = &room.Player{ID: "user_2", Username: "bob", Seat: 2}
+			found.PlayerCount = 2
+			found.Status = room.RoomStatusPlaying
+			found.Passcode = "hashed"
+			found.HasPasscode = true
+			require.NoError(t, repo.Save(found))
+			found, err = repo.Get("room_1")
+			require.NoError(t, err)
+			assert.Equal(t, 2, found.PlayerCount)
+			assert.Equal(t, "hashed", found.Passcode)
+			assert.True(t, found.HasPasscode)
+			assert.Equal(t, room.RoomStatusPlaying, found.Status)
+			require.NotNil(t, found.Players[2])
+			assert.Equal(t, "bob", found.Players[2].Username)
+
+			rooms, err := repo.List()
+			require.NoError(t, err)
+			require.Len(t, rooms, 3)
+			assert.Equal(t, []string{"room_2", "room_1", "room_0"}, []string{rooms[0].ID, rooms[1].ID, rooms[2].ID})
+
+			require.NoError(t, repo.Delete("room_1"))
+			_, err = repo.Get("room_1")
+			assert.True(t, errors.Is(err, room.ErrRoomNotFound))
+			assert.True(t, errors.Is(repo.Delete("room_1"), room.ErrRoomNotFound))
+			rooms, err = repo.List()
+			require.NoError(t, err)
+			assert.Len(t, rooms, 2)
+		})
+	}
+}
+
+func TestMatchArchiveContract(t *testing.T) {
+	for _, b := range backends() {
+		t.Run(b.name, func(t *testing.T) {
+			archive := b.matches(t)
+			base := time.Now()
+			for i := 0; i < 3; i++ {
+				record := &match.Record{
+					ID:          fmt.Sprintf("match_%d", i),
+					RoomID:      "room_1",
+					Ranked:      i == 1,
+					Winner:      i % 2,
+					FinalLevels: [2]int{14, 5 + i},
+					StartedAt:   base,
+					FinishedAt:  base.Add(time.Duration(i) * time.Minute),
+					Result:      &sdk.MatchResult{Winner: i % 2, FinalLevels: [2]int{14, 5 + i}},
+					Deals: []*match.DealRecord{{
+						Number:  1,
+						Level:   2,
+						Hands:   [4][]string{{"Spade_3", "Heart_2"}},
+						Actions: []match.Action{{Type: match.ActionPlay, Seat: 0, Target: -1, Cards: []string{"Spade_3"}}},
+						Result:  &sdk.DealResult{Rankings: []int{0, 1, 2, 3}},
+					}},
+				}
+				for seat := 0; seat < 4; seat++ {
+					// 第 2 局换了一个玩家
+					id := fmt.Sprintf("user_%d", seat)
+					if i == 2 && seat == 3 {
+						id = "user_9"
+					}
+					record.Players = append(record.Players, match.Player{ID: id, Username: id, Seat: seat, Bot: id == "user_9"})
+				}
+				require.NoError(t, archive.Save(record))
+			}
+
+			found, err := archive.Get("match_1")
+			require.NoError(t, err)
+			assert.Equal(t, 1, found.Winner)
+			assert.True(t, found.Ranked)
+			assert.Equal(t, [2]int{14, 6}, found.FinalLevels)
+			assert.Len(t, found.Players, 4)
+			assert.True(t, found.FinishedAt.Equal(base.Add(time.Minute)))
+			require.NotNil(t, found.Result)
+			assert.Equal(t, 1, found.Result.Winner)
+			require.Len(t, found.Deals, 1)
+			assert.Equal(t, []string{"Spade_3", "Heart_2"}, found.Deals[0].Hands[0])
+			assert.Equal(t, match.ActionPlay, found.Deals[0].Actions[0].Type)
+			assert.Equal(t, []int{0, 1, 2, 3}, found.Deals[0].Result.Rankings)
+
+			_, err = archive.Get("missing")
+			assert.True(t, errors.Is(err, match.ErrMatchNotFound))
+
+			all, err := archive.List("", 0, 0)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"match_2", "match_1", "match_0"}, recordIDs(all))
+
+			mine, err := archive.List("user_3", 0, 0)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"match_1", "match_0"}, recordIDs(mine))
+
+			page, err := archive.List("user_0", 1, 1)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"match_1"}, recordIDs(page))
+
+			empty, err := archive.List("user_0", 10, 5)
+			require.NoError(t, err)
+			assert.Empty(t, empty)
+
+			// 摘要不含牌局，按结束时间筛选
+			summaries, err := archive.Summaries(time.Time{})
+			require.NoError(t, err)
+			require.Len(t, summaries, 3)
+			assert.Equal(t, "match_2", summaries[0].ID)
+			assert.Equal(t, 0, summaries[0].Winner)
+			assert.True(t, summaries[0].FinishedAt.Equal(base.Add(2*time.Minute)))
+			assert.Equal(t, []match.Player{
+				{ID: "user_0", Username: "user_0", Seat: 0},
+				{ID: "user_1", Username: "user_1", Seat: 1},
+				{ID: "user_2", Username: "user_2", Seat: 2},
+				{ID: "user_9", Username: "user_9", Seat: 3, Bot: true},
+			}, summaries[0].Players)
+			recent, err := archive.Summaries(base.Add(time.Minute))
+			require.NoError(t, err)
+			require.Len(t, recent, 2)
+			assert.Equal(t, "match_1", recent[1].ID)
+
+			// 重新保存同一局会替换玩家列表
+			found.Players[3].ID = "user_8"
+			require.NoError(t, archive.Save(found))
+			mine, err = archive.List("user_3", 0, 0)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"match_0"}, recordIDs(mine))
+		})
+	}
+}
+
+func TestRatingRepositoryContract(t *testing.T) {
+	for _, b := range backends() {
+		t.Run(b.name, func(t *testing.T) {
+			repo := b.ratings(t)
+
+			_, err := repo.Get("user_1")
+			assert.True(t, errors.Is(err, rating.ErrRatingNotFound))
+
+			playedAt := time.Now()
+			require.NoError(t, repo.Save(&rating.Rating{PlayerID: "user_1", Mu: 27.5, Sigma: 7.25, Skill: 5.75, Matches: 1, LastPlayedAt: playedAt}))
+			require.NoError(t, repo.Save(&rating.Rating{PlayerID: "user_1", Mu: 29, Sigma: 6.5, Skill: 9.5, Matches: 2, LastPlayedAt: playedAt}))
+			stored, err := repo.Get("user_1")
+			require.NoError(t, err)
+			assert.Equal(t, 29.0, stored.Mu)
+			assert.Equal(t, 6.5, stored.Sigma)
+			assert.Equal(t, 9.5, stored.Skill)
+			assert.Equal(t, 2, stored.Matches)
+			assert.True(t, stored.LastPlayedAt.Equal(playedAt))
+
+			history, err := repo.History("user_1", 0)
+			require.NoError(t, err)
+			assert.Empty(t, history)
+
+			for i := 0; i < 3; i++ {
+				require.NoError(t, repo.AppendHistory(&rating.HistoryEntry{
+					PlayerID: "user_1", MatchID: fmt.Sprintf("match_%d", i), Won: i%2 == 0, Margin: i,
+					MuBefore: 25, SigmaBefore: 8, SkillBefore: 1, MuAfter: 26, SigmaAfter: 7, SkillAfter: 5,
+					At: playedAt.Add(time.Duration(i) * time.Minute),
+				}))
+			}
+			require.NoError(t, repo.AppendHistory(&rating.HistoryEntry{PlayerID: "user_2", MatchID: "match_0", At: playedAt}))
+
+			history, err = repo.History("user_1", 0)
+			require.NoError(t, err)
+			require.Len(t, history, 3)
+			assert.Equal(t, "match_2", history[0].MatchID)
+			assert.True(t, history[0].Won)
+			assert.Equal(t, 2, history[0].Margin)
+			assert.Equal(t, 26.0, history[0].MuAfter)
+			assert.Equal(t, 5.0, history[0].SkillAfter)
+			assert.True(t, history[0].At.Equal(playedAt.Add(2*time.Minute)))
+
+			latest, err := repo.History("user_1", 2)
+			require.NoError(t, err)
+			assert.Len(t, latest, 2)
+			assert.Equal(t, "match_1", latest[1].MatchID)
+		})
+	}
+}
+
+func recordIDs(records []*match.Record) []string {
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+	return ids
+}
+
+func TestOpenAppliesMigrationsOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reopen.db")
+	db, err := Open(path)
+	require.NoError(t, err)
+	require.NoError(t, db.Users().Create(&auth.User{ID: "user_1", Username: "alice", Password: "hash"}))
+	require.NoError(t, db.Close())
+
+	// 重新打开时不重复执行迁移，数据仍在
+	db, err = Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+	user, err := db.Users().GetByUsername("alice")
+	require.NoError(t, err)
+	assert.Equal(t, "user_1", user.ID)
+
+	migrations, err := loadMigrations()
+	require.NoError(t, err)
+	var version int
+	require.NoError(t, db.db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version))
+	assert.Equal(t, migrations[len(migrations)-1].version, version)
+}
+
+func TestServicesSurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "restart.db")
+	db, err := Open(path)
+	require.NoError(t, err)
+
+	authService := auth.NewAuthServiceWithRepositories(db.Users(), db.Tokens(), "secret", time.Hour)
+	roomService, err := room.NewRoomServiceWithRepository(authService, db.Rooms())
+	require.NoError(t, err)
+
+	var ids []string
+	for i := 0; i < 4; i++ {
+		user, err := authService.Register(fmt.Sprintf("player%d", i), "password123")
+		require.NoError(t, err)
+		ids = append(ids, user.ID)
+	}
+	token, err := authService.Login("player0", "password123")
+	require.NoError(t, err)
+
+	rm, err := roomService.CreateRoom(ids[0])
+	require.NoError(t, err)
+	for _, id := range ids[1:] {
+		_, err := roomService.JoinRoom(rm.ID, id)
+		require.NoError(t, err)
+		_, err = roomService.SetReady(rm.ID, id, true)
+		require.NoError(t, err)
+	}
+	require.NoError(t, roomService.StartGame(rm.ID, ids[0]))
+	require.NoError(t, db.Close())
+
+	db, err = Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+	authService = auth.NewAuthServiceWithRepositories(db.Users(), db.Tokens(), "secret", time.Hour)
+	roomService, err = room.NewRoomServiceWithRepository(authService, db.Rooms())
+	require.NoError(t, err)
+
+	user, err := authService.ValidateToken(token.Token)
+	require.NoError(t, err)
+	assert.Equal(t, ids[0], user.ID)
+	_, err = authService.Login("player1", "password123")
+	assert.NoError(t, err)
+
+	// 对局无法恢复，房间回到可开始状态
+	restored, err := roomService.GetPlayerRoom(ids[3])
+	require.NoError(t, err)
+	assert.Equal(t, rm.ID, restored.ID)
+	assert.Equal(t, room.RoomStatusReady, restored.Status)
+	assert.Equal(t, 4, restored.PlayerCount)
+	assert.NoError(t, roomService.StartGame(rm.ID, ids[0]))
+}
+
+func TestMigrationBackfillsMatchSummaries(t *testing.T) {
+	// 迁移前的数据库：match_players 只有座位和玩家ID
+	path := filepath.Join(t.TempDir(), "old.db")
+	raw, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = raw.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, applied_at INTEGER NOT NULL)`)
+	require.NoError(t, err)
+	for _, name := range []string{"0001_init.sql", "0002_ratings.sql"} {
+		body, err := migrationFiles.ReadFile("migrations/" + name)
+		require.NoError(t, err)
+		_, err = raw.Exec(string(body))
+		require.NoError(t, err)
+	}
+	_, err = raw.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES (1, 0), (2, 0)`)
+	require.NoError(t, err)
+	_, err = raw.Exec(`INSERT INTO matches (id, room_id, winner, started_at, finished_at, data) VALUES ('m1', 'r1', 1, 1, 2,
+		'{"id":"m1","players":[{"id":"u1","username":"alice","seat":0},{"id":"bot_r1_1","username":"Bot 2","seat":1,"bot":true}]}')`)
+	require.NoError(t, err)
+	_, err = raw.Exec(`INSERT INTO match_players (match_id, seat, player_id) VALUES ('m1', 0, 'u1'), ('m1', 1, 'bot_r1_1')`)
+	require.NoError(t, err)
+	require.NoError(t, raw.Close())
+
+	db, err := Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+	summaries, err := db.Matches().Summaries(time.Time{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 1, summaries[0].Winner)
+	assert.Equal(t, []match.Player{
+		{ID: "u1", Username: "alice", Seat: 0},
+		{ID: "bot_r1_1", Username: "Bot 2", Seat: 1, Bot: true},
+	}, summaries[0].Players)
+}
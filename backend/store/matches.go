@@ -0,0 +1,136 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"guandan-world/backend/match"
+)
+
+// matchArchive implements match.Archive, keeping each record as a JSON document
+// with its players indexed in match_players, which also serves match summaries
+type matchArchive struct {
+	db *sql.DB
+}
+
+// Save inserts or replaces a match
+func (a *matchArchive) Save(record *match.Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode match: %w", err)
+	}
+
+	tx, err := a.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save match: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM match_players WHERE match_id = ?`, record.ID); err != nil {
+		return fmt.Errorf("failed to save match: %w", err)
+	}
+	if _, err := tx.Exec(`INSERT OR REPLACE INTO matches (id, room_id, winner, started_at, finished_at, data) VALUES (?, ?, ?, ?, ?, ?)`,
+		record.ID, record.RoomID, record.Winner, toUnix(record.StartedAt), toUnix(record.FinishedAt), string(data)); err != nil {
+		return fmt.Errorf("failed to save match: %w", err)
+	}
+	for _, player := range record.Players {
+		if _, err := tx.Exec(`INSERT INTO match_players (match_id, seat, player_id, username, bot) VALUES (?, ?, ?, ?, ?)`,
+			record.ID, player.Seat, player.ID, player.Username, player.Bot); err != nil {
+			return fmt.Errorf("failed to save match player: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Get retrieves a match by ID
+func (a *matchArchive) Get(matchID string) (*match.Record, error) {
+	var data string
+	err := a.db.QueryRow(`SELECT data FROM matches WHERE id = ?`, matchID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, match.ErrMatchNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read match: %w", err)
+	}
+	return decodeMatch(data)
+}
+
+// List returns matches newest first, optionally only those the player took part in
+func (a *matchArchive) List(playerID string, limit, offset int) ([]*match.Record, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var rows *sql.Rows
+	var err error
+	if playerID == "" {
+		rows, err = a.db.Query(`SELECT data FROM matches ORDER BY finished_at DESC, id DESC LIMIT ? OFFSET ?`, limit, offset)
+	} else {
+		rows, err = a.db.Query(`SELECT m.data FROM matches m
+			WHERE m.id IN (SELECT match_id FROM match_players WHERE player_id = ?)
+			ORDER BY m.finished_at DESC, m.id DESC LIMIT ? OFFSET ?`, playerID, limit, offset)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list matches: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*match.Record{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read match: %w", err)
+		}
+		record, err := decodeMatch(data)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// Summaries returns the outcome of the matches finished at or after since,
+// newest first, read from the indexed columns without decoding the documents
+func (a *matchArchive) Summaries(since time.Time) ([]*match.Summary, error) {
+	rows, err := a.db.Query(`SELECT m.id, m.winner, m.finished_at, p.seat, p.player_id, p.username, p.bot
+		FROM matches m JOIN match_players p ON p.match_id = m.id
+		WHERE m.finished_at >= ?
+		ORDER BY m.finished_at DESC, m.id DESC, p.seat`, toUnix(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list match summaries: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := []*match.Summary{}
+	var current *match.Summary
+	for rows.Next() {
+		var id string
+		var winner int
+		var finishedAt int64
+		var player match.Player
+		if err := rows.Scan(&id, &winner, &finishedAt, &player.Seat, &player.ID, &player.Username, &player.Bot); err != nil {
+			return nil, fmt.Errorf("failed to read match summary: %w", err)
+		}
+		if current == nil || current.ID != id {
+			current = &match.Summary{ID: id, Winner: winner, FinishedAt: fromUnix(finishedAt)}
+			summaries = append(summaries, current)
+		}
+		current.Players = append(current.Players, player)
+	}
+	return summaries, rows.Err()
+}
+
+func decodeMatch(data string) (*match.Record, error) {
+	var record match.Record
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return nil, fmt.Errorf("failed to decode match: %w", err)
+	}
+	return &record, nil
+}
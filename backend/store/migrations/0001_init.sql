@@ -0,0 +1,46 @@
+-- 用户、令牌、房间和对局归档
+CREATE TABLE users (
+    id            TEXT PRIMARY KEY,
+    username      TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    online        INTEGER NOT NULL DEFAULT 0,
+    created_at    INTEGER NOT NULL
+);
+
+CREATE TABLE tokens (
+    token      TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    expires_at INTEGER NOT NULL
+);
+
+CREATE INDEX idx_tokens_user ON tokens(user_id);
+
+-- 房间整体以 JSON 保存，常用字段单独成列便于查询
+CREATE TABLE rooms (
+    id         TEXT PRIMARY KEY,
+    status     INTEGER NOT NULL,
+    owner      TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    data       TEXT NOT NULL
+);
+
+CREATE TABLE matches (
+    id          TEXT PRIMARY KEY,
+    room_id     TEXT NOT NULL,
+    winner      INTEGER NOT NULL,
+    started_at  INTEGER NOT NULL,
+    finished_at INTEGER NOT NULL,
+    data        TEXT NOT NULL
+);
+
+CREATE INDEX idx_matches_finished ON matches(finished_at);
+
+CREATE TABLE match_players (
+    match_id  TEXT NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    seat      INTEGER NOT NULL,
+    player_id TEXT NOT NULL,
+    PRIMARY KEY (match_id, seat)
+);
+
+CREATE INDEX idx_match_players_player ON match_players(player_id);
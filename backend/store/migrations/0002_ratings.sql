@@ -0,0 +1,26 @@
+-- 排位赛评分及其变化历史
+CREATE TABLE ratings (
+    player_id      TEXT PRIMARY KEY,
+    mu             REAL NOT NULL,
+    sigma          REAL NOT NULL,
+    skill          REAL NOT NULL,
+    matches        INTEGER NOT NULL,
+    last_played_at INTEGER NOT NULL
+);
+
+CREATE TABLE rating_history (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    player_id    TEXT NOT NULL,
+    match_id     TEXT NOT NULL,
+    won          INTEGER NOT NULL,
+    margin       INTEGER NOT NULL,
+    mu_before    REAL NOT NULL,
+    sigma_before REAL NOT NULL,
+    skill_before REAL NOT NULL,
+    mu_after     REAL NOT NULL,
+    sigma_after  REAL NOT NULL,
+    skill_after  REAL NOT NULL,
+    at           INTEGER NOT NULL
+);
+
+CREATE INDEX idx_rating_history_player ON rating_history(player_id, at);
@@ -0,0 +1,15 @@
+-- 排行榜只需要玩家、胜方和结束时间：玩家名和机器人标记单独成列，不必解码整场对局
+ALTER TABLE match_players ADD COLUMN username TEXT NOT NULL DEFAULT '';
+ALTER TABLE match_players ADD COLUMN bot INTEGER NOT NULL DEFAULT 0;
+
+UPDATE match_players SET
+    username = COALESCE((
+        SELECT json_extract(p.value, '$.username')
+        FROM matches m, json_each(m.data, '$.players') p
+        WHERE m.id = match_players.match_id AND json_extract(p.value, '$.seat') = match_players.seat
+    ), ''),
+    bot = COALESCE((
+        SELECT json_extract(p.value, '$.bot')
+        FROM matches m, json_each(m.data, '$.players') p
+        WHERE m.id = match_players.match_id AND json_extract(p.value, '$.seat') = match_players.seat
+    ), 0);
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"guandan-world/backend/rating"
+)
+
+// ratingRepository implements rating.Repository
+type ratingRepository struct {
+	db *sql.DB
+}
+
+// Get retrieves a player's rating
+func (r *ratingRepository) Get(playerID string) (*rating.Rating, error) {
+	var stored rating.Rating
+	var lastPlayedAt int64
+	err := r.db.QueryRow(`SELECT player_id, mu, sigma, skill, matches, last_played_at FROM ratings WHERE player_id = ?`, playerID).
+		Scan(&stored.PlayerID, &stored.Mu, &stored.Sigma, &stored.Skill, &stored.Matches, &lastPlayedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, rating.ErrRatingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rating: %w", err)
+	}
+	stored.LastPlayedAt = fromUnix(lastPlayedAt)
+	return &stored, nil
+}
+
+// Save inserts or replaces a player's rating
+func (r *ratingRepository) Save(stored *rating.Rating) error {
+	if _, err := r.db.Exec(`INSERT OR REPLACE INTO ratings (player_id, mu, sigma, skill, matches, last_played_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		stored.PlayerID, stored.Mu, stored.Sigma, stored.Skill, stored.Matches, toUnix(stored.LastPlayedAt)); err != nil {
+		return fmt.Errorf("failed to save rating: %w", err)
+	}
+	return nil
+}
+
+// AppendHistory adds an entry to a player's history
+func (r *ratingRepository) AppendHistory(entry *rating.HistoryEntry) error {
+	if _, err := r.db.Exec(`INSERT INTO rating_history
+		(player_id, match_id, won, margin, mu_before, sigma_before, skill_before, mu_after, sigma_after, skill_after, at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.PlayerID, entry.MatchID, entry.Won, entry.Margin,
+		entry.MuBefore, entry.SigmaBefore, entry.SkillBefore,
+		entry.MuAfter, entry.SigmaAfter, entry.SkillAfter, toUnix(entry.At)); err != nil {
+		return fmt.Errorf("failed to save rating history: %w", err)
+	}
+	return nil
+}
+
+// History returns the player's entries newest first
+func (r *ratingRepository) History(playerID string, limit int) ([]*rating.HistoryEntry, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+	rows, err := r.db.Query(`SELECT player_id, match_id, won, margin, mu_before, sigma_before, skill_before, mu_after, sigma_after, skill_after, at
+		FROM rating_history WHERE player_id = ? ORDER BY id DESC LIMIT ?`, playerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rating history: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*rating.HistoryEntry{}
+	for rows.Next() {
+		var entry rating.HistoryEntry
+		var at int64
+		if err := rows.Scan(&entry.PlayerID, &entry.MatchID, &entry.Won, &entry.Margin,
+			&entry.MuBefore, &entry.SigmaBefore, &entry.SkillBefore,
+			&entry.MuAfter, &entry.SigmaAfter, &entry.SkillAfter, &at); err != nil {
+			return nil, fmt.Errorf("failed to read rating history: %w", err)
+		}
+		entry.At = fromUnix(at)
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"guandan-world/backend/room"
+)
+
+// roomRepository implements room.RoomRepository, keeping each room as a JSON document
+type roomRepository struct {
+	db *sql.DB
+}
+
+// roomDocument is the stored form of a room. The passcode hash is left out of
+// the room's own JSON so it never reaches clients.
+type roomDocument struct {
+	*room.Room
+	Passcode string `json:"passcode,omitempty"`
+}
+
+// Save inserts or replaces a room
+func (r *roomRepository) Save(rm *room.Room) error {
+	data, err := json.Marshal(roomDocument{Room: rm, Passcode: rm.Passcode})
+	if err != nil {
+		return fmt.Errorf("failed to encode room: %w", err)
+	}
+	_, err = r.db.Exec(`INSERT OR REPLACE INTO rooms (id, status, owner, created_at, updated_at, data) VALUES (?, ?, ?, ?, ?, ?)`,
+		rm.ID, int(rm.Status), rm.Owner, toUnix(rm.CreatedAt), toUnix(rm.UpdatedAt), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to save room: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves a room by ID
+func (r *roomRepository) Get(roomID string) (*room.Room, error) {
+	var data string
+	err := r.db.QueryRow(`SELECT data FROM rooms WHERE id = ?`, roomID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, room.ErrRoomNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read room: %w", err)
+	}
+	return decodeRoom(data)
+}
+
+// Delete removes a room
+func (r *roomRepository) Delete(roomID string) error {
+	result, err := r.db.Exec(`DELETE FROM rooms WHERE id = ?`, roomID)
+	if err != nil {
+		return fmt.Errorf("failed to delete room: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return room.ErrRoomNotFound
+	}
+	return nil
+}
+
+// List returns all rooms ordered by creation time
+func (r *roomRepository) List() ([]*room.Room, error) {
+	rows, err := r.db.Query(`SELECT data FROM rooms ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rooms: %w", err)
+	}
+	defer rows.Close()
+
+	rooms := []*room.Room{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read room: %w", err)
+		}
+		rm, err := decodeRoom(data)
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, rm)
+	}
+	return rooms, rows.Err()
+}
+
+func decodeRoom(data string) (*room.Room, error) {
+	doc := roomDocument{Room: &room.Room{}}
+	if err := json.Unmarshal([]byte(data), &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode room: %w", err)
+	}
+	doc.Room.Passcode = doc.Passcode
+	return doc.Room, nil
+}
@@ -0,0 +1,168 @@
+// Package store implements the repositories of auth, room, match and rating on top of SQLite.
+package store
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"guandan-world/backend/auth"
+	"guandan-world/backend/match"
+	"guandan-world/backend/rating"
+	"guandan-world/backend/room"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// DB is a SQLite database holding users, tokens, rooms, archived matches and ratings
+type DB struct {
+	db *sql.DB
+}
+
+// Open opens (or creates) the SQLite database at path and applies pending migrations.
+// Use ":memory:" for a throwaway database.
+func Open(path string) (*DB, error) {
+	db, err := sql.Open("sqlite3", path+"?_foreign_keys=on&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	// SQLite serializes writers anyway, and ":memory:" databases are per connection
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &DB{db: db}, nil
+}
+
+// Close closes the database
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// Users returns the user repository
+func (d *DB) Users() auth.UserRepository {
+	return &userRepository{db: d.db}
+}
+
+// Tokens returns the token repository
+func (d *DB) Tokens() auth.TokenRepository {
+	return &tokenRepository{db: d.db}
+}
+
+// Rooms returns the room repository
+func (d *DB) Rooms() room.RoomRepository {
+	return &roomRepository{db: d.db}
+}
+
+// Matches returns the match archive
+func (d *DB) Matches() match.Archive {
+	return &matchArchive{db: d.db}
+}
+
+// Ratings returns the rating repository
+func (d *DB) Ratings() rating.Repository {
+	return &ratingRepository{db: d.db}
+}
+
+// migration is one embedded SQL file, applied in version order
+type migration struct {
+	version int
+	name    string
+}
+
+// migrate applies every embedded migration newer than the recorded schema version
+func migrate(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at INTEGER NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var current int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		body, err := migrationFiles.ReadFile("migrations/" + m.name)
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %w", m.name, err)
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %s: %w", m.name, err)
+		}
+		if _, err := tx.Exec(string(body)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %s: %w", m.name, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, m.version, time.Now().UnixNano()); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %s: %w", m.name, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %s: %w", m.name, err)
+		}
+	}
+	return nil
+}
+
+// loadMigrations lists the embedded migrations, named "<version>_<description>.sql"
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	var migrations []migration
+	for _, entry := range entries {
+		prefix, _, ok := strings.Cut(entry.Name(), "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s has no version prefix", entry.Name())
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has an invalid version: %w", entry.Name(), err)
+		}
+		migrations = append(migrations, migration{version: version, name: entry.Name()})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+	return migrations, nil
+}
+
+// toUnix converts a time to the nanosecond timestamp stored in the database
+func toUnix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// fromUnix converts a stored nanosecond timestamp back to a time
+func fromUnix(nanos int64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
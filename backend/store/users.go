@@ -0,0 +1,109 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"guandan-world/backend/auth"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// userRepository implements auth.UserRepository
+type userRepository struct {
+	db *sql.DB
+}
+
+// Create stores a new user
+func (r *userRepository) Create(user *auth.User) error {
+	_, err := r.db.Exec(`INSERT INTO users (id, username, password_hash, online, created_at) VALUES (?, ?, ?, ?, ?)`,
+		user.ID, user.Username, user.Password, user.Online, toUnix(user.CreatedAt))
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return auth.ErrUsernameTaken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to insert user: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *userRepository) GetByID(userID string) (*auth.User, error) {
+	return r.scanUser(r.db.QueryRow(`SELECT id, username, password_hash, online, created_at FROM users WHERE id = ?`, userID))
+}
+
+// GetByUsername retrieves a user by username
+func (r *userRepository) GetByUsername(username string) (*auth.User, error) {
+	return r.scanUser(r.db.QueryRow(`SELECT id, username, password_hash, online, created_at FROM users WHERE username = ?`, username))
+}
+
+// SetOnline updates the online flag of a user
+func (r *userRepository) SetOnline(userID string, online bool) error {
+	result, err := r.db.Exec(`UPDATE users SET online = ? WHERE id = ?`, online, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return auth.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) scanUser(row *sql.Row) (*auth.User, error) {
+	var user auth.User
+	var createdAt int64
+	err := row.Scan(&user.ID, &user.Username, &user.Password, &user.Online, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, auth.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user: %w", err)
+	}
+	user.CreatedAt = fromUnix(createdAt)
+	return &user, nil
+}
+
+// tokenRepository implements auth.TokenRepository
+type tokenRepository struct {
+	db *sql.DB
+}
+
+// Save stores a token, replacing any token with the same value
+func (r *tokenRepository) Save(token *auth.AuthToken) error {
+	_, err := r.db.Exec(`INSERT OR REPLACE INTO tokens (token, user_id, expires_at) VALUES (?, ?, ?)`,
+		token.Token, token.UserID, toUnix(token.ExpiresAt))
+	if err != nil {
+		return fmt.Errorf("failed to insert token: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves a token
+func (r *tokenRepository) Get(token string) (*auth.AuthToken, error) {
+	var authToken auth.AuthToken
+	var expiresAt int64
+	err := r.db.QueryRow(`SELECT token, user_id, expires_at FROM tokens WHERE token = ?`, token).
+		Scan(&authToken.Token, &authToken.UserID, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, auth.ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token: %w", err)
+	}
+	authToken.ExpiresAt = fromUnix(expiresAt)
+	return &authToken, nil
+}
+
+// Delete removes a token
+func (r *tokenRepository) Delete(token string) error {
+	result, err := r.db.Exec(`DELETE FROM tokens WHERE token = ?`, token)
+	if err != nil {
+		return fmt.Errorf("failed to delete token: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return auth.ErrTokenNotFound
+	}
+	return nil
+}
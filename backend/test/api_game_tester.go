@@ -75,22 +75,14 @@ func (t *APIGameTester) StartGame(roomID string) error {
 	// 给WebSocket一些时间来建立连接
 	time.Sleep(100 * time.Millisecond)
 	
-	// 3. 调用开始游戏API
-	players := []sdk.Player{
-		{ID: "test_player_0", Username: "TestPlayer1", Seat: 0},
-		{ID: "test_player_1", Username: "TestPlayer2", Seat: 1},
-		{ID: "test_player_2", Username: "TestPlayer3", Seat: 2},
-		{ID: "test_player_3", Username: "TestPlayer4", Seat: 3},
-	}
-	
+	// 3. 调用开始游戏API（玩家取自房间，调用者须为房主）
 	// 初始化AI算法
 	for i := 0; i < 4; i++ {
 		t.aiAlgorithms[i] = ai.NewSmartAutoPlayAlgorithm(2) // 从2级开始
 	}
 	
 	req := handlers.StartGameWithDriverRequest{
-		RoomID:  roomID,
-		Players: players,
+		RoomID: roomID,
 	}
 	
 	if err := t.callAPI("POST", "/api/game/driver/start", req, nil); err != nil {
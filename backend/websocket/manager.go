@@ -21,25 +21,36 @@ const (
 	MSG_LEAVE_ROOM = "leave_room"
 	MSG_START_GAME = "start_game"
 
+	// Room owner moderation messages
+	MSG_KICK_PLAYER        = "kick_player"
+	MSG_BAN_PLAYER         = "ban_player"
+	MSG_TRANSFER_OWNERSHIP = "transfer_ownership"
+
 	// Game operation messages
 	MSG_PLAY_CARDS     = "play_cards"
 	MSG_PASS           = "pass"
 	MSG_TRIBUTE_SELECT = "tribute_select"
 	MSG_TRIBUTE_RETURN = "tribute_return"
+	MSG_GET_HINTS      = "get_hints"
 
 	// Status and notification messages
-	MSG_ROOM_UPDATE = "room_update"
-	MSG_GAME_EVENT  = "game_event"
-	MSG_PLAYER_VIEW = "player_view"
-	MSG_GAME_ACTION = "game_action"
-	MSG_ERROR       = "error"
-	MSG_PING        = "ping"
-	MSG_PONG        = "pong"
+	MSG_ROOM_UPDATE  = "room_update"
+	MSG_GAME_EVENT   = "game_event"
+	MSG_PLAYER_VIEW  = "player_view"
+	MSG_GAME_ACTION  = "game_action"
+	MSG_HINTS        = "hints"
+	MSG_QUEUE_STATUS = "queue_status"
+	MSG_ROOM_INVITE  = "room_invite"
+	MSG_ERROR        = "error"
+	MSG_PING         = "ping"
+	MSG_PONG         = "pong"
 )
 
 // JoinRoomData represents the data for joining a room
 type JoinRoomData struct {
-	RoomID string `json:"room_id"`
+	RoomID   string `json:"room_id"`
+	Passcode string `json:"passcode,omitempty"` // Needed for private and passcode rooms, unless
+	Invite   string `json:"invite,omitempty"`   // an invite token is given instead
 }
 
 // LeaveRoomData represents the data for leaving a room
@@ -52,9 +63,16 @@ type StartGameData struct {
 	RoomID string `json:"room_id"`
 }
 
+// ModerationData represents the data for kicking, banning or handing the room to a player
+type ModerationData struct {
+	RoomID   string `json:"room_id"`
+	PlayerID string `json:"player_id"` // Player the owner acts on
+}
+
 // PlayCardsData represents the data for playing cards
 type PlayCardsData struct {
-	Cards []string `json:"cards"` // Card IDs
+	Cards       []string `json:"cards"`                 // Card IDs
+	Substitutes []string `json:"substitutes,omitempty"` // Card IDs each wildcard stands for, in order (optional)
 }
 
 // TributeSelectData represents the data for tribute selection
@@ -67,6 +85,11 @@ type TributeReturnData struct {
 	CardID string `json:"card_id"`
 }
 
+// GetHintsData represents the data for requesting play hints
+type GetHintsData struct {
+	RoomID string `json:"room_id,omitempty"` // Defaults to the room the connection has joined
+}
+
 // WSMessage represents a WebSocket message
 type WSMessage struct {
 	Type      string      `json:"type"`
@@ -161,6 +184,9 @@ func (m *WSManager) registerDefaultHandlers() {
 	m.messageHandlers[MSG_JOIN_ROOM] = m.handleJoinRoom
 	m.messageHandlers[MSG_LEAVE_ROOM] = m.handleLeaveRoom
 	m.messageHandlers[MSG_START_GAME] = m.handleStartGame
+	m.messageHandlers[MSG_KICK_PLAYER] = m.handleKickPlayer
+	m.messageHandlers[MSG_BAN_PLAYER] = m.handleBanPlayer
+	m.messageHandlers[MSG_TRANSFER_OWNERSHIP] = m.handleTransferOwnership
 	m.messageHandlers[MSG_PLAY_CARDS] = m.handlePlayCards
 	m.messageHandlers[MSG_PASS] = m.handlePass
 	m.messageHandlers[MSG_TRIBUTE_SELECT] = m.handleTributeSelect
@@ -522,8 +548,15 @@ func (m *WSManager) handleJoinRoom(conn *WSConnection, message *WSMessage) error
 		return fmt.Errorf("room ID is required")
 	}
 
-	// Join room through room service
-	room, err := m.roomService.JoinRoom(joinData.RoomID, conn.playerID)
+	// Join room through room service; private and passcode rooms need a passcode or invite
+	access := room.Access{Passcode: joinData.Passcode, Invite: joinData.Invite}
+	var joinedRoom *room.Room
+	var err error
+	if access == (room.Access{}) {
+		joinedRoom, err = m.roomService.JoinRoom(joinData.RoomID, conn.playerID)
+	} else {
+		joinedRoom, err = m.roomService.JoinRoomWithAccess(joinData.RoomID, conn.playerID, access)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to join room: %w", err)
 	}
@@ -555,7 +588,7 @@ func (m *WSManager) handleJoinRoom(conn *WSConnection, message *WSMessage) error
 		Type: MSG_ROOM_UPDATE,
 		Data: map[string]interface{}{
 			"action":    "player_joined",
-			"room":      room,
+			"room":      joinedRoom,
 			"player_id": conn.playerID,
 		},
 		Timestamp: time.Now(),
@@ -669,6 +702,118 @@ func (m *WSManager) handleStartGame(conn *WSConnection, message *WSMessage) erro
 	return nil
 }
 
+// parseModerationData parses an owner moderation message sent from the owner's room
+func parseModerationData(conn *WSConnection, message *WSMessage) (*ModerationData, error) {
+	var data ModerationData
+	if err := parseMessageData(message.Data, &data); err != nil {
+		return nil, fmt.Errorf("invalid moderation data: %w", err)
+	}
+
+	if data.RoomID == "" || data.PlayerID == "" {
+		return nil, fmt.Errorf("room ID and player ID are required")
+	}
+
+	// Verify the owner is in the specified room
+	if conn.roomID != data.RoomID {
+		return nil, fmt.Errorf("player is not in the specified room")
+	}
+
+	return &data, nil
+}
+
+// handleKickPlayer handles the owner kicking a player
+func (m *WSManager) handleKickPlayer(conn *WSConnection, message *WSMessage) error {
+	data, err := parseModerationData(conn, message)
+	if err != nil {
+		return err
+	}
+
+	room, err := m.roomService.KickPlayer(data.RoomID, conn.playerID, data.PlayerID)
+	if err != nil {
+		return fmt.Errorf("failed to kick player: %w", err)
+	}
+
+	m.removeFromRoom(data.RoomID, data.PlayerID, "player_kicked", room)
+	return nil
+}
+
+// handleBanPlayer handles the owner banning a player
+func (m *WSManager) handleBanPlayer(conn *WSConnection, message *WSMessage) error {
+	data, err := parseModerationData(conn, message)
+	if err != nil {
+		return err
+	}
+
+	room, err := m.roomService.BanPlayer(data.RoomID, conn.playerID, data.PlayerID)
+	if err != nil {
+		return fmt.Errorf("failed to ban player: %w", err)
+	}
+
+	m.removeFromRoom(data.RoomID, data.PlayerID, "player_banned", room)
+	return nil
+}
+
+// handleTransferOwnership handles the owner handing the room to another player
+func (m *WSManager) handleTransferOwnership(conn *WSConnection, message *WSMessage) error {
+	data, err := parseModerationData(conn, message)
+	if err != nil {
+		return err
+	}
+
+	room, err := m.roomService.TransferOwnership(data.RoomID, conn.playerID, data.PlayerID)
+	if err != nil {
+		return fmt.Errorf("failed to transfer ownership: %w", err)
+	}
+
+	m.BroadcastToRoom(data.RoomID, &WSMessage{
+		Type: MSG_ROOM_UPDATE,
+		Data: map[string]interface{}{
+			"action":    "owner_changed",
+			"room":      room,
+			"player_id": data.PlayerID,
+		},
+		Timestamp: time.Now(),
+	})
+	return nil
+}
+
+// removeFromRoom takes a kicked or banned player's connection out of the room,
+// tells them, and broadcasts the room update to the players still in it
+func (m *WSManager) removeFromRoom(roomID, playerID, action string, updatedRoom *room.Room) {
+	roomUpdateMsg := &WSMessage{
+		Type: MSG_ROOM_UPDATE,
+		Data: map[string]interface{}{
+			"action":    action,
+			"room":      updatedRoom,
+			"player_id": playerID,
+		},
+		Timestamp: time.Now(),
+	}
+
+	m.RemoveFromRoom(roomID, playerID)
+
+	// The player is no longer in the room, so tell them directly
+	_ = m.SendToPlayer(playerID, roomUpdateMsg)
+	m.BroadcastToRoom(roomID, roomUpdateMsg)
+}
+
+// RemoveFromRoom takes a player's connection out of a room so that they stop
+// receiving its broadcasts; room changes made over REST use it for kicks and bans
+func (m *WSManager) RemoveFromRoom(roomID, playerID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if conn, exists := m.connections[playerID]; exists && conn.roomID == roomID {
+		conn.roomID = ""
+	}
+	if roomConns, exists := m.rooms[roomID]; exists {
+		delete(roomConns, playerID)
+		if len(roomConns) == 0 {
+			delete(m.rooms, roomID)
+		}
+	}
+}
+
 // handlePlayCards handles card playing requests
 func (m *WSManager) handlePlayCards(conn *WSConnection, message *WSMessage) error {
 	// Parse play cards data
@@ -691,12 +836,16 @@ func (m *WSManager) handlePlayCards(conn *WSConnection, message *WSMessage) erro
 	// This will be implemented in task 9 (游戏服务)
 
 	// For now, just acknowledge the request
+	ackData := map[string]interface{}{
+		"player_id": conn.playerID,
+		"cards":     playData.Cards,
+	}
+	if len(playData.Substitutes) > 0 {
+		ackData["substitutes"] = playData.Substitutes
+	}
 	ackMsg := &WSMessage{
-		Type: "play_cards_ack",
-		Data: map[string]interface{}{
-			"player_id": conn.playerID,
-			"cards":     playData.Cards,
-		},
+		Type:      "play_cards_ack",
+		Data:      ackData,
 		Timestamp: time.Now(),
 	}
 
@@ -911,6 +1060,21 @@ func (c *WSConnection) sendPing() {
 	c.safeSend([]byte(`{"type":"ping","timestamp":"` + time.Now().Format(time.RFC3339) + `"}`))
 }
 
+// PlayerID returns the ID of the player owning the connection
+func (c *WSConnection) PlayerID() string {
+	return c.playerID
+}
+
+// RoomID returns the room the connection has joined, or "" if none
+func (c *WSConnection) RoomID() string {
+	return c.roomID
+}
+
+// DecodeData parses the message data into the specified struct
+func (m *WSMessage) DecodeData(target interface{}) error {
+	return parseMessageData(m.Data, target)
+}
+
 // handleMessage routes messages to appropriate handlers
 func (c *WSConnection) handleMessage(message *WSMessage) {
 	c.manager.mu.RLock()
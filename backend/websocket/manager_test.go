@@ -99,6 +99,138 @@ func (m *MockRoomService) GetPlayerRoom(playerID string) (*room.Room, error) {
 	return args.Get(0).(*room.Room), args.Error(1)
 }
 
+func (m *MockRoomService) CreateMatchedRoom(seats [4]string) (*room.Room, error) {
+	args := m.Called(seats)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Room), args.Error(1)
+}
+
+func (m *MockRoomService) AddBot(roomID, ownerID string, seat int, algorithm, difficulty string) (*room.Room, error) {
+	args := m.Called(roomID, ownerID, seat, algorithm, difficulty)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Room), args.Error(1)
+}
+
+func (m *MockRoomService) RemoveBot(roomID, ownerID string, seat int) (*room.Room, error) {
+	args := m.Called(roomID, ownerID, seat)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Room), args.Error(1)
+}
+
+func (m *MockRoomService) CreateRoomWithSettings(ownerID string, settings room.Settings) (*room.Room, error) {
+	args := m.Called(ownerID, settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Room), args.Error(1)
+}
+
+func (m *MockRoomService) UpdateSettings(roomID, ownerID string, settings room.Settings) (*room.Room, error) {
+	args := m.Called(roomID, ownerID, settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Room), args.Error(1)
+}
+
+func (m *MockRoomService) JoinRoomWithAccess(roomID, playerID string, access room.Access) (*room.Room, error) {
+	args := m.Called(roomID, playerID, access)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Room), args.Error(1)
+}
+
+func (m *MockRoomService) SetPasscode(roomID, ownerID, passcode string) (*room.Room, error) {
+	args := m.Called(roomID, ownerID, passcode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Room), args.Error(1)
+}
+
+func (m *MockRoomService) CreateInvite(roomID, inviterID string, ttl time.Duration) (*room.Invite, error) {
+	args := m.Called(roomID, inviterID, ttl)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Invite), args.Error(1)
+}
+
+func (m *MockRoomService) SetInviteSecret(secret string) {
+	m.Called(secret)
+}
+
+func (m *MockRoomService) ChooseSeat(roomID, playerID string, seat int) (*room.Room, error) {
+	args := m.Called(roomID, playerID, seat)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Room), args.Error(1)
+}
+
+func (m *MockRoomService) RespondSeatSwap(roomID, playerID, requesterID string, accept bool) (*room.Room, error) {
+	args := m.Called(roomID, playerID, requesterID, accept)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Room), args.Error(1)
+}
+
+func (m *MockRoomService) MoveSeat(roomID, ownerID string, from, to int) (*room.Room, error) {
+	args := m.Called(roomID, ownerID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Room), args.Error(1)
+}
+
+func (m *MockRoomService) SetReady(roomID, playerID string, ready bool) (*room.Room, error) {
+	args := m.Called(roomID, playerID, ready)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Room), args.Error(1)
+}
+
+func (m *MockRoomService) KickPlayer(roomID, ownerID, playerID string) (*room.Room, error) {
+	args := m.Called(roomID, ownerID, playerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Room), args.Error(1)
+}
+
+func (m *MockRoomService) BanPlayer(roomID, ownerID, playerID string) (*room.Room, error) {
+	args := m.Called(roomID, ownerID, playerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Room), args.Error(1)
+}
+
+func (m *MockRoomService) UnbanPlayer(roomID, ownerID, playerID string) (*room.Room, error) {
+	args := m.Called(roomID, ownerID, playerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Room), args.Error(1)
+}
+
+func (m *MockRoomService) TransferOwnership(roomID, ownerID, newOwnerID string) (*room.Room, error) {
+	args := m.Called(roomID, ownerID, newOwnerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*room.Room), args.Error(1)
+}
+
 func TestNewWSManager(t *testing.T) {
 	mockAuth := &MockAuthService{}
 	mockRoom := &MockRoomService{}
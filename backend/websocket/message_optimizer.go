@@ -157,6 +157,7 @@ func (mo *MessageOptimizer) processBatch(playerID string) {
 
 	// 这里应该调用实际的发送逻辑
 	// 由于我们在优化器中，这里只是示例
+	_ = batchMsg
 	mo.incrementStat("BatchedMessages")
 }
 
@@ -0,0 +1,12 @@
+// conformance 运行 JSON 规则用例，用法：conformance [-format text|json] [文件或目录...]
+package main
+
+import (
+	"os"
+
+	"guandan-world/simulator"
+)
+
+func main() {
+	os.Exit(simulator.RunCLI(append([]string{"conformance"}, os.Args[1:]...), os.Stdout, os.Stderr))
+}
@@ -362,4 +362,67 @@ describe('GameControls', () => {
 
     expect(screen.getByText('5张牌型 - 可以出牌')).toBeInTheDocument();
   });
+
+  it('lets the player declare a wildcard interpretation', () => {
+    const fiveCards = [
+      { id: 'Spade_6', suit: 0, rank: 6, is_joker: false },
+      { id: 'Club_6', suit: 2, rank: 6, is_joker: false },
+      { id: 'Spade_7', suit: 0, rank: 7, is_joker: false },
+      { id: 'Diamond_7', suit: 3, rank: 7, is_joker: false },
+      { id: 'Heart_2', suit: 1, rank: 2, is_joker: false },
+    ];
+    const interpretations = [
+      { type: 'FullHouse', substitute_ids: ['Spade_7'], default: true },
+      { type: 'FullHouse', substitute_ids: ['Spade_6'], default: false },
+    ];
+
+    render(
+      <GameControls
+        selectedCards={fiveCards}
+        canPlay={true}
+        isMyTurn={true}
+        turnTimeoutSeconds={20}
+        onPlayCards={mockOnPlayCards}
+        onPass={mockOnPass}
+        interpretations={interpretations}
+      />
+    );
+
+    expect(screen.getByText('变化牌可组成多种牌型，请选择：')).toBeInTheDocument();
+    fireEvent.click(screen.getByText('三带二（变化牌作 ♠6）'));
+
+    const playButton = screen.getByText('出牌 (5张)').closest('button');
+    fireEvent.click(playButton!);
+
+    expect(mockOnPlayCards).toHaveBeenCalledWith(fiveCards, ['Spade_6']);
+  });
+
+  it('shows the hint button only when onHint is provided', () => {
+    const mockOnHint = vi.fn();
+    const { rerender } = render(
+      <GameControls
+        selectedCards={[]}
+        canPlay={true}
+        isMyTurn={true}
+        turnTimeoutSeconds={20}
+        onPlayCards={mockOnPlayCards}
+        onPass={mockOnPass}
+      />
+    );
+    expect(screen.queryByText('提示')).not.toBeInTheDocument();
+
+    rerender(
+      <GameControls
+        selectedCards={[]}
+        canPlay={true}
+        isMyTurn={true}
+        turnTimeoutSeconds={20}
+        onPlayCards={mockOnPlayCards}
+        onPass={mockOnPass}
+        onHint={mockOnHint}
+      />
+    );
+    fireEvent.click(screen.getByText('提示'));
+    expect(mockOnHint).toHaveBeenCalled();
+  });
 });
\ No newline at end of file
@@ -1,16 +1,63 @@
 import React, { useState, useEffect, useCallback } from 'react';
-import { Card } from '../../types';
+import { Card, PlayInterpretation } from '../../types';
 
 interface GameControlsProps {
   selectedCards: Card[];
   canPlay: boolean;
   isMyTurn: boolean;
   turnTimeoutSeconds: number;
-  onPlayCards: (cards: Card[]) => void;
+  onPlayCards: (cards: Card[], substituteIds?: string[]) => void;
   onPass: () => void;
   disabled?: boolean;
+  // Shows the "提示" button when provided
+  onHint?: () => void;
+  // Interpretations of the selected cards when they contain wildcards (see /api/game/driver/interpretations)
+  interpretations?: PlayInterpretation[];
 }
 
+const COMBINATION_LABELS: Record<string, string> = {
+  Single: '单牌',
+  Pair: '对子',
+  Triple: '三张',
+  FullHouse: '三带二',
+  Straight: '顺子',
+  Plate: '钢板',
+  Tube: '连对',
+  JokerBomb: '王炸',
+  NaiveBomb: '炸弹',
+  StraightFlush: '同花顺'
+};
+
+const SUIT_SYMBOLS: Record<string, string> = {
+  Spade: '♠',
+  Heart: '♥',
+  Club: '♣',
+  Diamond: '♦'
+};
+
+const RANK_LABELS: Record<string, string> = {
+  '11': 'J',
+  '12': 'Q',
+  '13': 'K',
+  '14': 'A'
+};
+
+// Formats a card ID such as "Spade_7" for display
+const formatCardId = (cardId: string): string => {
+  const [color, rank] = cardId.split('_');
+  return `${SUIT_SYMBOLS[color] ?? color}${RANK_LABELS[rank] ?? rank}`;
+};
+
+const NO_INTERPRETATIONS: PlayInterpretation[] = [];
+
+const formatInterpretation = (interpretation: PlayInterpretation): string => {
+  const label = COMBINATION_LABELS[interpretation.type] ?? interpretation.type;
+  if (!interpretation.substitute_ids || interpretation.substitute_ids.length === 0) {
+    return label;
+  }
+  return `${label}（变化牌作 ${interpretation.substitute_ids.map(formatCardId).join(' ')}）`;
+};
+
 interface CountdownTimerProps {
   seconds: number;
   isActive: boolean;
@@ -70,9 +117,18 @@ const GameControls: React.FC<GameControlsProps> = ({
   turnTimeoutSeconds,
   onPlayCards,
   onPass,
-  disabled = false
+  disabled = false,
+  onHint,
+  interpretations = NO_INTERPRETATIONS
 }) => {
   const [validationResult, setValidationResult] = useState<PlayValidationResult>({ isValid: true });
+  const [chosenInterpretation, setChosenInterpretation] = useState(0);
+
+  // Start from the engine's default whenever the options change
+  useEffect(() => {
+    const defaultIndex = interpretations.findIndex(interpretation => interpretation.default);
+    setChosenInterpretation(defaultIndex >= 0 ? defaultIndex : 0);
+  }, [interpretations]);
 
   // Simple card type validation (this would be more complex in a real implementation)
   const validateCards = useCallback((cards: Card[]): PlayValidationResult => {
@@ -126,7 +182,14 @@ const GameControls: React.FC<GameControlsProps> = ({
       return;
     }
 
-    onPlayCards(selectedCards);
+    const substituteIds = interpretations.length > 1
+      ? interpretations[chosenInterpretation]?.substitute_ids
+      : undefined;
+    if (substituteIds && substituteIds.length > 0) {
+      onPlayCards(selectedCards, substituteIds);
+    } else {
+      onPlayCards(selectedCards);
+    }
   };
 
   const handlePass = () => {
@@ -183,6 +246,32 @@ const GameControls: React.FC<GameControlsProps> = ({
         </div>
       )}
 
+      {/* Wildcard Interpretations */}
+      {selectedCards.length > 0 && interpretations.length > 1 && (
+        <div className="mb-4">
+          <div className="text-sm text-gray-700 mb-2">变化牌可组成多种牌型，请选择：</div>
+          <div className="flex flex-wrap gap-2">
+            {interpretations.map((interpretation, index) => (
+              <button
+                key={`${interpretation.type}-${(interpretation.substitute_ids ?? []).join(',')}`}
+                type="button"
+                onClick={() => setChosenInterpretation(index)}
+                aria-pressed={index === chosenInterpretation}
+                className={`
+                  px-3 py-1 rounded border text-sm transition-colors duration-200
+                  ${index === chosenInterpretation
+                    ? 'bg-blue-600 text-white border-blue-600'
+                    : 'bg-white text-gray-700 border-gray-300 hover:bg-gray-100'
+                  }
+                `}
+              >
+                {formatInterpretation(interpretation)}
+              </button>
+            ))}
+          </div>
+        </div>
+      )}
+
       {/* Action Buttons */}
       <div className="flex space-x-3">
         <button
@@ -204,6 +293,22 @@ const GameControls: React.FC<GameControlsProps> = ({
           </div>
         </button>
 
+        {onHint && (
+          <button
+            onClick={onHint}
+            disabled={isPassDisabled}
+            className={`
+              py-3 px-4 rounded-lg font-medium transition-all duration-200
+              ${isPassDisabled
+                ? 'bg-gray-200 text-gray-500 cursor-not-allowed'
+                : 'bg-yellow-500 text-white hover:bg-yellow-600 active:bg-yellow-700 shadow-md hover:shadow-lg'
+              }
+            `}
+          >
+            提示
+          </button>
+        )}
+
         <button
           onClick={handlePass}
           disabled={isPassDisabled}
@@ -5,7 +5,9 @@ import type {
   AuthResponse,
   RoomListResponse,
   CreateRoomRequest,
-  Room
+  Room,
+  PlayInterpretation,
+  HintResult
 } from '../types';
 
 // API configuration
@@ -149,6 +151,21 @@ class ApiClient {
     });
   }
 
+  // Game APIs
+  async getPlayInterpretations(roomId: string, cardIds: string[]): Promise<ApiResponse<{ interpretations: PlayInterpretation[] }>> {
+    return this.request<{ interpretations: PlayInterpretation[] }>('/api/game/driver/interpretations', {
+      method: 'POST',
+      body: JSON.stringify({ room_id: roomId, card_ids: cardIds }),
+    });
+  }
+
+  async getHints(roomId: string, playerSeat: number): Promise<ApiResponse<{ hints: HintResult }>> {
+    return this.request<{ hints: HintResult }>('/api/game/driver/hints', {
+      method: 'POST',
+      body: JSON.stringify({ room_id: roomId, player_seat: playerSeat }),
+    });
+  }
+
   // Health check
   async healthCheck(): Promise<ApiResponse<{ status: string }>> {
     return this.request<{ status: string }>('/healthz');
@@ -43,6 +43,7 @@ class GameService {
     // Game state messages
     wsClient.on('game_event', this.handleGameEvent.bind(this));
     wsClient.on('player_view', this.handlePlayerView.bind(this));
+    wsClient.on('hints', this.handleHints.bind(this));
 
     // Player management
     wsClient.on('player_timeout', this.handlePlayerTimeout.bind(this));
@@ -209,6 +210,11 @@ class GameService {
     }
   }
 
+  // Asks the server for ranked play suggestions; the answer arrives as a 'hints' message
+  requestHints(roomId?: string): boolean {
+    return wsClient.send('get_hints', roomId ? { room_id: roomId } : {});
+  }
+
   // WebSocket message handlers
   private handleRoomUpdate(message: WSMessage): void {
     const roomData = message.data;
@@ -267,6 +273,10 @@ class GameService {
     }
   }
 
+  private handleHints(message: WSMessage): void {
+    useGameStore.getState().setHints(message.data);
+  }
+
   private handlePlayerTimeout(message: WSMessage): void {
     console.log('Player timeout:', message.data);
     useGameStore.getState().setLastMessage(message);
@@ -1,5 +1,5 @@
 import { create } from 'zustand';
-import type { WSMessage } from '../types';
+import type { WSMessage, HintResult } from '../types';
 
 interface GameState {
   isInGame: boolean;
@@ -10,6 +10,7 @@ interface GameState {
   lastMessage: WSMessage | null;
   isConnected: boolean;
   error: string | null;
+  hints: HintResult | null;
 }
 
 interface GameActions {
@@ -21,6 +22,7 @@ interface GameActions {
   setLastMessage: (message: WSMessage | null) => void;
   setConnected: (connected: boolean) => void;
   setError: (error: string | null) => void;
+  setHints: (hints: HintResult | null) => void;
   clearError: () => void;
   reset: () => void;
 }
@@ -35,7 +37,8 @@ const initialState: GameState = {
   countdown: null,
   lastMessage: null,
   isConnected: false,
-  error: null
+  error: null,
+  hints: null
 };
 
 export const useGameStore = create<GameStore>((set) => ({
@@ -58,6 +61,8 @@ export const useGameStore = create<GameStore>((set) => ({
   
   setError: (error: string | null) => set({ error }),
   
+  setHints: (hints: HintResult | null) => set({ hints }),
+  
   clearError: () => set({ error: null }),
   
   reset: () => set(initialState)
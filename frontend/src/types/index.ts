@@ -109,6 +109,8 @@ export const WS_MESSAGE_TYPES = {
   PLAY_DECISION: 'play_decision',
   TRIBUTE_SELECT: 'tribute_select',
   TRIBUTE_RETURN: 'tribute_return',
+  GET_HINTS: 'get_hints',
+  HINTS: 'hints',
   
   // State sync
   GAME_EVENT: 'game_event',
@@ -131,6 +133,39 @@ export interface Card {
   is_joker: boolean;
 }
 
+// Card as serialized by the SDK (e.g. inside hints)
+export interface SdkCard {
+  Number: number; // 2-14 (14=A), 15=small joker, 16=big joker
+  RawNumber: number;
+  Color: string; // "Spade" | "Heart" | "Club" | "Diamond" | "Joker"
+  Level: number;
+  Name: string;
+}
+
+// A suggested play for the "提示" button
+export interface PlayHint {
+  cards: SdkCard[];
+  type: number; // SDK CompType
+  is_bomb: boolean;
+  breaks_bomb: boolean;
+  breaks_straight: boolean;
+  uses_wildcard: boolean;
+}
+
+export interface HintResult {
+  player_seat: number;
+  is_leader: boolean;
+  has_legal_play: boolean; // false means the only option is to pass
+  hints: PlayHint[]; // Ranked from the smallest sufficient play to bombs
+}
+
+// One way the selected cards can be played when wildcards (heart level cards) are involved
+export interface PlayInterpretation {
+  type: string; // Combination type from the SDK, e.g. "FullHouse", "StraightFlush"
+  substitute_ids?: string[]; // Card IDs each wildcard stands for; omitted when the engine decides
+  default: boolean; // What the engine picks if nothing is declared
+}
+
 export interface PlayAction {
   player_seat: number;
   cards: Card[];
@@ -275,3 +275,68 @@ func ParseCardFromID(cardID string, level int) (*Card, error) {
 	// 使用现有的NewCard函数创建卡牌
 	return NewCard(number, color, level)
 }
+
+// ParseShortCard 从简化表示解析牌，格式与 ToShortString 一致
+// 参数:
+//
+//	short: 点数+花色首字母，如 "9H"、"10S"、"QS"、"AD"；小王 "SJ"，大王 "BJ"（不区分大小写）
+//	level: 当前游戏级别，用于变化牌判断
+//
+// 返回值:
+//
+//	*Card: 解析得到的卡牌对象
+//	error: 如果格式无效，返回错误
+func ParseShortCard(short string, level int) (*Card, error) {
+	if len(short) < 2 {
+		return nil, fmt.Errorf("invalid card %q", short)
+	}
+
+	upper := make([]byte, len(short))
+	for i := 0; i < len(short); i++ {
+		c := short[i]
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		upper[i] = c
+	}
+	switch string(upper) {
+	case "SJ":
+		return NewCard(15, "Joker", level)
+	case "BJ":
+		return NewCard(16, "Joker", level)
+	}
+
+	var color string
+	switch upper[len(upper)-1] {
+	case 'S':
+		color = "Spade"
+	case 'H':
+		color = "Heart"
+	case 'D':
+		color = "Diamond"
+	case 'C':
+		color = "Club"
+	default:
+		return nil, fmt.Errorf("invalid suit in card %q", short)
+	}
+
+	var number int
+	switch rank := string(upper[:len(upper)-1]); rank {
+	case "J":
+		number = 11
+	case "Q":
+		number = 12
+	case "K":
+		number = 13
+	case "A", "1", "14":
+		number = 14
+	case "10":
+		number = 10
+	default:
+		if len(rank) != 1 || rank[0] < '2' || rank[0] > '9' {
+			return nil, fmt.Errorf("invalid rank in card %q", short)
+		}
+		number = int(rank[0] - '0')
+	}
+	return NewCard(number, color, level)
+}
@@ -274,3 +274,39 @@ func TestParseCardFromID(t *testing.T) {
 		})
 	}
 }
+
+func TestParseShortCard(t *testing.T) {
+	testCases := []struct {
+		short  string
+		number int
+		color  string
+	}{
+		{"9H", 9, "Heart"},
+		{"10S", 10, "Spade"},
+		{"QS", 12, "Spade"},
+		{"ad", 14, "Diamond"},
+		{"1C", 14, "Club"},
+		{"SJ", 15, "Joker"},
+		{"bj", 16, "Joker"},
+	}
+
+	for _, tc := range testCases {
+		card, err := ParseShortCard(tc.short, 5)
+		if err != nil {
+			t.Errorf("ParseShortCard(%s) returned error: %v", tc.short, err)
+			continue
+		}
+		if card.Number != tc.number || card.Color != tc.color {
+			t.Errorf("ParseShortCard(%s) = %d %s, expected %d %s", tc.short, card.Number, card.Color, tc.number, tc.color)
+		}
+		if round, _ := ParseShortCard(card.ToShortString(), 5); round == nil || round.GetID() != card.GetID() {
+			t.Errorf("Round-trip failed for %s", tc.short)
+		}
+	}
+
+	for _, short := range []string{"", "H", "11H", "5X", "XJ", "0S"} {
+		if _, err := ParseShortCard(short, 5); err == nil {
+			t.Errorf("ParseShortCard(%q) should have returned an error", short)
+		}
+	}
+}
@@ -262,6 +262,7 @@ type BaseComp struct {
 	NormalizedCards []*Card  // 规范化牌组（万能牌已替换为具体牌）
 	Valid           bool
 	Type            CompType
+	Substitutes     []*Card  // 出牌时声明的变化牌代表的具体牌（nil 表示由 FromCardList 自动判断）
 }
 
 // GetCards 获取牌组中的原始牌（包含万能牌）
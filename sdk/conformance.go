@@ -0,0 +1,467 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ConformanceCards 用例中的一组牌，统一保存为简化表示（见 ParseShortCard）
+// JSON 中可以写成字符串 "5H 5C SJ"、字符串数组 ["5H", "5C"]，或旧格式 [[5, "Heart"], [5, "Club"]]
+type ConformanceCards []string
+
+// UnmarshalJSON 支持三种写法
+func (c *ConformanceCards) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*c = strings.Fields(text)
+		return nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("cards must be a string or a list: %w", err)
+	}
+	cards := make(ConformanceCards, 0, len(items))
+	for _, item := range items {
+		var short string
+		if err := json.Unmarshal(item, &short); err == nil {
+			cards = append(cards, short)
+			continue
+		}
+		var pair []interface{}
+		if err := json.Unmarshal(item, &pair); err != nil || len(pair) != 2 {
+			return fmt.Errorf("invalid card %s", item)
+		}
+		number, ok := pair[0].(float64)
+		color, ok2 := pair[1].(string)
+		if !ok || !ok2 {
+			return fmt.Errorf("invalid card %s", item)
+		}
+		card, err := NewCard(int(number), color, 0)
+		if err != nil {
+			return fmt.Errorf("invalid card %s: %w", item, err)
+		}
+		cards = append(cards, card.ToShortString())
+	}
+	*c = cards
+	return nil
+}
+
+// Cards 按指定级别创建牌
+func (c ConformanceCards) Cards(level int) ([]*Card, error) {
+	cards := make([]*Card, 0, len(c))
+	for _, short := range c {
+		card, err := ParseShortCard(short, level)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// String 返回 "[5H 5C SJ]" 形式的表示
+func (c ConformanceCards) String() string {
+	return "[" + strings.Join(c, " ") + "]"
+}
+
+// ClassificationCase 牌型识别用例：FromCardList 识别出的牌型
+type ClassificationCase struct {
+	Name  string           `json:"name,omitempty"`
+	Level int              `json:"level,omitempty"` // 为0时使用文件的级别
+	Cards ConformanceCards `json:"cards"`
+	Type  string           `json:"type"` // 期望的牌型，与 CompType.String() 一致，如 "Pair"、"IllegalComp"
+}
+
+// ComparisonCase 牌组比较用例：A 以 B 为上家牌型识别后，双方 GreaterThan 的结果
+type ComparisonCase struct {
+	Name     string           `json:"name,omitempty"`
+	Level    int              `json:"level,omitempty"`
+	A        ConformanceCards `json:"a"`
+	B        ConformanceCards `json:"b"`
+	BType    string           `json:"b_type,omitempty"` // B 含变化牌时按指定牌型构造，为空时自动识别
+	AGreater bool             `json:"a_greater"`
+	BGreater bool             `json:"b_greater"`
+}
+
+// TributeOutcome 一名上贡者的结果
+type TributeOutcome struct {
+	To   int    `json:"to"`   // 接收贡牌的座位，-1 表示放入贡牌池（双下）
+	Card string `json:"card"` // 上贡的牌
+}
+
+// TributeCase 贡牌用例：根据上一局名次和本局手牌确定抗贡和上贡
+type TributeCase struct {
+	Name     string                 `json:"name,omitempty"`
+	Level    int                    `json:"level,omitempty"`
+	Rankings []int                  `json:"rankings"` // 上一局的名次，座位号按第1到第4名排列
+	Hands    [4]ConformanceCards    `json:"hands"`    // 本局各座位的手牌，只需写出与贡牌有关的牌
+	Immune   bool                   `json:"immune"`   // 是否抗贡
+	Tributes map[int]TributeOutcome `json:"tributes"` // 上贡者座位 -> 结果，抗贡时为空
+}
+
+// UpgradeCase 升级用例：根据名次计算胜方、胜利类型和升级数
+type UpgradeCase struct {
+	Name        string `json:"name,omitempty"`
+	Rankings    []int  `json:"rankings"`
+	WinningTeam int    `json:"winning_team"`
+	VictoryType string `json:"victory_type"` // double_down、single_last、partner_last
+	Upgrades    [2]int `json:"upgrades"`
+}
+
+// ConformanceFile 一个用例文件
+// 同时兼容 test-data/comp.json（comp_list）和 test-data/comparison_test_data.json（comparisons）的旧格式
+type ConformanceFile struct {
+	Description    string               `json:"description,omitempty"`
+	Level          int                  `json:"level"`
+	Classification []ClassificationCase `json:"classification,omitempty"`
+	Comparison     []ComparisonCase     `json:"comparison,omitempty"`
+	Tribute        []TributeCase        `json:"tribute,omitempty"`
+	Upgrade        []UpgradeCase        `json:"upgrade,omitempty"`
+}
+
+// legacyConformanceFile 旧格式中的字段
+type legacyConformanceFile struct {
+	CompList    []ClassificationCase `json:"comp_list"`
+	Comparisons []struct {
+		TestID int `json:"test_id"`
+		Comp1  struct {
+			Cards ConformanceCards `json:"cards"`
+		} `json:"comp1"`
+		Comp2 struct {
+			Cards ConformanceCards `json:"cards"`
+			Type  string           `json:"type"`
+		} `json:"comp2"`
+		Comp1Greater bool `json:"comp1_greater_than_comp2"`
+		Comp2Greater bool `json:"comp2_greater_than_comp1"`
+	} `json:"comparisons"`
+}
+
+// LoadConformanceFile 读取一个用例文件
+func LoadConformanceFile(path string) (*ConformanceFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file ConformanceFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var legacy legacyConformanceFile
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	file.Classification = append(file.Classification, legacy.CompList...)
+	for _, c := range legacy.Comparisons {
+		file.Comparison = append(file.Comparison, ComparisonCase{
+			Name:     fmt.Sprintf("test_id %d", c.TestID),
+			A:        c.Comp1.Cards,
+			B:        c.Comp2.Cards,
+			BType:    c.Comp2.Type,
+			AGreater: c.Comp1Greater,
+			BGreater: c.Comp2Greater,
+		})
+	}
+	return &file, nil
+}
+
+// ConformanceFailure 一个未通过的用例
+type ConformanceFailure struct {
+	File     string `json:"file"`
+	Kind     string `json:"kind"` // classification、comparison、tribute、upgrade
+	Case     string `json:"case"` // 用例名称，没有名称时为 "#序号"
+	Input    string `json:"input"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// ConformanceTally 某一类用例的统计
+type ConformanceTally struct {
+	Total  int `json:"total"`
+	Passed int `json:"passed"`
+}
+
+// ConformanceReport 规则一致性测试报告
+type ConformanceReport struct {
+	Files    []string                     `json:"files"`
+	Total    int                          `json:"total"`
+	Passed   int                          `json:"passed"`
+	ByKind   map[string]*ConformanceTally `json:"by_kind"`
+	Failures []ConformanceFailure         `json:"failures"`
+}
+
+// OK 是否全部通过
+func (r *ConformanceReport) OK() bool {
+	return r.Passed == r.Total
+}
+
+// RunConformance 加载用例文件并在规则实现上运行
+// 参数:
+//
+//	paths: 用例文件或目录，目录中的 *.json 文件会被递归加载
+//
+// 返回值:
+//
+//	*ConformanceReport: 测试报告，差异使用简化牌面表示
+//	error: 文件无法读取或解析时返回错误（用例不通过不算错误）
+//
+// 功能说明:
+//   - 牌型识别用例调用 FromCardList，比较用例调用 GreaterThan
+//   - 贡牌用例调用 TributeManager，升级用例调用 DealResultCalculator
+func RunConformance(paths ...string) (*ConformanceReport, error) {
+	files, err := conformanceFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ConformanceReport{ByKind: make(map[string]*ConformanceTally)}
+	for _, path := range files {
+		file, err := LoadConformanceFile(path)
+		if err != nil {
+			return nil, err
+		}
+		report.Files = append(report.Files, path)
+		report.run(path, file)
+	}
+	return report, nil
+}
+
+// conformanceFiles 展开目录，返回排序后的文件列表
+func conformanceFiles(paths []string) ([]string, error) {
+	files := make([]string, 0)
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".json") {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// record 记录一个用例的结果，actual 与 expected 不同时记为失败
+func (r *ConformanceReport) record(file, kind, name string, index int, input, expected, actual string) {
+	tally := r.ByKind[kind]
+	if tally == nil {
+		tally = &ConformanceTally{}
+		r.ByKind[kind] = tally
+	}
+	tally.Total++
+	r.Total++
+	if expected == actual {
+		tally.Passed++
+		r.Passed++
+		return
+	}
+	if name == "" {
+		name = fmt.Sprintf("#%d", index)
+	}
+	r.Failures = append(r.Failures, ConformanceFailure{
+		File: file, Kind: kind, Case: name, Input: input, Expected: expected, Actual: actual,
+	})
+}
+
+// run 运行一个文件中的全部用例
+func (r *ConformanceReport) run(path string, file *ConformanceFile) {
+	level := func(caseLevel int) int {
+		if caseLevel != 0 {
+			return caseLevel
+		}
+		return file.Level
+	}
+
+	for i, c := range file.Classification {
+		r.record(path, "classification", c.Name, i, c.Cards.String(), c.Type, classify(c.Cards, level(c.Level)))
+	}
+	for i, c := range file.Comparison {
+		input := fmt.Sprintf("a=%s b=%s", c.A, c.B)
+		if c.BType != "" {
+			input += " b_type=" + c.BType
+		}
+		expected := fmt.Sprintf("a>b=%t b>a=%t", c.AGreater, c.BGreater)
+		actual, detail := compare(c, level(c.Level))
+		if actual != expected {
+			actual += detail
+		}
+		r.record(path, "comparison", c.Name, i, input, expected, actual)
+	}
+	for i, c := range file.Tribute {
+		input := fmt.Sprintf("rankings=%v hands=%v", c.Rankings, c.Hands)
+		r.record(path, "tribute", c.Name, i, input, formatTributeOutcome(c.Immune, c.Tributes), tribute(c, level(c.Level)))
+	}
+	for i, c := range file.Upgrade {
+		expected := fmt.Sprintf("winner=team%d %s upgrades=%v", c.WinningTeam, c.VictoryType, c.Upgrades)
+		r.record(path, "upgrade", c.Name, i, fmt.Sprintf("rankings=%v", c.Rankings), expected, upgrade(c))
+	}
+}
+
+// formatComp 以简化表示输出牌组，如 "Pair[5H 5C]"
+func formatComp(comp CardComp) string {
+	if comp == nil {
+		return "nil"
+	}
+	shorts := make([]string, 0, len(comp.GetCards()))
+	for _, card := range comp.GetCards() {
+		shorts = append(shorts, card.ToShortString())
+	}
+	return comp.GetType().String() + ConformanceCards(shorts).String()
+}
+
+func classify(cards ConformanceCards, level int) string {
+	list, err := cards.Cards(level)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return FromCardList(list, nil).GetType().String()
+}
+
+// compare 返回比较结果，以及失败时附加的双方识别出的牌型
+func compare(c ComparisonCase, level int) (string, string) {
+	aCards, err := c.A.Cards(level)
+	if err != nil {
+		return "error: " + err.Error(), ""
+	}
+	bCards, err := c.B.Cards(level)
+	if err != nil {
+		return "error: " + err.Error(), ""
+	}
+
+	var b CardComp
+	if c.BType != "" {
+		b = NormalizeComp(CreateCompByType(bCards, c.BType))
+	} else {
+		b = FromCardList(bCards, nil)
+	}
+	a := FromCardList(aCards, b)
+	return fmt.Sprintf("a>b=%t b>a=%t", a.GreaterThan(b), b.GreaterThan(a)),
+		fmt.Sprintf(" (a as %s, b as %s)", formatComp(a), formatComp(b))
+}
+
+// formatTributeOutcome 输出 "immune=false 3->0:AS" 形式的贡牌结果，-1 显示为 pool
+func formatTributeOutcome(immune bool, tributes map[int]TributeOutcome) string {
+	parts := []string{fmt.Sprintf("immune=%t", immune)}
+	for _, giver := range sortedSeats(tributes) {
+		outcome := tributes[giver]
+		to := fmt.Sprintf("%d", outcome.To)
+		if outcome.To == -1 {
+			to = "pool"
+		}
+		card := outcome.Card
+		if parsed, err := ParseShortCard(card, 0); err == nil {
+			card = parsed.ToShortString()
+		}
+		parts = append(parts, fmt.Sprintf("%d->%s:%s", giver, to, card))
+	}
+	return strings.Join(parts, " ")
+}
+
+// finishedDealResult 用 DealResultCalculator 计算给定名次的结果
+func finishedDealResult(rankings []int, level int) (*DealResult, error) {
+	if len(rankings) != 4 {
+		return nil, fmt.Errorf("rankings must list 4 seats, got %v", rankings)
+	}
+	deal := &Deal{Level: level, Status: DealStatusFinished, Rankings: rankings}
+	return NewDealResultCalculator(level).CalculateDealResult(deal, &Match{})
+}
+
+func tribute(c TributeCase, level int) string {
+	result, err := finishedDealResult(c.Rankings, level)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	var hands [4][]*Card
+	for seat := range hands {
+		if hands[seat], err = c.Hands[seat].Cards(level); err != nil {
+			return "error: " + err.Error()
+		}
+	}
+
+	tm := NewTributeManager(level)
+	if immune, _ := tm.GetTributeImmunityDetails(result, hands); immune {
+		return formatTributeOutcome(true, nil)
+	}
+	phase, err := NewTributePhase(result)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	if _, err := tm.ProcessTributePhaseAction(phase, hands); err != nil {
+		return "error: " + err.Error()
+	}
+	tributes := make(map[int]TributeOutcome)
+	for giver, receiver := range phase.TributeMap {
+		outcome := TributeOutcome{To: receiver}
+		if card := phase.TributeCards[giver]; card != nil {
+			outcome.Card = card.ToShortString()
+		}
+		tributes[giver] = outcome
+	}
+	return formatTributeOutcome(false, tributes)
+}
+
+func upgrade(c UpgradeCase) string {
+	result, err := finishedDealResult(c.Rankings, 2)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return fmt.Sprintf("winner=team%d %s upgrades=%v", result.WinningTeam, result.VictoryType, result.Upgrades)
+}
+
+// Write 按格式输出报告：text 或 json
+func (r *ConformanceReport) Write(w io.Writer, format string) error {
+	switch format {
+	case "text", "":
+		return r.WriteText(w)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	default:
+		return fmt.Errorf("unknown report format: %s", format)
+	}
+}
+
+// WriteText 输出可读的差异报告
+func (r *ConformanceReport) WriteText(w io.Writer) error {
+	var buf bytes.Buffer
+	for _, failure := range r.Failures {
+		fmt.Fprintf(&buf, "FAIL %s %s %s\n", failure.File, failure.Kind, failure.Case)
+		fmt.Fprintf(&buf, "  input:    %s\n", failure.Input)
+		fmt.Fprintf(&buf, "  expected: %s\n", failure.Expected)
+		fmt.Fprintf(&buf, "  actual:   %s\n", failure.Actual)
+	}
+	kinds := make([]string, 0, len(r.ByKind))
+	for kind := range r.ByKind {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		tally := r.ByKind[kind]
+		fmt.Fprintf(&buf, "%-15s %d/%d passed\n", kind, tally.Passed, tally.Total)
+	}
+	fmt.Fprintf(&buf, "%d files, %d/%d cases passed\n", len(r.Files), r.Passed, r.Total)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,103 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConformanceCardsNotations(t *testing.T) {
+	expected := ConformanceCards{"5H", "AC", "SJ"}
+	for _, data := range []string{
+		`"5H AC SJ"`,
+		`["5H", "AC", "SJ"]`,
+		`[[5, "Heart"], [1, "Club"], [15, "Joker"]]`,
+	} {
+		var cards ConformanceCards
+		if err := json.Unmarshal([]byte(data), &cards); err != nil {
+			t.Errorf("unmarshal %s: %v", data, err)
+			continue
+		}
+		if !reflect.DeepEqual(cards, expected) {
+			t.Errorf("unmarshal %s = %v, expected %v", data, cards, expected)
+		}
+	}
+
+	var cards ConformanceCards
+	if err := json.Unmarshal([]byte(`[[5, "Purple"]]`), &cards); err == nil {
+		t.Error("expected error for invalid legacy card")
+	}
+}
+
+// 仓库中的用例（包括旧格式的 comp.json 和 comparison_test_data.json）应全部通过
+func TestRunConformanceTestData(t *testing.T) {
+	report, err := RunConformance(filepath.Join("..", "test-data"))
+	if err != nil {
+		t.Fatalf("RunConformance: %v", err)
+	}
+	if len(report.Files) < 3 {
+		t.Errorf("expected at least 3 case files, got %v", report.Files)
+	}
+	for _, kind := range []string{"classification", "comparison", "tribute", "upgrade"} {
+		if report.ByKind[kind] == nil || report.ByKind[kind].Total == 0 {
+			t.Errorf("no %s cases were run", kind)
+		}
+	}
+	if !report.OK() {
+		var out bytes.Buffer
+		report.WriteText(&out)
+		t.Errorf("conformance failures:\n%s", out.String())
+	}
+}
+
+func TestRunConformanceReportsDiff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wrong.json")
+	data := `{
+	  "level": 5,
+	  "classification": [{"name": "wrong type", "cards": "5H 7C", "type": "Single"}],
+	  "comparison": [{"a": "AS", "b": "5S", "a_greater": true, "b_greater": false}],
+	  "tribute": [{"rankings": [0, 1, 2, 3], "hands": ["", "", "", "AS 3D"], "tributes": {"3": {"to": 0, "card": "3D"}}}],
+	  "upgrade": [{"rankings": [0, 1, 2], "winning_team": 0, "victory_type": "single_last", "upgrades": [2, 0]}]
+	}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := RunConformance(path)
+	if err != nil {
+		t.Fatalf("RunConformance: %v", err)
+	}
+	if report.Total != 4 || report.Passed != 0 || len(report.Failures) != 4 {
+		t.Fatalf("expected 4 failures, got %+v", report)
+	}
+
+	failure := report.Failures[0]
+	if failure.Case != "wrong type" || failure.Input != "[5H 7C]" || failure.Expected != "Single" || failure.Actual != "Pair" {
+		t.Errorf("unexpected classification failure %+v", failure)
+	}
+	if !strings.Contains(report.Failures[1].Actual, "a>b=false b>a=true (a as Single[AS], b as Single[5S])") {
+		t.Errorf("unexpected comparison failure %+v", report.Failures[1])
+	}
+	if report.Failures[2].Actual != "immune=false 3->0:AS" {
+		t.Errorf("unexpected tribute failure %+v", report.Failures[2])
+	}
+	if !strings.HasPrefix(report.Failures[3].Actual, "error:") {
+		t.Errorf("invalid rankings should be reported as an error, got %+v", report.Failures[3])
+	}
+
+	var out bytes.Buffer
+	if err := report.Write(&out, "text"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "FAIL "+path+" classification wrong type") || !strings.Contains(out.String(), "0/4 cases passed") {
+		t.Errorf("unexpected text report:\n%s", out.String())
+	}
+	out.Reset()
+	if err := report.Write(&out, "json"); err != nil || !json.Valid(out.Bytes()) {
+		t.Errorf("invalid json report: %v", err)
+	}
+}
@@ -37,6 +37,22 @@ func NewDeal(level int, lastResult *DealResult) (*Deal, error) {
 	return deal, nil
 }
 
+// SetSeed makes the deal's shuffle and first-player choice reproducible
+// Must be called before StartDeal
+func (d *Deal) SetSeed(seed int64) {
+	d.rng = rand.New(rand.NewSource(seed))
+}
+
+// DealSeed derives the seed of the deal at dealIndex from a match seed,
+// so that every deal of a seeded match gets an independent but reproducible shuffle
+func DealSeed(matchSeed int64, dealIndex int) int64 {
+	// splitmix64 finalizer
+	z := uint64(matchSeed) + uint64(dealIndex+1)*0x9E3779B97F4A7C15
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return int64(z ^ (z >> 31))
+}
+
 // StartDeal starts the deal by dealing cards to all players
 func (d *Deal) StartDeal() error {
 	if d.Status != DealStatusWaiting {
@@ -88,6 +104,12 @@ func (d *Deal) StartDeal() error {
 
 // PlayCards handles a player playing cards
 func (d *Deal) PlayCards(playerSeat int, cards []*Card) error {
+	return d.PlayCardsAs(playerSeat, cards, nil)
+}
+
+// PlayCardsAs handles a player playing cards with declared wildcard substitutes
+// substitutes 为空时与 PlayCards 相同，由 FromCardList 根据领先牌组自动判断牌型
+func (d *Deal) PlayCardsAs(playerSeat int, cards []*Card, substitutes []*Card) error {
 	if d.Status != DealStatusPlaying {
 		return fmt.Errorf("deal is not in playing status: %s", d.Status)
 	}
@@ -109,6 +131,12 @@ func (d *Deal) PlayCards(playerSeat int, cards []*Card) error {
 
 	// Create card combination and validate it
 	comp := FromCardList(cards, d.CurrentTrick.LeadComp)
+	if len(substitutes) > 0 {
+		comp, err = DeclareComp(cards, substitutes)
+		if err != nil {
+			return err
+		}
+	}
 	if !comp.IsValid() {
 		return errors.New("invalid card combination")
 	}
@@ -297,8 +325,13 @@ func (d *Deal) dealCards() error {
 	deck := d.createFullDeck()
 
 	// Shuffle deck
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(len(deck), func(i, j int) {
+	shuffle := rand.Shuffle
+	if d.rng != nil {
+		shuffle = d.rng.Shuffle
+	} else {
+		rand.Seed(time.Now().UnixNano())
+	}
+	shuffle(len(deck), func(i, j int) {
 		deck[i], deck[j] = deck[j], deck[i]
 	})
 
@@ -622,6 +655,9 @@ func (d *Deal) CalculateResult(match *Match) (*DealResult, error) {
 func (d *Deal) determineFirstPlayer() int {
 	// First deal in match: truly random selection
 	if d.LastResult == nil {
+		if d.rng != nil {
+			return d.rng.Intn(4)
+		}
 		return rand.Intn(4) // Random player 0-3
 	}
 
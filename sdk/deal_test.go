@@ -1,6 +1,7 @@
 package sdk
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -111,6 +112,34 @@ func TestDealCardDealing(t *testing.T) {
 	}
 }
 
+func TestDealSeededDealing(t *testing.T) {
+	hands := func(seed int64) [4][]string {
+		deal, _ := NewDeal(2, nil)
+		deal.SetSeed(seed)
+		if err := deal.dealCards(); err != nil {
+			t.Fatalf("dealCards failed: %v", err)
+		}
+		var ids [4][]string
+		for player := 0; player < 4; player++ {
+			for _, card := range deal.PlayerCards[player] {
+				ids[player] = append(ids[player], card.GetID())
+			}
+		}
+		return ids
+	}
+
+	first, second, other := hands(42), hands(42), hands(43)
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Error("same seed should deal the same hands")
+	}
+	if fmt.Sprint(first) == fmt.Sprint(other) {
+		t.Error("different seeds should deal different hands")
+	}
+	if DealSeed(42, 0) == DealSeed(42, 1) || DealSeed(42, 0) == DealSeed(43, 0) {
+		t.Error("derived deal seeds should differ by match seed and deal index")
+	}
+}
+
 func TestDealCreateFullDeck(t *testing.T) {
 	deal, _ := NewDeal(7, nil)
 
@@ -0,0 +1,554 @@
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FuzzConfig 引擎随机压力测试配置
+type FuzzConfig struct {
+	Seed           int64   `json:"seed"`            // 随机种子，同时决定发牌和动作选择
+	MaxSteps       int     `json:"max_steps"`       // 最多执行的动作数（0表示 DefaultFuzzSteps）
+	IllegalRate    float64 `json:"illegal_rate"`    // 尝试非法动作的概率
+	DisconnectRate float64 `json:"disconnect_rate"` // 随机断线/重连的概率
+	TimeoutRate    float64 `json:"timeout_rate"`    // 让当前玩家超时的概率
+	StallLimit     int     `json:"stall_limit"`     // 连续多少个动作没有牌被打出视为卡死（0表示 DefaultFuzzStallLimit）
+}
+
+// 压力测试默认参数
+const (
+	DefaultFuzzSteps      = 20000
+	DefaultFuzzStallLimit = 500
+)
+
+// DefaultFuzzConfig 返回默认配置
+func DefaultFuzzConfig(seed int64) FuzzConfig {
+	return FuzzConfig{
+		Seed:           seed,
+		MaxSteps:       DefaultFuzzSteps,
+		IllegalRate:    0.2,
+		DisconnectRate: 0.02,
+		TimeoutRate:    0.05,
+		StallLimit:     DefaultFuzzStallLimit,
+	}
+}
+
+// FuzzAction 动作日志中的一条记录
+type FuzzAction struct {
+	Step     int      `json:"step"`
+	Kind     string   `json:"kind"`
+	Seat     int      `json:"seat"`
+	Cards    []string `json:"cards,omitempty"`
+	Accepted bool     `json:"accepted"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// FuzzFailure 不变量被破坏时的复现信息
+type FuzzFailure struct {
+	Config    FuzzConfig   `json:"config"`    // 复现用的配置（MaxSteps 为出错的步数）
+	Step      int          `json:"step"`      // 出错的步数
+	Invariant string       `json:"invariant"` // 被破坏的不变量
+	Detail    string       `json:"detail"`
+	Log       []FuzzAction `json:"log"` // 从比赛开始到出错的全部动作
+}
+
+// Error 实现 error 接口
+func (f *FuzzFailure) Error() string {
+	return fmt.Sprintf("invariant %q broken at step %d (seed %d): %s", f.Invariant, f.Step, f.Config.Seed, f.Detail)
+}
+
+// Save 把复现信息保存为 JSON 文件
+func (f *FuzzFailure) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// FuzzResult 一次压力测试的统计
+type FuzzResult struct {
+	Seed          int64          `json:"seed"`
+	Steps         int            `json:"steps"`
+	Deals         int            `json:"deals"`
+	MatchFinished bool           `json:"match_finished"`
+	Actions       map[string]int `json:"actions"`  // 各类动作的次数
+	Rejected      map[string]int `json:"rejected"` // 各类动作被引擎拒绝的次数
+}
+
+// engineFuzzer 单次压力测试的状态
+type engineFuzzer struct {
+	config  FuzzConfig
+	engine  *GameEngine
+	rng     *rand.Rand
+	before  string // 动作前的状态摘要，用于检查被拒绝的动作
+	log     []FuzzAction
+	result  *FuzzResult
+	stalled int // 自上次有牌被打出以来的动作数
+}
+
+// RunEngineFuzz 用随机的合法和非法动作、断线重连和超时驱动 GameEngine，每一步后检查不变量
+// 参数:
+//
+//	config: 压力测试配置
+//
+// 返回值:
+//
+//	*FuzzResult: 统计信息
+//	error: 不变量被破坏时返回 *FuzzFailure，包含种子和完整的动作日志
+//
+// 功能说明:
+//   - 检查的不变量：108张牌在手牌和已出的牌之间守恒；出牌阶段恰好有一个有手牌的当前玩家；
+//     Rankings 无重复且与手牌一致；双方级别在2到A之间；被拒绝的动作不改变状态；牌局不会卡死
+//   - 同一配置的运行完全可复现
+func RunEngineFuzz(config FuzzConfig) (*FuzzResult, error) {
+	if config.MaxSteps <= 0 {
+		config.MaxSteps = DefaultFuzzSteps
+	}
+	if config.StallLimit <= 0 {
+		config.StallLimit = DefaultFuzzStallLimit
+	}
+
+	fuzzer := &engineFuzzer{
+		config: config,
+		engine: NewGameEngine(),
+		rng:    rand.New(rand.NewSource(config.Seed)),
+		result: &FuzzResult{
+			Seed:     config.Seed,
+			Actions:  make(map[string]int),
+			Rejected: make(map[string]int),
+		},
+	}
+	fuzzer.engine.SetRandomSeed(config.Seed)
+
+	players := make([]Player, 4)
+	for seat := range players {
+		players[seat] = Player{ID: fmt.Sprintf("fuzz_%d", seat), Username: fmt.Sprintf("Fuzz%d", seat), Seat: seat}
+	}
+	if err := fuzzer.engine.StartMatch(players); err != nil {
+		return nil, err
+	}
+
+	for step := 1; step <= config.MaxSteps && !fuzzer.engine.IsGameFinished(); step++ {
+		fuzzer.result.Steps = step
+		if err := fuzzer.step(step); err != nil {
+			return fuzzer.result, err
+		}
+	}
+	if match := fuzzer.engine.currentMatch; match != nil {
+		fuzzer.result.Deals = len(match.DealHistory)
+	}
+	fuzzer.result.MatchFinished = fuzzer.engine.IsGameFinished()
+	return fuzzer.result, nil
+}
+
+// step 执行一个随机动作并检查不变量
+func (f *engineFuzzer) step(step int) error {
+	match := f.engine.currentMatch
+	deal := match.CurrentDeal
+
+	var action FuzzAction
+	var mutating bool
+	f.before = f.fingerprint()
+
+	switch {
+	case deal == nil:
+		action = f.record(step, "start_deal", -1, nil, f.engine.StartDeal())
+		mutating = true
+	case f.rng.Float64() < f.config.DisconnectRate:
+		action, mutating = f.connection(step)
+	case deal.Status == DealStatusTribute:
+		action, mutating = f.tribute(step)
+	case deal.Status == DealStatusPlaying:
+		action, mutating = f.play(step, deal)
+	default:
+		return f.fail(step, "deal_status", fmt.Sprintf("unexpected deal status %s", deal.Status))
+	}
+
+	f.log = append(f.log, action)
+	f.result.Actions[action.Kind]++
+	if !action.Accepted {
+		f.result.Rejected[action.Kind]++
+		// 被拒绝的动作不能改变任何状态
+		if after := f.fingerprint(); after != f.before {
+			return f.fail(step, "rejected_action_is_noop", fmt.Sprintf("%s was rejected (%s) but changed state:\n before %s\n after  %s", action.Kind, action.Error, f.before, after))
+		}
+	} else if mutating && action.Kind != "start_deal" && action.Kind != "disconnect" && action.Kind != "reconnect" {
+		f.stalled++
+	}
+	if action.Accepted && (action.Kind == "play" || action.Kind == "autoplay" || action.Kind == "start_deal") {
+		f.stalled = 0
+	}
+	if f.stalled > f.config.StallLimit {
+		return f.fail(step, "progress", fmt.Sprintf("%d actions without any cards played", f.stalled))
+	}
+
+	if name, detail := f.checkInvariants(); name != "" {
+		return f.fail(step, name, detail)
+	}
+	return nil
+}
+
+// record 生成一条动作日志
+func (f *engineFuzzer) record(step int, kind string, seat int, cards []*Card, err error) FuzzAction {
+	action := FuzzAction{Step: step, Kind: kind, Seat: seat, Accepted: err == nil}
+	for _, card := range cards {
+		action.Cards = append(action.Cards, card.GetID())
+	}
+	if err != nil {
+		action.Error = err.Error()
+	}
+	return action
+}
+
+// fail 生成失败信息
+func (f *engineFuzzer) fail(step int, invariant, detail string) error {
+	config := f.config
+	config.MaxSteps = step
+	return &FuzzFailure{Config: config, Step: step, Invariant: invariant, Detail: detail, Log: f.log}
+}
+
+// connection 随机让一个玩家断线或重连
+func (f *engineFuzzer) connection(step int) (FuzzAction, bool) {
+	seat := f.rng.Intn(4)
+	if f.engine.currentMatch.IsPlayerOnline(seat) {
+		_, err := f.engine.HandlePlayerDisconnect(seat)
+		return f.record(step, "disconnect", seat, nil, err), true
+	}
+	_, err := f.engine.HandlePlayerReconnect(seat)
+	return f.record(step, "reconnect", seat, nil, err), true
+}
+
+// tribute 处理贡牌阶段：推进、合法或非法的选牌/还贡、超时跳过
+func (f *engineFuzzer) tribute(step int) (FuzzAction, bool) {
+	action, err := f.engine.ProcessTributePhase()
+	if err != nil || action == nil {
+		return f.record(step, "tribute_process", -1, nil, err), true
+	}
+
+	roll := f.rng.Float64()
+	switch {
+	case roll < f.config.TimeoutRate:
+		return f.record(step, "tribute_timeout", action.PlayerID, nil, f.engine.SkipTributeAction()), true
+	case roll < f.config.TimeoutRate+f.config.IllegalRate:
+		// 错误的玩家，或者不在选项中的牌；推进贡牌阶段本身是合法的，从这里开始比较状态
+		f.before = f.fingerprint()
+		seat := action.PlayerID
+		cardID := "Joker_99"
+		if f.rng.Intn(2) == 0 && len(action.Options) > 0 {
+			seat = (seat + 1 + f.rng.Intn(3)) % 4
+			cardID = action.Options[f.rng.Intn(len(action.Options))].GetID()
+		}
+		if action.Type == TributeActionSelect {
+			return f.record(step, "illegal_tribute_select", seat, nil, f.engine.SubmitTributeSelection(seat, cardID)), false
+		}
+		return f.record(step, "illegal_tribute_return", seat, nil, f.engine.SubmitReturnTribute(seat, cardID)), false
+	}
+
+	if len(action.Options) == 0 {
+		return f.record(step, "tribute_process", action.PlayerID, nil, errors.New("tribute action without options")), false
+	}
+	card := action.Options[f.rng.Intn(len(action.Options))]
+	if action.Type == TributeActionSelect {
+		return f.record(step, "tribute_select", action.PlayerID, []*Card{card}, f.engine.SubmitTributeSelection(action.PlayerID, card.GetID())), true
+	}
+	return f.record(step, "tribute_return", action.PlayerID, []*Card{card}, f.engine.SubmitReturnTribute(action.PlayerID, card.GetID())), true
+}
+
+// play 出牌阶段：合法出牌/过牌、掉线玩家托管、超时、非法动作
+func (f *engineFuzzer) play(step int, deal *Deal) (FuzzAction, bool) {
+	trick := deal.CurrentTrick
+	seat := trick.CurrentTurn
+	hand := deal.PlayerCards[seat]
+
+	roll := f.rng.Float64()
+	switch {
+	case roll < f.config.TimeoutRate:
+		// 让当前玩家的回合立即超时
+		trick.TurnTimeout = time.Now().Add(-time.Second)
+		if deal.TributePhase != nil {
+			deal.TributePhase.SelectTimeout = time.Now().Add(-time.Second)
+		}
+		events := f.engine.ProcessTimeouts()
+		var err error
+		if len(events) == 0 {
+			err = errors.New("no timeout processed")
+		}
+		return f.record(step, "timeout", seat, nil, err), true
+	case roll < f.config.TimeoutRate+f.config.IllegalRate:
+		return f.illegal(step, deal), false
+	}
+
+	if !f.engine.currentMatch.IsPlayerOnline(seat) || f.rng.Intn(10) == 0 {
+		_, err := f.engine.AutoPlayForPlayer(seat)
+		return f.record(step, "autoplay", seat, nil, err), true
+	}
+
+	leadComp := trick.LeadComp
+	if trick.Status == TrickStatusWaiting {
+		leadComp = nil
+	}
+	if leadComp != nil && f.rng.Intn(3) == 0 {
+		_, err := f.engine.PassTurn(seat)
+		return f.record(step, "pass", seat, nil, err), true
+	}
+	cards := f.randomPlay(hand, leadComp, deal.Level)
+	if cards == nil {
+		if leadComp == nil {
+			_, err := f.engine.AutoPlayForPlayer(seat)
+			return f.record(step, "autoplay", seat, nil, err), true
+		}
+		_, err := f.engine.PassTurn(seat)
+		return f.record(step, "pass", seat, nil, err), true
+	}
+	_, err := f.engine.PlayCards(seat, cards)
+	return f.record(step, "play", seat, cards, err), true
+}
+
+// randomPlay 随机挑选一手能出的牌：首出时任意同点数的牌组，跟牌时能压过的同张数牌组或炸弹
+func (f *engineFuzzer) randomPlay(hand []*Card, leadComp CardComp, level int) []*Card {
+	byNumber := make(map[int][]*Card)
+	numbers := make([]int, 0)
+	for _, card := range hand {
+		if len(byNumber[card.Number]) == 0 {
+			numbers = append(numbers, card.Number)
+		}
+		byNumber[card.Number] = append(byNumber[card.Number], card)
+	}
+	sort.Ints(numbers)
+
+	for attempt := 0; attempt < 20 && len(numbers) > 0; attempt++ {
+		group := byNumber[numbers[f.rng.Intn(len(numbers))]]
+		size := 1 + f.rng.Intn(len(group))
+		if leadComp != nil && f.rng.Intn(4) != 0 {
+			size = len(leadComp.GetCards())
+			if size > len(group) {
+				continue
+			}
+		}
+		cards := append([]*Card(nil), group[:size]...)
+		comp := FromCardList(cards, leadComp)
+		if comp == nil || !comp.IsValid() {
+			continue
+		}
+		if leadComp == nil || comp.GreaterThan(leadComp) {
+			return cards
+		}
+	}
+	return nil
+}
+
+// illegal 尝试一个非法动作：不是自己的回合、出手中没有的牌、无效牌型、首出过牌
+func (f *engineFuzzer) illegal(step int, deal *Deal) FuzzAction {
+	trick := deal.CurrentTrick
+	seat := trick.CurrentTurn
+	other := (seat + 1 + f.rng.Intn(3)) % 4
+
+	switch f.rng.Intn(4) {
+	case 0:
+		if len(deal.PlayerCards[other]) > 0 {
+			cards := []*Card{deal.PlayerCards[other][0]}
+			_, err := f.engine.PlayCards(other, cards)
+			return f.record(step, "illegal_wrong_turn", other, cards, err)
+		}
+		_, err := f.engine.PassTurn(other)
+		return f.record(step, "illegal_wrong_turn", other, nil, err)
+	case 1:
+		// 手中没有的牌：从其他玩家手里拿一张本人没有的牌
+		for _, card := range deal.PlayerCards[other] {
+			if !handContains(deal.PlayerCards[seat], card) {
+				cards := []*Card{card}
+				_, err := f.engine.PlayCards(seat, cards)
+				return f.record(step, "illegal_not_in_hand", seat, cards, err)
+			}
+		}
+	case 2:
+		// 两张不同点数的牌不是有效牌型
+		hand := deal.PlayerCards[seat]
+		for i := 1; i < len(hand); i++ {
+			if hand[i].Number != hand[0].Number && hand[i].Color != "Joker" && hand[0].Color != "Joker" &&
+				hand[i].Number != deal.Level && hand[0].Number != deal.Level {
+				cards := []*Card{hand[0], hand[i]}
+				_, err := f.engine.PlayCards(seat, cards)
+				return f.record(step, "illegal_combination", seat, cards, err)
+			}
+		}
+	}
+	if trick.LeadComp == nil || trick.Status == TrickStatusWaiting {
+		_, err := f.engine.PassTurn(seat)
+		return f.record(step, "illegal_leader_pass", seat, nil, err)
+	}
+	_, err := f.engine.PlayCards(seat, nil)
+	return f.record(step, "illegal_empty_play", seat, nil, err)
+}
+
+// handContains 手牌中是否有与 card 点数花色相同的牌
+func handContains(hand []*Card, card *Card) bool {
+	for _, c := range hand {
+		if c.Number == card.Number && c.Color == card.Color {
+			return true
+		}
+	}
+	return false
+}
+
+// fingerprint 返回影响对局的状态摘要，用于确认被拒绝的动作没有副作用
+func (f *engineFuzzer) fingerprint() string {
+	match := f.engine.currentMatch
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "status=%s levels=%v deals=%d", f.engine.status, match.TeamLevels, len(match.DealHistory))
+	deal := match.CurrentDeal
+	if deal == nil {
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, " deal=%s rankings=%v hands=", deal.Status, deal.Rankings)
+	for seat := 0; seat < 4; seat++ {
+		ids := make([]string, 0, len(deal.PlayerCards[seat]))
+		for _, card := range deal.PlayerCards[seat] {
+			ids = append(ids, card.GetID())
+		}
+		sort.Strings(ids)
+		fmt.Fprintf(&sb, "[%s]", strings.Join(ids, ","))
+	}
+	if trick := deal.CurrentTrick; trick != nil {
+		fmt.Fprintf(&sb, " trick=%s turn=%d leader=%d plays=%d history=%d", trick.Status, trick.CurrentTurn, trick.Leader, len(trick.Plays), len(deal.TrickHistory))
+	}
+	if phase := deal.TributePhase; phase != nil {
+		fmt.Fprintf(&sb, " tribute=%s selecting=%d pool=%d returns=%d", phase.Status, phase.SelectingPlayer, len(phase.PoolCards), len(phase.ReturnCards))
+	}
+	return sb.String()
+}
+
+// checkInvariants 检查所有不变量，返回被破坏的不变量名称和详情
+func (f *engineFuzzer) checkInvariants() (string, string) {
+	match := f.engine.currentMatch
+
+	for team, level := range match.TeamLevels {
+		if level < 2 || level > 14 {
+			return "levels", fmt.Sprintf("team %d level %d out of range", team, level)
+		}
+	}
+	if f.engine.status == GameStatusFinished && match.Winner != 0 && match.Winner != 1 {
+		return "winner", fmt.Sprintf("finished match has winner %d", match.Winner)
+	}
+
+	deal := match.CurrentDeal
+	if deal == nil {
+		return "", ""
+	}
+	if deal.Level < 2 || deal.Level > 14 {
+		return "levels", fmt.Sprintf("deal level %d out of range", deal.Level)
+	}
+
+	// 108张牌守恒：手牌 + 本局已出的牌恰好是两副牌
+	counts := make(map[string]int)
+	total := 0
+	for seat := 0; seat < 4; seat++ {
+		for _, card := range deal.PlayerCards[seat] {
+			counts[card.GetID()]++
+			total++
+		}
+	}
+	seen := make(map[*Trick]bool)
+	tricks := append([]*Trick(nil), deal.TrickHistory...)
+	if deal.CurrentTrick != nil {
+		tricks = append(tricks, deal.CurrentTrick)
+	}
+	for _, trick := range tricks {
+		if seen[trick] {
+			continue
+		}
+		seen[trick] = true
+		for _, play := range trick.Plays {
+			for _, card := range play.Cards {
+				counts[card.GetID()]++
+				total++
+			}
+		}
+	}
+	if total != 108 {
+		return "card_conservation", fmt.Sprintf("%d cards in hands and plays, expected 108", total)
+	}
+	for id, count := range counts {
+		if count != 2 {
+			return "card_conservation", fmt.Sprintf("card %s appears %d times", id, count)
+		}
+	}
+
+	// Rankings 无重复，已排名的玩家没有手牌
+	ranked := make(map[int]bool)
+	for _, seat := range deal.Rankings {
+		if seat < 0 || seat > 3 || ranked[seat] {
+			return "rankings", fmt.Sprintf("invalid rankings %v", deal.Rankings)
+		}
+		ranked[seat] = true
+		if deal.Status != DealStatusFinished && len(deal.PlayerCards[seat]) > 0 {
+			return "rankings", fmt.Sprintf("ranked seat %d still has %d cards", seat, len(deal.PlayerCards[seat]))
+		}
+	}
+
+	if deal.Status == DealStatusPlaying {
+		for seat := 0; seat < 4; seat++ {
+			if len(deal.PlayerCards[seat]) == 0 && !ranked[seat] {
+				return "rankings", fmt.Sprintf("seat %d has no cards but is not ranked %v", seat, deal.Rankings)
+			}
+		}
+		if len(deal.Rankings) >= 3 {
+			return "rankings", fmt.Sprintf("deal still playing with rankings %v", deal.Rankings)
+		}
+
+		// 恰好一个当前玩家：轮到的座位有效且还有手牌，且与引擎对外报告的一致
+		trick := deal.CurrentTrick
+		if trick == nil {
+			return "current_player", "playing deal has no current trick"
+		}
+		if trick.Status == TrickStatusFinished {
+			return "current_player", "current trick is finished but no new trick was started"
+		}
+		if trick.CurrentTurn < 0 || trick.CurrentTurn > 3 {
+			return "current_player", fmt.Sprintf("current turn %d out of range", trick.CurrentTurn)
+		}
+		if len(deal.PlayerCards[trick.CurrentTurn]) == 0 {
+			return "current_player", fmt.Sprintf("current player %d has no cards", trick.CurrentTurn)
+		}
+		if info := f.engine.GetCurrentTurnInfo(); info == nil || info.CurrentPlayer != trick.CurrentTurn {
+			return "current_player", "turn info does not match current trick"
+		}
+	}
+
+	if deal.Status == DealStatusFinished && len(deal.Rankings) != 4 {
+		return "rankings", fmt.Sprintf("finished deal has rankings %v", deal.Rankings)
+	}
+	return "", ""
+}
+
+// MinimizeFuzzFailure 在保持同一个不变量被破坏的前提下，依次关闭断线、超时和非法动作，
+// 并把步数截断到出错的那一步，得到最小的复现配置
+func MinimizeFuzzFailure(failure *FuzzFailure) *FuzzFailure {
+	best := failure
+	disable := []func(*FuzzConfig){
+		func(c *FuzzConfig) { c.DisconnectRate = 0 },
+		func(c *FuzzConfig) { c.TimeoutRate = 0 },
+		func(c *FuzzConfig) { c.IllegalRate = 0 },
+	}
+	for _, apply := range disable {
+		config := best.Config
+		config.MaxSteps = DefaultFuzzSteps
+		apply(&config)
+		if config == best.Config {
+			continue
+		}
+		_, err := RunEngineFuzz(config)
+		var candidate *FuzzFailure
+		if errors.As(err, &candidate) && candidate.Invariant == failure.Invariant && candidate.Step <= best.Step {
+			best = candidate
+		}
+	}
+	return best
+}
@@ -0,0 +1,111 @@
+package sdk
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRunEngineFuzz(t *testing.T) {
+	seeds := int64(20)
+	if testing.Short() {
+		seeds = 3
+	}
+	for seed := int64(1); seed <= seeds; seed++ {
+		result, err := RunEngineFuzz(DefaultFuzzConfig(seed))
+		if err != nil {
+			var failure *FuzzFailure
+			if errors.As(err, &failure) {
+				failure = MinimizeFuzzFailure(failure)
+				path := filepath.Join(t.TempDir(), "failure.json")
+				if saveErr := failure.Save(path); saveErr == nil {
+					t.Logf("reproduction saved to %s", path)
+				}
+				t.Fatalf("seed %d: %v (minimized config %+v)", seed, failure, failure.Config)
+			}
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		if !result.MatchFinished {
+			t.Errorf("seed %d: match not finished after %d steps", seed, result.Steps)
+		}
+		if result.Rejected["illegal_wrong_turn"] != result.Actions["illegal_wrong_turn"] {
+			t.Errorf("seed %d: some wrong-turn actions were accepted: %v / %v", seed, result.Rejected, result.Actions)
+		}
+	}
+}
+
+func TestRunEngineFuzzDeterministic(t *testing.T) {
+	config := DefaultFuzzConfig(7)
+	config.MaxSteps = 400
+
+	first, err := RunEngineFuzz(config)
+	if err != nil {
+		t.Fatalf("RunEngineFuzz: %v", err)
+	}
+	second, err := RunEngineFuzz(config)
+	if err != nil {
+		t.Fatalf("RunEngineFuzz: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed produced different runs:\n%+v\n%+v", first, second)
+	}
+}
+
+// 超时导致一轮结束时，引擎必须像正常过牌一样开始下一轮
+func TestGameEngineTimeoutFinishesTrick(t *testing.T) {
+	engine := NewGameEngine()
+	players := []Player{
+		{ID: "player1", Username: "Player1", Seat: 0},
+		{ID: "player2", Username: "Player2", Seat: 1},
+		{ID: "player3", Username: "Player3", Seat: 2},
+		{ID: "player4", Username: "Player4", Seat: 3},
+	}
+	if err := engine.StartMatch(players); err != nil {
+		t.Fatalf("Failed to start match: %v", err)
+	}
+	if err := engine.StartDeal(); err != nil {
+		t.Fatalf("Failed to start deal: %v", err)
+	}
+
+	deal := engine.GetGameState().CurrentMatch.CurrentDeal
+	leader := deal.CurrentTrick.CurrentTurn
+	if _, err := engine.PlayCards(leader, []*Card{deal.PlayerCards[leader][0]}); err != nil {
+		t.Fatalf("Leader failed to play: %v", err)
+	}
+	for i := 1; i <= 2; i++ {
+		if _, err := engine.PassTurn((leader + i) % 4); err != nil {
+			t.Fatalf("Player %d failed to pass: %v", (leader+i)%4, err)
+		}
+	}
+
+	// 最后一名玩家超时自动过牌，这一轮结束
+	deal.CurrentTrick.TurnTimeout = time.Now().Add(-time.Second)
+	if events := engine.ProcessTimeouts(); len(events) == 0 {
+		t.Fatal("Expected timeout events")
+	}
+
+	if len(deal.TrickHistory) != 1 {
+		t.Errorf("Expected finished trick in history, got %d tricks", len(deal.TrickHistory))
+	}
+	if deal.CurrentTrick == nil || deal.CurrentTrick.Status == TrickStatusFinished {
+		t.Fatal("Expected a new trick after the timeout finished the previous one")
+	}
+	if deal.CurrentTrick.CurrentTurn != leader {
+		t.Errorf("Expected trick winner %d to lead, got %d", leader, deal.CurrentTrick.CurrentTurn)
+	}
+}
+
+func FuzzEngine(f *testing.F) {
+	for _, seed := range []int64{1, 2, 3} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		config := DefaultFuzzConfig(seed)
+		config.MaxSteps = 2000
+		if _, err := RunEngineFuzz(config); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
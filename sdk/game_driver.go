@@ -16,8 +16,9 @@ const (
 
 // PlayDecision 表示玩家的出牌决策
 type PlayDecision struct {
-	Action ActionType `json:"action"`          // 动作类型：出牌或过牌
-	Cards  []*Card    `json:"cards,omitempty"` // 如果是出牌，包含要出的牌
+	Action      ActionType `json:"action"`                // 动作类型：出牌或过牌
+	Cards       []*Card    `json:"cards,omitempty"`       // 如果是出牌，包含要出的牌
+	Substitutes []*Card    `json:"substitutes,omitempty"` // 可选，声明每张变化牌代表的具体牌（见 InterpretCards）
 }
 
 // PlayerInputProvider 定义玩家输入提供者接口
@@ -78,6 +79,12 @@ type GameDriverConfig struct {
 
 	// 事件处理
 	AsyncEventHandling bool `json:"async_event_handling"` // 是否异步处理事件
+
+	// 局数限制
+	MaxDeals int `json:"max_deals"` // 最多运行的局数（0表示打完整场比赛），用于只比较固定牌局的评测
+
+	// 时间限制：超过后打完当前这局即结束比赛（0表示不限时），比赛未结束时 Winner 为 -1
+	MaxDuration time.Duration `json:"max_duration"`
 }
 
 // DefaultGameDriverConfig 返回默认的游戏驱动器配置
@@ -205,6 +212,12 @@ func (gd *GameDriver) RunMatch(players []Player) (*GameDriverResult, error) {
 
 	// 比赛主循环
 	for !gd.engine.IsGameFinished() {
+		if gd.config.MaxDeals > 0 && dealCount >= gd.config.MaxDeals {
+			break // 达到局数上限，比赛未结束时 Winner 为 -1
+		}
+		if gd.config.MaxDuration > 0 && time.Since(startTime) >= gd.config.MaxDuration {
+			break // 超过时间限制
+		}
 		dealCount++
 
 		if err := gd.runDeal(); err != nil {
@@ -441,7 +454,7 @@ func (gd *GameDriver) runTrick() error {
 				return fmt.Errorf("player %d chose to play but provided no cards", currentPlayer)
 			}
 
-			_, err = gd.engine.PlayCards(currentPlayer, decision.Cards)
+			_, err = gd.engine.PlayCardsAs(currentPlayer, decision.Cards, decision.Substitutes)
 			if err != nil {
 				return fmt.Errorf("failed to play cards for player %d: %w", currentPlayer, err)
 			}
@@ -97,6 +97,9 @@ type GameEngine struct {
 	mutex         sync.RWMutex                         // 读写锁，保护并发访问游戏状态
 	createdAt     time.Time                            // 游戏引擎创建时间
 	updatedAt     time.Time                            // 最后更新时间
+	seed          *int64                               // 发牌随机种子（nil 表示使用时间作为种子）
+	variant       RuleVariant                          // 规则变体（空表示标准规则）
+	startLevels   *[2]int                              // 两队起始级别（nil 表示都从2开始）
 }
 
 // GameEngineInterface 定义了游戏引擎的公共接口
@@ -144,6 +147,18 @@ type GameEngineInterface interface {
 	//   - 触发玩家出牌事件
 	PlayCards(playerSeat int, cards []*Card) (*GameEvent, error)
 
+	// PlayCardsAs 玩家出牌并声明变化牌代表的具体牌
+	// 参数:
+	//   playerSeat: 玩家座位号(0-3)
+	//   cards: 要出的牌的列表
+	//   substitutes: 每张变化牌代表的具体牌，顺序与 cards 中变化牌的顺序一致，为空时与 PlayCards 相同
+	// 返回值:
+	//   *GameEvent: 出牌成功时返回的游戏事件
+	//   error: 如果声明无效、出牌无效或不是该玩家回合，返回错误
+	// 功能说明:
+	//   - 同一组牌可能有多种牌型解释（见 InterpretCards），声明后按声明的解释判断和比较
+	PlayCardsAs(playerSeat int, cards []*Card, substitutes []*Card) (*GameEvent, error)
+
 	// PassTurn 玩家选择不出牌（过牌）
 	// 参数:
 	//   playerSeat: 玩家座位号(0-3)
@@ -329,6 +344,17 @@ type GameEngineInterface interface {
 	//   - 替代直接访问deal.CurrentTrick的需求
 	GetCurrentTurnInfo() *TurnInfo
 
+	// GetHints 获取玩家当前回合的出牌提示
+	// 参数:
+	//   playerSeat: 玩家座位号(0-3)
+	// 返回值:
+	//   *HintResult: 按推荐顺序排列的候选出牌，HasLegalPlay 为 false 表示只能过牌
+	//   error: 如果没有进行中的牌局或不是该玩家回合，返回错误
+	// 功能说明:
+	//   - 从刚好能压过领先牌组的出牌开始，炸弹排在最后
+	//   - 标记会拆散炸弹或顺子的出牌
+	GetHints(playerSeat int) (*HintResult, error)
+
 	// GetMatchDetails 获取比赛详细信息
 	// 返回值:
 	//   *MatchDetails: 比赛的详细信息，如果没有活跃比赛返回nil
@@ -351,6 +377,40 @@ func NewGameEngine() *GameEngine {
 	}
 }
 
+// SetRandomSeed 设置发牌随机种子，之后开始的比赛每一局的洗牌和首出都可以复现
+// 必须在 StartMatch 之前调用
+func (ge *GameEngine) SetRandomSeed(seed int64) {
+	ge.mutex.Lock()
+	defer ge.mutex.Unlock()
+	ge.seed = &seed
+}
+
+// SetVariant 设置之后开始的比赛使用的规则变体
+// 必须在 StartMatch 之前调用
+func (ge *GameEngine) SetVariant(variant RuleVariant) error {
+	if !variant.Valid() {
+		return fmt.Errorf("unknown rule variant: %s", variant)
+	}
+	ge.mutex.Lock()
+	defer ge.mutex.Unlock()
+	ge.variant = variant
+	return nil
+}
+
+// SetStartingLevels 设置之后开始的比赛中两队的起始级别（队伍0为座位0、2）
+// 级别范围为2到13（K），必须在 StartMatch 之前调用
+func (ge *GameEngine) SetStartingLevels(levels [2]int) error {
+	for team, level := range levels {
+		if level < 2 || level > 13 {
+			return fmt.Errorf("invalid starting level %d for team %d", level, team)
+		}
+	}
+	ge.mutex.Lock()
+	defer ge.mutex.Unlock()
+	ge.startLevels = &levels
+	return nil
+}
+
 // StartMatch initializes a new match with the given players
 func (ge *GameEngine) StartMatch(players []Player) error {
 	ge.mutex.Lock()
@@ -370,6 +430,15 @@ func (ge *GameEngine) StartMatch(players []Player) error {
 		return fmt.Errorf("failed to create match: %w", err)
 	}
 
+	if ge.seed != nil {
+		seed := *ge.seed
+		match.Seed = &seed
+	}
+	match.Variant = ge.variant
+	if ge.startLevels != nil {
+		match.TeamLevels = *ge.startLevels
+	}
+
 	ge.currentMatch = match
 	ge.status = GameStatusStarted
 	ge.updatedAt = time.Now()
@@ -477,6 +546,11 @@ func (ge *GameEngine) StartDeal() error {
 
 // PlayCards handles a player's card play action
 func (ge *GameEngine) PlayCards(playerSeat int, cards []*Card) (*GameEvent, error) {
+	return ge.PlayCardsAs(playerSeat, cards, nil)
+}
+
+// PlayCardsAs handles a player's card play action with declared wildcard substitutes
+func (ge *GameEngine) PlayCardsAs(playerSeat int, cards []*Card, substitutes []*Card) (*GameEvent, error) {
 	ge.mutex.Lock()
 	defer ge.mutex.Unlock()
 
@@ -488,7 +562,7 @@ func (ge *GameEngine) PlayCards(playerSeat int, cards []*Card) (*GameEvent, erro
 
 	// Use validator to validate the play
 	validator := NewPlayValidator(deal.Level)
-	err := validator.ValidatePlay(playerSeat, cards, deal.PlayerCards[playerSeat], deal.CurrentTrick)
+	err := validator.ValidatePlayAs(playerSeat, cards, substitutes, deal.PlayerCards[playerSeat], deal.CurrentTrick)
 	if err != nil {
 		return nil, fmt.Errorf("invalid play: %w", err)
 	}
@@ -500,7 +574,7 @@ func (ge *GameEngine) PlayCards(playerSeat int, cards []*Card) (*GameEvent, erro
 	}
 
 	// Execute the play
-	err = deal.PlayCards(playerSeat, cards)
+	err = deal.PlayCardsAs(playerSeat, cards, substitutes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to play cards: %w", err)
 	}
@@ -508,13 +582,17 @@ func (ge *GameEngine) PlayCards(playerSeat int, cards []*Card) (*GameEvent, erro
 	ge.updatedAt = time.Now()
 
 	// Create and emit player played event
+	data := map[string]interface{}{
+		"player_seat": playerSeat,
+		"cards":       cards,
+		"deal_state":  deal,
+	}
+	if len(substitutes) > 0 {
+		data["substitutes"] = substitutes
+	}
 	event := &GameEvent{
-		Type: EventPlayerPlayed,
-		Data: map[string]interface{}{
-			"player_seat": playerSeat,
-			"cards":       cards,
-			"deal_state":  deal,
-		},
+		Type:       EventPlayerPlayed,
+		Data:       data,
 		Timestamp:  time.Now(),
 		PlayerSeat: playerSeat,
 	}
@@ -656,6 +734,15 @@ func (ge *GameEngine) ProcessTimeouts() []*GameEvent {
 		for _, event := range timeoutEvents {
 			ge.emitEvent(event)
 		}
+
+		// 超时动作同样可能结束一轮或一局，需要与正常出牌一样推进状态
+		if len(timeoutEvents) > 0 {
+			postEvents := ge.checkPostActionStateTransitions()
+			for _, event := range postEvents {
+				ge.emitEvent(event)
+			}
+			events = append(events, postEvents...)
+		}
 	}
 
 	return events
@@ -1072,7 +1159,7 @@ func (ge *GameEngine) ProcessTributePhase() (*TributeAction, error) {
 		selectionOrder := []int{deal.TributePhase.SelectingPlayer}
 
 		// 根据贡牌映射找出贡献者
-		for giver := range deal.TributePhase.TributeMap {
+		for _, giver := range sortedSeats(deal.TributePhase.TributeMap) {
 			if deal.TributePhase.TributeMap[giver] == -1 {
 				// 贡献到池子的玩家
 				if tributeCard := deal.TributePhase.TributeCards[giver]; tributeCard != nil {
@@ -1106,7 +1193,8 @@ func (ge *GameEngine) ProcessTributePhase() (*TributeAction, error) {
 	}
 
 	// 检测上贡卡牌是否刚刚被确定（适用于所有场景）
-	for giver, receiver := range deal.TributePhase.TributeMap {
+	for _, giver := range sortedSeats(deal.TributePhase.TributeMap) {
+		receiver := deal.TributePhase.TributeMap[giver]
 		if receiver != -1 { // 不是贡献到池子的情况
 			// 检查是否有新的贡牌被确定（从nil变为非nil）
 			currentCard := deal.TributePhase.TributeCards[giver]
@@ -1386,6 +1474,35 @@ func (ge *GameEngine) GetCurrentTurnInfo() *TurnInfo {
 	}
 }
 
+// GetHints 获取玩家当前回合的出牌提示
+func (ge *GameEngine) GetHints(playerSeat int) (*HintResult, error) {
+	ge.mutex.RLock()
+	defer ge.mutex.RUnlock()
+
+	if ge.currentMatch == nil || ge.currentMatch.CurrentDeal == nil {
+		return nil, errors.New("no active deal")
+	}
+
+	deal := ge.currentMatch.CurrentDeal
+	if deal.Status != DealStatusPlaying || deal.CurrentTrick == nil {
+		return nil, fmt.Errorf("deal is not in playing status: %s", deal.Status)
+	}
+	if playerSeat < 0 || playerSeat > 3 {
+		return nil, fmt.Errorf("invalid player seat: %d", playerSeat)
+	}
+	if deal.CurrentTrick.CurrentTurn != playerSeat {
+		return nil, fmt.Errorf("not player %d's turn, current turn is %d", playerSeat, deal.CurrentTrick.CurrentTurn)
+	}
+
+	hints := SuggestPlays(deal.PlayerCards[playerSeat], deal.CurrentTrick.LeadComp)
+	return &HintResult{
+		PlayerSeat:   playerSeat,
+		IsLeader:     deal.CurrentTrick.LeadComp == nil,
+		HasLegalPlay: len(hints) > 0,
+		Hints:        hints,
+	}, nil
+}
+
 // GetMatchDetails 获取比赛详细信息
 func (ge *GameEngine) GetMatchDetails() *MatchDetails {
 	ge.mutex.RLock()
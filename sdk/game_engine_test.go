@@ -331,3 +331,56 @@ func TestGameStatusTypes(t *testing.T) {
 		}
 	}
 }
+
+func TestGameEngineMatchRules(t *testing.T) {
+	engine := NewGameEngine()
+
+	if err := engine.SetVariant("no_such_variant"); err == nil {
+		t.Error("SetVariant should reject unknown variants")
+	}
+	if err := engine.SetStartingLevels([2]int{2, 14}); err == nil {
+		t.Error("SetStartingLevels should reject levels above K")
+	}
+	if err := engine.SetVariant(RuleVariantNoTribute); err != nil {
+		t.Fatalf("SetVariant failed: %v", err)
+	}
+	if err := engine.SetStartingLevels([2]int{5, 3}); err != nil {
+		t.Fatalf("SetStartingLevels failed: %v", err)
+	}
+
+	players := []Player{
+		{ID: "p1", Username: "Player1", Seat: 0},
+		{ID: "p2", Username: "Player2", Seat: 1},
+		{ID: "p3", Username: "Player3", Seat: 2},
+		{ID: "p4", Username: "Player4", Seat: 3},
+	}
+	if err := engine.StartMatch(players); err != nil {
+		t.Fatalf("Failed to start match: %v", err)
+	}
+	match := engine.currentMatch
+	if match.TeamLevels != [2]int{5, 3} || match.Variant != RuleVariantNoTribute {
+		t.Errorf("Expected the rules to apply to the match, got levels %v and variant %q", match.TeamLevels, match.Variant)
+	}
+
+	// A deal after a finished one skips tribute and the first player out leads
+	now := time.Now()
+	match.DealHistory = []*Deal{{
+		ID:       "deal1",
+		Level:    5,
+		Rankings: []int{1, 3, 0, 2},
+		EndTime:  &now,
+	}}
+	if err := engine.StartDeal(); err != nil {
+		t.Fatalf("Failed to start deal: %v", err)
+	}
+	deal := match.CurrentDeal
+	if deal.Level != 5 {
+		t.Errorf("Expected the deal to be played at level 5, got %d", deal.Level)
+	}
+	if deal.TributePhase != nil || deal.Status != DealStatusPlaying {
+		t.Errorf("Expected no tribute phase, got status %s", deal.Status)
+	}
+	if deal.CurrentTrick == nil || deal.CurrentTrick.CurrentTurn != 1 {
+		t.Errorf("Expected seat 1 to lead, got %+v", deal.CurrentTrick)
+	}
+}
@@ -0,0 +1,970 @@
+package sdk
+
+// HandMask 手牌的紧凑表示
+//
+// 非变化牌按点数（Number，2-14 为普通牌，15/16 为小王/大王）计数，另外按花色记录每个点数的张数
+// （每个点数占 2 bit），变化牌单独计数。牌型判断、比较和出牌枚举都直接在计数上完成，
+// 不需要像 FromCardList 那样排序、克隆牌组和逐一尝试万能牌替换。
+//
+// 判断结果与 FromCardList 完全一致（包括它对 A 和变化牌的各种特殊处理），
+// 适用于两副牌的对局（变化牌最多两张）。
+type HandMask struct {
+	Counts [17]uint8 // 非变化牌按 Number 计数
+	Suits  [4]uint32 // 各花色非变化牌的张数，点数 n 位于第 2n 位起的 2 bit，花色顺序与 Colors 一致
+	Wild   uint8     // 变化牌（红桃级牌）数量
+	Size   uint8     // 总张数
+	Level  uint8     // 当前级别
+}
+
+// MaskComp 在 HandMask 上判断得到的牌型
+// 同类牌型之间按 Key 比较，炸弹之间还要比较张数，比较结果与对应 CardComp 的 GreaterThan 一致
+type MaskComp struct {
+	Type CompType
+	Size uint8 // 张数
+	Key  int8  // 比较键值
+}
+
+// 点数的比较值：级牌大于 A，小于王
+const (
+	maskLevelValue = 15
+	maskWildKey    = maskLevelValue
+)
+
+// NewHandMask 从牌列表构建紧凑表示，级别取自牌本身
+func NewHandMask(cards []*Card) HandMask {
+	var h HandMask
+	if len(cards) > 0 {
+		h.Level = uint8(cards[0].Level)
+	}
+	for _, card := range cards {
+		h.Add(card)
+	}
+	return h
+}
+
+// Add 加入一张牌
+func (h *HandMask) Add(card *Card) {
+	h.Size++
+	if card.IsWildcard() {
+		h.Wild++
+		return
+	}
+	h.Counts[card.Number]++
+	if suit := suitIndex(card.Color); suit >= 0 {
+		h.Suits[suit] += 1 << (2 * uint(card.Number))
+	}
+}
+
+// Len 返回总张数
+func (h HandMask) Len() int {
+	return int(h.Size)
+}
+
+// Contains 检查 move 是否是手牌的子集
+func (h HandMask) Contains(move HandMask) bool {
+	if move.Wild > h.Wild {
+		return false
+	}
+	for n := 2; n <= 16; n++ {
+		if move.Counts[n] > h.Counts[n] {
+			return false
+		}
+	}
+	for suit := range h.Suits {
+		for n := 2; n <= 14; n++ {
+			if move.suitCount(suit, n) > h.suitCount(suit, n) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Sub 返回去掉 move 之后的手牌，move 必须是手牌的子集
+func (h HandMask) Sub(move HandMask) HandMask {
+	for n := 2; n <= 16; n++ {
+		h.Counts[n] -= move.Counts[n]
+	}
+	for suit := range h.Suits {
+		h.Suits[suit] -= move.Suits[suit]
+	}
+	h.Wild -= move.Wild
+	h.Size -= move.Size
+	return h
+}
+
+// Classify 判断牌型
+// 参数:
+//
+//	prev: 上一手牌的类型，与 FromCardList 的 prev 参数作用相同（首出时传 TypeFold）
+//
+// 返回值:
+//
+//	MaskComp: 牌型及比较键值，无法组成牌型时 Type 为 TypeIllegal
+func (h HandMask) Classify(prev CompType) MaskComp {
+	comp := MaskComp{Type: TypeIllegal, Size: h.Size}
+
+	switch h.Size {
+	case 0:
+		comp.Type = TypeFold
+	case 1:
+		comp.Type = TypeSingle
+		comp.Key = maskWildKey
+		if h.Wild == 0 {
+			number, _ := h.singleRank()
+			comp.Key = h.value(number)
+		}
+	case 2:
+		if key, ok := h.pairKey(); ok {
+			comp.Type, comp.Key = TypePair, key
+		}
+	case 3:
+		if key, ok := h.tripleKey(); ok {
+			comp.Type, comp.Key = TypeTriple, key
+		}
+	case 4:
+		if h.Counts[15] == 2 && h.Counts[16] == 2 {
+			comp.Type = TypeJokerBomb
+		} else if key, ok := h.bombKey(); ok {
+			comp.Type, comp.Key = TypeNaiveBomb, key
+		}
+	case 5:
+		// 同花顺 > 炸弹 > 葫芦 > 顺子，葫芦和顺子不会同时成立，因此 prev 不影响结果
+		if key, ok := h.straightKey(); ok {
+			comp.Type, comp.Key = TypeStraight, key
+			if h.isFlush() {
+				comp.Type = TypeStraightFlush
+			}
+		} else if key, ok := h.bombKey(); ok {
+			comp.Type, comp.Key = TypeNaiveBomb, key
+		} else if triple, _, ok := h.fullHouseRanks(); ok {
+			comp.Type, comp.Key = TypeFullHouse, h.value(triple)
+		}
+	case 6:
+		if key, ok := h.bombKey(); ok {
+			comp.Type, comp.Key = TypeNaiveBomb, key
+			break
+		}
+		tubeKey, isTube := h.tubeKey()
+		if isTube && prev != TypePlate {
+			comp.Type, comp.Key = TypeTube, tubeKey
+		} else if key, ok := h.plateKey(); ok {
+			comp.Type, comp.Key = TypePlate, key
+		} else if isTube {
+			comp.Type, comp.Key = TypeTube, tubeKey
+		}
+	default:
+		if key, ok := h.bombKey(); ok {
+			comp.Type, comp.Key = TypeNaiveBomb, key
+		}
+	}
+	return comp
+}
+
+// MaskCompOf 把已有的牌组（例如一轮中的领先牌组）转换为 MaskComp，nil 视为 TypeFold
+func MaskCompOf(comp CardComp) MaskComp {
+	if comp == nil {
+		return MaskComp{Type: TypeFold}
+	}
+	if base := baseCompOf(comp); base != nil && base.Substitutes != nil {
+		// 声明过变化牌的牌组按代入后的牌判断
+		return NewHandMask(base.NormalizedCards).Classify(TypeFold)
+	}
+	prev := TypeFold
+	if comp.GetType() == TypePlate {
+		// 钢板和钢管可能由同一组牌构成，按原牌组的类型优先判断
+		prev = TypePlate
+	}
+	return NewHandMask(comp.GetCards()).Classify(prev)
+}
+
+// IsValid 检查是否为有效牌组
+func (c MaskComp) IsValid() bool {
+	return c.Type != TypeIllegal
+}
+
+// IsBomb 检查是否为炸弹（王炸、普通炸弹、同花顺）
+func (c MaskComp) IsBomb() bool {
+	return c.Type == TypeJokerBomb || c.Type == TypeNaiveBomb || c.Type == TypeStraightFlush
+}
+
+// GreaterThan 检查是否能压过 other，规则与各 CardComp 的 GreaterThan 相同
+func (c MaskComp) GreaterThan(other MaskComp) bool {
+	switch c.Type {
+	case TypeFold, TypeIllegal:
+		return false
+	case TypeJokerBomb:
+		return other.Type != TypeJokerBomb
+	case TypeNaiveBomb:
+		switch {
+		case !other.IsBomb():
+			return true
+		case other.Type == TypeStraightFlush:
+			// 6张以上的炸弹 > 同花顺
+			return c.Size >= 6
+		case other.Type == TypeNaiveBomb:
+			if c.Size != other.Size {
+				return c.Size > other.Size
+			}
+			return c.Key > other.Key
+		}
+		return false
+	case TypeStraightFlush:
+		switch {
+		case !other.IsBomb():
+			return true
+		case other.Type == TypeStraightFlush:
+			return c.Key > other.Key
+		case other.Type == TypeNaiveBomb:
+			return other.Size <= 5
+		}
+		return false
+	}
+	return other.Type == c.Type && c.Key > other.Key
+}
+
+// LegalMoves 枚举手牌中所有能压过 lead 的出牌
+// 参数:
+//
+//	lead: 当前领先的牌组，Type 为 TypeFold 表示首出，此时返回所有有效牌组
+//
+// 返回值:
+//
+//	[]HandMask: 手牌的子集，点数、变化牌数和牌型都相同的出牌只返回一次，可用 PickCards 还原为具体的牌
+func (h HandMask) LegalMoves(lead MaskComp) []HandMask {
+	g := moveGenerator{
+		hand: h,
+		lead: lead,
+		seen: make(map[moveKey]bool),
+	}
+	g.generate()
+	return g.moves
+}
+
+// PickCards 按 move 中的点数、花色和变化牌数从手牌中取出具体的牌
+func PickCards(hand []*Card, move HandMask) []*Card {
+	picked := make([]*Card, 0, move.Size)
+	for _, card := range hand {
+		if card.IsWildcard() {
+			if move.Wild > 0 {
+				move.Wild--
+				picked = append(picked, card)
+			}
+			continue
+		}
+		if move.Counts[card.Number] == 0 {
+			continue
+		}
+		suit := suitIndex(card.Color)
+		if suit >= 0 {
+			if move.suitCount(suit, card.Number) == 0 {
+				continue
+			}
+			move.Suits[suit] -= 1 << (2 * uint(card.Number))
+		}
+		move.Counts[card.Number]--
+		picked = append(picked, card)
+	}
+	return picked
+}
+
+// suitIndex 返回花色在 Colors 中的位置，王返回 -1
+func suitIndex(color string) int {
+	switch color {
+	case "Spade":
+		return 0
+	case "Club":
+		return 1
+	case "Heart":
+		return 2
+	case "Diamond":
+		return 3
+	}
+	return -1
+}
+
+// suitCount 返回某花色某点数的张数
+func (h *HandMask) suitCount(suit, number int) int {
+	return int(h.Suits[suit]>>(2*uint(number))) & 3
+}
+
+// value 返回点数的比较值，与 Card.GreaterThan 的顺序一致
+func (h *HandMask) value(number int) int8 {
+	switch {
+	case number == 0:
+		// 只有变化牌
+		return maskWildKey
+	case number == int(h.Level):
+		return maskLevelValue
+	case number >= 15:
+		return int8(number + 1)
+	}
+	return int8(number)
+}
+
+// numberOfRaw 把 RawNumber 转换为 Number（A 的 RawNumber 为 1）
+func numberOfRaw(raw int) int {
+	if raw == 1 || raw == 14 {
+		return 14
+	}
+	return raw
+}
+
+// rawOf 把 Number 转换为 RawNumber
+func rawOf(number int) int {
+	if number == 14 {
+		return 1
+	}
+	return number
+}
+
+// singleRank 返回非变化牌唯一的点数，没有非变化牌时返回 0，点数不止一种时 ok 为 false
+func (h *HandMask) singleRank() (number int, ok bool) {
+	for n := 2; n <= 16; n++ {
+		if h.Counts[n] == 0 {
+			continue
+		}
+		if number != 0 {
+			return 0, false
+		}
+		number = n
+	}
+	return number, true
+}
+
+// rankCount 一种点数及其张数
+type rankCount struct {
+	number int
+	count  int
+}
+
+// ranks 按 Number 从小到大列出非变化牌的点数，超过 len(buf) 种时返回 -1
+func (h *HandMask) ranks(buf []rankCount) int {
+	n := 0
+	for number := 2; number <= 16; number++ {
+		if h.Counts[number] == 0 {
+			continue
+		}
+		if n == len(buf) {
+			return -1
+		}
+		buf[n] = rankCount{number: number, count: int(h.Counts[number])}
+		n++
+	}
+	return n
+}
+
+// rawSequence 按 RawNumber 从小到大展开非变化牌（A 为 1），有王时 ok 为 false
+func (h *HandMask) rawSequence(buf []int) (n int, ok bool) {
+	if h.Counts[15] > 0 || h.Counts[16] > 0 {
+		return 0, false
+	}
+	for raw := 1; raw <= 13; raw++ {
+		for i := 0; i < int(h.Counts[numberOfRaw(raw)]); i++ {
+			buf[n] = raw
+			n++
+		}
+	}
+	return n, true
+}
+
+// isFlush 检查非变化牌是否同一花色
+func (h *HandMask) isFlush() bool {
+	suits := 0
+	for _, bits := range h.Suits {
+		if bits != 0 {
+			suits++
+		}
+	}
+	return suits == 1
+}
+
+// pairKey 对子：两张相同点数，或一张变化牌配一张非王的牌
+func (h *HandMask) pairKey() (int8, bool) {
+	number, ok := h.singleRank()
+	if !ok || (h.Wild == 1 && number >= 15) {
+		return 0, false
+	}
+	return h.value(number), true
+}
+
+// tripleKey 三张：不能有王，非变化牌点数相同
+func (h *HandMask) tripleKey() (int8, bool) {
+	number, ok := h.singleRank()
+	if !ok || number >= 15 {
+		return 0, false
+	}
+	return h.value(number), true
+}
+
+// bombKey 普通炸弹：四张以上，非变化牌点数相同（包括王）
+func (h *HandMask) bombKey() (int8, bool) {
+	if h.Size < 4 {
+		return 0, false
+	}
+	number, ok := h.singleRank()
+	if !ok || (number == 0 && h.Wild < 4) {
+		return 0, false
+	}
+	return h.value(number), true
+}
+
+// fullHouseRanks 按 fullHouseSatisfy 的规则找出葫芦的三张和对子点数
+// 只由变化牌组成的部分点数为 0
+func (h *HandMask) fullHouseRanks() (triple, pair int, ok bool) {
+	if h.Size != FULL_HOUSE_CARD_COUNT {
+		return 0, 0, false
+	}
+
+	// 有王时最大的两张必须是一对王，其余三张组成三张
+	if jokers := h.Counts[15] + h.Counts[16]; jokers > 0 {
+		if jokers != 2 || (h.Counts[15] != 2 && h.Counts[16] != 2) {
+			return 0, 0, false
+		}
+		pair = 15
+		if h.Counts[16] == 2 {
+			pair = 16
+		}
+		rest := *h
+		rest.Counts[pair] = 0
+		triple, ok = rest.singleRank()
+		return triple, pair, ok
+	}
+
+	var buf [3]rankCount
+	n := h.ranks(buf[:])
+	switch h.Wild {
+	case 0:
+		// 3 + 2
+		if n == 2 && (buf[0].count == 3 || buf[1].count == 3) {
+			if buf[0].count == 3 {
+				return buf[0].number, buf[1].number, true
+			}
+			return buf[1].number, buf[0].number, true
+		}
+	case 1:
+		if n != 2 {
+			break
+		}
+		// 2 + 2：较大的对子配变化牌组成三张
+		if buf[0].count == 2 {
+			if h.value(buf[0].number) > h.value(buf[1].number) {
+				return buf[0].number, buf[1].number, true
+			}
+			return buf[1].number, buf[0].number, true
+		}
+		// 3 + 1
+		if buf[0].count == 3 {
+			return buf[0].number, buf[1].number, true
+		}
+		return buf[1].number, buf[0].number, true
+	case 2:
+		// 单张配两张变化牌组成三张，对子保持不变
+		if n == 1 {
+			return buf[0].number, 0, true
+		}
+		if n == 2 {
+			if buf[0].count == 1 {
+				return buf[0].number, buf[1].number, true
+			}
+			return buf[1].number, buf[0].number, true
+		}
+	}
+	return 0, 0, false
+}
+
+// straightKey 按 straightSatisfy 的规则判断顺子，并按规范化后的牌计算比较键值
+func (h *HandMask) straightKey() (int8, bool) {
+	if h.Size != STRAIGHT_CARD_COUNT {
+		return 0, false
+	}
+	var raws [STRAIGHT_CARD_COUNT]int
+	n, ok := h.rawSequence(raws[:])
+	if !ok {
+		return 0, false
+	}
+	for i := 1; i < n; i++ {
+		if raws[i] == raws[i-1] {
+			return 0, false
+		}
+	}
+
+	// 排列后的顺子，0 表示变化牌
+	var arranged [STRAIGHT_CARD_COUNT]int
+	r := raws
+	aceHigh := func(order ...int) bool {
+		// order 为 10-J-Q-K-A 中实际存在的牌，缺少的位置由变化牌补上
+		for i := 0; i < n; i++ {
+			if r[i] != order[i] {
+				return false
+			}
+		}
+		arranged = [STRAIGHT_CARD_COUNT]int{}
+		for _, raw := range order {
+			if raw == 1 {
+				arranged[4] = 1
+			} else {
+				arranged[raw-10] = raw
+			}
+		}
+		return true
+	}
+	diffs := func(pattern ...int) bool {
+		for i, d := range pattern {
+			if r[i]-r[0] != d {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch n {
+	case 5:
+		switch {
+		case r[4]-r[0] == 4:
+			arranged = r
+		case aceHigh(1, 10, 11, 12, 13):
+		default:
+			return 0, false
+		}
+	case 4:
+		switch {
+		case aceHigh(1, 11, 12, 13), aceHigh(1, 10, 12, 13), aceHigh(1, 10, 11, 13), aceHigh(1, 10, 11, 12):
+		case diffs(0, 1, 2, 3) && r[3] <= 12:
+			arranged = [5]int{r[0], r[1], r[2], r[3], 0}
+		case diffs(0, 1, 2, 4):
+			arranged = [5]int{r[0], r[1], r[2], 0, r[3]}
+		case diffs(0, 1, 3, 4):
+			arranged = [5]int{r[0], r[1], 0, r[2], r[3]}
+		case diffs(0, 2, 3, 4):
+			arranged = [5]int{r[0], 0, r[1], r[2], r[3]}
+		default:
+			return 0, false
+		}
+	case 3:
+		switch {
+		case aceHigh(1, 12, 13), aceHigh(1, 11, 13), aceHigh(1, 11, 12), aceHigh(1, 10, 13), aceHigh(1, 10, 11):
+		case r[0] == 11 && r[1] == 12 && r[2] == 13:
+			arranged = [5]int{0, 11, 12, 13, 0}
+		case diffs(0, 1, 2) && r[2] <= 11,
+			diffs(0, 2, 3), diffs(0, 1, 3), diffs(0, 2, 4), diffs(0, 3, 4), diffs(0, 1, 4):
+			arranged = [5]int{r[0], r[1], r[2], 0, 0}
+		default:
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	return straightArrangementKey(arranged), true
+}
+
+// straightArrangementKey 按 normalizeStraight 填入变化牌，再按 getStraightComparisonKey 计算键值
+// arranged 为 RawNumber 序列，0 表示变化牌
+func straightArrangementKey(arranged [STRAIGHT_CARD_COUNT]int) int8 {
+	var raws [STRAIGHT_CARD_COUNT]int
+	wild := [STRAIGHT_CARD_COUNT]bool{}
+	for i, raw := range arranged {
+		raws[i] = raw
+		wild[i] = raw == 0
+	}
+
+	// 替换牌由 createReplacementCard 生成：RawNumber 14 会变成 A（RawNumber 1）
+	replace := func(raw int) int {
+		if raw == 14 {
+			return 1
+		}
+		return raw
+	}
+	for i := range raws {
+		if !wild[i] {
+			continue
+		}
+		switch {
+		case i == 0 && wild[1]:
+			raws[i] = replace(raws[2] - 2)
+		case i == 0:
+			raws[i] = replace(raws[1] - 1)
+		default:
+			// 前一张已经是具体的牌
+			raws[i] = replace(raws[i-1] + 1)
+		}
+	}
+	if raws[4] == 1 {
+		positions := [STRAIGHT_CARD_COUNT]int{10, 11, 12, 13, 1}
+		for i := range raws {
+			if wild[i] {
+				raws[i] = positions[i]
+			}
+		}
+	}
+
+	hasAce, hasSmall := false, false
+	minNumber := 0
+	for i, raw := range raws {
+		number := numberOfRaw(raw)
+		if number == 14 {
+			hasAce = true
+		}
+		if number >= 2 && number <= 5 {
+			hasSmall = true
+		}
+		if i == 0 || number < minNumber {
+			minNumber = number
+		}
+	}
+	switch {
+	case hasAce && hasSmall:
+		return 1
+	case hasAce:
+		return 10
+	}
+	return int8(minNumber)
+}
+
+// plateKey 按 plateSatisfy 的规则判断钢板，并按 getPlateComparisonKey 计算键值
+func (h *HandMask) plateKey() (int8, bool) {
+	if h.Size != PLATE_CARD_COUNT || h.Counts[15] > 0 || h.Counts[16] > 0 {
+		return 0, false
+	}
+
+	var first, second int
+	if h.Wild == 0 {
+		var buf [2]rankCount
+		if h.ranks(buf[:]) != 2 || buf[0].count != 3 {
+			return 0, false
+		}
+		first, second = rawOf(buf[0].number), rawOf(buf[1].number)
+		if first > second {
+			first, second = second, first
+		}
+		if first+1 != second && !(first == 1 && second == 13) {
+			return 0, false
+		}
+	} else {
+		// 前五张（变化牌排在最后）需要组成葫芦，再加一张变化牌
+		five := *h
+		five.Size--
+		five.Wild--
+		triple, pair, ok := five.fullHouseRanks()
+		if !ok || triple == 0 || pair == 0 {
+			return 0, false
+		}
+		tripleRaw, pairRaw := rawOf(triple), rawOf(pair)
+		switch {
+		case tripleRaw+1 == pairRaw, tripleRaw == 13 && pairRaw == 1:
+			first, second = tripleRaw, pairRaw
+		case tripleRaw-1 == pairRaw, tripleRaw == 1 && pairRaw == 13:
+			first, second = pairRaw, tripleRaw
+		default:
+			return 0, false
+		}
+	}
+
+	switch {
+	case (first == 1 && second == 2) || (first == 2 && second == 1):
+		return 1, true
+	case (first == 1 && second == 13) || (first == 13 && second == 1):
+		return 15, true
+	}
+	return int8(numberOfRaw(first)), true
+}
+
+// tubeKey 按 tubeSatisfy 的规则判断钢管，并按 normalizeTube 和 getTubeComparisonKey 计算键值
+func (h *HandMask) tubeKey() (int8, bool) {
+	if h.Size != TUBE_CARD_COUNT {
+		return 0, false
+	}
+	var r [TUBE_CARD_COUNT]int
+	n, ok := h.rawSequence(r[:])
+	if !ok {
+		return 0, false
+	}
+	diffs := func(pattern ...int) bool {
+		for i, d := range pattern {
+			if r[i]-r[0] != d {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch h.Wild {
+	case 0:
+		if !diffs(0, 0, 1, 1, 2, 2) {
+			return 0, false
+		}
+	case 1:
+		if !diffs(0, 0, 1, 1, 2) && !diffs(0, 0, 1, 2, 2) && !diffs(0, 1, 1, 2, 2) {
+			return 0, false
+		}
+	case 2:
+		// A、K 加两张变化牌视为循环连续
+		onlyAK := n == 4
+		for i := 0; i < n; i++ {
+			if r[i] != 1 && r[i] != 13 {
+				onlyAK = false
+			}
+		}
+		onlyAK = onlyAK && r[0] == 1 && r[n-1] == 13
+		if !onlyAK && !diffs(0, 0, 1, 1) && !diffs(0, 0, 2, 2) && !diffs(0, 0, 1, 2) &&
+			!diffs(0, 1, 1, 2) && !diffs(0, 1, 2, 2) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	// normalizeTube：变化牌替换成的点数
+	var count [14]int
+	for i := 0; i < n; i++ {
+		count[r[i]]++
+	}
+	var pairs, singles []int
+	var pairBuf, singleBuf [3]int
+	pairs, singles = pairBuf[:0], singleBuf[:0]
+	for raw := 1; raw <= 13; raw++ {
+		switch {
+		case count[raw] >= 2:
+			pairs = append(pairs, raw)
+		case count[raw] == 1:
+			singles = append(singles, raw)
+		}
+	}
+	target := 0
+	switch h.Wild {
+	case 1:
+		if len(singles) > 0 {
+			target = singles[len(singles)-1]
+		}
+	case 2:
+		if len(singles) == 2 {
+			target = singles[1]
+		} else if len(pairs) == 2 {
+			minPair, maxPair := pairs[0], pairs[1]
+			switch {
+			case maxPair-minPair == 2:
+				target = minPair + 1
+			case maxPair-minPair == 1:
+				target = maxPair + 1
+				if target > 13 {
+					target = minPair - 1
+				}
+			case minPair == 1 && maxPair == 13:
+				target = 2
+			default:
+				target = maxPair + 1
+				if target > 13 {
+					target = 1
+				}
+			}
+		}
+	}
+	if target > 0 {
+		count[target]++
+	}
+
+	// getTubeComparisonKey
+	switch {
+	case count[1] > 0 && count[2] > 0 && count[3] > 0 && count[13] == 0:
+		return 1, true
+	case count[1] > 0 && count[13] > 0 && (count[12] > 0 || count[2] > 0):
+		return 15, true
+	}
+	for raw := 1; raw <= 13; raw++ {
+		if count[raw] > 0 {
+			return int8(numberOfRaw(raw)), true
+		}
+	}
+	return int8(h.Level), true
+}
+
+// moveKey 出牌去重用的键
+type moveKey struct {
+	counts [17]uint8
+	wild   uint8
+	typ    CompType
+}
+
+// moveGenerator 在 HandMask 上按牌型枚举候选出牌
+type moveGenerator struct {
+	hand  HandMask
+	lead  MaskComp
+	seen  map[moveKey]bool
+	moves []HandMask
+}
+
+// generate 枚举所有候选出牌
+func (g *moveGenerator) generate() {
+	h := &g.hand
+	var counts [17]uint8
+
+	// 一种点数加若干变化牌：单张、对子、三张和普通炸弹（number 为 0 表示只用变化牌）
+	for number := 0; number <= 16; number++ {
+		if number == 1 || (number > 0 && h.Counts[number] == 0) {
+			continue
+		}
+		minK, maxK := 1, int(h.Counts[number])
+		if number == 0 {
+			minK, maxK = 0, 0
+		}
+		for k := minK; k <= maxK; k++ {
+			for w := 0; w <= int(h.Wild); w++ {
+				if k+w == 0 {
+					continue
+				}
+				counts = [17]uint8{}
+				counts[number] = uint8(k)
+				g.try(counts, w, -1)
+			}
+		}
+	}
+
+	// 王炸
+	if h.Counts[15] >= 2 && h.Counts[16] >= 2 {
+		counts = [17]uint8{}
+		counts[15], counts[16] = 2, 2
+		g.try(counts, 0, -1)
+	}
+
+	// 葫芦：三张部分 i 张、对子部分 j 张，其余用变化牌补齐
+	for triple := 2; triple <= 16; triple++ {
+		for pair := 2; pair <= 16; pair++ {
+			if triple == pair || h.Counts[triple] == 0 || h.Counts[pair] == 0 {
+				continue
+			}
+			for i := 1; i <= 3 && i <= int(h.Counts[triple]); i++ {
+				for j := 1; j <= 2 && j <= int(h.Counts[pair]); j++ {
+					w := FULL_HOUSE_CARD_COUNT - i - j
+					if w < 0 || w > int(h.Wild) {
+						continue
+					}
+					counts = [17]uint8{}
+					counts[triple], counts[pair] = uint8(i), uint8(j)
+					g.try(counts, w, -1)
+				}
+			}
+		}
+	}
+
+	// 顺子和同花顺：每个五连点数窗口中任选不超过变化牌数量的位置用变化牌代替
+	for start := 1; start <= 10; start++ {
+		for sub := 0; sub < 1<<STRAIGHT_CARD_COUNT; sub++ {
+			w := 0
+			ok := true
+			counts = [17]uint8{}
+			for i := 0; i < STRAIGHT_CARD_COUNT; i++ {
+				if sub&(1<<i) != 0 {
+					w++
+					continue
+				}
+				number := numberOfRaw(start + i)
+				if h.Counts[number] == 0 {
+					ok = false
+					break
+				}
+				counts[number] = 1
+			}
+			if !ok || w > int(h.Wild) {
+				continue
+			}
+			g.try(counts, w, -1)
+			for suit := range h.Suits {
+				g.try(counts, w, suit)
+			}
+		}
+	}
+
+	// 钢板和钢管：三连点数窗口（包括 Q-K-A 和 K-A-2）中各取若干张，其余用变化牌补齐
+	for start := 1; start <= 13; start++ {
+		var window [3]int
+		for i := range window {
+			window[i] = numberOfRaw((start+i-1)%13 + 1)
+		}
+		for a := 0; a <= int(h.Counts[window[0]]) && a <= 4; a++ {
+			for b := 0; b <= int(h.Counts[window[1]]) && b <= 4; b++ {
+				for c := 0; c <= int(h.Counts[window[2]]) && c <= 4; c++ {
+					w := PLATE_CARD_COUNT - a - b - c
+					if w < 0 || w > int(h.Wild) {
+						continue
+					}
+					counts = [17]uint8{}
+					counts[window[0]], counts[window[1]], counts[window[2]] = uint8(a), uint8(b), uint8(c)
+					g.try(counts, w, -1)
+				}
+			}
+		}
+	}
+}
+
+// try 从手牌中取出指定点数的牌，判断牌型并记录能压过 lead 的出牌
+// suit 为 -1 时尽量使用不同花色，否则只使用该花色
+func (g *moveGenerator) try(counts [17]uint8, wild int, suit int) {
+	h := &g.hand
+	move := HandMask{Counts: counts, Wild: uint8(wild), Level: h.Level}
+	move.Size = uint8(wild)
+	for number := 2; number <= 16; number++ {
+		need := int(counts[number])
+		if need == 0 {
+			continue
+		}
+		move.Size += uint8(need)
+		if number >= 15 {
+			continue
+		}
+		if suit >= 0 {
+			if h.suitCount(suit, number) < need {
+				return
+			}
+			move.Suits[suit] += uint32(need) << (2 * uint(number))
+			continue
+		}
+		for s := range h.Suits {
+			take := h.suitCount(s, number)
+			if take > need {
+				take = need
+			}
+			move.Suits[s] += uint32(take) << (2 * uint(number))
+			need -= take
+		}
+	}
+	if suit < 0 && move.Size-move.Wild > 1 && move.isFlush() {
+		move.diversify(h)
+	}
+
+	comp := move.Classify(g.lead.Type)
+	if !comp.IsValid() || comp.Type == TypeFold {
+		return
+	}
+	if g.lead.Type != TypeFold && !comp.GreaterThan(g.lead) {
+		return
+	}
+	key := moveKey{counts: move.Counts, wild: move.Wild, typ: comp.Type}
+	if g.seen[key] {
+		return
+	}
+	g.seen[key] = true
+	g.moves = append(g.moves, move)
+}
+
+// diversify 把同一花色的出牌换出一张其他花色的牌（手牌允许时），避免误成同花顺
+func (h *HandMask) diversify(hand *HandMask) {
+	flush := 0
+	for s, bits := range h.Suits {
+		if bits != 0 {
+			flush = s
+		}
+	}
+	for number := 2; number <= 14; number++ {
+		if h.suitCount(flush, number) == 0 {
+			continue
+		}
+		for s := range hand.Suits {
+			if s == flush || hand.suitCount(s, number) <= h.suitCount(s, number) {
+				continue
+			}
+			h.Suits[flush] -= 1 << (2 * uint(number))
+			h.Suits[s] += 1 << (2 * uint(number))
+			return
+		}
+	}
+}
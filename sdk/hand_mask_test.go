@@ -0,0 +1,359 @@
+package sdk
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// maskShapeCards 按点数计数生成具体的牌，flush 为 true 时非变化牌尽量使用同一花色
+func maskShapeCards(counts [17]uint8, wild, level int, flush bool, rotate int) []*Card {
+	cards := make([]*Card, 0, 8)
+	suit := rotate
+	for number := 2; number <= 16; number++ {
+		for i := 0; i < int(counts[number]); i++ {
+			color := "Joker"
+			if number <= 14 {
+				if flush {
+					color = "Spade"
+				} else {
+					color = Colors[suit%4]
+					suit++
+					if number == level && color == "Heart" {
+						color = Colors[suit%4]
+						suit++
+					}
+				}
+			}
+			card, _ := NewCard(number, color, level)
+			cards = append(cards, card)
+		}
+	}
+	for i := 0; i < wild; i++ {
+		card, _ := NewCard(level, "Heart", level)
+		cards = append(cards, card)
+	}
+	return cards
+}
+
+// forEachMaskShape 枚举 size 张牌的所有点数组合（两副牌范围内）
+func forEachMaskShape(size int, fn func(counts [17]uint8, wild int)) {
+	var counts [17]uint8
+	var walk func(number, left int)
+	walk = func(number, left int) {
+		if number > 16 {
+			if left <= 2 {
+				fn(counts, left)
+			}
+			return
+		}
+		limit := 8
+		if number >= 15 {
+			limit = 2
+		}
+		for k := 0; k <= left && k <= limit; k++ {
+			counts[number] = uint8(k)
+			walk(number+1, left-k)
+		}
+		counts[number] = 0
+	}
+	walk(2, size)
+}
+
+func shortCards(cards []*Card) string {
+	names := make([]string, len(cards))
+	for i, card := range cards {
+		names[i] = card.ToShortString()
+	}
+	return strings.Join(names, " ")
+}
+
+func maskPrevComps(level int) []CardComp {
+	parse := func(notation string) CardComp {
+		var cards ConformanceCards
+		if err := cards.UnmarshalJSON([]byte(fmt.Sprintf("%q", notation))); err != nil {
+			panic(err)
+		}
+		list, err := cards.Cards(level)
+		if err != nil {
+			panic(err)
+		}
+		return FromCardList(list, nil)
+	}
+	return []CardComp{nil, parse("3S 3C 3D 4S 4C 4D"), parse("3S 4C 5D 6S 7C")}
+}
+
+func TestHandMaskClassifyMatchesFromCardList(t *testing.T) {
+	levels := []int{2, 5, 10, 13, 14}
+	if testing.Short() {
+		levels = []int{2, 14}
+	}
+	for _, level := range levels {
+		prevs := maskPrevComps(level)
+		checked := 0
+		for size := 0; size <= 7; size++ {
+			forEachMaskShape(size, func(counts [17]uint8, wild int) {
+				if counts[level] > 6 {
+					return
+				}
+				variants := [][]*Card{maskShapeCards(counts, wild, level, false, size)}
+				if size == 5 {
+					variants = append(variants, maskShapeCards(counts, wild, level, true, 0))
+				}
+				for _, cards := range variants {
+					mask := NewHandMask(cards)
+					for _, prev := range prevs {
+						prevType := TypeFold
+						if prev != nil {
+							prevType = prev.GetType()
+						}
+						want := FromCardList(cards, prev).GetType()
+						if got := mask.Classify(prevType).Type; got != want {
+							t.Fatalf("level %d prev %v %s: got %v, want %v", level, prevType, shortCards(cards), got, want)
+						}
+						checked++
+					}
+				}
+			})
+		}
+		t.Logf("level %d: %d shapes checked", level, checked)
+	}
+}
+
+func TestHandMaskGreaterThanMatchesCardComp(t *testing.T) {
+	type sample struct {
+		comp CardComp
+		mask MaskComp
+	}
+	check := func(level int, a, b sample) {
+		if got, want := a.mask.GreaterThan(b.mask), a.comp.GreaterThan(b.comp); got != want {
+			t.Fatalf("level %d: %v > %v: got %v, want %v", level, a.comp, b.comp, got, want)
+		}
+	}
+
+	levels := []int{2, 7, 13, 14}
+	if testing.Short() {
+		levels = []int{2, 14}
+	}
+	for _, level := range levels {
+		// 所有有效牌组按牌型分组，同类牌型两两比较，炸弹与其他牌型比较
+		groups := make(map[CompType][]sample)
+		add := func(cards []*Card) {
+			if comp := FromCardList(cards, nil); comp.IsValid() {
+				groups[comp.GetType()] = append(groups[comp.GetType()], sample{comp, NewHandMask(cards).Classify(TypeFold)})
+			}
+		}
+		for size := 1; size <= 6; size++ {
+			forEachMaskShape(size, func(counts [17]uint8, wild int) {
+				if counts[level] > 6 {
+					return
+				}
+				add(maskShapeCards(counts, wild, level, false, size))
+				if size == 5 {
+					add(maskShapeCards(counts, wild, level, true, 0))
+				}
+			})
+		}
+		// 更大的炸弹
+		for number := 2; number <= 14; number++ {
+			for size := 7; size <= 10; size++ {
+				var counts [17]uint8
+				counts[number] = uint8(size - 2)
+				add(maskShapeCards(counts, 2, level, false, 0))
+			}
+		}
+		// 炸弹与非炸弹的比较与键值无关，每种非炸弹牌型取几个代表即可
+		var others, bombs []sample
+		total := 0
+		for _, group := range groups {
+			total += len(group)
+			if group[0].comp.IsBomb() {
+				bombs = append(bombs, group...)
+				others = append(others, group...)
+			} else if len(group) > 3 {
+				others = append(others, group[:3]...)
+			} else {
+				others = append(others, group...)
+			}
+		}
+		for compType, group := range groups {
+			if compType == TypeNaiveBomb || compType == TypeStraightFlush || compType == TypeJokerBomb {
+				continue
+			}
+			for _, a := range group {
+				for _, b := range group {
+					check(level, a, b)
+				}
+			}
+		}
+		for _, bomb := range bombs {
+			for _, other := range others {
+				check(level, bomb, other)
+				check(level, other, bomb)
+			}
+		}
+		t.Logf("level %d: %d valid shapes, %d bombs", level, total, len(bombs))
+	}
+}
+
+// maskTestHand 从两副牌中抽取集中在几个相邻点数上的手牌，便于组成各种牌型
+func maskTestHand(rng *rand.Rand, level, size int) []*Card {
+	dealer, _ := NewDealer(level)
+	start := rng.Intn(13) + 1
+	var pool []*Card
+	for _, card := range dealer.CreateFullDeck() {
+		offset := (card.RawNumber - start + 13) % 13
+		if card.Color == "Joker" || card.IsWildcard() || offset < 5 {
+			pool = append(pool, card)
+		}
+	}
+	rng.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+	return pool[:size]
+}
+
+func TestHandMaskLegalMovesMatchesBruteForce(t *testing.T) {
+	type playKey struct {
+		counts [17]uint8
+		wild   uint8
+		typ    CompType
+	}
+	rounds := 300
+	if testing.Short() {
+		rounds = 40
+	}
+	rng := rand.New(rand.NewSource(42))
+	for round := 0; round < rounds; round++ {
+		level := rng.Intn(13) + 2
+		hand := maskTestHand(rng, level, 10)
+
+		// 领先牌组取自另一手牌中的随机有效牌组，也包括首出
+		var lead CardComp
+		if round%4 != 0 {
+			other := maskTestHand(rng, level, 8)
+			for lead == nil || !lead.IsValid() {
+				var cards []*Card
+				for _, card := range other {
+					if rng.Intn(2) == 0 {
+						cards = append(cards, card)
+					}
+				}
+				if len(cards) > 0 {
+					lead = FromCardList(cards, nil)
+				}
+			}
+		}
+
+		want := make(map[playKey]bool)
+		for subset := 1; subset < 1<<len(hand); subset++ {
+			var cards []*Card
+			for i, card := range hand {
+				if subset&(1<<i) != 0 {
+					cards = append(cards, card)
+				}
+			}
+			comp := FromCardList(cards, lead)
+			if !comp.IsValid() || (lead != nil && !comp.GreaterThan(lead)) {
+				continue
+			}
+			mask := NewHandMask(cards)
+			want[playKey{mask.Counts, mask.Wild, comp.GetType()}] = true
+		}
+
+		leadMask := MaskCompOf(lead)
+		got := make(map[playKey]bool)
+		handMask := NewHandMask(hand)
+		for _, move := range handMask.LegalMoves(leadMask) {
+			if !handMask.Contains(move) {
+				t.Fatalf("round %d: move %+v is not part of hand %s", round, move, shortCards(hand))
+			}
+			cards := PickCards(hand, move)
+			comp := FromCardList(cards, lead)
+			key := playKey{move.Counts, move.Wild, comp.GetType()}
+			if len(cards) != move.Len() || !want[key] {
+				t.Fatalf("round %d: hand %s lead %v: move %s (%v) is not legal", round, shortCards(hand), lead, shortCards(cards), comp.GetType())
+			}
+			got[key] = true
+		}
+		for key := range want {
+			if !got[key] {
+				t.Fatalf("round %d: hand %s lead %v: missing move %+v", round, shortCards(hand), lead, key)
+			}
+		}
+	}
+}
+
+func TestHandMaskSub(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	hand := maskTestHand(rng, 5, 12)
+	mask := NewHandMask(hand)
+	rest := mask
+	for _, move := range mask.LegalMoves(MaskComp{Type: TypeFold}) {
+		if !rest.Contains(move) {
+			continue
+		}
+		rest = rest.Sub(move)
+	}
+	if rest.Len() >= mask.Len() {
+		t.Fatalf("expected Sub to remove cards, %d -> %d", mask.Len(), rest.Len())
+	}
+	used := make(map[*Card]bool)
+	for _, card := range PickCards(hand, mask.Sub(rest)) {
+		used[card] = true
+	}
+	var remaining []*Card
+	for _, card := range hand {
+		if !used[card] {
+			remaining = append(remaining, card)
+		}
+	}
+	if NewHandMask(remaining) != rest {
+		t.Errorf("Sub does not match remaining cards: %+v vs %+v", NewHandMask(remaining), rest)
+	}
+}
+
+// maskBenchCombos 基准测试用的牌组：各种牌型的有效牌组和一部分无效牌组
+func maskBenchCombos() [][]*Card {
+	rng := rand.New(rand.NewSource(9))
+	var combos [][]*Card
+	for len(combos) < 512 {
+		hand := maskTestHand(rng, rng.Intn(13)+2, 6)
+		cards := hand[:rng.Intn(6)+1]
+		if FromCardList(cards, nil).IsValid() || len(combos)%4 == 0 {
+			combos = append(combos, cards)
+		}
+	}
+	return combos
+}
+
+func BenchmarkFromCardList(b *testing.B) {
+	combos := maskBenchCombos()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a := FromCardList(combos[i%len(combos)], nil)
+		c := FromCardList(combos[(i+1)%len(combos)], nil)
+		a.GreaterThan(c)
+	}
+}
+
+func BenchmarkHandMaskClassify(b *testing.B) {
+	combos := maskBenchCombos()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a := NewHandMask(combos[i%len(combos)]).Classify(TypeFold)
+		c := NewHandMask(combos[(i+1)%len(combos)]).Classify(TypeFold)
+		a.GreaterThan(c)
+	}
+}
+
+func BenchmarkHandMaskLegalMoves(b *testing.B) {
+	rng := rand.New(rand.NewSource(5))
+	dealer, _ := NewDealer(2)
+	deck := dealer.CreateFullDeck()
+	rng.Shuffle(len(deck), func(i, j int) { deck[i], deck[j] = deck[j], deck[i] })
+	hand := NewHandMask(deck[:27])
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hand.LegalMoves(MaskComp{Type: TypeFold})
+	}
+}
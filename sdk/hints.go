@@ -0,0 +1,142 @@
+package sdk
+
+import "sort"
+
+// Hint 一条出牌提示
+type Hint struct {
+	Cards          []*Card  `json:"cards"`           // 建议打出的牌
+	Type           CompType `json:"type"`            // 牌型
+	IsBomb         bool     `json:"is_bomb"`         // 是否为炸弹（王炸、普通炸弹、同花顺）
+	BreaksBomb     bool     `json:"breaks_bomb"`     // 是否拆散了手中的炸弹
+	BreaksStraight bool     `json:"breaks_straight"` // 是否拆散了手中的顺子
+	UsesWildcard   bool     `json:"uses_wildcard"`   // 是否用到了变化牌
+}
+
+// HintResult 某个玩家当前回合的出牌提示
+type HintResult struct {
+	PlayerSeat   int     `json:"player_seat"`    // 玩家座位号
+	IsLeader     bool    `json:"is_leader"`      // 是否为首出（首出时必须出牌）
+	HasLegalPlay bool    `json:"has_legal_play"` // 是否有能出的牌，false 表示只能过牌
+	Hints        []*Hint `json:"hints"`          // 按推荐顺序排列的提示
+}
+
+// SuggestPlays 列出手牌中所有能压过 lead 的出牌，按推荐顺序排列
+// 参数:
+//
+//	hand: 玩家手牌
+//	lead: 当前领先的牌组，nil 表示首出
+//
+// 返回值:
+//
+//	[]*Hint: 出牌提示；没有能出的牌时返回空切片
+//
+// 功能说明:
+//   - 先是非炸弹，再是炸弹；不拆炸弹、不拆顺子、不用变化牌的出牌排在前面
+//   - 同一类出牌中从刚好够大的开始，由小到大排列
+//   - 每种点数组合和牌型只给出一次，出牌结果与 PlayCards 的判断一致
+func SuggestPlays(hand []*Card, lead CardComp) []*Hint {
+	type ranked struct {
+		hint    *Hint
+		comp    MaskComp
+		penalty int
+	}
+
+	leadMask := MaskCompOf(lead)
+	mask := NewHandMask(hand)
+	straights := mask.naturalStraights()
+
+	var candidates []ranked
+	for _, move := range mask.LegalMoves(leadMask) {
+		comp := move.Classify(leadMask.Type)
+		rest := mask.Sub(move)
+		hint := &Hint{
+			Cards:        PickCards(hand, move),
+			Type:         comp.Type,
+			IsBomb:       comp.IsBomb(),
+			UsesWildcard: move.Wild > 0,
+		}
+		if !hint.IsBomb {
+			hint.BreaksBomb = mask.breaksBomb(move)
+		}
+		if comp.Type != TypeStraight && comp.Type != TypeStraightFlush {
+			hint.BreaksStraight = straights&^rest.naturalStraights() != 0
+		}
+
+		penalty := 0
+		if hint.BreaksBomb {
+			penalty += 4
+		}
+		if hint.BreaksStraight {
+			penalty += 2
+		}
+		if hint.UsesWildcard {
+			penalty++
+		}
+		candidates = append(candidates, ranked{hint, comp, penalty})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.hint.IsBomb != b.hint.IsBomb {
+			return !a.hint.IsBomb
+		}
+		if a.hint.IsBomb {
+			// 炸弹从小到大
+			return b.comp.GreaterThan(a.comp)
+		}
+		if a.penalty != b.penalty {
+			return a.penalty < b.penalty
+		}
+		if a.comp.Key != b.comp.Key {
+			return a.comp.Key < b.comp.Key
+		}
+		if a.comp.Size != b.comp.Size {
+			return a.comp.Size < b.comp.Size
+		}
+		return a.comp.Type < b.comp.Type
+	})
+
+	hints := make([]*Hint, len(candidates))
+	for i, candidate := range candidates {
+		hints[i] = candidate.hint
+	}
+	return hints
+}
+
+// naturalStraights 不用变化牌就能组成的顺子，第 i 位表示以原始点数 i 开头（A 可作 1 或 14）
+func (h HandMask) naturalStraights() uint16 {
+	var has [15]bool
+	for raw := 1; raw <= 14; raw++ {
+		number := raw
+		if raw == 1 {
+			number = 14
+		}
+		has[raw] = h.Counts[number] > 0
+	}
+
+	var starts uint16
+	for start := 1; start <= 10; start++ {
+		ok := true
+		for raw := start; raw < start+STRAIGHT_CARD_COUNT; raw++ {
+			if !has[raw] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			starts |= 1 << uint(start)
+		}
+	}
+	return starts
+}
+
+// breaksBomb 打出 move 后是否拆散了手中的炸弹（包括王炸）
+func (h HandMask) breaksBomb(move HandMask) bool {
+	for number := 2; number <= 14; number++ {
+		if h.Counts[number] >= 4 && move.Counts[number] > 0 && h.Counts[number]-move.Counts[number] < 4 {
+			return true
+		}
+	}
+	jokers := h.Counts[15] + h.Counts[16]
+	return jokers == 4 && move.Counts[15]+move.Counts[16] > 0
+}
@@ -0,0 +1,138 @@
+package sdk
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestSuggestPlaysOrdering(t *testing.T) {
+	hand := parseShortCards(t, "3S 4C 5D 6S 7H 9S 9C 9D 9D KD", 2)
+	lead := FromCardList(parseShortCards(t, "5C", 2), nil)
+
+	hints := SuggestPlays(hand, lead)
+	if len(hints) == 0 {
+		t.Fatal("expected hints")
+	}
+
+	var order []string
+	for _, hint := range hints {
+		order = append(order, shortCards(hint.Cards))
+	}
+	// K 不拆任何牌型排在最前；6、7 拆顺子；9 拆炸弹；炸弹排在最后
+	want := []string{"KD", "6S", "7H", "9S", "9S 9C 9D 9D"}
+	if len(order) != len(want) {
+		t.Fatalf("got hints %v, want %v", order, want)
+	}
+	for i := range want {
+		if NewHandMask(hints[i].Cards).Counts != NewHandMask(parseShortCards(t, want[i], 2)).Counts {
+			t.Fatalf("got hints %v, want %v", order, want)
+		}
+	}
+
+	if hints[0].BreaksStraight || hints[0].BreaksBomb {
+		t.Errorf("KD should not break anything: %+v", hints[0])
+	}
+	if !hints[1].BreaksStraight || hints[1].BreaksBomb {
+		t.Errorf("6S should break the straight: %+v", hints[1])
+	}
+	if !hints[3].BreaksBomb {
+		t.Errorf("a single 9 should break the bomb: %+v", hints[3])
+	}
+	if last := hints[len(hints)-1]; !last.IsBomb || last.Type != TypeNaiveBomb || last.BreaksBomb {
+		t.Errorf("expected the bomb last: %+v", last)
+	}
+}
+
+func TestSuggestPlaysNoLegalPlay(t *testing.T) {
+	hand := parseShortCards(t, "3S 4D", 2)
+	lead := FromCardList(parseShortCards(t, "AS", 2), nil)
+	if hints := SuggestPlays(hand, lead); len(hints) != 0 {
+		t.Errorf("expected no hints, got %d", len(hints))
+	}
+}
+
+func TestSuggestPlaysAreLegal(t *testing.T) {
+	rng := rand.New(rand.NewSource(21))
+	rounds := 200
+	if testing.Short() {
+		rounds = 30
+	}
+	for round := 0; round < rounds; round++ {
+		level := rng.Intn(13) + 2
+		hand := maskTestHand(rng, level, 12)
+
+		var lead CardComp
+		if round%3 != 0 {
+			other := maskTestHand(rng, level, 8)
+			for lead == nil || !lead.IsValid() {
+				var cards []*Card
+				for _, card := range other {
+					if rng.Intn(2) == 0 {
+						cards = append(cards, card)
+					}
+				}
+				if len(cards) > 0 {
+					lead = FromCardList(cards, nil)
+				}
+			}
+		}
+
+		hints := SuggestPlays(hand, lead)
+		if want := len(NewHandMask(hand).LegalMoves(MaskCompOf(lead))); len(hints) != want {
+			t.Fatalf("round %d: got %d hints, want %d", round, len(hints), want)
+		}
+		seenBomb := false
+		for _, hint := range hints {
+			comp := FromCardList(hint.Cards, lead)
+			if !comp.IsValid() || comp.GetType() != hint.Type || (lead != nil && !comp.GreaterThan(lead)) {
+				t.Fatalf("round %d: hand %s lead %v: hint %s (%v) is not legal", round, shortCards(hand), lead, shortCards(hint.Cards), hint.Type)
+			}
+			if hint.IsBomb != comp.IsBomb() {
+				t.Fatalf("round %d: hint %s bomb flag mismatch", round, shortCards(hint.Cards))
+			}
+			if seenBomb && !hint.IsBomb {
+				t.Fatalf("round %d: non-bomb hint %s after a bomb", round, shortCards(hint.Cards))
+			}
+			seenBomb = seenBomb || hint.IsBomb
+		}
+	}
+}
+
+func TestGameEngineGetHints(t *testing.T) {
+	engine := NewGameEngine()
+	if _, err := engine.GetHints(0); err == nil {
+		t.Error("expected an error without an active deal")
+	}
+
+	engine.SetRandomSeed(7)
+	players := make([]Player, 4)
+	for seat := range players {
+		players[seat] = Player{ID: fmt.Sprintf("hint_%d", seat), Username: fmt.Sprintf("Hint%d", seat), Seat: seat}
+	}
+	if err := engine.StartMatch(players); err != nil {
+		t.Fatalf("StartMatch: %v", err)
+	}
+	if err := engine.StartDeal(); err != nil {
+		t.Fatalf("StartDeal: %v", err)
+	}
+
+	turn := engine.GetCurrentTurnInfo()
+	if turn == nil || !turn.HasActiveTrick {
+		t.Fatalf("expected an active trick, got %+v", turn)
+	}
+	if _, err := engine.GetHints((turn.CurrentPlayer + 1) % 4); err == nil {
+		t.Error("expected an error for a player who is not on turn")
+	}
+
+	result, err := engine.GetHints(turn.CurrentPlayer)
+	if err != nil {
+		t.Fatalf("GetHints: %v", err)
+	}
+	if !result.IsLeader || !result.HasLegalPlay || len(result.Hints) == 0 {
+		t.Fatalf("a leader always has legal plays: %+v", result)
+	}
+	if _, err := engine.PlayCards(turn.CurrentPlayer, result.Hints[0].Cards); err != nil {
+		t.Errorf("the first hint should be playable: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// SeatInputRouter 按座位分发输入请求的 PlayerInputProvider
+// GameDriver 只持有一个输入提供者，当不同座位的输入来源不同（本地算法、外部进程、远程服务、真人）时，
+// 用它把每个座位的请求转发给对应的提供者
+type SeatInputRouter struct {
+	mutex     sync.RWMutex
+	providers map[int]PlayerInputProvider
+	fallback  PlayerInputProvider
+}
+
+// NewSeatInputRouter 创建座位路由输入提供者
+// 参数:
+//
+//	fallback: 未单独指定提供者的座位使用的默认提供者（可以为nil）
+func NewSeatInputRouter(fallback PlayerInputProvider) *SeatInputRouter {
+	return &SeatInputRouter{
+		providers: make(map[int]PlayerInputProvider),
+		fallback:  fallback,
+	}
+}
+
+// SetSeatProvider 为指定座位设置输入提供者，provider为nil时恢复使用默认提供者
+func (r *SeatInputRouter) SetSeatProvider(playerSeat int, provider PlayerInputProvider) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if provider == nil {
+		delete(r.providers, playerSeat)
+		return
+	}
+	r.providers[playerSeat] = provider
+}
+
+// GetSeatProvider 获取指定座位实际使用的输入提供者
+func (r *SeatInputRouter) GetSeatProvider(playerSeat int) PlayerInputProvider {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if provider, exists := r.providers[playerSeat]; exists {
+		return provider
+	}
+	return r.fallback
+}
+
+func (r *SeatInputRouter) providerFor(playerSeat int) (PlayerInputProvider, error) {
+	provider := r.GetSeatProvider(playerSeat)
+	if provider == nil {
+		return nil, fmt.Errorf("no input provider for seat %d", playerSeat)
+	}
+	return provider, nil
+}
+
+// RequestPlayDecision 实现PlayerInputProvider接口
+func (r *SeatInputRouter) RequestPlayDecision(ctx context.Context, playerSeat int, hand []*Card, trickInfo *TrickInfo) (*PlayDecision, error) {
+	provider, err := r.providerFor(playerSeat)
+	if err != nil {
+		return nil, err
+	}
+	return provider.RequestPlayDecision(ctx, playerSeat, hand, trickInfo)
+}
+
+// RequestTributeSelection 实现PlayerInputProvider接口
+func (r *SeatInputRouter) RequestTributeSelection(ctx context.Context, playerSeat int, options []*Card) (*Card, error) {
+	provider, err := r.providerFor(playerSeat)
+	if err != nil {
+		return nil, err
+	}
+	return provider.RequestTributeSelection(ctx, playerSeat, options)
+}
+
+// RequestReturnTribute 实现PlayerInputProvider接口
+func (r *SeatInputRouter) RequestReturnTribute(ctx context.Context, playerSeat int, hand []*Card) (*Card, error) {
+	provider, err := r.providerFor(playerSeat)
+	if err != nil {
+		return nil, err
+	}
+	return provider.RequestReturnTribute(ctx, playerSeat, hand)
+}
@@ -0,0 +1,62 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+)
+
+// seatRecordingProvider 记录收到请求的测试输入提供者
+type seatRecordingProvider struct {
+	name  string
+	calls []int
+}
+
+func (p *seatRecordingProvider) RequestPlayDecision(ctx context.Context, playerSeat int, hand []*Card, trickInfo *TrickInfo) (*PlayDecision, error) {
+	p.calls = append(p.calls, playerSeat)
+	return &PlayDecision{Action: ActionPass}, nil
+}
+
+func (p *seatRecordingProvider) RequestTributeSelection(ctx context.Context, playerSeat int, options []*Card) (*Card, error) {
+	p.calls = append(p.calls, playerSeat)
+	return options[0], nil
+}
+
+func (p *seatRecordingProvider) RequestReturnTribute(ctx context.Context, playerSeat int, hand []*Card) (*Card, error) {
+	p.calls = append(p.calls, playerSeat)
+	return hand[0], nil
+}
+
+func TestSeatInputRouter(t *testing.T) {
+	fallback := &seatRecordingProvider{name: "fallback"}
+	bot := &seatRecordingProvider{name: "bot"}
+
+	router := NewSeatInputRouter(fallback)
+	router.SetSeatProvider(1, bot)
+
+	card, _ := NewCard(5, "Spade", 2)
+	for seat := 0; seat < 4; seat++ {
+		if _, err := router.RequestPlayDecision(context.Background(), seat, []*Card{card}, &TrickInfo{}); err != nil {
+			t.Fatalf("RequestPlayDecision failed: %v", err)
+		}
+	}
+	if _, err := router.RequestReturnTribute(context.Background(), 1, []*Card{card}); err != nil {
+		t.Fatalf("RequestReturnTribute failed: %v", err)
+	}
+
+	if len(bot.calls) != 2 || bot.calls[0] != 1 {
+		t.Errorf("seat 1 requests should go to the bot, got %v", bot.calls)
+	}
+	if len(fallback.calls) != 3 {
+		t.Errorf("other seats should use the fallback, got %v", fallback.calls)
+	}
+
+	router.SetSeatProvider(1, nil)
+	if router.GetSeatProvider(1) != fallback {
+		t.Error("clearing a seat provider should restore the fallback")
+	}
+
+	empty := NewSeatInputRouter(nil)
+	if _, err := empty.RequestPlayDecision(context.Background(), 0, nil, &TrickInfo{}); err == nil {
+		t.Error("expected error when no provider is configured")
+	}
+}
@@ -0,0 +1,211 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Interpretation 一组含变化牌的牌在某种代入方式下构成的牌组
+//
+// FromCardList 遇到变化牌时只会按 prev 的提示选择一种解释，而同一组牌可能既是顺子又是同花顺，
+// 或者是三张不同的葫芦。InterpretCards 列出所有可能的解释，出牌时通过声明 Substitutes 选择其中一种。
+type Interpretation struct {
+	Comp        CardComp // 按该解释构成的牌组，GetCards 仍返回原始牌
+	Substitutes []*Card  // 每张变化牌代表的具体牌，顺序与原始牌中变化牌的顺序一致；nil 表示按 FromCardList 自动判断
+}
+
+// InterpretCards 列出一组牌所有不同的牌型解释
+// 参数:
+//
+//	cards: 要出的牌（可以包含变化牌）
+//	prev: 当前领先的牌组，仅用于确定 FromCardList 的默认解释，可以为 nil
+//
+// 返回值:
+//
+//	[]*Interpretation: 牌型或大小各不相同的解释；无法构成有效牌组时返回 nil
+//
+// 功能说明:
+//   - 第一个总是 FromCardList(cards, prev) 的默认解释（如果有效），其余按牌型、从大到小排列
+//   - 变化牌可以代入除王和红桃级牌以外的任意牌；单张变化牌只能作为级牌
+//   - 不含变化牌时最多只有一种解释
+func InterpretCards(cards []*Card, prev CardComp) []*Interpretation {
+	var result []*Interpretation
+	defaultComp := FromCardList(cards, prev)
+	defaultMask := MaskCompOf(defaultComp)
+	wild := countWildcards(cards)
+	if wild == 0 || len(cards) == 1 {
+		if defaultComp.IsValid() && defaultComp.GetType() != TypeFold {
+			result = append(result, &Interpretation{Comp: defaultComp})
+		}
+		return result
+	}
+
+	base := NewHandMask(getNormalCards(cards))
+	base.Level = uint8(cards[0].Level)
+	candidates := substituteCandidates(cards[0].Level)
+
+	seen := make(map[MaskComp][]*Card)
+	var order []MaskComp
+	try := func(substitutes ...*Card) {
+		mask := base
+		for _, card := range substitutes {
+			// 一种花色和点数最多两张，更多的代入与换一种花色等价
+			if mask.suitCount(suitIndex(card.Color), card.Number) >= 2 {
+				return
+			}
+			mask.Add(card)
+		}
+		comp := mask.Classify(TypeFold)
+		if !comp.IsValid() {
+			return
+		}
+		if _, ok := seen[comp]; !ok {
+			seen[comp] = substitutes
+			order = append(order, comp)
+		}
+	}
+	for i, first := range candidates {
+		if wild == 1 {
+			try(first)
+			continue
+		}
+		for _, second := range candidates[i:] {
+			try(first, second)
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].Type != order[j].Type {
+			return order[i].Type < order[j].Type
+		}
+		return order[i].Key > order[j].Key
+	})
+
+	if defaultComp.IsValid() {
+		if substitutes, ok := seen[defaultMask]; ok {
+			comp, _ := DeclareComp(cards, substitutes)
+			result = append(result, &Interpretation{Comp: comp, Substitutes: baseCompOf(comp).Substitutes})
+		} else {
+			result = append(result, &Interpretation{Comp: defaultComp})
+		}
+	}
+	for _, mask := range order {
+		if defaultComp.IsValid() && mask == defaultMask {
+			continue
+		}
+		comp, err := DeclareComp(cards, seen[mask])
+		if err != nil {
+			continue
+		}
+		result = append(result, &Interpretation{Comp: comp, Substitutes: baseCompOf(comp).Substitutes})
+	}
+	return result
+}
+
+// DeclareComp 按声明的变化牌代入方式构成牌组
+// 参数:
+//
+//	cards: 要出的牌（可以包含变化牌）
+//	substitutes: 每张变化牌代表的具体牌，顺序与 cards 中变化牌的顺序一致；为空时等同于 FromCardList(cards, nil)
+//
+// 返回值:
+//
+//	CardComp: 有效的牌组，GetCards 返回原始牌，比较时使用代入后的牌
+//	error: 声明的张数不符、代入了王或红桃级牌、或代入后不是有效牌组时返回错误
+func DeclareComp(cards []*Card, substitutes []*Card) (CardComp, error) {
+	if len(substitutes) == 0 {
+		comp := FromCardList(cards, nil)
+		if !comp.IsValid() {
+			return nil, errors.New("invalid card combination")
+		}
+		return comp, nil
+	}
+
+	if wild := countWildcards(cards); len(substitutes) != wild {
+		return nil, fmt.Errorf("declared %d substitutes for %d wildcards", len(substitutes), wild)
+	}
+	if len(cards) == 1 {
+		return nil, errors.New("a single wildcard cannot be declared as another card")
+	}
+
+	level := cards[0].Level
+	concrete := make([]*Card, len(cards))
+	declared := make([]*Card, 0, len(substitutes))
+	for i, card := range cards {
+		if !card.IsWildcard() {
+			concrete[i] = card
+			continue
+		}
+		substitute := substitutes[len(declared)]
+		if substitute == nil || substitute.Number < 2 || substitute.Number > 14 {
+			return nil, fmt.Errorf("wildcard cannot substitute %v", substitute)
+		}
+		clone, err := NewCard(substitute.Number, substitute.Color, level)
+		if err != nil {
+			return nil, fmt.Errorf("wildcard cannot substitute %v: %w", substitute, err)
+		}
+		if clone.IsWildcard() {
+			return nil, fmt.Errorf("wildcard cannot substitute another wildcard %v", substitute)
+		}
+		concrete[i] = clone
+		declared = append(declared, clone)
+	}
+
+	comp := FromCardList(concrete, nil)
+	if !comp.IsValid() {
+		return nil, errors.New("declared combination is invalid")
+	}
+	base := baseCompOf(comp)
+	if base.NormalizedCards == nil {
+		base.NormalizedCards = concrete
+	}
+	base.Cards = cards
+	base.Substitutes = declared
+	return comp, nil
+}
+
+// substituteCandidates 变化牌可以代入的所有具体牌
+func substituteCandidates(level int) []*Card {
+	candidates := make([]*Card, 0, 52)
+	for number := 2; number <= 14; number++ {
+		for _, color := range Colors {
+			card, _ := NewCard(number, color, level)
+			if !card.IsWildcard() {
+				candidates = append(candidates, card)
+			}
+		}
+	}
+	return candidates
+}
+
+// baseCompOf 取出牌组的 BaseComp
+func baseCompOf(comp CardComp) *BaseComp {
+	switch c := comp.(type) {
+	case *Fold:
+		return &c.BaseComp
+	case *IllegalComp:
+		return &c.BaseComp
+	case *Single:
+		return &c.BaseComp
+	case *Pair:
+		return &c.BaseComp
+	case *Triple:
+		return &c.BaseComp
+	case *FullHouse:
+		return &c.BaseComp
+	case *Straight:
+		return &c.BaseComp
+	case *Plate:
+		return &c.BaseComp
+	case *Tube:
+		return &c.BaseComp
+	case *JokerBomb:
+		return &c.BaseComp
+	case *NaiveBomb:
+		return &c.BaseComp
+	case *StraightFlush:
+		return &c.BaseComp
+	}
+	return nil
+}
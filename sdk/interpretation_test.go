@@ -0,0 +1,209 @@
+package sdk
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// parseShortCards 解析空格分隔的简化牌面，"W" 表示一张变化牌（红桃级牌）
+func parseShortCards(t *testing.T, notation string, level int) []*Card {
+	t.Helper()
+	var cards []*Card
+	for _, short := range strings.Fields(notation) {
+		if short == "W" {
+			card, _ := NewCard(level, "Heart", level)
+			cards = append(cards, card)
+			continue
+		}
+		card, err := ParseShortCard(short, level)
+		if err != nil {
+			t.Fatalf("parse %q: %v", short, err)
+		}
+		cards = append(cards, card)
+	}
+	return cards
+}
+
+func interpretationTypes(interpretations []*Interpretation) map[CompType]int {
+	types := make(map[CompType]int)
+	for _, interpretation := range interpretations {
+		types[interpretation.Comp.GetType()]++
+	}
+	return types
+}
+
+func TestInterpretCardsFullHouse(t *testing.T) {
+	cards := parseShortCards(t, "6S 6C 7S 7D W", 5)
+	interpretations := InterpretCards(cards, nil)
+	if len(interpretations) != 2 {
+		t.Fatalf("expected 2 interpretations, got %d", len(interpretations))
+	}
+
+	var triples []int
+	for _, interpretation := range interpretations {
+		if interpretation.Comp.GetType() != TypeFullHouse {
+			t.Fatalf("expected full house, got %v", interpretation.Comp.GetType())
+		}
+		if len(interpretation.Substitutes) != 1 {
+			t.Fatalf("expected 1 substitute, got %v", interpretation.Substitutes)
+		}
+		triples = append(triples, interpretation.Substitutes[0].Number)
+	}
+	if !(triples[0] == 6 && triples[1] == 7) && !(triples[0] == 7 && triples[1] == 6) {
+		t.Errorf("expected triples of 6 and 7, got %v", triples)
+	}
+
+	// 第一个解释与 FromCardList 的默认判断一致
+	if !sameMaskComp(interpretations[0].Comp, FromCardList(cards, nil)) {
+		t.Errorf("first interpretation %v does not match FromCardList", interpretations[0].Comp)
+	}
+}
+
+func TestInterpretCardsStraightOrStraightFlush(t *testing.T) {
+	cards := parseShortCards(t, "6H 7H 8H 9H W", 5)
+	types := interpretationTypes(InterpretCards(cards, nil))
+	if types[TypeStraight] == 0 || types[TypeStraightFlush] == 0 {
+		t.Errorf("expected both straight and straight flush, got %v", types)
+	}
+}
+
+func TestInterpretCardsWithoutWildcard(t *testing.T) {
+	cards := parseShortCards(t, "3S 3D 3C 4S 4D 4C", 5)
+	interpretations := InterpretCards(cards, nil)
+	if len(interpretations) != 1 || interpretations[0].Comp.GetType() != TypePlate || interpretations[0].Substitutes != nil {
+		t.Fatalf("expected a single plate interpretation, got %v", interpretations)
+	}
+
+	if got := InterpretCards(parseShortCards(t, "3S 5D", 5), nil); got != nil {
+		t.Errorf("expected no interpretation for an invalid combination, got %v", got)
+	}
+	if got := InterpretCards(parseShortCards(t, "W", 5), nil); len(got) != 1 || got[0].Comp.GetType() != TypeSingle {
+		t.Errorf("expected a single wildcard to be a single, got %v", got)
+	}
+}
+
+func TestInterpretCardsConsistency(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	rounds := 400
+	if testing.Short() {
+		rounds = 60
+	}
+	for round := 0; round < rounds; round++ {
+		level := rng.Intn(13) + 2
+		pool := maskTestHand(rng, level, 12)
+		var cards []*Card
+		for _, card := range pool {
+			if !card.IsWildcard() && len(cards) < 4+rng.Intn(3) {
+				cards = append(cards, card)
+			}
+		}
+		for i := 0; i < 1+rng.Intn(2); i++ {
+			wild, _ := NewCard(level, "Heart", level)
+			cards = append(cards, wild)
+		}
+
+		seen := make(map[MaskComp]bool)
+		for _, interpretation := range InterpretCards(cards, nil) {
+			comp := interpretation.Comp
+			if !comp.IsValid() || len(comp.GetCards()) != len(cards) {
+				t.Fatalf("round %d %s: invalid interpretation %v", round, shortCards(cards), comp)
+			}
+			mask := MaskCompOf(comp)
+			if seen[mask] {
+				t.Fatalf("round %d %s: duplicate interpretation %+v", round, shortCards(cards), mask)
+			}
+			seen[mask] = true
+
+			if interpretation.Substitutes == nil {
+				continue
+			}
+			declared, err := DeclareComp(cards, interpretation.Substitutes)
+			if err != nil {
+				t.Fatalf("round %d %s: DeclareComp: %v", round, shortCards(cards), err)
+			}
+			concrete := append([]*Card(nil), getNormalCards(cards)...)
+			concrete = append(concrete, interpretation.Substitutes...)
+			if want := NewHandMask(concrete).Classify(TypeFold); MaskCompOf(declared) != want {
+				t.Fatalf("round %d %s: declared %+v, want %+v", round, shortCards(cards), MaskCompOf(declared), want)
+			}
+		}
+	}
+}
+
+func TestDeclareCompErrors(t *testing.T) {
+	level := 5
+	cards := parseShortCards(t, "6S 6C 7S 7D W", level)
+	cases := []struct {
+		name        string
+		substitutes string
+	}{
+		{"too many substitutes", "7C 6D"},
+		{"joker", "BJ"},
+		{"wildcard", "5H"},
+		{"invalid result", "9C"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := DeclareComp(cards, parseShortCards(t, tc.substitutes, level)); err == nil {
+				t.Errorf("expected an error declaring %s", tc.substitutes)
+			}
+		})
+	}
+
+	if _, err := DeclareComp(parseShortCards(t, "W", level), parseShortCards(t, "AS", level)); err == nil {
+		t.Error("expected an error declaring a single wildcard")
+	}
+	comp, err := DeclareComp(cards, parseShortCards(t, "7C", level))
+	if err != nil {
+		t.Fatalf("DeclareComp: %v", err)
+	}
+	if got := comp.GetCards(); len(got) != len(cards) || got[4] != cards[4] {
+		t.Errorf("declared comp should keep the original cards, got %v", got)
+	}
+}
+
+func TestDealPlayCardsAs(t *testing.T) {
+	newPlayingDeal := func(t *testing.T, hands ...string) *Deal {
+		deal, _ := NewDeal(5, nil)
+		for seat, hand := range hands {
+			deal.PlayerCards[seat] = parseShortCards(t, hand, 5)
+		}
+		deal.Status = DealStatusPlaying
+		deal.CurrentTrick, _ = NewTrick(0)
+		deal.CurrentTrick.StartTrick()
+		return deal
+	}
+	lead := "6S 6C 7S 7D W 2S"
+	follow := "7H 7C 7S 3S 3D 2D"
+
+	deal := newPlayingDeal(t, lead, follow)
+	cards := deal.PlayerCards[0][:5]
+	if err := deal.PlayCardsAs(0, cards, parseShortCards(t, "7C", 5)); err != nil {
+		t.Fatalf("PlayCardsAs: %v", err)
+	}
+	if err := deal.PlayCards(1, deal.PlayerCards[1][:5]); err == nil {
+		t.Error("a full house of 7s should not beat a declared full house of 7s")
+	}
+
+	deal = newPlayingDeal(t, lead, follow)
+	cards = deal.PlayerCards[0][:5]
+	if err := deal.PlayCardsAs(0, cards, parseShortCards(t, "6D", 5)); err != nil {
+		t.Fatalf("PlayCardsAs: %v", err)
+	}
+	if err := deal.PlayCards(1, deal.PlayerCards[1][:5]); err != nil {
+		t.Errorf("a full house of 7s should beat a declared full house of 6s: %v", err)
+	}
+
+	deal = newPlayingDeal(t, lead, follow)
+	if err := deal.PlayCardsAs(0, deal.PlayerCards[0][:5], parseShortCards(t, "9C", 5)); err == nil {
+		t.Error("expected an invalid declaration to be rejected")
+	}
+	if len(deal.PlayerCards[0]) != 6 {
+		t.Errorf("rejected play should not remove cards, hand has %d", len(deal.PlayerCards[0]))
+	}
+}
+
+func sameMaskComp(a, b CardComp) bool {
+	return MaskCompOf(a) == MaskCompOf(b)
+}
@@ -60,6 +60,16 @@ func (m *Match) StartNewDeal() error {
 		return fmt.Errorf("failed to create deal: %w", err)
 	}
 
+	// Without tribute the first player out of the previous deal leads
+	if m.Variant == RuleVariantNoTribute {
+		deal.TributePhase = nil
+	}
+
+	// Seeded matches deal reproducibly
+	if m.Seed != nil {
+		deal.SetSeed(DealSeed(*m.Seed, len(m.DealHistory)))
+	}
+
 	// Set current deal and update status
 	m.CurrentDeal = deal
 	m.Status = MatchStatusPlaying
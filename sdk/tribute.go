@@ -3,6 +3,7 @@ package sdk
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 )
 
@@ -150,7 +151,7 @@ func (tm *TributeManager) startTributePhase(tributePhase *TributePhase, playerHa
 		// Create tribute pool from losing players' highest cards (excluding heart trump)
 		poolCards := make([]*Card, 0)
 
-		for giver := range tributePhase.TributeMap {
+		for _, giver := range sortedSeats(tributePhase.TributeMap) {
 			if tributePhase.TributeMap[giver] == -1 {
 				// Get highest card excluding heart trump from this player
 				tributeCard := tm.getHighestCardExcludingHeartTrump(playerHands[giver])
@@ -404,9 +405,10 @@ func (tp *TributePhase) selectTribute(playerSeat int, card *Card) error {
 			tp.PoolCards = append(tp.PoolCards[:i], tp.PoolCards[i+1:]...)
 			found = true
 
-			// Find the original giver of this card
-			for giver, tributeCard := range tp.TributeCards {
-				if tp.TributeMap[giver] == -1 && tp.cardsEqual(tributeCard, card) {
+			// Find the original giver of this card; with two decks both pool cards
+			// may be identical, so skip a giver whose card was already taken
+			for _, giver := range sortedSeats(tp.TributeCards) {
+				if tributeCard := tp.TributeCards[giver]; tp.TributeMap[giver] == -1 && tp.cardsEqual(tributeCard, card) && !tp.giverSelected(giver) {
 					originalGiver = giver
 					break
 				}
@@ -506,6 +508,27 @@ func (tp *TributePhase) cardsEqual(card1, card2 *Card) bool {
 	return card1.Number == card2.Number && card1.Color == card2.Color
 }
 
+// giverSelected reports whether the card contributed by giver was already selected from the pool
+func (tp *TributePhase) giverSelected(giver int) bool {
+	for _, originalGiver := range tp.SelectionResults {
+		if originalGiver == giver {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedSeats returns the seats keyed in m in ascending order, so that
+// tribute actions are processed in a stable order (map iteration is random)
+func sortedSeats[V any](m map[int]V) []int {
+	seats := make([]int, 0, len(m))
+	for seat := range m {
+		seats = append(seats, seat)
+	}
+	sort.Ints(seats)
+	return seats
+}
+
 // getSecondPlace returns the seat number of second place
 func (tp *TributePhase) getSecondPlace() int {
 	// Find the teammate of current selecting player
@@ -565,7 +588,8 @@ func (tm *TributeManager) ProcessTributePhaseAction(phase *TributePhase, playerC
 
 	case TributeStatusReturning:
 		// Find player who needs to return tribute
-		for giver, receiver := range phase.TributeMap {
+		for _, giver := range sortedSeats(phase.TributeMap) {
+			receiver := phase.TributeMap[giver]
 			if receiver != -1 && phase.TributeCards[giver] != nil {
 				// Check if return is already done
 				if phase.ReturnCards[receiver] == nil {
@@ -690,7 +714,8 @@ func (tm *TributeManager) HandleTimeoutAction(phase *TributePhase, playerCards [
 
 	case TributeStatusReturning:
 		// Find player who needs to return and auto-select lowest card
-		for giver, receiver := range phase.TributeMap {
+		for _, giver := range sortedSeats(phase.TributeMap) {
+			receiver := phase.TributeMap[giver]
 			if receiver != -1 && phase.TributeCards[giver] != nil {
 				if phase.ReturnCards[receiver] == nil {
 					// Get lowest card for auto-return
@@ -730,7 +755,8 @@ func (tm *TributeManager) GetTributeStatusInfo(phase *TributePhase, playerCards
 		}
 
 	case TributeStatusReturning:
-		for giver, receiver := range phase.TributeMap {
+		for _, giver := range sortedSeats(phase.TributeMap) {
+			receiver := phase.TributeMap[giver]
 			if receiver != -1 && phase.TributeCards[giver] != nil {
 				if phase.ReturnCards[receiver] == nil {
 					pendingActions = append(pendingActions, &TributeAction{
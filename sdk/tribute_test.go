@@ -510,3 +510,45 @@ func formatCardForTest(card *Card) string {
 
 	return number + suit
 }
+
+func TestDoubleDownIdenticalPoolCards(t *testing.T) {
+	// 双下：两个败方上贡的是两副牌中相同的牌，两人都必须各自被还贡
+	lastResult := &DealResult{
+		Rankings:    []int{0, 2, 1, 3},
+		WinningTeam: 0,
+		VictoryType: VictoryTypeDoubleDown,
+	}
+	playerHands := [4][]*Card{
+		{{Number: 3, Color: "Spade"}, {Number: 4, Color: "Club"}},
+		{{Number: 14, Color: "Spade"}, {Number: 5, Color: "Club"}},
+		{{Number: 3, Color: "Heart"}, {Number: 6, Color: "Club"}},
+		{{Number: 14, Color: "Spade"}, {Number: 8, Color: "Diamond"}},
+	}
+
+	for run := 0; run < 20; run++ {
+		tributePhase, err := NewTributePhase(lastResult)
+		if err != nil {
+			t.Fatalf("创建上贡阶段失败: %v", err)
+		}
+		tm := NewTributeManager(2)
+		if _, err := tm.ProcessTributePhaseAction(tributePhase, playerHands); err != nil {
+			t.Fatalf("处理上贡失败: %v", err)
+		}
+		if tributePhase.Status != TributeStatusSelecting || len(tributePhase.PoolCards) != 2 {
+			t.Fatalf("期望进入选牌阶段且贡牌池有2张牌, got %s / %d", tributePhase.Status, len(tributePhase.PoolCards))
+		}
+		for tributePhase.Status == TributeStatusSelecting {
+			if err := tributePhase.selectTribute(tributePhase.SelectingPlayer, tributePhase.PoolCards[0]); err != nil {
+				t.Fatalf("选牌失败: %v", err)
+			}
+		}
+
+		if len(tributePhase.TributeMap) != 2 {
+			t.Fatalf("期望两个还贡关系, got %v", tributePhase.TributeMap)
+		}
+		// 第一名（座位0）先选，取得座位1的牌；第二名（座位2）取得座位3的牌
+		if tributePhase.TributeMap[1] != 0 || tributePhase.TributeMap[3] != 2 {
+			t.Fatalf("还贡关系不稳定: %v", tributePhase.TributeMap)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package sdk
 
-import "time"
+import (
+	"math/rand"
+	"time"
+)
 
 // Player represents a game player
 type Player struct {
@@ -40,6 +43,19 @@ const (
 	TrickStatusFinished TrickStatus = "finished"
 )
 
+// RuleVariant selects optional rules of a match
+type RuleVariant string
+
+const (
+	RuleVariantStandard  RuleVariant = "standard"   // Losers pay tribute to the winners of the previous deal
+	RuleVariantNoTribute RuleVariant = "no_tribute" // No tribute; the first player out of the previous deal leads
+)
+
+// Valid reports whether the variant is known; the empty variant is standard
+func (v RuleVariant) Valid() bool {
+	return v == "" || v == RuleVariantStandard || v == RuleVariantNoTribute
+}
+
 // Match represents a complete match (multiple deals until someone reaches A level)
 type Match struct {
 	ID          string      `json:"id"`
@@ -51,6 +67,8 @@ type Match struct {
 	Winner      int         `json:"winner"`      // -1 if not finished, 0 or 1 for winning team
 	StartTime   time.Time   `json:"start_time"`
 	EndTime     *time.Time  `json:"end_time,omitempty"`
+	Seed        *int64      `json:"seed,omitempty"` // Random seed for dealing (nil: time-seeded)
+	Variant     RuleVariant `json:"variant,omitempty"`
 }
 
 // Deal represents a single deal (one round of the game)
@@ -66,6 +84,7 @@ type Deal struct {
 	StartTime    time.Time     `json:"start_time"`
 	EndTime      *time.Time    `json:"end_time,omitempty"`
 	LastResult   *DealResult   `json:"-"` // Previous deal result (not serialized)
+	rng          *rand.Rand    // Seeded random source (nil: global source)
 }
 
 // Trick represents a single trick (one round of card plays)
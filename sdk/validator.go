@@ -19,6 +19,11 @@ func NewPlayValidator(level int) *PlayValidator {
 
 // ValidatePlay validates a player's card play action
 func (pv *PlayValidator) ValidatePlay(playerSeat int, cards []*Card, playerCards []*Card, currentTrick *Trick) error {
+	return pv.ValidatePlayAs(playerSeat, cards, nil, playerCards, currentTrick)
+}
+
+// ValidatePlayAs validates a player's card play with declared wildcard substitutes
+func (pv *PlayValidator) ValidatePlayAs(playerSeat int, cards []*Card, substitutes []*Card, playerCards []*Card, currentTrick *Trick) error {
 	// Basic validation
 	if len(cards) == 0 {
 		return errors.New("cannot play empty cards")
@@ -42,6 +47,12 @@ func (pv *PlayValidator) ValidatePlay(playerSeat int, cards []*Card, playerCards
 	}
 
 	comp := FromCardList(cards, prevComp)
+	if len(substitutes) > 0 {
+		comp, err = DeclareComp(cards, substitutes)
+		if err != nil {
+			return fmt.Errorf("invalid declaration: %w", err)
+		}
+	}
 	if !comp.IsValid() {
 		return errors.New("invalid card combination")
 	}
@@ -0,0 +1,366 @@
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"guandan-world/ai"
+)
+
+// ArenaStateVersion 状态文件格式版本
+const ArenaStateVersion = 1
+
+// ArenaConfig 机器人评分竞技场配置
+type ArenaConfig struct {
+	Entrants        []string      // 参赛算法配置（见 ai.NewAlgorithmByName），每个配置组成一支两人队伍
+	Rounds          int           // 循环赛轮数，每轮每对配置交手一次，奇数轮交换座位
+	Workers         int           // 并行的工作协程数
+	Seed            int64         // 基础种子，第k场比赛使用 Seed+k
+	StatePath       string        // 评分状态文件（为空时不持久化）
+	EloK            float64       // Elo K 因子（0表示使用 DefaultEloK）
+	DecisionTimeout time.Duration // 单次决策超时（0表示使用驱动器默认值）
+	MatchTimeout    time.Duration // 单场比赛超时（0表示不限制）
+}
+
+// ArenaRating 一个参赛配置的评分和战绩
+type ArenaRating struct {
+	Elo       float64         `json:"elo"`
+	TrueSkill TrueSkillRating `json:"trueskill"`
+	Games     int             `json:"games"`
+	Wins      int             `json:"wins"`
+}
+
+// ArenaState 可持久化的竞技场状态，中断后从 Completed 继续
+type ArenaState struct {
+	Version   int                       `json:"version"`
+	Seed      int64                     `json:"seed"`
+	Entrants  []string                  `json:"entrants"`
+	Completed int                       `json:"completed"` // 按赛程顺序已处理的比赛数
+	Failures  int                       `json:"failures"`
+	Ratings   map[string]*ArenaRating   `json:"ratings"`
+	Wins      map[string]map[string]int `json:"wins"` // Wins[a][b] = a 战胜 b 的场数
+	UpdatedAt time.Time                 `json:"updated_at"`
+}
+
+// ArenaStanding 排行榜中的一行
+type ArenaStanding struct {
+	Rank         int     `json:"rank"`
+	Name         string  `json:"name"`
+	Elo          float64 `json:"elo"`
+	Mu           float64 `json:"mu"`
+	Sigma        float64 `json:"sigma"`
+	Conservative float64 `json:"conservative"` // TrueSkill μ-3σ，排行榜按它排序
+	Games        int     `json:"games"`
+	Wins         int     `json:"wins"`
+	WinRate      float64 `json:"win_rate"`
+}
+
+// arenaGame 赛程中的一场比赛：Home 坐座位0、2，Away 坐座位1、3
+type arenaGame struct {
+	index int
+	home  int
+	away  int
+}
+
+// arenaResult 一场比赛的结果
+type arenaResult struct {
+	game   arenaGame
+	winner int // 获胜配置的下标
+	err    error
+}
+
+// Arena 机器人评分竞技场
+type Arena struct {
+	config    *ArenaConfig
+	state     *ArenaState
+	factories []ai.AlgorithmFactory
+}
+
+// NewArena 创建竞技场，状态文件存在时从中恢复
+// 参数:
+//
+//	config: 竞技场配置
+//
+// 返回值:
+//
+//	*Arena: 竞技场
+//	error: 配置无效、算法无法创建，或状态文件与配置不一致时返回错误
+func NewArena(config *ArenaConfig) (*Arena, error) {
+	if len(config.Entrants) < 2 {
+		return nil, errors.New("arena needs at least two entrants")
+	}
+	if config.Rounds <= 0 {
+		return nil, errors.New("rounds must be positive")
+	}
+	seen := make(map[string]bool)
+	factories := make([]ai.AlgorithmFactory, len(config.Entrants))
+	for i, name := range config.Entrants {
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate entrant %q", name)
+		}
+		seen[name] = true
+		factory, err := ai.NewAlgorithmFactory(name)
+		if err != nil {
+			return nil, fmt.Errorf("entrant %q: %w", name, err)
+		}
+		factories[i] = factory
+	}
+
+	state, err := loadArenaState(config.StatePath)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		state = newArenaState(config)
+	} else if err := state.checkCompatible(config); err != nil {
+		return nil, err
+	}
+
+	return &Arena{config: config, state: state, factories: factories}, nil
+}
+
+// newArenaState 创建初始状态
+func newArenaState(config *ArenaConfig) *ArenaState {
+	state := &ArenaState{
+		Version:  ArenaStateVersion,
+		Seed:     config.Seed,
+		Entrants: append([]string(nil), config.Entrants...),
+		Ratings:  make(map[string]*ArenaRating),
+		Wins:     make(map[string]map[string]int),
+	}
+	for _, name := range config.Entrants {
+		state.Ratings[name] = &ArenaRating{Elo: DefaultElo, TrueSkill: NewTrueSkillRating()}
+		state.Wins[name] = make(map[string]int)
+	}
+	return state
+}
+
+// checkCompatible 检查恢复的状态与当前配置是否一致；轮数可以增加，用于延长已有的排名
+func (s *ArenaState) checkCompatible(config *ArenaConfig) error {
+	if s.Version != ArenaStateVersion {
+		return fmt.Errorf("unsupported arena state version %d", s.Version)
+	}
+	if s.Seed != config.Seed {
+		return fmt.Errorf("state was created with seed %d, not %d", s.Seed, config.Seed)
+	}
+	if len(s.Entrants) != len(config.Entrants) {
+		return fmt.Errorf("state was created with entrants %v", s.Entrants)
+	}
+	for i, name := range s.Entrants {
+		if config.Entrants[i] != name {
+			return fmt.Errorf("state was created with entrants %v", s.Entrants)
+		}
+	}
+	return nil
+}
+
+// loadArenaState 读取状态文件，文件不存在时返回 nil
+func loadArenaState(path string) (*ArenaState, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state ArenaState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid arena state %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// save 先写临时文件再重命名，避免中断时留下损坏的状态文件
+func (a *Arena) save() error {
+	if a.config.StatePath == "" {
+		return nil
+	}
+	a.state.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(a.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(a.config.StatePath), ".arena-*.json")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), a.config.StatePath)
+}
+
+// State 返回当前状态
+func (a *Arena) State() *ArenaState {
+	return a.state
+}
+
+// TotalGames 返回赛程中的比赛总数
+func (a *Arena) TotalGames() int {
+	n := len(a.config.Entrants)
+	return a.config.Rounds * n * (n - 1) / 2
+}
+
+// schedule 返回第 index 场比赛：按轮次依次排列所有配置对，奇数轮交换座位
+func (a *Arena) schedule(index int) arenaGame {
+	n := len(a.config.Entrants)
+	pairs := n * (n - 1) / 2
+	round, pair := index/pairs, index%pairs
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if pair == 0 {
+				if round%2 == 1 {
+					i, j = j, i
+				}
+				return arenaGame{index: index, home: i, away: j}
+			}
+			pair--
+		}
+	}
+	panic("arena schedule index out of range")
+}
+
+// Run 运行剩余的赛程
+// 参数:
+//
+//	ctx: 取消后在当前一批比赛结束时停止，已完成的结果会被保存
+//
+// 功能说明:
+//   - 每批并行运行 Workers 场比赛，然后按赛程顺序更新评分并保存状态，
+//     因此中断后恢复与一次跑完的结果完全相同
+func (a *Arena) Run(ctx context.Context) error {
+	workers := a.config.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	total := a.TotalGames()
+
+	for a.state.Completed < total {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch := make([]arenaGame, 0, workers)
+		for index := a.state.Completed; index < total && len(batch) < workers; index++ {
+			batch = append(batch, a.schedule(index))
+		}
+
+		results := make([]arenaResult, len(batch))
+		var wg sync.WaitGroup
+		for i, game := range batch {
+			wg.Add(1)
+			go func(i int, game arenaGame) {
+				defer wg.Done()
+				results[i] = a.play(game)
+			}(i, game)
+		}
+		wg.Wait()
+
+		for _, result := range results {
+			a.apply(result)
+		}
+		if err := a.save(); err != nil {
+			return fmt.Errorf("failed to save arena state: %w", err)
+		}
+	}
+	return nil
+}
+
+// play 运行一场比赛
+func (a *Arena) play(game arenaGame) arenaResult {
+	var factories [4]ai.AlgorithmFactory
+	for seat := 0; seat < 4; seat++ {
+		if seat%2 == 0 {
+			factories[seat] = a.factories[game.home]
+		} else {
+			factories[seat] = a.factories[game.away]
+		}
+	}
+	seed := a.config.Seed + int64(game.index)
+	sim := newSeededSimulator(seed, factories, a.config.DecisionTimeout, a.config.MatchTimeout)
+	result, err := sim.RunMatch()
+	if err != nil {
+		return arenaResult{game: game, err: fmt.Errorf("game %d (seed %d): %w", game.index, seed, err)}
+	}
+	switch result.Winner {
+	case 0:
+		return arenaResult{game: game, winner: game.home}
+	case 1:
+		return arenaResult{game: game, winner: game.away}
+	default:
+		return arenaResult{game: game, err: fmt.Errorf("game %d (seed %d) finished without a winner", game.index, seed)}
+	}
+}
+
+// apply 按一场比赛的结果更新评分和胜负矩阵
+func (a *Arena) apply(result arenaResult) {
+	a.state.Completed++
+	if result.err != nil {
+		a.state.Failures++
+		return
+	}
+
+	loser := result.game.home
+	if result.winner == loser {
+		loser = result.game.away
+	}
+	winnerName := a.config.Entrants[result.winner]
+	loserName := a.config.Entrants[loser]
+	winner := a.state.Ratings[winnerName]
+	defeated := a.state.Ratings[loserName]
+
+	k := a.config.EloK
+	if k <= 0 {
+		k = DefaultEloK
+	}
+	winner.Elo, defeated.Elo = EloUpdate(winner.Elo, defeated.Elo, k)
+	winner.TrueSkill, defeated.TrueSkill = TrueSkillUpdate(winner.TrueSkill, defeated.TrueSkill)
+	winner.Games++
+	winner.Wins++
+	defeated.Games++
+	a.state.Wins[winnerName][loserName]++
+}
+
+// Leaderboard 返回按 TrueSkill 保守估计排序的排行榜
+func (a *Arena) Leaderboard() []ArenaStanding {
+	standings := make([]ArenaStanding, 0, len(a.state.Entrants))
+	for _, name := range a.state.Entrants {
+		rating := a.state.Ratings[name]
+		standing := ArenaStanding{
+			Name:         name,
+			Elo:          rating.Elo,
+			Mu:           rating.TrueSkill.Mu,
+			Sigma:        rating.TrueSkill.Sigma,
+			Conservative: rating.TrueSkill.Conservative(),
+			Games:        rating.Games,
+			Wins:         rating.Wins,
+		}
+		if rating.Games > 0 {
+			standing.WinRate = float64(rating.Wins) / float64(rating.Games)
+		}
+		standings = append(standings, standing)
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].Conservative > standings[j].Conservative
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+	return standings
+}
@@ -0,0 +1,109 @@
+package simulator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ArenaReport 竞技场报告：排行榜和胜负矩阵
+type ArenaReport struct {
+	Completed   int                       `json:"completed"`
+	Total       int                       `json:"total"`
+	Failures    int                       `json:"failures"`
+	Leaderboard []ArenaStanding           `json:"leaderboard"`
+	Wins        map[string]map[string]int `json:"wins"` // Wins[a][b] = a 战胜 b 的场数
+}
+
+// Report 生成当前状态的报告
+func (a *Arena) Report() *ArenaReport {
+	return &ArenaReport{
+		Completed:   a.state.Completed,
+		Total:       a.TotalGames(),
+		Failures:    a.state.Failures,
+		Leaderboard: a.Leaderboard(),
+		Wins:        a.state.Wins,
+	}
+}
+
+// Write 按指定格式输出报告
+func (r *ArenaReport) Write(w io.Writer, format string) error {
+	switch format {
+	case ReportText, "":
+		return r.WriteText(w)
+	case ReportJSON:
+		return r.WriteJSON(w)
+	case ReportCSV:
+		return r.WriteCSV(w)
+	default:
+		return fmt.Errorf("unknown report format: %s", format)
+	}
+}
+
+// WriteText 输出排行榜和按排名排列的胜负矩阵（行对列的胜场-负场）
+func (r *ArenaReport) WriteText(w io.Writer) error {
+	fmt.Fprintln(w, "=========== Arena Leaderboard ===========")
+	fmt.Fprintf(w, "Games: %d / %d (failures: %d)\n", r.Completed, r.Total, r.Failures)
+	fmt.Fprintf(w, "%-4s %-24s %8s %7s %6s %8s %6s %7s\n", "#", "entrant", "elo", "mu", "sigma", "mu-3σ", "games", "win%")
+	for _, s := range r.Leaderboard {
+		fmt.Fprintf(w, "%-4d %-24s %8.1f %7.2f %6.2f %8.2f %6d %6.1f%%\n",
+			s.Rank, s.Name, s.Elo, s.Mu, s.Sigma, s.Conservative, s.Games, s.WinRate*100)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Win matrix (row vs column, wins-losses):")
+	fmt.Fprintf(w, "%-24s", "")
+	for i := range r.Leaderboard {
+		fmt.Fprintf(w, " %9s", fmt.Sprintf("#%d", i+1))
+	}
+	fmt.Fprintln(w)
+	for i, row := range r.Leaderboard {
+		fmt.Fprintf(w, "%-24s", fmt.Sprintf("#%d %s", i+1, row.Name))
+		for _, column := range r.Leaderboard {
+			if row.Name == column.Name {
+				fmt.Fprintf(w, " %9s", "-")
+				continue
+			}
+			fmt.Fprintf(w, " %9s", fmt.Sprintf("%d-%d", r.Wins[row.Name][column.Name], r.Wins[column.Name][row.Name]))
+		}
+		fmt.Fprintln(w)
+	}
+	_, err := fmt.Fprintln(w, "=========================================")
+	return err
+}
+
+// WriteJSON 输出 JSON 报告
+func (r *ArenaReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV 输出排行榜 CSV，每个对手的胜场数作为额外的列
+func (r *ArenaReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	float := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+
+	header := []string{"rank", "entrant", "elo", "mu", "sigma", "conservative", "games", "wins", "win_rate"}
+	for _, s := range r.Leaderboard {
+		header = append(header, "wins_vs_"+s.Name)
+	}
+	rows := [][]string{header}
+	for _, s := range r.Leaderboard {
+		row := []string{
+			strconv.Itoa(s.Rank), s.Name, float(s.Elo), float(s.Mu), float(s.Sigma),
+			float(s.Conservative), strconv.Itoa(s.Games), strconv.Itoa(s.Wins), float(s.WinRate),
+		}
+		for _, opponent := range r.Leaderboard {
+			row = append(row, strconv.Itoa(r.Wins[s.Name][opponent.Name]))
+		}
+		rows = append(rows, row)
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
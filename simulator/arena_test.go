@@ -0,0 +1,145 @@
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestArena(t *testing.T, rounds int, statePath string) *Arena {
+	t.Helper()
+	arena, err := NewArena(&ArenaConfig{
+		Entrants:  []string{"simple", "smart", "beginner"},
+		Rounds:    rounds,
+		Workers:   2,
+		Seed:      5,
+		StatePath: statePath,
+	})
+	if err != nil {
+		t.Fatalf("NewArena failed: %v", err)
+	}
+	return arena
+}
+
+func TestArenaSchedule(t *testing.T) {
+	arena := newTestArena(t, 2, "")
+	if arena.TotalGames() != 6 {
+		t.Fatalf("expected 6 games, got %d", arena.TotalGames())
+	}
+	home := make(map[[2]int]int)
+	for i := 0; i < arena.TotalGames(); i++ {
+		game := arena.schedule(i)
+		if game.index != i || game.home == game.away {
+			t.Fatalf("invalid game %d: %+v", i, game)
+		}
+		home[[2]int{game.home, game.away}]++
+	}
+	// 每对配置在两轮中各坐一次主位
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if i != j && home[[2]int{i, j}] != 1 {
+				t.Errorf("pair %d vs %d played %d times at home", i, j, home[[2]int{i, j}])
+			}
+		}
+	}
+}
+
+func TestArenaRunAndResume(t *testing.T) {
+	dir := t.TempDir()
+
+	// 一次跑完两轮
+	full := newTestArena(t, 2, filepath.Join(dir, "full.json"))
+	if err := full.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if full.State().Completed != 6 || full.State().Failures != 0 {
+		t.Fatalf("unexpected state: completed %d failures %d", full.State().Completed, full.State().Failures)
+	}
+	games := 0
+	for _, rating := range full.State().Ratings {
+		games += rating.Games
+	}
+	if games != 12 {
+		t.Errorf("each game should count for both entrants, got %d", games)
+	}
+
+	// 先跑一轮，再用同一个状态文件恢复并延长到两轮
+	path := filepath.Join(dir, "resumed.json")
+	first := newTestArena(t, 1, path)
+	if err := first.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	resumed := newTestArena(t, 2, path)
+	if resumed.State().Completed != 3 {
+		t.Fatalf("expected to resume after 3 games, got %d", resumed.State().Completed)
+	}
+	if err := resumed.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !reflect.DeepEqual(full.State().Ratings, resumed.State().Ratings) || !reflect.DeepEqual(full.State().Wins, resumed.State().Wins) {
+		t.Errorf("resumed run differs from uninterrupted run:\n%+v\n%+v", full.State().Ratings, resumed.State().Ratings)
+	}
+
+	// 排行榜按保守估计排序
+	board := resumed.Leaderboard()
+	for i := 1; i < len(board); i++ {
+		if board[i-1].Conservative < board[i].Conservative {
+			t.Errorf("leaderboard not sorted: %+v", board)
+		}
+	}
+}
+
+func TestArenaCancelAndMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "arena.json")
+	arena := newTestArena(t, 1, path)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := arena.Run(ctx); err == nil || arena.State().Completed != 0 {
+		t.Errorf("cancelled run should stop before playing, got %v with %d games", err, arena.State().Completed)
+	}
+	if err := newTestArena(t, 1, path).Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if _, err := NewArena(&ArenaConfig{Entrants: []string{"simple", "smart"}, Rounds: 1, Seed: 5, StatePath: path}); err == nil {
+		t.Error("expected error when entrants differ from the state file")
+	}
+	if _, err := NewArena(&ArenaConfig{Entrants: []string{"simple", "smart", "beginner"}, Rounds: 1, Seed: 6, StatePath: path}); err == nil {
+		t.Error("expected error when seed differs from the state file")
+	}
+	if _, err := NewArena(&ArenaConfig{Entrants: []string{"simple", "simple"}, Rounds: 1}); err == nil {
+		t.Error("expected error for duplicate entrants")
+	}
+}
+
+func TestArenaReportFormats(t *testing.T) {
+	arena := newTestArena(t, 1, "")
+	if err := arena.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	report := arena.Report()
+
+	var text bytes.Buffer
+	if err := report.Write(&text, ReportText); err != nil || !strings.Contains(text.String(), "Win matrix") {
+		t.Errorf("text report missing matrix: %v\n%s", err, text.String())
+	}
+
+	var out bytes.Buffer
+	if err := report.Write(&out, ReportCSV); err != nil {
+		t.Fatalf("csv report failed: %v", err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil || len(rows) != 4 || len(rows[0]) != 12 {
+		t.Fatalf("expected 4 rows of 12 columns, got %v (%v)", rows, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := RunCLI([]string{"arena", "-entrants", "simple,beginner", "-rounds", "1", "-format", "json"}, &stdout, &stderr)
+	if code != 0 || !strings.Contains(stdout.String(), "\"leaderboard\"") {
+		t.Errorf("arena command failed with %d: %s%s", code, stdout.String(), stderr.String())
+	}
+}
@@ -0,0 +1,346 @@
+package simulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"guandan-world/ai"
+	_ "guandan-world/ai/remote" // 注册 process:<命令> 外部机器人
+	"guandan-world/sdk"
+)
+
+// ErrMatchTimeout 比赛超过时间上限
+var ErrMatchTimeout = errors.New("match timed out")
+
+// BatchConfig 批量模拟配置
+type BatchConfig struct {
+	Matches         int           // 比赛场数
+	Workers         int           // 并行的工作协程数
+	Seed            int64         // 基础种子，第i场比赛使用 Seed+i
+	Seats           [4]string     // 各座位的算法名称（见 ai.NewAlgorithmByName）
+	RotateSeats     bool          // 每场比赛轮换座位，消除座位和先后手的影响
+	DecisionTimeout time.Duration // 单次决策超时（0表示使用驱动器默认值）
+	MatchTimeout    time.Duration // 单场比赛超时（0表示不限制）
+	Dataset         *DatasetWriter
+}
+
+// DefaultBatchConfig 返回默认配置：智能算法对战100场
+func DefaultBatchConfig() *BatchConfig {
+	return &BatchConfig{
+		Matches: 100,
+		Workers: 1,
+		Seed:    1,
+		Seats:   [4]string{"smart", "smart", "smart", "smart"},
+	}
+}
+
+// LineupStats 一个阵容（配置中座位0、2或座位1、3的算法组合）的统计
+type LineupStats struct {
+	Name    string  `json:"name"`
+	Wins    int     `json:"wins"`
+	WinRate float64 `json:"win_rate"`
+	CILow   float64 `json:"ci_low"`  // 胜率95%置信区间下限（Wilson）
+	CIHigh  float64 `json:"ci_high"` // 胜率95%置信区间上限（Wilson）
+}
+
+// BatchReport 批量模拟报告
+type BatchReport struct {
+	Matches          int            `json:"matches"`  // 完成的比赛场数
+	Failures         int            `json:"failures"` // 出错或超时的比赛场数
+	Seed             int64          `json:"seed"`
+	Lineups          [2]LineupStats `json:"lineups"`
+	TotalDeals       int            `json:"total_deals"`
+	AvgDealsPerMatch float64        `json:"avg_deals_per_match"`
+	VictoryTypes     map[string]int `json:"victory_types"` // 各胜利类型的局数
+	TributeDeals     int            `json:"tribute_deals"` // 需要进贡的局数
+	ImmuneDeals      int            `json:"immune_deals"`  // 抗贡的局数
+	ImmunityRate     float64        `json:"immunity_rate"` // 抗贡率 = 抗贡局数 / 需要进贡的局数
+	Duration         time.Duration  `json:"duration"`
+	MatchesPerSecond float64        `json:"matches_per_second"`
+	Errors           []string       `json:"errors,omitempty"` // 前几条错误信息
+}
+
+// matchOutcome 单场比赛的结果
+type matchOutcome struct {
+	err          error
+	winnerLineup int
+	deals        int
+	victoryTypes map[string]int
+	tributeDeals int
+	immuneDeals  int
+}
+
+// batchStatsObserver 收集单场比赛中的牌局统计
+type batchStatsObserver struct {
+	victoryTypes map[string]int
+	tributeDeals int
+	immuneDeals  int
+}
+
+func (o *batchStatsObserver) OnGameEvent(event *sdk.GameEvent) {
+	switch event.Type {
+	case sdk.EventDealEnded:
+		if data, ok := event.Data.(map[string]interface{}); ok {
+			if result, ok := data["result"].(*sdk.DealResult); ok && result != nil {
+				o.victoryTypes[string(result.VictoryType)]++
+			}
+		}
+	case sdk.EventTributeRulesSet:
+		o.tributeDeals++
+	case sdk.EventTributeImmunity:
+		o.immuneDeals++
+	}
+}
+
+// RunBatch 并行运行多场比赛并汇总统计
+// 参数:
+//
+//	config: 批量模拟配置
+//
+// 返回值:
+//
+//	*BatchReport: 汇总报告（单场比赛出错计入 Failures，不中断整体运行）
+//	error: 配置无效时返回错误
+func RunBatch(config *BatchConfig) (*BatchReport, error) {
+	if config.Matches <= 0 {
+		return nil, errors.New("matches must be positive")
+	}
+	workers := config.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	// 每个座位的算法名称只解析一次
+	var factories [4]ai.AlgorithmFactory
+	for seat, name := range config.Seats {
+		factory, err := ai.NewAlgorithmFactory(name)
+		if err != nil {
+			return nil, fmt.Errorf("seat %d: %w", seat, err)
+		}
+		factories[seat] = factory
+	}
+
+	startTime := time.Now()
+	jobs := make(chan int)
+	outcomes := make(chan *matchOutcome)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				outcomes <- runBatchMatch(config, factories, index)
+			}
+		}()
+	}
+	go func() {
+		for i := 0; i < config.Matches; i++ {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	report := &BatchReport{
+		Seed:         config.Seed,
+		VictoryTypes: make(map[string]int),
+	}
+	report.Lineups[0].Name = config.Seats[0] + "+" + config.Seats[2]
+	report.Lineups[1].Name = config.Seats[1] + "+" + config.Seats[3]
+
+	for outcome := range outcomes {
+		if outcome.err != nil {
+			report.Failures++
+			if len(report.Errors) < 5 {
+				report.Errors = append(report.Errors, outcome.err.Error())
+			}
+			continue
+		}
+		report.Matches++
+		report.Lineups[outcome.winnerLineup].Wins++
+		report.TotalDeals += outcome.deals
+		report.TributeDeals += outcome.tributeDeals
+		report.ImmuneDeals += outcome.immuneDeals
+		for victoryType, count := range outcome.victoryTypes {
+			report.VictoryTypes[victoryType] += count
+		}
+	}
+
+	report.Duration = time.Since(startTime)
+	if report.Duration > 0 {
+		report.MatchesPerSecond = float64(report.Matches) / report.Duration.Seconds()
+	}
+	if report.Matches > 0 {
+		report.AvgDealsPerMatch = float64(report.TotalDeals) / float64(report.Matches)
+	}
+	for i := range report.Lineups {
+		lineup := &report.Lineups[i]
+		if report.Matches > 0 {
+			lineup.WinRate = float64(lineup.Wins) / float64(report.Matches)
+		}
+		lineup.CILow, lineup.CIHigh = WilsonInterval(lineup.Wins, report.Matches, 1.96)
+	}
+	if report.TributeDeals > 0 {
+		report.ImmunityRate = float64(report.ImmuneDeals) / float64(report.TributeDeals)
+	}
+	return report, nil
+}
+
+// runBatchMatch 运行批量模拟中的第 index 场比赛
+func runBatchMatch(config *BatchConfig, factories [4]ai.AlgorithmFactory, index int) *matchOutcome {
+	seed := config.Seed + int64(index)
+
+	// 轮换时配置中的座位 s 坐到 (s+offset)%4
+	offset := 0
+	if config.RotateSeats {
+		offset = index % 4
+	}
+
+	var seated [4]ai.AlgorithmFactory
+	for seat := 0; seat < 4; seat++ {
+		seated[(seat+offset)%4] = factories[seat]
+	}
+	sim := newSeededSimulator(seed, seated, config.DecisionTimeout, config.MatchTimeout)
+
+	stats := &batchStatsObserver{victoryTypes: make(map[string]int)}
+	sim.AddObserver(stats)
+
+	var recorder *DatasetRecorder
+	if config.Dataset != nil {
+		recorder = sim.EnableDatasetExport(config.Dataset)
+	}
+
+	result, err := sim.RunMatch()
+	if err == nil && recorder != nil {
+		err = recorder.Err()
+	}
+	if err != nil {
+		return &matchOutcome{err: fmt.Errorf("match %d (seed %d): %w", index, seed, err)}
+	}
+
+	// 获胜队伍换算回配置中的阵容：阵容0坐在座位 offset、offset+2
+	return &matchOutcome{
+		winnerLineup: (result.Winner + offset) % 2,
+		deals:        result.DealCount,
+		victoryTypes: stats.victoryTypes,
+		tributeDeals: stats.tributeDeals,
+		immuneDeals:  stats.immuneDeals,
+	}
+}
+
+// newSeededSimulator 创建使用给定种子的静默模拟器，座位 seat 使用 factories[seat] 创建的算法
+// 算法的随机种子只由比赛种子和座位决定，同一种子下重复运行结果相同
+func newSeededSimulator(seed int64, factories [4]ai.AlgorithmFactory, decisionTimeout, matchTimeout time.Duration) *MatchSimulatorV2 {
+	sim := NewMatchSimulatorV2(false)
+	sim.SetSeed(seed)
+	if decisionTimeout > 0 {
+		sim.SetDecisionTimeout(decisionTimeout)
+	}
+	if matchTimeout > 0 {
+		sim.SetMatchTimeout(matchTimeout)
+	}
+	for seat := 0; seat < 4; seat++ {
+		sim.SetPlayerAlgorithm(seat, factories[seat](2, sdk.DealSeed(seed, 100+seat)))
+	}
+	return sim
+}
+
+// WilsonInterval 计算二项比例的 Wilson 置信区间
+// 参数:
+//
+//	successes: 成功次数
+//	trials: 试验次数
+//	z: 正态分位数（95%置信度为1.96）
+func WilsonInterval(successes, trials int, z float64) (float64, float64) {
+	if trials <= 0 {
+		return 0, 1
+	}
+	n := float64(trials)
+	p := float64(successes) / n
+	denominator := 1 + z*z/n
+	center := (p + z*z/(2*n)) / denominator
+	margin := z * math.Sqrt(p*(1-p)/n+z*z/(4*n*n)) / denominator
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// deadlineInputProvider 在截止时间之后拒绝所有决策请求，用于限制整场比赛的时长
+type deadlineInputProvider struct {
+	inner    sdk.PlayerInputProvider
+	deadline time.Time
+}
+
+func newDeadlineInputProvider(inner sdk.PlayerInputProvider, deadline time.Time) *deadlineInputProvider {
+	return &deadlineInputProvider{inner: inner, deadline: deadline}
+}
+
+func (p *deadlineInputProvider) context(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	if !time.Now().Before(p.deadline) {
+		return nil, nil, ErrMatchTimeout
+	}
+	ctx, cancel := context.WithDeadline(ctx, p.deadline)
+	return ctx, cancel, nil
+}
+
+func (p *deadlineInputProvider) RequestPlayDecision(ctx context.Context, playerSeat int, hand []*sdk.Card, trickInfo *sdk.TrickInfo) (*sdk.PlayDecision, error) {
+	ctx, cancel, err := p.context(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	return p.inner.RequestPlayDecision(ctx, playerSeat, hand, trickInfo)
+}
+
+func (p *deadlineInputProvider) RequestTributeSelection(ctx context.Context, playerSeat int, options []*sdk.Card) (*sdk.Card, error) {
+	ctx, cancel, err := p.context(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	return p.inner.RequestTributeSelection(ctx, playerSeat, options)
+}
+
+func (p *deadlineInputProvider) RequestReturnTribute(ctx context.Context, playerSeat int, hand []*sdk.Card) (*sdk.Card, error) {
+	ctx, cancel, err := p.context(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	return p.inner.RequestReturnTribute(ctx, playerSeat, hand)
+}
+
+// sortedVictoryTypes 返回按名称排序的胜利类型，保证报告输出顺序稳定
+func sortedVictoryTypes(counts map[string]int) []string {
+	types := make([]string, 0, len(counts))
+	for victoryType := range counts {
+		types = append(types, victoryType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// ParseSeats 解析逗号分隔的座位算法列表，只给一个名称时四个座位都使用它
+func ParseSeats(s string) ([4]string, error) {
+	var seats [4]string
+	parts := strings.Split(s, ",")
+	switch len(parts) {
+	case 1:
+		for i := range seats {
+			seats[i] = strings.TrimSpace(parts[0])
+		}
+	case 4:
+		for i := range seats {
+			seats[i] = strings.TrimSpace(parts[i])
+		}
+	default:
+		return seats, fmt.Errorf("expected 1 or 4 comma-separated algorithms, got %d", len(parts))
+	}
+	return seats, nil
+}
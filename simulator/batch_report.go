@@ -0,0 +1,110 @@
+package simulator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// 报告格式
+const (
+	ReportText = "text"
+	ReportJSON = "json"
+	ReportCSV  = "csv"
+)
+
+// Write 按指定格式输出报告
+func (r *BatchReport) Write(w io.Writer, format string) error {
+	switch format {
+	case ReportText, "":
+		return r.WriteText(w)
+	case ReportJSON:
+		return r.WriteJSON(w)
+	case ReportCSV:
+		return r.WriteCSV(w)
+	default:
+		return fmt.Errorf("unknown report format: %s", format)
+	}
+}
+
+// WriteText 输出便于阅读的文本报告
+func (r *BatchReport) WriteText(w io.Writer) error {
+	fmt.Fprintln(w, "========== Batch Summary ==========")
+	fmt.Fprintf(w, "Matches: %d (failures: %d), seed %d\n", r.Matches, r.Failures, r.Seed)
+	fmt.Fprintf(w, "Duration: %v (%.2f matches/s)\n", r.Duration, r.MatchesPerSecond)
+	for _, lineup := range r.Lineups {
+		fmt.Fprintf(w, "  %-30s wins %5d  win rate %5.1f%%  95%% CI [%5.1f%%, %5.1f%%]\n",
+			lineup.Name, lineup.Wins, lineup.WinRate*100, lineup.CILow*100, lineup.CIHigh*100)
+	}
+	fmt.Fprintf(w, "Deals: %d (%.2f per match)\n", r.TotalDeals, r.AvgDealsPerMatch)
+	fmt.Fprintln(w, "Victory types:")
+	for _, victoryType := range sortedVictoryTypes(r.VictoryTypes) {
+		count := r.VictoryTypes[victoryType]
+		fmt.Fprintf(w, "  %-14s %6d (%5.1f%%)\n", victoryType, count, percent(count, r.TotalDeals))
+	}
+	fmt.Fprintf(w, "Tribute immunity: %d / %d deals (%.1f%%)\n", r.ImmuneDeals, r.TributeDeals, r.ImmunityRate*100)
+	for _, message := range r.Errors {
+		fmt.Fprintf(w, "Error: %s\n", message)
+	}
+	_, err := fmt.Fprintln(w, "===================================")
+	return err
+}
+
+// WriteJSON 输出 JSON 报告
+func (r *BatchReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV 输出 metric,value 两列的 CSV 报告
+func (r *BatchReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	float := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+
+	rows := [][]string{
+		{"metric", "value"},
+		{"matches", strconv.Itoa(r.Matches)},
+		{"failures", strconv.Itoa(r.Failures)},
+		{"seed", strconv.FormatInt(r.Seed, 10)},
+	}
+	for i, lineup := range r.Lineups {
+		prefix := fmt.Sprintf("lineup%d_", i)
+		rows = append(rows,
+			[]string{prefix + "name", lineup.Name},
+			[]string{prefix + "wins", strconv.Itoa(lineup.Wins)},
+			[]string{prefix + "win_rate", float(lineup.WinRate)},
+			[]string{prefix + "ci_low", float(lineup.CILow)},
+			[]string{prefix + "ci_high", float(lineup.CIHigh)},
+		)
+	}
+	rows = append(rows,
+		[]string{"total_deals", strconv.Itoa(r.TotalDeals)},
+		[]string{"avg_deals_per_match", float(r.AvgDealsPerMatch)},
+	)
+	for _, victoryType := range sortedVictoryTypes(r.VictoryTypes) {
+		rows = append(rows, []string{"victory_" + victoryType, strconv.Itoa(r.VictoryTypes[victoryType])})
+	}
+	rows = append(rows,
+		[]string{"tribute_deals", strconv.Itoa(r.TributeDeals)},
+		[]string{"immune_deals", strconv.Itoa(r.ImmuneDeals)},
+		[]string{"immunity_rate", float(r.ImmunityRate)},
+		[]string{"duration_seconds", float(r.Duration.Seconds())},
+		[]string{"matches_per_second", float(r.MatchesPerSecond)},
+	)
+
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func percent(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) * 100 / float64(total)
+}
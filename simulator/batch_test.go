@@ -0,0 +1,176 @@
+package simulator
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunBatch(t *testing.T) {
+	config := &BatchConfig{
+		Matches:     4,
+		Workers:     2,
+		Seed:        7,
+		Seats:       [4]string{"smart", "simple", "smart", "simple"},
+		RotateSeats: true,
+	}
+	report, err := RunBatch(config)
+	if err != nil {
+		t.Fatalf("RunBatch failed: %v", err)
+	}
+	if report.Matches != 4 || report.Failures != 0 {
+		t.Fatalf("expected 4 successful matches, got %d (failures %d: %v)", report.Matches, report.Failures, report.Errors)
+	}
+	if report.Lineups[0].Wins+report.Lineups[1].Wins != 4 {
+		t.Errorf("lineup wins should add up to matches: %+v", report.Lineups)
+	}
+	if report.Lineups[0].Name != "smart+smart" || report.Lineups[1].Name != "simple+simple" {
+		t.Errorf("unexpected lineup names: %q %q", report.Lineups[0].Name, report.Lineups[1].Name)
+	}
+	total := 0
+	for _, count := range report.VictoryTypes {
+		total += count
+	}
+	if total != report.TotalDeals || report.TotalDeals < 4 {
+		t.Errorf("victory types %v should cover all %d deals", report.VictoryTypes, report.TotalDeals)
+	}
+	if report.ImmuneDeals > report.TributeDeals {
+		t.Errorf("immune deals %d exceed tribute deals %d", report.ImmuneDeals, report.TributeDeals)
+	}
+}
+
+func TestRunBatchDeterministic(t *testing.T) {
+	config := &BatchConfig{
+		Matches: 3,
+		Workers: 3,
+		Seed:    42,
+		Seats:   [4]string{"smart", "expert", "beginner", "simple"},
+	}
+	first, err := RunBatch(config)
+	if err != nil {
+		t.Fatalf("RunBatch failed: %v", err)
+	}
+	second, err := RunBatch(config)
+	if err != nil {
+		t.Fatalf("RunBatch failed: %v", err)
+	}
+	if first.TotalDeals != second.TotalDeals || first.Lineups[0].Wins != second.Lineups[0].Wins {
+		t.Fatalf("same seed produced different results: %d deals/%d wins vs %d deals/%d wins",
+			first.TotalDeals, first.Lineups[0].Wins, second.TotalDeals, second.Lineups[0].Wins)
+	}
+	for victoryType, count := range first.VictoryTypes {
+		if second.VictoryTypes[victoryType] != count {
+			t.Fatalf("victory type %s differs: %d vs %d", victoryType, count, second.VictoryTypes[victoryType])
+		}
+	}
+}
+
+func TestRunBatchInvalidConfig(t *testing.T) {
+	if _, err := RunBatch(&BatchConfig{Matches: 0, Seats: [4]string{"smart", "smart", "smart", "smart"}}); err == nil {
+		t.Error("expected error for zero matches")
+	}
+	if _, err := RunBatch(&BatchConfig{Matches: 1, Seats: [4]string{"smart", "nope", "smart", "smart"}}); err == nil {
+		t.Error("expected error for unknown algorithm")
+	}
+}
+
+func TestWilsonInterval(t *testing.T) {
+	low, high := WilsonInterval(50, 100, 1.96)
+	if low < 0.40 || low > 0.41 || high < 0.59 || high > 0.60 {
+		t.Errorf("unexpected interval for 50/100: [%f, %f]", low, high)
+	}
+	low, high = WilsonInterval(0, 10, 1.96)
+	if low != 0 || high <= 0 || high >= 0.5 {
+		t.Errorf("unexpected interval for 0/10: [%f, %f]", low, high)
+	}
+	if low, high = WilsonInterval(0, 0, 1.96); low != 0 || high != 1 {
+		t.Errorf("no trials should give [0, 1], got [%f, %f]", low, high)
+	}
+}
+
+func TestParseSeats(t *testing.T) {
+	seats, err := ParseSeats("smart")
+	if err != nil || seats != [4]string{"smart", "smart", "smart", "smart"} {
+		t.Errorf("single name: got %v, %v", seats, err)
+	}
+	seats, err = ParseSeats("smart, simple,expert ,beginner")
+	if err != nil || seats != [4]string{"smart", "simple", "expert", "beginner"} {
+		t.Errorf("four names: got %v, %v", seats, err)
+	}
+	if _, err := ParseSeats("smart,simple"); err == nil {
+		t.Error("expected error for two names")
+	}
+}
+
+func TestBatchReportFormats(t *testing.T) {
+	report := &BatchReport{
+		Matches:      10,
+		Seed:         3,
+		Lineups:      [2]LineupStats{{Name: "smart+smart", Wins: 7, WinRate: 0.7}, {Name: "simple+simple", Wins: 3, WinRate: 0.3}},
+		TotalDeals:   40,
+		VictoryTypes: map[string]int{"double_down": 10, "single_last": 30},
+		Duration:     2 * time.Second,
+	}
+
+	var text bytes.Buffer
+	if err := report.Write(&text, ReportText); err != nil {
+		t.Fatalf("text report failed: %v", err)
+	}
+	if !strings.Contains(text.String(), "smart+smart") || !strings.Contains(text.String(), "double_down") {
+		t.Errorf("text report missing content:\n%s", text.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := report.Write(&jsonOut, ReportJSON); err != nil {
+		t.Fatalf("json report failed: %v", err)
+	}
+	var decoded BatchReport
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid json report: %v", err)
+	}
+	if decoded.Lineups[0].Wins != 7 || decoded.VictoryTypes["single_last"] != 30 {
+		t.Errorf("json report lost data: %+v", decoded)
+	}
+
+	var csvOut bytes.Buffer
+	if err := report.Write(&csvOut, ReportCSV); err != nil {
+		t.Fatalf("csv report failed: %v", err)
+	}
+	rows, err := csv.NewReader(&csvOut).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv report: %v", err)
+	}
+	values := make(map[string]string)
+	for _, row := range rows {
+		values[row[0]] = row[1]
+	}
+	if values["lineup0_wins"] != "7" || values["victory_double_down"] != "10" {
+		t.Errorf("csv report missing values: %v", values)
+	}
+
+	if err := report.Write(&bytes.Buffer{}, "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestRunCLIBatch(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := RunCLI([]string{"-n", "2", "-seed", "5", "-seats", "simple", "-format", "json"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+	}
+	var report BatchReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("invalid json output: %v", err)
+	}
+	if report.Matches != 2 || report.Seed != 5 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	if code := RunCLI([]string{"-seats", "a,b"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for invalid seats, got %d", code)
+	}
+}
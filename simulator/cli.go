@@ -0,0 +1,412 @@
+package simulator
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"guandan-world/ai"
+	"guandan-world/sdk"
+)
+
+// RunCLI 命令行入口，simulator/cmd 和 cmd/simulate 共用
+// 参数:
+//
+//	args: 命令行参数（不含程序名）
+//	stdout: 报告输出
+//	stderr: 错误和用法输出
+//
+// 返回值:
+//
+//	int: 进程退出码
+//
+// 功能说明:
+//   - 不带 -n 时与原来一样运行一场比赛并输出详细过程（-q 关闭）
+//   - 带 -n 时批量运行比赛，输出 text/json/csv 格式的汇总报告
+//   - 带 -duplicate A,B 时进行复式评测，-n 为牌的块数
+//   - 子命令 arena 运行评分竞技场（见 runArenaCLI）
+//   - 子命令 tune 调优智能算法的权重（见 runTuneCLI）
+//   - 子命令 fuzz 对游戏引擎进行随机不变量测试（见 runFuzzCLI）
+//   - 子命令 conformance 运行 JSON 规则用例（见 runConformanceCLI）
+func RunCLI(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "arena" {
+		return runArenaCLI(args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "tune" {
+		return runTuneCLI(args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "fuzz" {
+		return runFuzzCLI(args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "conformance" {
+		return runConformanceCLI(args[1:], stdout, stderr)
+	}
+
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	quiet := flags.Bool("q", false, "安静模式，不输出单场比赛的详细过程")
+	matches := flags.Int("n", 0, "批量模拟的比赛场数（0表示运行一场详细比赛）")
+	workers := flags.Int("workers", 1, "并行的工作协程数")
+	seats := flags.String("seats", "smart", "各座位的算法，逗号分隔的4个名称或1个名称（simple、smart、beginner、intermediate、expert、smart:<权重文件>、neural:<权重文件>、process:<外部机器人命令>）")
+	seed := flags.Int64("seed", 0, "基础随机种子，第i场比赛使用 seed+i（0表示使用当前时间）")
+	rotate := flags.Bool("rotate", false, "每场比赛轮换座位")
+	decisionTimeout := flags.Duration("decision-timeout", 0, "单次决策超时（如 2s）")
+	matchTimeout := flags.Duration("match-timeout", 0, "单场比赛超时（如 1m）")
+	format := flags.String("format", ReportText, "报告格式：text、json、csv")
+	datasetPath := flags.String("dataset", "", "把每个决策点以 JSONL 格式导出到该文件，用于离线训练")
+	duplicate := flags.String("duplicate", "", "复式评测的两个算法，逗号分隔，如 smart,expert")
+	deals := flags.Int("deals", 0, "复式评测每桌只打前几局（0表示打完整场比赛）")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	seatNames, err := ParseSeats(*seats)
+	if err != nil {
+		fmt.Fprintf(stderr, "无效的 -seats: %v\n", err)
+		return 2
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	if *duplicate != "" {
+		return runDuplicateCLI(*duplicate, *matches, *workers, *seed, *deals, *decisionTimeout, *matchTimeout, *format, stdout, stderr)
+	}
+
+	// 训练数据导出
+	var dataset *DatasetWriter
+	if *datasetPath != "" {
+		file, err := os.Create(*datasetPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "无法创建数据文件: %v\n", err)
+			return 1
+		}
+		buffered := bufio.NewWriter(file)
+		defer func() {
+			if err := buffered.Flush(); err != nil {
+				fmt.Fprintf(stderr, "写入数据文件失败: %v\n", err)
+			}
+			file.Close()
+		}()
+		dataset = NewDatasetWriter(buffered)
+	}
+
+	if *matches <= 0 {
+		return runSingleMatch(!*quiet, seatNames, *seed, dataset, stdout, stderr)
+	}
+
+	config := &BatchConfig{
+		Matches:         *matches,
+		Workers:         *workers,
+		Seed:            *seed,
+		Seats:           seatNames,
+		RotateSeats:     *rotate,
+		DecisionTimeout: *decisionTimeout,
+		MatchTimeout:    *matchTimeout,
+		Dataset:         dataset,
+	}
+	report, err := RunBatch(config)
+	if err != nil {
+		fmt.Fprintf(stderr, "模拟失败: %v\n", err)
+		return 1
+	}
+	if err := report.Write(stdout, *format); err != nil {
+		fmt.Fprintf(stderr, "输出报告失败: %v\n", err)
+		return 1
+	}
+	if report.Failures > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runDuplicateCLI 运行复式评测并输出报告
+func runDuplicateCLI(algorithms string, boards, workers int, seed int64, deals int, decisionTimeout, matchTimeout time.Duration, format string, stdout, stderr io.Writer) int {
+	names := strings.Split(algorithms, ",")
+	if len(names) != 2 {
+		fmt.Fprintf(stderr, "无效的 -duplicate: 需要两个逗号分隔的算法，得到 %d 个\n", len(names))
+		return 2
+	}
+	if boards <= 0 {
+		boards = 100
+	}
+	report, err := RunDuplicate(&DuplicateConfig{
+		Boards:          boards,
+		Workers:         workers,
+		Seed:            seed,
+		NameA:           strings.TrimSpace(names[0]),
+		NameB:           strings.TrimSpace(names[1]),
+		DealsPerBoard:   deals,
+		DecisionTimeout: decisionTimeout,
+		MatchTimeout:    matchTimeout,
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "复式评测失败: %v\n", err)
+		return 1
+	}
+	if err := report.Write(stdout, format); err != nil {
+		fmt.Fprintf(stderr, "输出报告失败: %v\n", err)
+		return 1
+	}
+	if report.Failures > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runArenaCLI 运行评分竞技场：循环赛、评分持久化、中断后重新运行同一命令即可继续
+func runArenaCLI(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("simulate arena", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	entrants := flags.String("entrants", "simple,smart,expert", "参赛的算法配置，逗号分隔（每个配置组成一支队伍）")
+	rounds := flags.Int("rounds", 2, "循环赛轮数，每轮每对配置交手一次")
+	workers := flags.Int("workers", 1, "并行的工作协程数")
+	seed := flags.Int64("seed", 1, "基础随机种子（恢复时必须与状态文件一致）")
+	statePath := flags.String("state", "", "评分状态文件，存在时从中继续")
+	eloK := flags.Float64("k", DefaultEloK, "Elo K 因子")
+	decisionTimeout := flags.Duration("decision-timeout", 0, "单次决策超时（如 2s）")
+	matchTimeout := flags.Duration("match-timeout", 0, "单场比赛超时（如 1m）")
+	format := flags.String("format", ReportText, "报告格式：text、json、csv")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	names := strings.Split(*entrants, ",")
+	for i := range names {
+		names[i] = strings.TrimSpace(names[i])
+	}
+	arena, err := NewArena(&ArenaConfig{
+		Entrants:        names,
+		Rounds:          *rounds,
+		Workers:         *workers,
+		Seed:            *seed,
+		StatePath:       *statePath,
+		EloK:            *eloK,
+		DecisionTimeout: *decisionTimeout,
+		MatchTimeout:    *matchTimeout,
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "无法创建竞技场: %v\n", err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	runErr := arena.Run(ctx)
+
+	if err := arena.Report().Write(stdout, *format); err != nil {
+		fmt.Fprintf(stderr, "输出报告失败: %v\n", err)
+		return 1
+	}
+	if runErr != nil {
+		if errors.Is(runErr, context.Canceled) {
+			fmt.Fprintln(stderr, "已中断，使用相同的 -state 重新运行即可继续")
+		} else {
+			fmt.Fprintf(stderr, "竞技场运行失败: %v\n", runErr)
+		}
+		return 1
+	}
+	return 0
+}
+
+// runTuneCLI 用遗传算法调优智能算法权重，输出学习曲线并把最优权重写入文件
+// 得到的文件可以通过 "smart:<文件>" 在 -seats、-duplicate 和 arena 中使用
+func runTuneCLI(args []string, stdout, stderr io.Writer) int {
+	defaults := DefaultTunerConfig()
+	flags := flag.NewFlagSet("simulate tune", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	generations := flags.Int("generations", defaults.Generations, "迭代代数")
+	population := flags.Int("population", defaults.Population, "每代个体数")
+	elite := flags.Int("elite", defaults.Elite, "每代直接保留的最优个体数")
+	boards := flags.Int("boards", defaults.Boards, "每个个体与基线复式评测的块数")
+	deals := flags.Int("deals", defaults.DealsPerBoard, "每桌只打前几局（0表示打完整场比赛）")
+	workers := flags.Int("workers", defaults.Workers, "并行评估的个体数")
+	seed := flags.Int64("seed", defaults.Seed, "随机种子")
+	baseline := flags.String("baseline", defaults.Baseline, "基线算法")
+	initial := flags.String("initial", "", "初始权重文件（为空时使用默认权重）")
+	mutation := flags.Float64("mutation", defaults.MutationScale, "变异幅度")
+	out := flags.String("out", "", "最优权重的输出文件")
+	format := flags.String("format", ReportText, "报告格式：text、json、csv")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	config := &TunerConfig{
+		Generations:   *generations,
+		Population:    *population,
+		Elite:         *elite,
+		Boards:        *boards,
+		DealsPerBoard: *deals,
+		Workers:       *workers,
+		Seed:          *seed,
+		Baseline:      *baseline,
+		Initial:       defaults.Initial,
+		MutationScale: *mutation,
+	}
+	if *initial != "" {
+		weights, err := ai.LoadSmartWeights(*initial)
+		if err != nil {
+			fmt.Fprintf(stderr, "无法加载初始权重: %v\n", err)
+			return 2
+		}
+		config.Initial = weights
+	}
+
+	result, err := TuneSmartWeights(config, func(generation TunerGeneration) {
+		fmt.Fprintf(stderr, "generation %d: best %+.3f mean %+.3f (%v)\n",
+			generation.Generation, generation.BestFitness, generation.MeanFitness, generation.Duration)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "调优失败: %v\n", err)
+		return 1
+	}
+	if *out != "" {
+		if err := result.Best.Save(*out); err != nil {
+			fmt.Fprintf(stderr, "保存权重失败: %v\n", err)
+			return 1
+		}
+	}
+	if err := result.Write(stdout, *format); err != nil {
+		fmt.Fprintf(stderr, "输出报告失败: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runFuzzCLI 用连续的种子运行引擎随机不变量测试，发现问题时最小化并保存复现文件
+func runFuzzCLI(args []string, stdout, stderr io.Writer) int {
+	defaults := sdk.DefaultFuzzConfig(1)
+	flags := flag.NewFlagSet("simulate fuzz", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	seed := flags.Int64("seed", defaults.Seed, "起始种子，第i次运行使用 seed+i")
+	runs := flags.Int("runs", 100, "运行次数")
+	steps := flags.Int("steps", defaults.MaxSteps, "每次运行最多执行的动作数")
+	illegal := flags.Float64("illegal", defaults.IllegalRate, "非法动作的概率")
+	disconnect := flags.Float64("disconnect", defaults.DisconnectRate, "断线/重连的概率")
+	timeout := flags.Float64("timeout", defaults.TimeoutRate, "超时的概率")
+	out := flags.String("out", "fuzz_failure.json", "复现文件的输出路径")
+	minimize := flags.Bool("minimize", true, "保存前最小化复现配置")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	totalSteps, totalDeals := 0, 0
+	for i := 0; i < *runs; i++ {
+		config := defaults
+		config.Seed = *seed + int64(i)
+		config.MaxSteps = *steps
+		config.IllegalRate = *illegal
+		config.DisconnectRate = *disconnect
+		config.TimeoutRate = *timeout
+
+		result, err := sdk.RunEngineFuzz(config)
+		var failure *sdk.FuzzFailure
+		if errors.As(err, &failure) {
+			if *minimize {
+				failure = sdk.MinimizeFuzzFailure(failure)
+			}
+			fmt.Fprintf(stdout, "FAIL %v\n", failure)
+			if err := failure.Save(*out); err != nil {
+				fmt.Fprintf(stderr, "保存复现文件失败: %v\n", err)
+			} else {
+				fmt.Fprintf(stdout, "reproduction (%d actions) saved to %s\n", len(failure.Log), *out)
+			}
+			return 1
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "seed %d: %v\n", config.Seed, err)
+			return 1
+		}
+		totalSteps += result.Steps
+		totalDeals += result.Deals
+	}
+	fmt.Fprintf(stdout, "ok: %d runs, %d actions, %d deals, no invariant violations\n", *runs, totalSteps, totalDeals)
+	return 0
+}
+
+// runConformanceCLI 运行目录或文件中的规则用例，有用例不通过时返回1
+func runConformanceCLI(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("simulate conformance", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", ReportText, "报告格式：text、json")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"test-data"}
+	}
+	report, err := sdk.RunConformance(paths...)
+	if err != nil {
+		fmt.Fprintf(stderr, "无法加载用例: %v\n", err)
+		return 2
+	}
+	if err := report.Write(stdout, *format); err != nil {
+		fmt.Fprintf(stderr, "输出报告失败: %v\n", err)
+		return 2
+	}
+	if !report.OK() {
+		return 1
+	}
+	return 0
+}
+
+// runSingleMatch 运行一场带详细输出的比赛
+func runSingleMatch(verbose bool, seats [4]string, seed int64, dataset *DatasetWriter, stdout, stderr io.Writer) int {
+	fmt.Fprintln(stdout, "🀄 掼蛋牌局模拟器 🀄")
+	fmt.Fprintln(stdout, "==================")
+	fmt.Fprintln(stdout)
+
+	// 创建模拟器（使用新架构）
+	sim := NewMatchSimulatorV2(verbose)
+	sim.SetSeed(seed)
+	for seat, name := range seats {
+		if err := sim.SetPlayerAlgorithmByName(seat, name); err != nil {
+			fmt.Fprintf(stderr, "座位 %d: %v\n", seat, err)
+			return 2
+		}
+	}
+	var recorder *DatasetRecorder
+	if dataset != nil {
+		recorder = sim.EnableDatasetExport(dataset)
+	}
+
+	fmt.Fprintln(stdout, "开始模拟掼蛋牌局...")
+	startTime := time.Now()
+
+	// 运行模拟
+	if err := sim.SimulateMatch(); err != nil {
+		fmt.Fprintf(stderr, "模拟失败: %v\n", err)
+		return 1
+	}
+	if recorder != nil && recorder.Err() != nil {
+		fmt.Fprintf(stderr, "写入数据文件失败: %v\n", recorder.Err())
+		return 1
+	}
+
+	// 输出结果
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "🎉 模拟完成！")
+	fmt.Fprintf(stdout, "⏱️  总耗时: %v\n", time.Since(startTime))
+	fmt.Fprintf(stdout, "🎲 随机种子: %d\n", seed)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "✨ 模拟结束")
+	return 0
+}
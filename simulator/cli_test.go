@@ -0,0 +1,41 @@
+package simulator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCLIFuzz(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "failure.json")
+	var stdout, stderr bytes.Buffer
+	code := RunCLI([]string{"fuzz", "-runs", "2", "-steps", "500", "-out", path}, &stdout, &stderr)
+	if code != 0 || !strings.Contains(stdout.String(), "ok: 2 runs") {
+		t.Fatalf("fuzz command failed with %d: %s%s", code, stdout.String(), stderr.String())
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("no reproduction file expected without failures, stat returned %v", err)
+	}
+}
+
+func TestRunCLIConformance(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := RunCLI([]string{"conformance", filepath.Join("..", "test-data", "conformance")}, &stdout, &stderr)
+	if code != 0 || !strings.Contains(stdout.String(), "cases passed") {
+		t.Fatalf("conformance command failed with %d: %s%s", code, stdout.String(), stderr.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "wrong.json")
+	if err := os.WriteFile(path, []byte(`{"level": 2, "classification": [{"cards": "3S 4S", "type": "Pair"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	if code := RunCLI([]string{"conformance", path}, &stdout, &stderr); code != 1 || !strings.Contains(stdout.String(), "input:    [3S 4S]") {
+		t.Errorf("expected failing conformance run, got %d: %s", code, stdout.String())
+	}
+	if code := RunCLI([]string{"conformance", "does-not-exist"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for missing path, got %d", code)
+	}
+}
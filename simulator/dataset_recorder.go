@@ -0,0 +1,283 @@
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"guandan-world/ai"
+	"guandan-world/sdk"
+)
+
+// 决策类型
+const (
+	DecisionPlay          = "play"
+	DecisionTributeSelect = "tribute_select"
+	DecisionReturnTribute = "return_tribute"
+)
+
+// DealOutcome 决策所在牌局的结果（相对于决策座位）
+type DealOutcome struct {
+	Rank        int    `json:"rank"`         // 决策座位的出完名次（1-4）
+	Won         bool   `json:"won"`          // 决策座位所在队伍是否获胜
+	Upgrade     int    `json:"upgrade"`      // 己方升级数减去对方升级数
+	VictoryType string `json:"victory_type"` // 胜利类型
+}
+
+// MatchOutcome 决策所在比赛的结果（相对于决策座位）
+type MatchOutcome struct {
+	Won         bool   `json:"won"`          // 决策座位所在队伍是否赢得比赛
+	FinalLevels [2]int `json:"final_levels"` // 最终级别：[己方, 对方]
+	Deals       int    `json:"deals"`        // 比赛总局数
+}
+
+// DecisionRecord 训练数据中的一条记录，对应一个决策点
+type DecisionRecord struct {
+	Version      int             `json:"version"` // ai.FeatureVersion
+	Match        int64           `json:"match"`   // 比赛序号（同一个 DatasetWriter 内唯一）
+	Deal         int             `json:"deal"`    // 比赛中的第几局（从0开始）
+	Step         int             `json:"step"`    // 牌局中的第几个决策（从0开始）
+	Kind         string          `json:"kind"`    // 决策类型
+	Seat         int             `json:"seat"`
+	Observation  *ai.Observation `json:"observation"`
+	LegalMoves   [][]string      `json:"legal_moves"`  // 合法动作，空列表表示过牌
+	Chosen       []string        `json:"chosen"`       // 实际选择的动作，空列表表示过牌
+	ChosenIndex  int             `json:"chosen_index"` // Chosen 在 LegalMoves 中的下标
+	DealOutcome  *DealOutcome    `json:"deal_outcome"`
+	MatchOutcome *MatchOutcome   `json:"match_outcome"`
+}
+
+// DatasetWriter 把决策记录以 JSONL 格式写出，可以被多个 DatasetRecorder 并发共享
+type DatasetWriter struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+	matches int64
+	records int64
+}
+
+// NewDatasetWriter 创建数据集写出器
+func NewDatasetWriter(w io.Writer) *DatasetWriter {
+	return &DatasetWriter{encoder: json.NewEncoder(w)}
+}
+
+// writeMatch 写出一场比赛的全部记录，并为它们分配比赛序号
+func (dw *DatasetWriter) writeMatch(records []*DecisionRecord) error {
+	dw.mutex.Lock()
+	defer dw.mutex.Unlock()
+
+	id := dw.matches
+	dw.matches++
+	for _, record := range records {
+		record.Match = id
+		if err := dw.encoder.Encode(record); err != nil {
+			return err
+		}
+		dw.records++
+	}
+	return nil
+}
+
+// Stats 返回已写出的比赛数和记录数
+func (dw *DatasetWriter) Stats() (matches, records int64) {
+	dw.mutex.Lock()
+	defer dw.mutex.Unlock()
+	return dw.matches, dw.records
+}
+
+// DatasetRecorder 记录每个决策点的输入提供者装饰器
+// 它把决策转发给内部提供者，同时作为 sdk.EventObserver 跟踪公开信息和对局结果，
+// 比赛结束时把整场比赛的记录写入 DatasetWriter（未完成的比赛不会写出）
+type DatasetRecorder struct {
+	inner   sdk.PlayerInputProvider
+	writer  *DatasetWriter
+	tracker *ai.ObservationTracker
+
+	mutex       sync.Mutex
+	deal        int
+	step        int
+	dealRecords []*DecisionRecord
+	pending     []*DecisionRecord
+	err         error
+}
+
+// NewDatasetRecorder 创建决策记录器
+// 参数:
+//
+//	inner: 实际做决策的输入提供者
+//	writer: 记录写出目标
+func NewDatasetRecorder(inner sdk.PlayerInputProvider, writer *DatasetWriter) *DatasetRecorder {
+	return &DatasetRecorder{
+		inner:   inner,
+		writer:  writer,
+		tracker: ai.NewObservationTracker(),
+		deal:    -1,
+	}
+}
+
+// Err 返回最近一次写出失败的错误
+func (dr *DatasetRecorder) Err() error {
+	dr.mutex.Lock()
+	defer dr.mutex.Unlock()
+	return dr.err
+}
+
+// RequestPlayDecision 实现PlayerInputProvider接口
+func (dr *DatasetRecorder) RequestPlayDecision(ctx context.Context, playerSeat int, hand []*sdk.Card, trickInfo *sdk.TrickInfo) (*sdk.PlayDecision, error) {
+	obs := dr.tracker.Observe(playerSeat, hand, trickInfo)
+	decision, err := dr.inner.RequestPlayDecision(ctx, playerSeat, hand, trickInfo)
+	if err != nil || decision == nil {
+		return decision, err
+	}
+
+	legal := ai.EnumerateLegalPlays(hand, trickInfo)
+	if trickInfo != nil && !trickInfo.IsLeader {
+		legal = append(legal, nil)
+	}
+
+	var chosen []*sdk.Card
+	if decision.Action == sdk.ActionPlay {
+		chosen = decision.Cards
+	}
+	dr.record(DecisionPlay, playerSeat, obs, legal, chosen)
+	return decision, nil
+}
+
+// RequestTributeSelection 实现PlayerInputProvider接口
+func (dr *DatasetRecorder) RequestTributeSelection(ctx context.Context, playerSeat int, options []*sdk.Card) (*sdk.Card, error) {
+	obs := dr.tracker.Observe(playerSeat, options, nil)
+	card, err := dr.inner.RequestTributeSelection(ctx, playerSeat, options)
+	if err == nil && card != nil {
+		dr.record(DecisionTributeSelect, playerSeat, obs, singles(options), []*sdk.Card{card})
+	}
+	return card, err
+}
+
+// RequestReturnTribute 实现PlayerInputProvider接口
+func (dr *DatasetRecorder) RequestReturnTribute(ctx context.Context, playerSeat int, hand []*sdk.Card) (*sdk.Card, error) {
+	obs := dr.tracker.Observe(playerSeat, hand, nil)
+	card, err := dr.inner.RequestReturnTribute(ctx, playerSeat, hand)
+	if err == nil && card != nil {
+		dr.record(DecisionReturnTribute, playerSeat, obs, singles(hand), []*sdk.Card{card})
+	}
+	return card, err
+}
+
+// record 保存一个决策点，所选动作不在枚举的合法动作中时补充进去，保证监督目标总是存在
+func (dr *DatasetRecorder) record(kind string, seat int, obs *ai.Observation, legal [][]*sdk.Card, chosen []*sdk.Card) {
+	record := &DecisionRecord{
+		Version:     ai.FeatureVersion,
+		Seat:        seat,
+		Kind:        kind,
+		Observation: obs,
+		LegalMoves:  make([][]string, 0, len(legal)+1),
+		Chosen:      cardIDList(chosen),
+		ChosenIndex: -1,
+	}
+
+	chosenKey := moveKey(chosen)
+	for i, move := range legal {
+		record.LegalMoves = append(record.LegalMoves, cardIDList(move))
+		if record.ChosenIndex < 0 && moveKey(move) == chosenKey {
+			record.ChosenIndex = i
+		}
+	}
+	if record.ChosenIndex < 0 {
+		record.ChosenIndex = len(record.LegalMoves)
+		record.LegalMoves = append(record.LegalMoves, record.Chosen)
+	}
+
+	dr.mutex.Lock()
+	defer dr.mutex.Unlock()
+	record.Deal = dr.deal
+	record.Step = dr.step
+	dr.step++
+	dr.dealRecords = append(dr.dealRecords, record)
+}
+
+// OnGameEvent 实现 sdk.EventObserver
+func (dr *DatasetRecorder) OnGameEvent(event *sdk.GameEvent) {
+	dr.tracker.OnGameEvent(event)
+
+	data, _ := event.Data.(map[string]interface{})
+
+	dr.mutex.Lock()
+	defer dr.mutex.Unlock()
+
+	switch event.Type {
+	case sdk.EventMatchStarted:
+		dr.deal = -1
+		dr.dealRecords = nil
+		dr.pending = nil
+	case sdk.EventDealStarted:
+		dr.deal++
+		dr.step = 0
+		dr.dealRecords = nil
+	case sdk.EventDealEnded:
+		if result, ok := data["result"].(*sdk.DealResult); ok && result != nil {
+			for _, record := range dr.dealRecords {
+				record.DealOutcome = dealOutcomeFor(record.Seat, result)
+			}
+		}
+		dr.pending = append(dr.pending, dr.dealRecords...)
+		dr.dealRecords = nil
+	case sdk.EventMatchEnded:
+		winner, _ := data["winner"].(int)
+		levels, _ := data["final_levels"].([2]int)
+		for _, record := range dr.pending {
+			team := record.Seat % 2
+			record.MatchOutcome = &MatchOutcome{
+				Won:         winner == team,
+				FinalLevels: [2]int{levels[team], levels[1-team]},
+				Deals:       dr.deal + 1,
+			}
+		}
+		if err := dr.writer.writeMatch(dr.pending); err != nil {
+			dr.err = err
+		}
+		dr.pending = nil
+	}
+}
+
+// dealOutcomeFor 计算牌局结果相对于指定座位的表示
+func dealOutcomeFor(seat int, result *sdk.DealResult) *DealOutcome {
+	team := seat % 2
+	outcome := &DealOutcome{
+		Won:         result.WinningTeam == team,
+		Upgrade:     result.Upgrades[team] - result.Upgrades[1-team],
+		VictoryType: string(result.VictoryType),
+	}
+	for rank, s := range result.Rankings {
+		if s == seat {
+			outcome.Rank = rank + 1
+			break
+		}
+	}
+	return outcome
+}
+
+// singles 把每张可选的牌作为一个单独的动作
+func singles(cards []*sdk.Card) [][]*sdk.Card {
+	moves := make([][]*sdk.Card, len(cards))
+	for i, card := range cards {
+		moves[i] = []*sdk.Card{card}
+	}
+	return moves
+}
+
+func cardIDList(cards []*sdk.Card) []string {
+	ids := make([]string, 0, len(cards))
+	for _, card := range cards {
+		ids = append(ids, card.GetID())
+	}
+	return ids
+}
+
+// moveKey 生成与牌的顺序无关的动作标识
+func moveKey(cards []*sdk.Card) string {
+	ids := cardIDList(cards)
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
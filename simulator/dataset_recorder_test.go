@@ -0,0 +1,75 @@
+package simulator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"guandan-world/ai"
+)
+
+func TestDatasetExport(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewDatasetWriter(&buf)
+
+	sim := NewMatchSimulatorV2(false)
+	recorder := sim.EnableDatasetExport(writer)
+	if err := sim.SimulateMatch(); err != nil {
+		t.Fatalf("Failed to simulate match: %v", err)
+	}
+	if err := recorder.Err(); err != nil {
+		t.Fatalf("Failed to write dataset: %v", err)
+	}
+
+	matches, records := writer.Stats()
+	if matches != 1 || records == 0 {
+		t.Fatalf("expected one match of records, got %d matches %d records", matches, records)
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	count := int64(0)
+	plays := 0
+	for scanner.Scan() {
+		var record DecisionRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid JSONL line %d: %v", count, err)
+		}
+		count++
+
+		if record.Version != ai.FeatureVersion {
+			t.Errorf("unexpected feature version %d", record.Version)
+		}
+		if record.Observation == nil || record.Observation.Seat != record.Seat {
+			t.Fatalf("record %d has no observation for its seat", count)
+		}
+		if record.ChosenIndex < 0 || record.ChosenIndex >= len(record.LegalMoves) {
+			t.Fatalf("record %d: chosen index %d out of range", count, record.ChosenIndex)
+		}
+		if len(record.LegalMoves[record.ChosenIndex]) != len(record.Chosen) {
+			t.Errorf("record %d: chosen move does not match legal move", count)
+		}
+		if record.DealOutcome == nil || record.DealOutcome.Rank < 1 || record.DealOutcome.Rank > 4 {
+			t.Fatalf("record %d: missing deal outcome", count)
+		}
+		if record.MatchOutcome == nil || record.MatchOutcome.Deals == 0 {
+			t.Fatalf("record %d: missing match outcome", count)
+		}
+		if record.Kind == DecisionPlay {
+			plays++
+			if len(record.Observation.Hand) == 0 {
+				t.Errorf("record %d: play decision without hand", count)
+			}
+		}
+		if len(ai.EncodeObservation(record.Observation)) != ai.ObservationFeatureSize {
+			t.Fatalf("record %d: wrong feature size", count)
+		}
+	}
+	if count != records {
+		t.Errorf("expected %d lines, got %d", records, count)
+	}
+	if plays == 0 {
+		t.Error("expected play decisions in the dataset")
+	}
+}
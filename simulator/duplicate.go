@@ -0,0 +1,383 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"guandan-world/ai"
+	"guandan-world/sdk"
+)
+
+// DuplicateConfig 复式评测配置
+// 每块牌（board）用同一个种子打两桌：第一桌 A 坐座位0、2，B 坐座位1、3；
+// 第二桌交换座位，A 拿到第一桌 B 的牌。两桌的得分相加后，发牌运气基本抵消
+type DuplicateConfig struct {
+	Boards          int                 // 牌的块数（每块打两桌）
+	Workers         int                 // 并行的工作协程数
+	Seed            int64               // 基础种子，第i块牌使用 Seed+i
+	NameA           string              // 算法A的名称（见 ai.NewAlgorithmByName）
+	NameB           string              // 算法B的名称
+	A               ai.AlgorithmFactory // 算法A（为nil时按 NameA 创建），用于比较未注册名称的算法
+	B               ai.AlgorithmFactory // 算法B（为nil时按 NameB 创建）
+	DealsPerBoard   int                 // 每桌只打前几局（0表示打完整场比赛）
+	DecisionTimeout time.Duration       // 单次决策超时（0表示使用驱动器默认值）
+	MatchTimeout    time.Duration       // 单桌比赛超时（0表示不限制）
+}
+
+// DuplicateTable 一桌的结果
+type DuplicateTable struct {
+	ASeats int    `json:"a_seats"` // A 所在的队伍（0表示座位0、2，1表示座位1、3）
+	Deals  int    `json:"deals"`
+	Score  int    `json:"score"`            // A 队累计升级数 - B 队累计升级数
+	Winner string `json:"winner,omitempty"` // 整场比赛的胜者 "A" 或 "B"（只打部分局时为空）
+}
+
+// DuplicateBoard 一块牌的结果
+type DuplicateBoard struct {
+	Board  int               `json:"board"`
+	Seed   int64             `json:"seed"`
+	Tables [2]DuplicateTable `json:"tables"`
+	Score  int               `json:"score"` // 两桌得分之和，正数表示 A 更好
+}
+
+// DuplicateReport 复式评测报告
+type DuplicateReport struct {
+	Boards      int              `json:"boards"`   // 完成的块数
+	Failures    int              `json:"failures"` // 出错或超时的块数
+	Seed        int64            `json:"seed"`
+	NameA       string           `json:"name_a"`
+	NameB       string           `json:"name_b"`
+	BoardsWonA  int              `json:"boards_won_a"` // A 得分为正的块数
+	BoardsWonB  int              `json:"boards_won_b"`
+	BoardsTied  int              `json:"boards_tied"`
+	MatchWinsA  int              `json:"match_wins_a"` // 两桌合计 A 赢下的整场比赛数
+	MatchWinsB  int              `json:"match_wins_b"`
+	MeanScore   float64          `json:"mean_score"` // 每块牌 A 的平均得分差
+	StdDev      float64          `json:"std_dev"`
+	StdErr      float64          `json:"std_err"`
+	CILow       float64          `json:"ci_low"`  // 平均得分差的95%置信区间（t分布）
+	CIHigh      float64          `json:"ci_high"` // 平均得分差的95%置信区间上限
+	TStat       float64          `json:"t_stat"`  // 配对t检验统计量（标准误为0时为0）
+	PValue      float64          `json:"p_value"` // 双侧p值，原假设为两个算法水平相同
+	Significant bool             `json:"significant"`
+	Duration    time.Duration    `json:"duration"`
+	Results     []DuplicateBoard `json:"results,omitempty"` // 按块号排序的逐块结果
+	Errors      []string         `json:"errors,omitempty"`
+}
+
+// duplicateOutcome 单块牌的结果
+type duplicateOutcome struct {
+	board *DuplicateBoard
+	err   error
+}
+
+// upgradeObserver 累计每个队伍在各局中的升级数
+type upgradeObserver struct {
+	upgrades [2]int
+}
+
+func (o *upgradeObserver) OnGameEvent(event *sdk.GameEvent) {
+	if event.Type != sdk.EventDealEnded {
+		return
+	}
+	if data, ok := event.Data.(map[string]interface{}); ok {
+		if result, ok := data["result"].(*sdk.DealResult); ok && result != nil {
+			o.upgrades[0] += result.Upgrades[0]
+			o.upgrades[1] += result.Upgrades[1]
+		}
+	}
+}
+
+// RunDuplicate 运行复式评测，比较两个算法
+// 参数:
+//
+//	config: 复式评测配置
+//
+// 返回值:
+//
+//	*DuplicateReport: 评测报告（单块牌出错计入 Failures，不中断整体运行）
+//	error: 配置无效时返回错误
+//
+// 功能说明:
+//   - 两桌使用相同的比赛种子，因此每一局的发牌完全相同，只是两个算法互换了座位
+//   - 每块牌的得分差用配对t检验判断两个算法的差异是否显著
+func RunDuplicate(config *DuplicateConfig) (*DuplicateReport, error) {
+	if config.Boards <= 0 {
+		return nil, errors.New("boards must be positive")
+	}
+	factoryA, err := resolveFactory(config.A, config.NameA)
+	if err != nil {
+		return nil, fmt.Errorf("algorithm A: %w", err)
+	}
+	factoryB, err := resolveFactory(config.B, config.NameB)
+	if err != nil {
+		return nil, fmt.Errorf("algorithm B: %w", err)
+	}
+	workers := config.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	startTime := time.Now()
+	jobs := make(chan int)
+	outcomes := make(chan *duplicateOutcome)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				outcomes <- runDuplicateBoard(config, factoryA, factoryB, index)
+			}
+		}()
+	}
+	go func() {
+		for i := 0; i < config.Boards; i++ {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	report := &DuplicateReport{
+		Seed:  config.Seed,
+		NameA: config.NameA,
+		NameB: config.NameB,
+	}
+	for outcome := range outcomes {
+		if outcome.err != nil {
+			report.Failures++
+			if len(report.Errors) < 5 {
+				report.Errors = append(report.Errors, outcome.err.Error())
+			}
+			continue
+		}
+		report.Results = append(report.Results, *outcome.board)
+	}
+	sort.Slice(report.Results, func(i, j int) bool {
+		return report.Results[i].Board < report.Results[j].Board
+	})
+
+	scores := make([]float64, 0, len(report.Results))
+	for _, board := range report.Results {
+		scores = append(scores, float64(board.Score))
+		switch {
+		case board.Score > 0:
+			report.BoardsWonA++
+		case board.Score < 0:
+			report.BoardsWonB++
+		default:
+			report.BoardsTied++
+		}
+		for _, table := range board.Tables {
+			switch table.Winner {
+			case "A":
+				report.MatchWinsA++
+			case "B":
+				report.MatchWinsB++
+			}
+		}
+	}
+	report.Boards = len(report.Results)
+
+	test := PairedTTest(scores)
+	report.MeanScore = test.Mean
+	report.StdDev = test.StdDev
+	report.StdErr = test.StdErr
+	report.CILow = test.CILow
+	report.CIHigh = test.CIHigh
+	report.TStat = test.T
+	report.PValue = test.P
+	report.Significant = report.Boards >= 2 && test.P < 0.05
+	report.Duration = time.Since(startTime)
+	return report, nil
+}
+
+// resolveFactory 优先使用给定的算法工厂，否则按名称创建
+func resolveFactory(factory ai.AlgorithmFactory, name string) (ai.AlgorithmFactory, error) {
+	if factory != nil {
+		return factory, nil
+	}
+	return ai.NewAlgorithmFactory(name)
+}
+
+// runDuplicateBoard 用同一个种子打两桌，A 依次坐在队伍0和队伍1
+func runDuplicateBoard(config *DuplicateConfig, factoryA, factoryB ai.AlgorithmFactory, index int) *duplicateOutcome {
+	seed := config.Seed + int64(index)
+	board := &DuplicateBoard{Board: index, Seed: seed}
+
+	for table := 0; table < 2; table++ {
+		var factories [4]ai.AlgorithmFactory
+		for seat := 0; seat < 4; seat++ {
+			if seat%2 == table {
+				factories[seat] = factoryA
+			} else {
+				factories[seat] = factoryB
+			}
+		}
+		sim := newSeededSimulator(seed, factories, config.DecisionTimeout, config.MatchTimeout)
+		sim.SetMaxDeals(config.DealsPerBoard)
+		upgrades := &upgradeObserver{}
+		sim.AddObserver(upgrades)
+
+		result, err := sim.RunMatch()
+		if err != nil {
+			return &duplicateOutcome{err: fmt.Errorf("board %d table %d (seed %d): %w", index, table, seed, err)}
+		}
+
+		tableResult := DuplicateTable{
+			ASeats: table,
+			Deals:  result.DealCount,
+			Score:  upgrades.upgrades[table] - upgrades.upgrades[1-table],
+		}
+		switch result.Winner {
+		case table:
+			tableResult.Winner = "A"
+		case 1 - table:
+			tableResult.Winner = "B"
+		}
+		board.Tables[table] = tableResult
+		board.Score += tableResult.Score
+	}
+	return &duplicateOutcome{board: board}
+}
+
+// TTestResult 配对t检验结果
+type TTestResult struct {
+	Mean   float64
+	StdDev float64
+	StdErr float64
+	T      float64
+	P      float64 // 双侧p值
+	CILow  float64 // 均值的95%置信区间
+	CIHigh float64
+}
+
+// PairedTTest 对逐块得分差做配对t检验（原假设：均值为0）
+// 样本少于2个时 P 为1；标准误为0时 T 为0，均值非0则 P 为0
+func PairedTTest(diffs []float64) TTestResult {
+	result := TTestResult{P: 1}
+	n := len(diffs)
+	if n == 0 {
+		return result
+	}
+	for _, d := range diffs {
+		result.Mean += d
+	}
+	result.Mean /= float64(n)
+	result.CILow, result.CIHigh = result.Mean, result.Mean
+	if n < 2 {
+		return result
+	}
+
+	sumSquares := 0.0
+	for _, d := range diffs {
+		sumSquares += (d - result.Mean) * (d - result.Mean)
+	}
+	result.StdDev = math.Sqrt(sumSquares / float64(n-1))
+	result.StdErr = result.StdDev / math.Sqrt(float64(n))
+	df := float64(n - 1)
+
+	if result.StdErr == 0 {
+		if result.Mean != 0 {
+			result.P = 0
+		}
+		return result
+	}
+	result.T = result.Mean / result.StdErr
+	result.P = StudentTTwoSided(result.T, df)
+	margin := studentTCritical(0.05, df) * result.StdErr
+	result.CILow, result.CIHigh = result.Mean-margin, result.Mean+margin
+	return result
+}
+
+// StudentTTwoSided 返回自由度为 df 的t分布中 |T| >= |t| 的概率
+func StudentTTwoSided(t, df float64) float64 {
+	return regularizedIncompleteBeta(df/(df+t*t), df/2, 0.5)
+}
+
+// studentTCritical 返回双侧显著性水平为 alpha 的t分布临界值（二分查找）
+func studentTCritical(alpha, df float64) float64 {
+	low, high := 0.0, 1000.0
+	for i := 0; i < 100; i++ {
+		mid := (low + high) / 2
+		if StudentTTwoSided(mid, df) > alpha {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
+
+// regularizedIncompleteBeta 计算正则化不完全贝塔函数 I_x(a, b)（连分式展开）
+func regularizedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lgammaA, _ := math.Lgamma(a)
+	lgammaB, _ := math.Lgamma(b)
+	lgammaAB, _ := math.Lgamma(a + b)
+	front := math.Exp(lgammaAB - lgammaA - lgammaB + a*math.Log(x) + b*math.Log(1-x))
+	// 连分式在 x < (a+1)/(a+b+2) 时收敛较快，否则利用对称性 I_x(a,b) = 1 - I_{1-x}(b,a)
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+// betaContinuedFraction 不完全贝塔函数的连分式（Lentz算法）
+func betaContinuedFraction(x, a, b float64) float64 {
+	const (
+		maxIterations = 200
+		epsilon       = 1e-12
+		tiny          = 1e-300
+	)
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+		// 偶数项
+		numerator := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 + numerator*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + numerator/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+		// 奇数项
+		numerator = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 + numerator*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + numerator/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
@@ -0,0 +1,75 @@
+package simulator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Write 按指定格式输出复式评测报告
+func (r *DuplicateReport) Write(w io.Writer, format string) error {
+	switch format {
+	case ReportText, "":
+		return r.WriteText(w)
+	case ReportJSON:
+		return r.WriteJSON(w)
+	case ReportCSV:
+		return r.WriteCSV(w)
+	default:
+		return fmt.Errorf("unknown report format: %s", format)
+	}
+}
+
+// WriteText 输出便于阅读的文本报告
+func (r *DuplicateReport) WriteText(w io.Writer) error {
+	fmt.Fprintln(w, "======== Duplicate Summary ========")
+	fmt.Fprintf(w, "A: %s  vs  B: %s\n", r.NameA, r.NameB)
+	fmt.Fprintf(w, "Boards: %d (failures: %d), seed %d, duration %v\n", r.Boards, r.Failures, r.Seed, r.Duration)
+	fmt.Fprintf(w, "Boards won: A %d  B %d  tied %d\n", r.BoardsWonA, r.BoardsWonB, r.BoardsTied)
+	fmt.Fprintf(w, "Match wins: A %d  B %d\n", r.MatchWinsA, r.MatchWinsB)
+	fmt.Fprintf(w, "Score (A - B levels per board): mean %+.3f  sd %.3f  95%% CI [%+.3f, %+.3f]\n",
+		r.MeanScore, r.StdDev, r.CILow, r.CIHigh)
+	verdict := "not significant"
+	if r.Significant {
+		verdict = "significant at 5%"
+	}
+	fmt.Fprintf(w, "Paired t-test: t = %.3f, p = %.4f (%s)\n", r.TStat, r.PValue, verdict)
+	for _, message := range r.Errors {
+		fmt.Fprintf(w, "Error: %s\n", message)
+	}
+	_, err := fmt.Fprintln(w, "===================================")
+	return err
+}
+
+// WriteJSON 输出 JSON 报告（包含逐块结果）
+func (r *DuplicateReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV 输出逐块结果的 CSV，每块一行，便于进一步分析
+func (r *DuplicateReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{{"board", "seed", "table0_deals", "table0_score", "table0_winner", "table1_deals", "table1_score", "table1_winner", "score"}}
+	for _, board := range r.Results {
+		rows = append(rows, []string{
+			strconv.Itoa(board.Board),
+			strconv.FormatInt(board.Seed, 10),
+			strconv.Itoa(board.Tables[0].Deals),
+			strconv.Itoa(board.Tables[0].Score),
+			board.Tables[0].Winner,
+			strconv.Itoa(board.Tables[1].Deals),
+			strconv.Itoa(board.Tables[1].Score),
+			board.Tables[1].Winner,
+			strconv.Itoa(board.Score),
+		})
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,117 @@
+package simulator
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPairedTTest(t *testing.T) {
+	result := PairedTTest([]float64{1, 2, 3, 4, 5})
+	if result.Mean != 3 || math.Abs(result.StdDev-1.5811) > 1e-4 {
+		t.Errorf("unexpected mean/sd: %+v", result)
+	}
+	if math.Abs(result.T-4.2426) > 1e-4 || math.Abs(result.P-0.01324) > 1e-4 {
+		t.Errorf("unexpected t/p: t=%f p=%f", result.T, result.P)
+	}
+	// t(0.975, 4) = 2.7764
+	if math.Abs(result.CILow-(3-2.7764*result.StdErr)) > 1e-3 || math.Abs(result.CIHigh-(3+2.7764*result.StdErr)) > 1e-3 {
+		t.Errorf("unexpected CI: [%f, %f]", result.CILow, result.CIHigh)
+	}
+
+	if p := StudentTTwoSided(2, 10); math.Abs(p-0.07339) > 1e-4 {
+		t.Errorf("StudentTTwoSided(2, 10) = %f, want 0.07339", p)
+	}
+	if p := StudentTTwoSided(0, 7); math.Abs(p-1) > 1e-9 {
+		t.Errorf("StudentTTwoSided(0, 7) = %f, want 1", p)
+	}
+
+	if result := PairedTTest([]float64{0, 0, 0}); result.P != 1 || result.T != 0 {
+		t.Errorf("all ties should not be significant: %+v", result)
+	}
+	if result := PairedTTest([]float64{2, 2, 2}); result.P != 0 {
+		t.Errorf("constant non-zero difference should have p = 0: %+v", result)
+	}
+	if result := PairedTTest([]float64{4}); result.P != 1 {
+		t.Errorf("single board should have p = 1: %+v", result)
+	}
+}
+
+func TestRunDuplicateMirror(t *testing.T) {
+	// 同一算法对打时，两桌的牌和决策完全镜像，每块牌的得分差都应该是0
+	report, err := RunDuplicate(&DuplicateConfig{
+		Boards:        6,
+		Workers:       3,
+		Seed:          9,
+		NameA:         "smart",
+		NameB:         "smart",
+		DealsPerBoard: 1,
+	})
+	if err != nil {
+		t.Fatalf("RunDuplicate failed: %v", err)
+	}
+	if report.Boards != 6 || report.Failures != 0 {
+		t.Fatalf("expected 6 boards, got %d (failures %d: %v)", report.Boards, report.Failures, report.Errors)
+	}
+	for i, board := range report.Results {
+		if board.Board != i {
+			t.Errorf("results not sorted by board: %d at %d", board.Board, i)
+		}
+		if board.Tables[0].Deals != 1 || board.Tables[1].Deals != 1 {
+			t.Errorf("board %d: expected one deal per table, got %+v", i, board.Tables)
+		}
+		if board.Tables[0].Score == 0 || board.Score != 0 {
+			t.Errorf("board %d: expected mirrored non-zero table scores, got %+v", i, board)
+		}
+	}
+	if report.BoardsTied != 6 || report.Significant {
+		t.Errorf("identical algorithms should tie every board: %+v", report)
+	}
+}
+
+func TestRunDuplicateDifferentAlgorithms(t *testing.T) {
+	report, err := RunDuplicate(&DuplicateConfig{
+		Boards:  4,
+		Workers: 2,
+		Seed:    1,
+		NameA:   "smart",
+		NameB:   "simple",
+	})
+	if err != nil {
+		t.Fatalf("RunDuplicate failed: %v", err)
+	}
+	if report.Boards != 4 || report.MatchWinsA+report.MatchWinsB != 8 {
+		t.Fatalf("expected 8 finished matches, got %+v", report)
+	}
+	if report.BoardsWonA+report.BoardsWonB+report.BoardsTied != 4 {
+		t.Errorf("board counts do not add up: %+v", report)
+	}
+	if report.CILow > report.MeanScore || report.CIHigh < report.MeanScore {
+		t.Errorf("mean %f outside CI [%f, %f]", report.MeanScore, report.CILow, report.CIHigh)
+	}
+
+	var out bytes.Buffer
+	if err := report.Write(&out, ReportCSV); err != nil {
+		t.Fatalf("csv report failed: %v", err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil || len(rows) != 5 {
+		t.Fatalf("expected header and 4 board rows, got %d rows (%v)", len(rows), err)
+	}
+	out.Reset()
+	if err := report.Write(&out, ReportText); err != nil || !strings.Contains(out.String(), "Paired t-test") {
+		t.Errorf("text report missing t-test line: %v\n%s", err, out.String())
+	}
+}
+
+func TestRunDuplicateInvalidConfig(t *testing.T) {
+	if _, err := RunDuplicate(&DuplicateConfig{Boards: 1, NameA: "smart", NameB: "nope"}); err == nil {
+		t.Error("expected error for unknown algorithm")
+	}
+	var stdout, stderr bytes.Buffer
+	if code := RunCLI([]string{"-duplicate", "smart"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for a single duplicate algorithm, got %d", code)
+	}
+}
@@ -11,9 +11,13 @@ import (
 // MatchSimulatorV2 重构后的比赛模拟器
 // 新架构：专注于输入提供和事件观察，游戏循环由SDK内部处理
 type MatchSimulatorV2 struct {
+	engine        *sdk.GameEngine          // 游戏引擎
 	driver        *sdk.GameDriver          // 游戏驱动器
 	inputProvider *SimulatingInputProvider // 模拟输入提供者
+	inputRouter   *sdk.SeatInputRouter     // 按座位分发输入（外部机器人等）
+	input         sdk.PlayerInputProvider  // 驱动器实际使用的输入提供者
 	observer      *MatchSimulatorObserver  // 事件观察者
+	matchTimeout  time.Duration            // 整场比赛的时间上限（0表示不限制）
 	verbose       bool                     // 是否详细输出
 	eventLog      []string                 // 事件日志
 }
@@ -32,9 +36,15 @@ func NewMatchSimulatorV2(verbose bool) *MatchSimulatorV2 {
 	// 创建事件观察者
 	observer := NewMatchSimulatorObserver(engine, verbose, nil)
 
+	// 默认所有座位使用模拟输入提供者，可按座位替换为外部机器人
+	inputRouter := sdk.NewSeatInputRouter(inputProvider)
+
 	simulator := &MatchSimulatorV2{
+		engine:        engine,
 		driver:        driver,
 		inputProvider: inputProvider,
+		inputRouter:   inputRouter,
+		input:         inputRouter,
 		observer:      observer,
 		verbose:       verbose,
 		eventLog:      make([]string, 0),
@@ -44,10 +54,11 @@ func NewMatchSimulatorV2(verbose bool) *MatchSimulatorV2 {
 	observer.SetLogger(simulator.log)
 
 	// 设置输入提供者
-	driver.SetInputProvider(inputProvider)
+	driver.SetInputProvider(inputRouter)
 
 	// 添加观察者
 	driver.AddObserver(observer)
+	driver.AddObserver(inputProvider) // 为对手手牌推断提供事件
 
 	return simulator
 }
@@ -55,12 +66,33 @@ func NewMatchSimulatorV2(verbose bool) *MatchSimulatorV2 {
 // SimulateMatch 模拟完整比赛
 // 新架构下，这个方法变得非常简洁
 func (ms *MatchSimulatorV2) SimulateMatch() error {
+	result, err := ms.RunMatch()
+	if err != nil {
+		return err
+	}
+
+	// 打印最终结果
+	ms.printMatchSummary(result)
+
+	return nil
+}
+
+// RunMatch 运行完整比赛并返回结果，不打印比赛总结（用于批量模拟）
+func (ms *MatchSimulatorV2) RunMatch() (*sdk.GameDriverResult, error) {
 	// 创建4个模拟玩家
 	players := ms.createPlayers()
 
 	// 设置玩家算法
 	if err := ms.setupPlayerAlgorithms(); err != nil {
-		return fmt.Errorf("failed to setup player algorithms: %w", err)
+		return nil, fmt.Errorf("failed to setup player algorithms: %w", err)
+	}
+	// 比赛结束或中止后结束外部机器人进程，再次比赛时按需重新启动
+	defer ms.inputProvider.Close()
+
+	// 整场比赛超时后，后续的决策请求都返回错误，驱动器随之中止比赛
+	if ms.matchTimeout > 0 {
+		ms.driver.SetInputProvider(newDeadlineInputProvider(ms.input, time.Now().Add(ms.matchTimeout)))
+		defer ms.driver.SetInputProvider(ms.input)
 	}
 
 	ms.log("Match started with 4 players")
@@ -71,13 +103,10 @@ func (ms *MatchSimulatorV2) SimulateMatch() error {
 	// 运行比赛（所有游戏逻辑都在SDK内部）
 	result, err := ms.driver.RunMatch(players)
 	if err != nil {
-		return fmt.Errorf("failed to run match: %w", err)
+		return nil, fmt.Errorf("failed to run match: %w", err)
 	}
 
-	// 打印最终结果
-	ms.printMatchSummary(result)
-
-	return nil
+	return result, nil
 }
 
 // createPlayers 创建4个玩家
@@ -98,14 +127,16 @@ func (ms *MatchSimulatorV2) createPlayers() []sdk.Player {
 }
 
 // setupPlayerAlgorithms 设置玩家算法
+// 已通过 SetPlayerAlgorithm 指定算法的座位保持不变
 func (ms *MatchSimulatorV2) setupPlayerAlgorithms() error {
-	algorithms := make([]ai.AutoPlayAlgorithm, 4)
-
 	for i := 0; i < 4; i++ {
-		algorithms[i] = ai.NewSmartAutoPlayAlgorithm(2) // 从2级开始 - 使用智能算法
+		if ms.inputProvider.HasPlayerAlgorithm(i) {
+			continue
+		}
+		ms.inputProvider.SetPlayerAlgorithm(i, ai.NewSmartAutoPlayAlgorithm(2)) // 从2级开始 - 使用智能算法
 	}
 
-	return ms.inputProvider.BatchSetAlgorithms(algorithms)
+	return nil
 }
 
 // printMatchSummary 打印比赛总结
@@ -167,11 +198,62 @@ func (ms *MatchSimulatorV2) GetEngine() sdk.GameEngineInterface {
 	return ms.driver.GetEngine()
 }
 
+// SetSeed 设置发牌随机种子，使比赛可以复现
+// 算法自身的随机性需要在创建算法时单独设置种子
+func (ms *MatchSimulatorV2) SetSeed(seed int64) {
+	ms.engine.SetRandomSeed(seed)
+}
+
+// SetDecisionTimeout 设置单次出牌和贡牌决策的超时时间
+func (ms *MatchSimulatorV2) SetDecisionTimeout(timeout time.Duration) {
+	config := ms.driver.GetConfig()
+	config.PlayDecisionTimeout = timeout
+	config.TributeTimeout = timeout
+}
+
+// SetMatchTimeout 设置整场比赛的时间上限，超时后比赛以错误结束
+func (ms *MatchSimulatorV2) SetMatchTimeout(timeout time.Duration) {
+	ms.matchTimeout = timeout
+}
+
+// SetMaxDeals 只运行前 maxDeals 局（0表示打完整场比赛）
+func (ms *MatchSimulatorV2) SetMaxDeals(maxDeals int) {
+	ms.driver.GetConfig().MaxDeals = maxDeals
+}
+
 // SetPlayerAlgorithm 设置特定玩家的算法
 func (ms *MatchSimulatorV2) SetPlayerAlgorithm(playerSeat int, algorithm ai.AutoPlayAlgorithm) {
 	ms.inputProvider.SetPlayerAlgorithm(playerSeat, algorithm)
 }
 
+// SetPlayerAlgorithmByName 按名称为特定玩家设置算法（名称格式见 ai.NewAlgorithmByName）
+func (ms *MatchSimulatorV2) SetPlayerAlgorithmByName(playerSeat int, name string) error {
+	algorithm, err := ai.NewAlgorithmByName(name, 2)
+	if err != nil {
+		return err
+	}
+	ms.inputProvider.SetPlayerAlgorithm(playerSeat, algorithm)
+	return nil
+}
+
+// SetPlayerInputProvider 为特定玩家设置独立的输入提供者（如外部进程或远程机器人）
+// 如果提供者同时实现了 sdk.EventObserver，会自动接收游戏事件
+func (ms *MatchSimulatorV2) SetPlayerInputProvider(playerSeat int, provider sdk.PlayerInputProvider) {
+	ms.inputRouter.SetSeatProvider(playerSeat, provider)
+	if observer, ok := provider.(sdk.EventObserver); ok {
+		ms.driver.AddObserver(observer)
+	}
+}
+
+// EnableDatasetExport 开启训练数据导出：每个决策点生成一条记录，比赛结束时写入 writer
+func (ms *MatchSimulatorV2) EnableDatasetExport(writer *DatasetWriter) *DatasetRecorder {
+	recorder := NewDatasetRecorder(ms.inputRouter, writer)
+	ms.input = recorder
+	ms.driver.SetInputProvider(recorder)
+	ms.driver.AddObserver(recorder)
+	return recorder
+}
+
 // AddObserver 添加额外的事件观察者
 func (ms *MatchSimulatorV2) AddObserver(observer sdk.EventObserver) {
 	ms.driver.AddObserver(observer)
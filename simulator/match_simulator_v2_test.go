@@ -142,3 +142,33 @@ func BenchmarkMatchSimulatorV2(b *testing.B) {
 		}
 	}
 }
+
+// TestMatchSimulatorV2WithDifficulties 测试按座位设置不同难度的机器人
+func TestMatchSimulatorV2WithDifficulties(t *testing.T) {
+	simulator := NewMatchSimulatorV2(false)
+	simulator.inputProvider.SetPlayerDifficulty(0, ai.DifficultyBeginner)
+	simulator.inputProvider.SetPlayerDifficulty(1, ai.DifficultyIntermediate)
+	simulator.inputProvider.SetPlayerDifficulty(2, ai.DifficultyExpert)
+
+	if err := simulator.SimulateMatch(); err != nil {
+		t.Fatalf("Failed to simulate match with bot difficulties: %v", err)
+	}
+
+	if _, ok := simulator.inputProvider.algorithms[0].(*ai.BotAutoPlayAlgorithm); !ok {
+		t.Error("seat 0 algorithm should not be overwritten by the default setup")
+	}
+	if simulator.inputProvider.GetHandInference(2) == nil {
+		t.Error("expert bot should get a hand inference")
+	}
+}
+
+func TestMatchSimulatorV2WithNeuralBot(t *testing.T) {
+	model := ai.NewRandomNeuralModel([]int{16}, 7)
+	simulator := NewMatchSimulatorV2(false)
+	simulator.SetPlayerAlgorithm(0, ai.NewNeuralAutoPlayAlgorithm(model, 2, ai.NeuralConfig{Temperature: 1, Seed: 1}))
+	simulator.SetPlayerAlgorithm(2, ai.NewNeuralAutoPlayAlgorithm(model, 2, ai.NeuralConfig{Seed: 2}))
+
+	if err := simulator.SimulateMatch(); err != nil {
+		t.Fatalf("Failed to simulate match with neural bots: %v", err)
+	}
+}
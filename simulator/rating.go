@@ -0,0 +1,79 @@
+package simulator
+
+import "math"
+
+// Elo 默认参数
+const (
+	DefaultElo  = 1500.0
+	DefaultEloK = 32.0
+)
+
+// TrueSkill 默认参数（与原论文一致）
+const (
+	DefaultMu     = 25.0
+	DefaultSigma  = DefaultMu / 3
+	TrueSkillBeta = DefaultSigma / 2   // 表现波动
+	TrueSkillTau  = DefaultSigma / 100 // 每场比赛前加入的动态不确定度
+)
+
+// EloExpected 返回评分为 a 的一方战胜评分为 b 的一方的期望胜率
+func EloExpected(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// EloUpdate 根据一场比赛的结果更新双方的 Elo 评分
+// 参数:
+//
+//	winner, loser: 胜方和负方赛前的评分
+//	k: K 因子
+//
+// 返回值:
+//
+//	float64, float64: 胜方和负方赛后的评分
+func EloUpdate(winner, loser, k float64) (float64, float64) {
+	delta := k * (1 - EloExpected(winner, loser))
+	return winner + delta, loser - delta
+}
+
+// TrueSkillRating TrueSkill 评分（均值和标准差）
+type TrueSkillRating struct {
+	Mu    float64 `json:"mu"`
+	Sigma float64 `json:"sigma"`
+}
+
+// NewTrueSkillRating 返回初始 TrueSkill 评分
+func NewTrueSkillRating() TrueSkillRating {
+	return TrueSkillRating{Mu: DefaultMu, Sigma: DefaultSigma}
+}
+
+// Conservative 返回保守估计 μ-3σ，用于排行榜排序
+func (r TrueSkillRating) Conservative() float64 {
+	return r.Mu - 3*r.Sigma
+}
+
+// TrueSkillUpdate 按双人无平局的 TrueSkill 更新规则更新评分
+// 掼蛋的一支队伍由同一配置的两个机器人组成，因此把整支队伍当作一个选手
+func TrueSkillUpdate(winner, loser TrueSkillRating) (TrueSkillRating, TrueSkillRating) {
+	winnerVar := winner.Sigma*winner.Sigma + TrueSkillTau*TrueSkillTau
+	loserVar := loser.Sigma*loser.Sigma + TrueSkillTau*TrueSkillTau
+	c := math.Sqrt(2*TrueSkillBeta*TrueSkillBeta + winnerVar + loserVar)
+
+	t := (winner.Mu - loser.Mu) / c
+	v := normalPDF(t) / normalCDF(t)
+	w := v * (v + t)
+
+	update := func(rating TrueSkillRating, variance, sign float64) TrueSkillRating {
+		mu := rating.Mu + sign*variance/c*v
+		sigma := math.Sqrt(variance * math.Max(1-variance/(c*c)*w, 1e-6))
+		return TrueSkillRating{Mu: mu, Sigma: sigma}
+	}
+	return update(winner, winnerVar, 1), update(loser, loserVar, -1)
+}
+
+func normalPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
+
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
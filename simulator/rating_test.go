@@ -0,0 +1,49 @@
+package simulator
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEloUpdate(t *testing.T) {
+	if e := EloExpected(1500, 1500); e != 0.5 {
+		t.Errorf("equal ratings should give 0.5, got %f", e)
+	}
+	if e := EloExpected(1900, 1500); math.Abs(e-0.9091) > 1e-4 {
+		t.Errorf("400 points ahead should give 0.909, got %f", e)
+	}
+	winner, loser := EloUpdate(1500, 1500, 32)
+	if winner != 1516 || loser != 1484 {
+		t.Errorf("expected 1516/1484, got %f/%f", winner, loser)
+	}
+	// 爆冷时变化更大
+	upsetWinner, _ := EloUpdate(1400, 1600, 32)
+	if upsetWinner-1400 <= 16 {
+		t.Errorf("upset should gain more than 16 points, gained %f", upsetWinner-1400)
+	}
+}
+
+func TestTrueSkillUpdate(t *testing.T) {
+	winner, loser := TrueSkillUpdate(NewTrueSkillRating(), NewTrueSkillRating())
+	if winner.Mu <= DefaultMu || loser.Mu >= DefaultMu {
+		t.Errorf("winner should gain and loser lose mu: %+v %+v", winner, loser)
+	}
+	if math.Abs((winner.Mu-DefaultMu)-(DefaultMu-loser.Mu)) > 1e-9 {
+		t.Errorf("equal ratings should move symmetrically: %+v %+v", winner, loser)
+	}
+	if winner.Sigma >= DefaultSigma || loser.Sigma >= DefaultSigma {
+		t.Errorf("sigma should shrink after a game: %+v %+v", winner, loser)
+	}
+	// 无平局、加入τ后的1v1首局结果（μ≈29.21, σ≈7.19）
+	if math.Abs(winner.Mu-29.21) > 0.01 || math.Abs(winner.Sigma-7.19) > 0.01 {
+		t.Errorf("unexpected first-game rating: %+v", winner)
+	}
+
+	// 强者战胜弱者时变化很小
+	strong := TrueSkillRating{Mu: 40, Sigma: 2}
+	weak := TrueSkillRating{Mu: 10, Sigma: 2}
+	newStrong, _ := TrueSkillUpdate(strong, weak)
+	if newStrong.Mu-strong.Mu > 0.01 {
+		t.Errorf("expected result should barely move ratings, moved %f", newStrong.Mu-strong.Mu)
+	}
+}
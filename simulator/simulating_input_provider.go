@@ -3,6 +3,7 @@ package simulator
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"guandan-world/ai"
 	"guandan-world/sdk"
@@ -12,18 +13,87 @@ import (
 // 将现有的AutoPlayAlgorithm适配到新的PlayerInputProvider接口
 type SimulatingInputProvider struct {
 	algorithms map[int]ai.AutoPlayAlgorithm // 每个玩家的自动算法
+	inferences map[int]*ai.HandInference    // 支持推断的算法所在座位的对手手牌推断
+	tracker    *ai.ObservationTracker       // 公开信息跟踪器，供需要出牌历史的算法使用
 }
 
 // NewSimulatingInputProvider 创建新的模拟输入提供者
 func NewSimulatingInputProvider() *SimulatingInputProvider {
 	return &SimulatingInputProvider{
 		algorithms: make(map[int]ai.AutoPlayAlgorithm),
+		inferences: make(map[int]*ai.HandInference),
+		tracker:    ai.NewObservationTracker(),
 	}
 }
 
 // SetPlayerAlgorithm 为指定玩家设置自动算法
 func (sip *SimulatingInputProvider) SetPlayerAlgorithm(playerSeat int, algorithm ai.AutoPlayAlgorithm) {
 	sip.algorithms[playerSeat] = algorithm
+
+	// 为支持推断的算法挂载对手手牌推断
+	if aware, ok := algorithm.(ai.InferenceAwareAlgorithm); ok {
+		inference := ai.NewHandInference(playerSeat, 2, nil)
+		sip.inferences[playerSeat] = inference
+		aware.SetHandInference(inference)
+	} else {
+		delete(sip.inferences, playerSeat)
+	}
+
+	if aware, ok := algorithm.(ai.ObservationAwareAlgorithm); ok {
+		aware.SetObservationSource(playerSeat, sip.tracker)
+	}
+}
+
+// HasPlayerAlgorithm 检查指定玩家是否已设置算法
+func (sip *SimulatingInputProvider) HasPlayerAlgorithm(playerSeat int) bool {
+	_, exists := sip.algorithms[playerSeat]
+	return exists
+}
+
+// SetPlayerDifficulty 为指定玩家设置指定难度的机器人（使用该难度的默认性格）
+func (sip *SimulatingInputProvider) SetPlayerDifficulty(playerSeat int, difficulty ai.Difficulty) {
+	sip.SetPlayerAlgorithm(playerSeat, ai.NewBotAutoPlayAlgorithmWithDifficulty(2, difficulty))
+}
+
+// GetHandInference 获取指定座位的对手手牌推断（未启用时返回nil）
+func (sip *SimulatingInputProvider) GetHandInference(playerSeat int) *ai.HandInference {
+	return sip.inferences[playerSeat]
+}
+
+// OnGameEvent 实现EventObserver接口，将事件转发给公开信息跟踪器、各座位的手牌推断，
+// 以及自身实现了 sdk.EventObserver 的算法（如外部进程机器人）
+func (sip *SimulatingInputProvider) OnGameEvent(event *sdk.GameEvent) {
+	sip.tracker.OnGameEvent(event)
+
+	if event.Type == sdk.EventDealStarted {
+		level := 2
+		if data, ok := event.Data.(map[string]interface{}); ok {
+			if l, ok := data["deal_level"].(int); ok {
+				level = l
+			}
+		}
+		for seat, inference := range sip.inferences {
+			inference.Reset(seat, level, nil)
+		}
+	}
+
+	for _, inference := range sip.inferences {
+		inference.OnGameEvent(event)
+	}
+	for _, algorithm := range sip.algorithms {
+		if observer, ok := algorithm.(sdk.EventObserver); ok {
+			observer.OnGameEvent(event)
+		}
+	}
+}
+
+// Close 释放算法持有的资源（如外部机器人进程）
+func (sip *SimulatingInputProvider) Close() {
+	for _, algorithm := range sip.algorithms {
+		if closer, ok := algorithm.(io.Closer); ok {
+			_ = closer.Close()
+		}
+	}
 }
 
 // RequestPlayDecision 实现PlayerInputProvider接口 - 请求出牌决策
@@ -39,6 +109,11 @@ func (sip *SimulatingInputProvider) RequestPlayDecision(
 		return &sdk.PlayDecision{Action: sdk.ActionPass}, nil
 	}
 
+	// 同步推断中的自己手牌
+	if inference, ok := sip.inferences[playerSeat]; ok {
+		inference.SetHand(hand)
+	}
+
 	// 使用算法选择卡牌
 	selectedCards := algorithm.SelectCardsToPlay(hand, trickInfo)
 
@@ -0,0 +1,244 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"guandan-world/ai"
+	"guandan-world/sdk"
+)
+
+// TunerConfig 智能算法权重调优配置（遗传算法）
+type TunerConfig struct {
+	Generations   int             // 迭代代数
+	Population    int             // 每代个体数
+	Elite         int             // 直接保留到下一代的最优个体数
+	Boards        int             // 每个个体与基线进行复式评测的块数
+	DealsPerBoard int             // 每桌只打前几局（0表示打完整场比赛）
+	Workers       int             // 并行评估的个体数
+	Seed          int64           // 随机种子，决定初始种群、遗传操作和每代的牌
+	Baseline      string          // 基线算法名称（见 ai.NewAlgorithmByName）
+	Initial       ai.SmartWeights // 初始权重，种群围绕它生成
+	MutationScale float64         // 变异幅度，相对于初始权重绝对值（至少为1）的比例
+}
+
+// DefaultTunerConfig 返回默认调优配置：以默认权重为起点，对抗默认智能算法
+func DefaultTunerConfig() *TunerConfig {
+	return &TunerConfig{
+		Generations:   10,
+		Population:    12,
+		Elite:         2,
+		Boards:        20,
+		DealsPerBoard: 3,
+		Workers:       1,
+		Seed:          1,
+		Baseline:      "smart",
+		Initial:       ai.DefaultSmartWeights(),
+		MutationScale: 0.2,
+	}
+}
+
+// TunerGeneration 学习曲线中的一代
+type TunerGeneration struct {
+	Generation  int             `json:"generation"`
+	BestFitness float64         `json:"best_fitness"` // 本代最优个体相对基线的平均复式得分差
+	MeanFitness float64         `json:"mean_fitness"`
+	Best        ai.SmartWeights `json:"best"`
+	Duration    time.Duration   `json:"duration"`
+}
+
+// TunerResult 调优结果
+type TunerResult struct {
+	Best        ai.SmartWeights   `json:"best"`         // 最后一代的最优权重
+	BestFitness float64           `json:"best_fitness"` // 最优权重在最后一代牌上的得分
+	Baseline    string            `json:"baseline"`
+	History     []TunerGeneration `json:"history"` // 学习曲线
+	Duration    time.Duration     `json:"duration"`
+}
+
+// tunerIndividual 种群中的个体
+type tunerIndividual struct {
+	genes   []float64
+	fitness float64
+}
+
+// TuneSmartWeights 用遗传算法调优智能算法的权重
+// 参数:
+//
+//	config: 调优配置
+//	progress: 每代结束后的回调（可为nil），用于输出进度
+//
+// 返回值:
+//
+//	*TunerResult: 最优权重和学习曲线
+//	error: 配置无效或评测失败时返回错误
+//
+// 功能说明:
+//   - 每个个体的适应度是它与基线算法复式评测的平均得分差，同一代的所有个体使用相同的牌，
+//     不同代使用不同的牌，避免对固定的牌过拟合
+//   - 精英个体在下一代的新牌上重新评估，噪声大的个体不会一直占据榜首
+func TuneSmartWeights(config *TunerConfig, progress func(TunerGeneration)) (*TunerResult, error) {
+	if config.Generations <= 0 || config.Population < 2 || config.Boards <= 0 {
+		return nil, errors.New("generations, boards must be positive and population at least 2")
+	}
+	baseline, err := ai.NewAlgorithmFactory(config.Baseline)
+	if err != nil {
+		return nil, fmt.Errorf("baseline: %w", err)
+	}
+	elite := config.Elite
+	if elite < 0 || elite >= config.Population {
+		elite = 1
+	}
+
+	rng := rand.New(rand.NewSource(config.Seed))
+	initial := config.Initial.Vector()
+	scales := make([]float64, len(initial))
+	for i, v := range initial {
+		scales[i] = config.MutationScale * math.Max(math.Abs(v), 1)
+	}
+
+	// 初始种群：第一个个体就是初始权重，其余在其周围随机扰动
+	population := make([]*tunerIndividual, config.Population)
+	for i := range population {
+		genes := append([]float64(nil), initial...)
+		if i > 0 {
+			for j := range genes {
+				genes[j] += rng.NormFloat64() * scales[j]
+			}
+		}
+		population[i] = &tunerIndividual{genes: genes}
+	}
+
+	startTime := time.Now()
+	result := &TunerResult{Baseline: config.Baseline}
+	for generation := 0; generation < config.Generations; generation++ {
+		generationStart := time.Now()
+		boardSeed := sdk.DealSeed(config.Seed, generation)
+		if err := evaluatePopulation(config, population, baseline, boardSeed); err != nil {
+			return nil, fmt.Errorf("generation %d: %w", generation, err)
+		}
+		sort.SliceStable(population, func(i, j int) bool {
+			return population[i].fitness > population[j].fitness
+		})
+
+		best, _ := ai.SmartWeightsFromVector(population[0].genes)
+		record := TunerGeneration{
+			Generation:  generation,
+			BestFitness: population[0].fitness,
+			Best:        best,
+			Duration:    time.Since(generationStart),
+		}
+		for _, individual := range population {
+			record.MeanFitness += individual.fitness
+		}
+		record.MeanFitness /= float64(len(population))
+		result.History = append(result.History, record)
+		result.Best = best
+		result.BestFitness = record.BestFitness
+		if progress != nil {
+			progress(record)
+		}
+
+		if generation+1 < config.Generations {
+			population = nextGeneration(population, elite, scales, rng)
+		}
+	}
+	result.Duration = time.Since(startTime)
+	return result, nil
+}
+
+// evaluatePopulation 并行评估种群中每个个体的适应度
+func evaluatePopulation(config *TunerConfig, population []*tunerIndividual, baseline ai.AlgorithmFactory, boardSeed int64) error {
+	workers := config.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	jobs := make(chan *tunerIndividual)
+	errs := make(chan error, len(population))
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for individual := range jobs {
+				if err := evaluateIndividual(config, individual, baseline, boardSeed); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	for _, individual := range population {
+		jobs <- individual
+	}
+	close(jobs)
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+// evaluateIndividual 个体与基线进行复式评测，适应度为每块牌的平均得分差
+func evaluateIndividual(config *TunerConfig, individual *tunerIndividual, baseline ai.AlgorithmFactory, boardSeed int64) error {
+	weights, err := ai.SmartWeightsFromVector(individual.genes)
+	if err != nil {
+		return err
+	}
+	report, err := RunDuplicate(&DuplicateConfig{
+		Boards:  config.Boards,
+		Workers: 1,
+		Seed:    boardSeed,
+		NameA:   "candidate",
+		NameB:   config.Baseline,
+		A: func(level int, seed int64) ai.AutoPlayAlgorithm {
+			return ai.NewSmartAutoPlayAlgorithmWithWeights(level, weights)
+		},
+		B:             baseline,
+		DealsPerBoard: config.DealsPerBoard,
+	})
+	if err != nil {
+		return err
+	}
+	if report.Failures > 0 {
+		return fmt.Errorf("%d boards failed: %v", report.Failures, report.Errors)
+	}
+	individual.fitness = report.MeanScore
+	return nil
+}
+
+// nextGeneration 保留精英，其余个体由锦标赛选择、混合交叉和高斯变异产生
+func nextGeneration(population []*tunerIndividual, elite int, scales []float64, rng *rand.Rand) []*tunerIndividual {
+	next := make([]*tunerIndividual, 0, len(population))
+	for i := 0; i < elite; i++ {
+		next = append(next, &tunerIndividual{genes: append([]float64(nil), population[i].genes...)})
+	}
+
+	tournament := func() *tunerIndividual {
+		best := population[rng.Intn(len(population))]
+		for i := 0; i < 2; i++ {
+			if candidate := population[rng.Intn(len(population))]; candidate.fitness > best.fitness {
+				best = candidate
+			}
+		}
+		return best
+	}
+
+	for len(next) < len(population) {
+		a, b := tournament(), tournament()
+		genes := make([]float64, len(a.genes))
+		for j := range genes {
+			// BLX-0.25 混合交叉：子代在父代区间两侧各外延25%的范围内取值
+			u := rng.Float64()*1.5 - 0.25
+			genes[j] = a.genes[j] + u*(b.genes[j]-a.genes[j])
+			if rng.Float64() < 0.3 {
+				genes[j] += rng.NormFloat64() * scales[j]
+			}
+		}
+		next = append(next, &tunerIndividual{genes: genes})
+	}
+	return next
+}
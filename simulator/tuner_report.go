@@ -0,0 +1,68 @@
+package simulator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"guandan-world/ai"
+)
+
+// Write 按指定格式输出调优报告
+func (r *TunerResult) Write(w io.Writer, format string) error {
+	switch format {
+	case ReportText, "":
+		return r.WriteText(w)
+	case ReportJSON:
+		return r.WriteJSON(w)
+	case ReportCSV:
+		return r.WriteCSV(w)
+	default:
+		return fmt.Errorf("unknown report format: %s", format)
+	}
+}
+
+// WriteText 输出学习曲线和最优权重
+func (r *TunerResult) WriteText(w io.Writer) error {
+	fmt.Fprintln(w, "========== Tuning Report ==========")
+	fmt.Fprintf(w, "Baseline: %s, duration %v\n", r.Baseline, r.Duration)
+	fmt.Fprintf(w, "%-10s %12s %12s\n", "generation", "best", "mean")
+	for _, generation := range r.History {
+		fmt.Fprintf(w, "%-10d %+12.3f %+12.3f\n", generation.Generation, generation.BestFitness, generation.MeanFitness)
+	}
+	fmt.Fprintf(w, "Best weights (fitness %+.3f):\n", r.BestFitness)
+	for i, value := range r.Best.Vector() {
+		fmt.Fprintf(w, "  %-14s %9.4f\n", ai.SmartWeightNames()[i], value)
+	}
+	_, err := fmt.Fprintln(w, "===================================")
+	return err
+}
+
+// WriteJSON 输出 JSON 报告
+func (r *TunerResult) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV 输出学习曲线 CSV，每代一行，附带该代最优个体的权重
+func (r *TunerResult) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	float := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+
+	rows := [][]string{append([]string{"generation", "best_fitness", "mean_fitness"}, ai.SmartWeightNames()...)}
+	for _, generation := range r.History {
+		row := []string{strconv.Itoa(generation.Generation), float(generation.BestFitness), float(generation.MeanFitness)}
+		for _, value := range generation.Best.Vector() {
+			row = append(row, float(value))
+		}
+		rows = append(rows, row)
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,78 @@
+package simulator
+
+import (
+	"bytes"
+	"encoding/csv"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"guandan-world/ai"
+)
+
+func smallTunerConfig() *TunerConfig {
+	config := DefaultTunerConfig()
+	config.Generations = 2
+	config.Population = 3
+	config.Elite = 1
+	config.Boards = 2
+	config.DealsPerBoard = 1
+	config.Workers = 2
+	config.Seed = 4
+	return config
+}
+
+func TestTuneSmartWeights(t *testing.T) {
+	var progress []int
+	result, err := TuneSmartWeights(smallTunerConfig(), func(generation TunerGeneration) {
+		progress = append(progress, generation.Generation)
+	})
+	if err != nil {
+		t.Fatalf("TuneSmartWeights failed: %v", err)
+	}
+	if len(result.History) != 2 || !reflect.DeepEqual(progress, []int{0, 1}) {
+		t.Fatalf("expected two generations of history, got %d (progress %v)", len(result.History), progress)
+	}
+	for _, generation := range result.History {
+		if generation.BestFitness < generation.MeanFitness {
+			t.Errorf("generation %d: best %f below mean %f", generation.Generation, generation.BestFitness, generation.MeanFitness)
+		}
+	}
+	if result.Best != result.History[1].Best {
+		t.Errorf("best weights should come from the last generation")
+	}
+
+	// 同一种子的调优结果完全相同
+	again, err := TuneSmartWeights(smallTunerConfig(), nil)
+	if err != nil {
+		t.Fatalf("TuneSmartWeights failed: %v", err)
+	}
+	if again.Best != result.Best || again.BestFitness != result.BestFitness {
+		t.Errorf("same seed produced different results: %+v vs %+v", again.Best, result.Best)
+	}
+
+	var out bytes.Buffer
+	if err := result.Write(&out, ReportCSV); err != nil {
+		t.Fatalf("csv report failed: %v", err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil || len(rows) != 3 || len(rows[0]) != 3+len(ai.SmartWeightNames()) {
+		t.Errorf("unexpected learning curve csv: %v (%v)", rows, err)
+	}
+}
+
+func TestTuneCLIWritesBestWeights(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "best.json")
+	var stdout, stderr bytes.Buffer
+	code := RunCLI([]string{"tune", "-generations", "1", "-population", "2", "-boards", "1", "-deals", "1", "-out", path}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("tune failed with %d: %s", code, stderr.String())
+	}
+	if _, err := ai.LoadSmartWeights(path); err != nil {
+		t.Errorf("best weights not written: %v", err)
+	}
+
+	if _, err := TuneSmartWeights(&TunerConfig{Generations: 1, Population: 1, Boards: 1, Baseline: "smart"}, nil); err == nil {
+		t.Error("expected error for population below 2")
+	}
+}